@@ -105,9 +105,17 @@ CREATE TABLE IF NOT EXISTS send_log (
     recipient VARCHAR(20) NOT NULL,
     status VARCHAR(20) DEFAULT 'SENT',
     sent_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
-    error TEXT
+    error TEXT,
+    -- Filled from the outcomes workers report on /health/message
+    worker_id VARCHAR(50),
+    message_id VARCHAR(100),
+    job_id VARCHAR(50)
 );
 CREATE INDEX IF NOT EXISTS idx_send_log_campaign ON send_log(campaign_id);
+CREATE INDEX IF NOT EXISTS idx_send_log_message ON send_log(phone, message_id);
+-- Workers push a job's outcome again until acknowledged
+CREATE UNIQUE INDEX IF NOT EXISTS idx_send_log_job ON send_log(job_id)
+WHERE job_id IS NOT NULL;
 -- ============================================
 -- CHAT_HISTORY TABLE (for existing chats tracking)
 -- ============================================
@@ -122,6 +130,70 @@ CREATE TABLE IF NOT EXISTS chat_history (
 CREATE INDEX IF NOT EXISTS idx_chat_history_recipient ON chat_history(recipient_phone);
 CREATE INDEX IF NOT EXISTS idx_chat_history_last_message ON chat_history(last_message_at);
 -- ============================================
+-- MESSAGE_STATUS TABLE (delivery and read receipts workers report)
+-- ============================================
+CREATE TABLE IF NOT EXISTS message_status (
+    phone VARCHAR(20) NOT NULL,
+    message_id VARCHAR(100) NOT NULL,
+    recipient VARCHAR(20) NOT NULL,
+    status VARCHAR(20) NOT NULL,
+    -- sent, delivered, read or failed, as reported by the worker
+    sent_at TIMESTAMP WITH TIME ZONE,
+    delivered_at TIMESTAMP WITH TIME ZONE,
+    read_at TIMESTAMP WITH TIME ZONE,
+    failed_at TIMESTAMP WITH TIME ZONE,
+    error TEXT,
+    worker_id VARCHAR(50),
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (phone, message_id)
+);
+CREATE INDEX IF NOT EXISTS idx_message_status_recipient ON message_status(recipient);
+-- ============================================
+-- INBOUND_MESSAGES TABLE (replies workers forward)
+-- ============================================
+CREATE TABLE IF NOT EXISTS inbound_messages (
+    id SERIAL PRIMARY KEY,
+    phone VARCHAR(20) NOT NULL,
+    -- Account that received the message
+    message_id VARCHAR(100) NOT NULL,
+    from_phone VARCHAR(50) NOT NULL,
+    from_name VARCHAR(100),
+    type VARCHAR(20) NOT NULL,
+    body TEXT,
+    worker_id VARCHAR(50),
+    payload JSONB NOT NULL,
+    -- The message as the worker sent it, media and reply context included
+    received_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    CONSTRAINT inbound_messages_unique UNIQUE (phone, message_id)
+);
+CREATE INDEX IF NOT EXISTS idx_inbound_from ON inbound_messages(phone, from_phone, received_at DESC);
+-- ============================================
+-- OPT_OUTS TABLE (recipients that replied with an opt-out keyword)
+-- ============================================
+CREATE TABLE IF NOT EXISTS opt_outs (
+    recipient VARCHAR(20) PRIMARY KEY,
+    phone VARCHAR(20) NOT NULL,
+    -- Account that received the opt-out
+    source VARCHAR(20) NOT NULL,
+    keyword VARCHAR(50),
+    worker_id VARCHAR(50),
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+-- ============================================
+-- FREQUENCY_RESERVATIONS TABLE (sends counted by the frequency cap)
+-- ============================================
+CREATE TABLE IF NOT EXISTS frequency_reservations (
+    id BIGSERIAL PRIMARY KEY,
+    recipient VARCHAR(20) NOT NULL,
+    -- Account that sends the message
+    phone VARCHAR(20) NOT NULL,
+    worker_id VARCHAR(50),
+    reserved_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_frequency_reservations_recipient ON frequency_reservations(recipient, reserved_at);
+-- ============================================
 -- MESSAGE_QUEUE TABLE (for queued messages)
 -- ============================================
 CREATE TABLE IF NOT EXISTS message_queue (
@@ -137,6 +209,10 @@ CREATE TABLE IF NOT EXISTS message_queue (
     assigned_sender VARCHAR(20),
     retry_count INTEGER DEFAULT 0,
     -- Number of retry attempts (max 3)
+    retry_at TIMESTAMP WITH TIME ZONE,
+    -- Set when a worker policy deferred the send (quiet hours, frequency cap)
+    error_code VARCHAR(50),
+    -- Policy code of the last refusal, e.g. consent_missing
     created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
     processed_at TIMESTAMP WITH TIME ZONE,
     CONSTRAINT queue_status_check CHECK (
@@ -146,6 +222,8 @@ CREATE TABLE IF NOT EXISTS message_queue (
 CREATE INDEX IF NOT EXISTS idx_queue_status ON message_queue(status);
 CREATE INDEX IF NOT EXISTS idx_queue_priority ON message_queue(priority DESC, created_at);
 CREATE INDEX IF NOT EXISTS idx_queue_campaign ON message_queue(campaign_id);
+CREATE INDEX IF NOT EXISTS idx_queue_retry_at ON message_queue(retry_at)
+WHERE status = 'pending' AND retry_at IS NOT NULL;
 -- ============================================
 -- VIEW: accounts_with_status
 -- Shows account status based on connected sessions
@@ -0,0 +1,79 @@
+-- Migration script for v10.0 - Record the send outcomes workers report
+-- Run this script on existing database before updating the master
+
+-- Add worker report columns to send_log table
+ALTER TABLE send_log
+ADD COLUMN IF NOT EXISTS worker_id VARCHAR(50),
+ADD COLUMN IF NOT EXISTS message_id VARCHAR(100),
+ADD COLUMN IF NOT EXISTS job_id VARCHAR(50);
+
+CREATE INDEX IF NOT EXISTS idx_send_log_message ON send_log(phone, message_id);
+
+-- Workers push a job's outcome again until acknowledged
+CREATE UNIQUE INDEX IF NOT EXISTS idx_send_log_job ON send_log(job_id)
+WHERE job_id IS NOT NULL;
+
+-- Add policy deferral columns to message_queue table
+ALTER TABLE message_queue
+ADD COLUMN IF NOT EXISTS retry_at TIMESTAMP WITH TIME ZONE,
+ADD COLUMN IF NOT EXISTS error_code VARCHAR(50);
+
+CREATE INDEX IF NOT EXISTS idx_queue_retry_at ON message_queue(retry_at)
+WHERE status = 'pending' AND retry_at IS NOT NULL;
+
+-- Create inbound_messages table for the replies workers forward
+CREATE TABLE IF NOT EXISTS inbound_messages (
+    id SERIAL PRIMARY KEY,
+    phone VARCHAR(20) NOT NULL,
+    message_id VARCHAR(100) NOT NULL,
+    from_phone VARCHAR(50) NOT NULL,
+    from_name VARCHAR(100),
+    type VARCHAR(20) NOT NULL,
+    body TEXT,
+    worker_id VARCHAR(50),
+    payload JSONB NOT NULL,
+    received_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    CONSTRAINT inbound_messages_unique UNIQUE (phone, message_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_inbound_from ON inbound_messages(phone, from_phone, received_at DESC);
+
+-- Create message_status table for the receipts workers report
+CREATE TABLE IF NOT EXISTS message_status (
+    phone VARCHAR(20) NOT NULL,
+    message_id VARCHAR(100) NOT NULL,
+    recipient VARCHAR(20) NOT NULL,
+    status VARCHAR(20) NOT NULL,
+    sent_at TIMESTAMP WITH TIME ZONE,
+    delivered_at TIMESTAMP WITH TIME ZONE,
+    read_at TIMESTAMP WITH TIME ZONE,
+    failed_at TIMESTAMP WITH TIME ZONE,
+    error TEXT,
+    worker_id VARCHAR(50),
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (phone, message_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_message_status_recipient ON message_status(recipient);
+
+-- Create opt_outs table for the opt-outs workers report
+CREATE TABLE IF NOT EXISTS opt_outs (
+    recipient VARCHAR(20) PRIMARY KEY,
+    phone VARCHAR(20) NOT NULL,
+    source VARCHAR(20) NOT NULL,
+    keyword VARCHAR(50),
+    worker_id VARCHAR(50),
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Create frequency_reservations table for the cap shared by all workers
+CREATE TABLE IF NOT EXISTS frequency_reservations (
+    id BIGSERIAL PRIMARY KEY,
+    recipient VARCHAR(20) NOT NULL,
+    phone VARCHAR(20) NOT NULL,
+    worker_id VARCHAR(50),
+    reserved_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_frequency_reservations_recipient ON frequency_reservations(recipient, reserved_at);
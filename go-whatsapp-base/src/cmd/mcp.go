@@ -30,7 +30,7 @@ func mcpServer(_ *cobra.Command, _ []string) {
 	// Set auto reconnect to whatsapp server after booting
 	go helpers.SetAutoConnectAfterBooting(appUsecase)
 	// Set auto reconnect checking
-	go helpers.SetAutoReconnectChecking(whatsappCli)
+	go helpers.SetAutoReconnectChecking()
 
 	// Create MCP server with capabilities
 	mcpServer := server.NewMCPServer(
@@ -53,6 +53,12 @@ func mcpServer(_ *cobra.Command, _ []string) {
 	groupHandler := mcp.InitMcpGroup(groupUsecase)
 	groupHandler.AddGroupTools(mcpServer)
 
+	communityHandler := mcp.InitMcpCommunity(groupUsecase, sendUsecase)
+	communityHandler.AddCommunityTools(mcpServer)
+
+	templateHandler := mcp.InitMcpTemplate(templateUsecase)
+	templateHandler.AddTemplateTools(mcpServer)
+
 	// Create SSE server
 	sseServer := server.NewSSEServer(
 		mcpServer,
@@ -64,7 +64,15 @@ func restServer(_ *cobra.Command, _ []string) {
 		Browse:     true,
 	}))
 
+	// Create base path group or use app directly
+	var apiGroup fiber.Router = app
+	if config.AppBasePath != "" {
+		apiGroup = app.Group(config.AppBasePath)
+	}
+
 	app.Use(middleware.Recovery())
+	// Probes go before basic auth so orchestrators need no credentials
+	rest.InitRestHealth(apiGroup, healthUsecase)
 	app.Use(middleware.BasicAuth())
 	if config.AppDebug {
 		app.Use(logger.New())
@@ -89,12 +97,6 @@ func restServer(_ *cobra.Command, _ []string) {
 		}))
 	}
 
-	// Create base path group or use app directly
-	var apiGroup fiber.Router = app
-	if config.AppBasePath != "" {
-		apiGroup = app.Group(config.AppBasePath)
-	}
-
 	// Rest
 	rest.InitRestApp(apiGroup, appUsecase)
 	rest.InitRestChat(apiGroup, chatUsecase)
@@ -102,7 +104,13 @@ func restServer(_ *cobra.Command, _ []string) {
 	rest.InitRestUser(apiGroup, userUsecase)
 	rest.InitRestMessage(apiGroup, messageUsecase)
 	rest.InitRestGroup(apiGroup, groupUsecase)
+	rest.InitRestCommunity(apiGroup, groupUsecase, sendUsecase)
 	rest.InitRestNewsletter(apiGroup, newsletterUsecase)
+	rest.InitRestAutoReply(apiGroup, autoReplyUsecase)
+	rest.InitRestTemplate(apiGroup, templateUsecase)
+	rest.InitRestContact(apiGroup, contactUsecase)
+	rest.InitRestLabel(apiGroup, labelUsecase)
+	rest.InitRestOpenAPI(apiGroup)
 
 	apiGroup.Get("/", func(c *fiber.Ctx) error {
 		return c.Render("views/index", fiber.Map{
@@ -121,7 +129,7 @@ func restServer(_ *cobra.Command, _ []string) {
 	// Set auto reconnect to whatsapp server after booting
 	go helpers.SetAutoConnectAfterBooting(appUsecase)
 	// Set auto reconnect checking
-	go helpers.SetAutoReconnectChecking(whatsappCli)
+	go helpers.SetAutoReconnectChecking()
 
 	if err := app.Listen(":" + config.AppPort); err != nil {
 		logrus.Fatalln("Failed to start: ", err.Error())
@@ -12,12 +12,17 @@ import (
 
 	"github.com/aldinokemal/go-whatsapp-web-multidevice/config"
 	domainApp "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/app"
+	domainAutoReply "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/autoreply"
 	domainChat "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/chat"
 	domainChatStorage "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/chatstorage"
+	domainContact "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/contact"
 	domainGroup "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/group"
+	domainHealth "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/health"
+	domainLabel "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/label"
 	domainMessage "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/message"
 	domainNewsletter "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/newsletter"
 	domainSend "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/send"
+	domainTemplate "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/template"
 	domainUser "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/user"
 	"github.com/aldinokemal/go-whatsapp-web-multidevice/infrastructure/chatstorage"
 	"github.com/aldinokemal/go-whatsapp-web-multidevice/infrastructure/whatsapp"
@@ -50,6 +55,11 @@ var (
 	messageUsecase    domainMessage.IMessageUsecase
 	groupUsecase      domainGroup.IGroupUsecase
 	newsletterUsecase domainNewsletter.INewsletterUsecase
+	autoReplyUsecase  domainAutoReply.IAutoReplyUsecase
+	templateUsecase   domainTemplate.ITemplateUsecase
+	contactUsecase    domainContact.IContactUsecase
+	labelUsecase      domainLabel.ILabelUsecase
+	healthUsecase     domainHealth.IHealthUsecase
 )
 
 // rootCmd represents the base command when called without any subcommands
@@ -98,6 +108,12 @@ func initEnvConfig() {
 		proxies := strings.Split(envTrustedProxies, ",")
 		config.AppTrustedProxies = proxies
 	}
+	if viper.IsSet("app_ready_max_webhook_backlog") {
+		config.AppReadyMaxWebhookBacklog = viper.GetInt("app_ready_max_webhook_backlog")
+	}
+	if viper.IsSet("app_ready_min_free_disk") {
+		config.AppReadyMinFreeDisk = viper.GetInt64("app_ready_min_free_disk")
+	}
 
 	// Database settings
 	if envDBURI := viper.GetString("db_uri"); envDBURI != "" {
@@ -127,6 +143,21 @@ func initEnvConfig() {
 	if viper.IsSet("whatsapp_account_validation") {
 		config.WhatsappAccountValidation = viper.GetBool("whatsapp_account_validation")
 	}
+	if viper.IsSet("whatsapp_mention_all_max") {
+		config.WhatsappMentionAllMax = viper.GetInt("whatsapp_mention_all_max")
+	}
+	if viper.IsSet("whatsapp_history_sync_dump") {
+		config.WhatsappHistorySyncDump = viper.GetBool("whatsapp_history_sync_dump")
+	}
+	if viper.IsSet("whatsapp_idempotency_window") {
+		config.WhatsappIdempotencyWindow = viper.GetDuration("whatsapp_idempotency_window")
+	}
+	if viper.IsSet("whatsapp_send_queue") {
+		config.WhatsappSendQueue = viper.GetBool("whatsapp_send_queue")
+	}
+	if viper.IsSet("whatsapp_send_queue_ttl") {
+		config.WhatsappSendQueueTTL = viper.GetDuration("whatsapp_send_queue_ttl")
+	}
 }
 
 func initFlags() {
@@ -168,6 +199,18 @@ func initFlags() {
 		config.AppTrustedProxies,
 		`trusted proxy IP ranges for reverse proxy deployments --trusted-proxies <string> | example: --trusted-proxies="0.0.0.0/0" or --trusted-proxies="10.0.0.0/8,172.16.0.0/12"`,
 	)
+	rootCmd.PersistentFlags().IntVarP(
+		&config.AppReadyMaxWebhookBacklog,
+		"ready-max-webhook-backlog", "",
+		config.AppReadyMaxWebhookBacklog,
+		`webhook deliveries in flight above which /readyz reports not ready --ready-max-webhook-backlog <number> | example: --ready-max-webhook-backlog=500`,
+	)
+	rootCmd.PersistentFlags().Int64VarP(
+		&config.AppReadyMinFreeDisk,
+		"ready-min-free-disk", "",
+		config.AppReadyMinFreeDisk,
+		`free bytes under the media folder below which /readyz reports not ready --ready-min-free-disk <bytes> | example: --ready-min-free-disk=1073741824`,
+	)
 
 	// Database flags
 	rootCmd.PersistentFlags().StringVarP(
@@ -188,7 +231,7 @@ func initFlags() {
 		&config.WhatsappAutoReplyMessage,
 		"autoreply", "",
 		config.WhatsappAutoReplyMessage,
-		`auto reply when received message --autoreply <string> | example: --autoreply="Don't reply this message"`,
+		`deprecated, use the /auto-reply/rules endpoints. creates a catch-all auto-reply rule --autoreply <string> | example: --autoreply="Don't reply this message"`,
 	)
 	rootCmd.PersistentFlags().BoolVarP(
 		&config.WhatsappAutoMarkRead,
@@ -220,6 +263,36 @@ func initFlags() {
 		config.WhatsappAccountValidation,
 		`enable or disable account validation --account-validation <true/false> | example: --account-validation=true`,
 	)
+	rootCmd.PersistentFlags().IntVarP(
+		&config.WhatsappMentionAllMax,
+		"mention-all-max", "",
+		config.WhatsappMentionAllMax,
+		`maximum group participants mention_all is allowed to tag --mention-all-max <number> | example: --mention-all-max=512`,
+	)
+	rootCmd.PersistentFlags().BoolVarP(
+		&config.WhatsappHistorySyncDump,
+		"history-sync-dump", "",
+		config.WhatsappHistorySyncDump,
+		`write raw history sync data to the storages folder as JSON --history-sync-dump <true/false> | example: --history-sync-dump=false`,
+	)
+	rootCmd.PersistentFlags().DurationVarP(
+		&config.WhatsappIdempotencyWindow,
+		"idempotency-window", "",
+		config.WhatsappIdempotencyWindow,
+		`how long an Idempotency-Key replays the first response, 0 disables it --idempotency-window <duration> | example: --idempotency-window=1h`,
+	)
+	rootCmd.PersistentFlags().BoolVarP(
+		&config.WhatsappSendQueue,
+		"send-queue", "",
+		config.WhatsappSendQueue,
+		`queue sends made while disconnected and deliver them after reconnect --send-queue <true/false> | example: --send-queue=true`,
+	)
+	rootCmd.PersistentFlags().DurationVarP(
+		&config.WhatsappSendQueueTTL,
+		"send-queue-ttl", "",
+		config.WhatsappSendQueueTTL,
+		`default time a queued send waits for a connection before it expires --send-queue-ttl <duration> | example: --send-queue-ttl=2h`,
+	)
 }
 
 func initChatStorage() (*sql.DB, error) {
@@ -269,6 +342,10 @@ func initApp() {
 	chatStorageRepo = chatstorage.NewStorageRepository(chatStorageDB)
 	chatStorageRepo.InitializeSchema()
 
+	if err := whatsapp.SyncLegacyAutoReplyRule(chatStorageRepo); err != nil {
+		logrus.Errorf("failed to sync legacy auto-reply rule: %v", err)
+	}
+
 	whatsappDB := whatsapp.InitWaDB(ctx, config.DBURI)
 	var keysDB *sqlstore.Container
 	if config.DBKeysURI != "" {
@@ -280,11 +357,16 @@ func initApp() {
 	// Usecase
 	appUsecase = usecase.NewAppService(chatStorageRepo)
 	chatUsecase = usecase.NewChatService(chatStorageRepo)
-	sendUsecase = usecase.NewSendService(appUsecase, chatStorageRepo)
-	userUsecase = usecase.NewUserService()
+	groupUsecase = usecase.NewGroupService(chatStorageRepo)
+	sendUsecase = usecase.NewSendService(appUsecase, groupUsecase, chatStorageRepo)
+	userUsecase = usecase.NewUserService(chatStorageRepo)
 	messageUsecase = usecase.NewMessageService(chatStorageRepo)
-	groupUsecase = usecase.NewGroupService()
 	newsletterUsecase = usecase.NewNewsletterService()
+	autoReplyUsecase = usecase.NewAutoReplyService(chatStorageRepo)
+	templateUsecase = usecase.NewTemplateService(chatStorageRepo)
+	contactUsecase = usecase.NewContactService(chatStorageRepo)
+	labelUsecase = usecase.NewLabelService(chatStorageRepo)
+	healthUsecase = usecase.NewHealthService(chatStorageRepo)
 }
 
 // Execute adds all child commands to the root command and sets flags appropriately.
@@ -1,6 +1,8 @@
 package config
 
 import (
+	"time"
+
 	"go.mau.fi/whatsmeow/proto/waCompanionReg"
 )
 
@@ -14,6 +16,9 @@ var (
 	AppBasePath            = ""
 	AppTrustedProxies      []string // Trusted proxy IP ranges (e.g., "0.0.0.0/0" for all, or specific CIDRs)
 
+	AppReadyMaxWebhookBacklog       = 1000              // Webhook deliveries in flight above which /readyz fails
+	AppReadyMinFreeDisk       int64 = 100 * 1024 * 1024 // Free bytes under PathMedia below which /readyz fails
+
 	McpPort = "8080"
 	McpHost = "localhost"
 
@@ -38,6 +43,11 @@ var (
 	WhatsappTypeUser                     = "@s.whatsapp.net"
 	WhatsappTypeGroup                    = "@g.us"
 	WhatsappAccountValidation            = true
+	WhatsappMentionAllMax                = 256            // Max group participants mention_all will tag
+	WhatsappHistorySyncDump              = true           // Write each raw history sync to PathStorages as JSON
+	WhatsappIdempotencyWindow            = 24 * time.Hour // How long an Idempotency-Key replays its first response
+	WhatsappSendQueue                    = false          // Queue sends made while disconnected and deliver them after reconnect
+	WhatsappSendQueueTTL                 = 24 * time.Hour // Default time a queued send waits before it expires
 
 	ChatStorageURI               = "file:storages/chatstorage.db"
 	ChatStorageEnableForeignKeys = true
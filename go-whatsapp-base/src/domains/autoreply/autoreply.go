@@ -0,0 +1,76 @@
+package autoreply
+
+// Request and Response structures for auto-reply rule management
+
+type RuleRequest struct {
+	RuleID          int64    `json:"rule_id" uri:"rule_id"`
+	Name            string   `json:"name" form:"name"`
+	Enabled         *bool    `json:"enabled" form:"enabled"`
+	Priority        int      `json:"priority" form:"priority"`
+	Keywords        []string `json:"keywords" form:"keywords"`
+	Pattern         string   `json:"pattern" form:"pattern"`
+	Senders         []string `json:"senders" form:"senders"`
+	ChatType        string   `json:"chat_type" form:"chat_type"`
+	ResponseType    string   `json:"response_type" form:"response_type"`
+	ResponseText    string   `json:"response_text" form:"response_text"`
+	MediaURL        string   `json:"media_url" form:"media_url"`
+	CooldownSeconds int      `json:"cooldown_seconds" form:"cooldown_seconds"`
+}
+
+type GetRuleRequest struct {
+	RuleID int64 `json:"rule_id" uri:"rule_id"`
+}
+
+type DeleteRuleRequest struct {
+	RuleID int64 `json:"rule_id" uri:"rule_id"`
+}
+
+type ListHitsRequest struct {
+	RuleID int64  `json:"rule_id" query:"rule_id"`
+	Sender string `json:"sender" query:"sender"`
+	Limit  int    `json:"limit" query:"limit"`
+	Offset int    `json:"offset" query:"offset"`
+}
+
+type RuleInfo struct {
+	ID              int64    `json:"id"`
+	Name            string   `json:"name"`
+	Enabled         bool     `json:"enabled"`
+	Priority        int      `json:"priority"`
+	Keywords        []string `json:"keywords"`
+	Pattern         string   `json:"pattern"`
+	Senders         []string `json:"senders"`
+	ChatType        string   `json:"chat_type"`
+	ResponseType    string   `json:"response_type"`
+	ResponseText    string   `json:"response_text"`
+	MediaURL        string   `json:"media_url"`
+	CooldownSeconds int      `json:"cooldown_seconds"`
+	CreatedAt       string   `json:"created_at"`
+	UpdatedAt       string   `json:"updated_at"`
+}
+
+type HitInfo struct {
+	ID             int64  `json:"id"`
+	RuleID         int64  `json:"rule_id"`
+	ChatJID        string `json:"chat_jid"`
+	SenderJID      string `json:"sender_jid"`
+	MessageID      string `json:"message_id"`
+	ReplyMessageID string `json:"reply_message_id"`
+	Status         string `json:"status"`
+	Error          string `json:"error"`
+	CreatedAt      string `json:"created_at"`
+}
+
+type ListRulesResponse struct {
+	Data []RuleInfo `json:"data"`
+}
+
+type ListHitsResponse struct {
+	Data       []HitInfo          `json:"data"`
+	Pagination PaginationResponse `json:"pagination"`
+}
+
+type PaginationResponse struct {
+	Limit  int `json:"limit"`
+	Offset int `json:"offset"`
+}
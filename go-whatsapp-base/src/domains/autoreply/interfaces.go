@@ -0,0 +1,15 @@
+package autoreply
+
+import (
+	"context"
+)
+
+// IAutoReplyUsecase defines the interface for auto-reply rule management
+type IAutoReplyUsecase interface {
+	ListRules(ctx context.Context) (response ListRulesResponse, err error)
+	GetRule(ctx context.Context, request GetRuleRequest) (response RuleInfo, err error)
+	CreateRule(ctx context.Context, request RuleRequest) (response RuleInfo, err error)
+	UpdateRule(ctx context.Context, request RuleRequest) (response RuleInfo, err error)
+	DeleteRule(ctx context.Context, request DeleteRuleRequest) (err error)
+	ListHits(ctx context.Context, request ListHitsRequest) (response ListHitsResponse, err error)
+}
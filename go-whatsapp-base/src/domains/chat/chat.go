@@ -7,6 +7,7 @@ type ListChatsRequest struct {
 	Offset   int    `json:"offset" query:"offset"`
 	Search   string `json:"search" query:"search"`
 	HasMedia bool   `json:"has_media" query:"has_media"`
+	LabelID  string `json:"label_id" query:"label_id"`
 }
 
 type ListChatsResponse struct {
@@ -44,13 +45,38 @@ type PinChatResponse struct {
 	Pinned  bool   `json:"pinned"`
 }
 
+// History backfill operations
+type BackfillChatRequest struct {
+	ChatJID string `json:"chat_jid" uri:"chat_jid"`
+	Count   int    `json:"count" form:"count"`
+}
+
+type GetChatBackfillRequest struct {
+	ChatJID string `json:"chat_jid" uri:"chat_jid"`
+}
+
+type BackfillInfo struct {
+	ChatJID          string `json:"chat_jid"`
+	Status           string `json:"status"`
+	RequestedCount   int    `json:"requested_count"`
+	AnchorMessageID  string `json:"anchor_message_id"`
+	AnchorTimestamp  string `json:"anchor_timestamp"`
+	ReceivedMessages int    `json:"received_messages"`
+	StoredMessages   int    `json:"stored_messages"`
+	TotalStored      int    `json:"total_stored"`
+	Requests         int    `json:"requests"`
+	RequestedAt      string `json:"requested_at"`
+	CompletedAt      string `json:"completed_at,omitempty"`
+}
+
 type ChatInfo struct {
-	JID                 string `json:"jid"`
-	Name                string `json:"name"`
-	LastMessageTime     string `json:"last_message_time"`
-	EphemeralExpiration uint32 `json:"ephemeral_expiration"`
-	CreatedAt           string `json:"created_at"`
-	UpdatedAt           string `json:"updated_at"`
+	JID                 string   `json:"jid"`
+	Name                string   `json:"name"`
+	LastMessageTime     string   `json:"last_message_time"`
+	EphemeralExpiration uint32   `json:"ephemeral_expiration"`
+	Labels              []string `json:"labels,omitempty"`
+	CreatedAt           string   `json:"created_at"`
+	UpdatedAt           string   `json:"updated_at"`
 }
 
 type MessageInfo struct {
@@ -64,8 +90,18 @@ type MessageInfo struct {
 	Filename   string `json:"filename"`
 	URL        string `json:"url"`
 	FileLength uint64 `json:"file_length"`
-	CreatedAt  string `json:"created_at"`
-	UpdatedAt  string `json:"updated_at"`
+	ViewOnce   bool   `json:"view_once,omitempty"`
+	// InteractiveReply is the choice made when the message replies to a buttons, list or template message
+	InteractiveReply *InteractiveReply `json:"interactive_reply,omitempty"`
+	CreatedAt        string            `json:"created_at"`
+	UpdatedAt        string            `json:"updated_at"`
+}
+
+type InteractiveReply struct {
+	Type              string `json:"type"`
+	SelectedID        string `json:"selected_id"`
+	SelectedTitle     string `json:"selected_title"`
+	OriginalMessageID string `json:"original_message_id,omitempty"`
 }
 
 type PaginationResponse struct {
@@ -9,4 +9,6 @@ type IChatUsecase interface {
 	ListChats(ctx context.Context, request ListChatsRequest) (response ListChatsResponse, err error)
 	GetChatMessages(ctx context.Context, request GetChatMessagesRequest) (response GetChatMessagesResponse, err error)
 	PinChat(ctx context.Context, request PinChatRequest) (response PinChatResponse, err error)
+	BackfillChat(ctx context.Context, request BackfillChatRequest) (response BackfillInfo, err error)
+	GetChatBackfill(ctx context.Context, request GetChatBackfillRequest) (response BackfillInfo, err error)
 }
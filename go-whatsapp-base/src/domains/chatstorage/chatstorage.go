@@ -27,8 +27,20 @@ type Message struct {
 	FileSHA256    []byte    `db:"file_sha256"`
 	FileEncSHA256 []byte    `db:"file_enc_sha256"`
 	FileLength    uint64    `db:"file_length"`
-	CreatedAt     time.Time `db:"created_at"`
-	UpdatedAt     time.Time `db:"updated_at"`
+	// ViewOnce marks media the sender allowed to be viewed only once
+	ViewOnce bool `db:"view_once"`
+	// Mimetype is the media type the sender gave, kept so a forward matches it
+	Mimetype string `db:"mimetype"`
+	// ForwardingScore counts how many times the message was already forwarded
+	ForwardingScore uint32 `db:"forwarding_score"`
+	// InteractiveType, SelectedID, SelectedTitle and OriginalMessageID hold the
+	// choice made in a reply to a buttons, list or template message
+	InteractiveType   string    `db:"interactive_type"`
+	SelectedID        string    `db:"selected_id"`
+	SelectedTitle     string    `db:"selected_title"`
+	OriginalMessageID string    `db:"original_message_id"`
+	CreatedAt         time.Time `db:"created_at"`
+	UpdatedAt         time.Time `db:"updated_at"`
 }
 
 // MediaInfo represents downloadable media information
@@ -61,4 +73,291 @@ type ChatFilter struct {
 	Offset     int
 	SearchName string
 	HasMedia   bool
+	LabelID    string
+}
+
+// Auto-reply chat types a rule can be limited to
+const (
+	AutoReplyChatPrivate = "private"
+	AutoReplyChatGroup   = "group"
+	AutoReplyChatAll     = "all"
+)
+
+// Auto-reply response types
+const (
+	AutoReplyResponseText     = "text"
+	AutoReplyResponseTemplate = "template"
+	AutoReplyResponseImage    = "image"
+	AutoReplyResponseVideo    = "video"
+)
+
+// Auto-reply hit statuses
+const (
+	AutoReplyStatusSent     = "sent"
+	AutoReplyStatusFailed   = "failed"
+	AutoReplyStatusCooldown = "cooldown"
+)
+
+// AutoReplyRule represents a stored auto-reply rule. All configured matchers
+// (keywords, pattern, senders, chat type) must match for the rule to fire.
+type AutoReplyRule struct {
+	ID              int64     `db:"id"`
+	Name            string    `db:"name"`
+	Enabled         bool      `db:"enabled"`
+	Priority        int       `db:"priority"`
+	Keywords        []string  `db:"keywords"`
+	Pattern         string    `db:"pattern"`
+	Senders         []string  `db:"senders"`
+	ChatType        string    `db:"chat_type"`
+	ResponseType    string    `db:"response_type"`
+	ResponseText    string    `db:"response_text"`
+	MediaURL        string    `db:"media_url"`
+	CooldownSeconds int       `db:"cooldown_seconds"`
+	CreatedAt       time.Time `db:"created_at"`
+	UpdatedAt       time.Time `db:"updated_at"`
+}
+
+// AutoReplyHit records an incoming message that matched an auto-reply rule
+type AutoReplyHit struct {
+	ID             int64     `db:"id"`
+	RuleID         int64     `db:"rule_id"`
+	ChatJID        string    `db:"chat_jid"`
+	SenderJID      string    `db:"sender_jid"`
+	MessageID      string    `db:"message_id"`
+	ReplyMessageID string    `db:"reply_message_id"`
+	Status         string    `db:"status"`
+	Error          string    `db:"error"`
+	CreatedAt      time.Time `db:"created_at"`
+}
+
+// AutoReplyHitFilter represents query filters for auto-reply hits
+type AutoReplyHitFilter struct {
+	RuleID    int64
+	SenderJID string
+	Limit     int
+	Offset    int
+}
+
+// Message template variable types
+const (
+	TemplateVarText   = "text"
+	TemplateVarNumber = "number"
+	TemplateVarDate   = "date"
+	TemplateVarURL    = "url"
+)
+
+// TemplateVariable describes a placeholder a message template accepts
+type TemplateVariable struct {
+	Name     string `json:"name"`
+	Type     string `json:"type"`
+	Required bool   `json:"required"`
+	Default  string `json:"default,omitempty"`
+}
+
+// TemplateVariant is the body of a message template in one language
+type TemplateVariant struct {
+	Language string `json:"language"`
+	Body     string `json:"body"`
+}
+
+// MessageTemplate represents a named message template. Bodies reference
+// variables as {{name}}.
+type MessageTemplate struct {
+	ID              string             `db:"id"`
+	Description     string             `db:"description"`
+	DefaultLanguage string             `db:"default_language"`
+	Variables       []TemplateVariable `db:"variables"`
+	Variants        []TemplateVariant  `db:"variants"`
+	CreatedAt       time.Time          `db:"created_at"`
+	UpdatedAt       time.Time          `db:"updated_at"`
+}
+
+// Chat backfill statuses
+const (
+	BackfillStatusPending   = "pending"
+	BackfillStatusCompleted = "completed"
+	BackfillStatusFailed    = "failed"
+)
+
+// ChatBackfill tracks on-demand history requests for one chat. Each request
+// asks the primary device for messages older than the anchor message.
+type ChatBackfill struct {
+	ChatJID          string     `db:"chat_jid"`
+	Status           string     `db:"status"`
+	RequestedCount   int        `db:"requested_count"`
+	AnchorMessageID  string     `db:"anchor_message_id"`
+	AnchorTimestamp  time.Time  `db:"anchor_timestamp"`
+	ReceivedMessages int        `db:"received_messages"`
+	StoredMessages   int        `db:"stored_messages"`
+	TotalStored      int        `db:"total_stored"`
+	Requests         int        `db:"requests"`
+	RequestedAt      time.Time  `db:"requested_at"`
+	CompletedAt      *time.Time `db:"completed_at"`
+}
+
+// Contact is a WhatsApp user known to this account. JID is the phone-number
+// JID when known, otherwise the LID; PhoneJID and LIDJID map one to the other.
+type Contact struct {
+	JID          string     `db:"jid"`
+	PhoneJID     string     `db:"phone_jid"`
+	LIDJID       string     `db:"lid_jid"`
+	SavedName    string     `db:"saved_name"`
+	PushName     string     `db:"push_name"`
+	BusinessName string     `db:"business_name"`
+	FirstSeen    *time.Time `db:"first_seen"`
+	LastSeen     *time.Time `db:"last_seen"`
+	CreatedAt    time.Time  `db:"created_at"`
+	UpdatedAt    time.Time  `db:"updated_at"`
+}
+
+// DisplayName returns the best known name: the address book name, then the
+// push name, then the verified business name
+func (c *Contact) DisplayName() string {
+	switch {
+	case c.SavedName != "":
+		return c.SavedName
+	case c.PushName != "":
+		return c.PushName
+	default:
+		return c.BusinessName
+	}
+}
+
+// ContactFilter represents query filters for contacts
+type ContactFilter struct {
+	Search string
+	Limit  int
+	Offset int
+}
+
+// Group membership actions recorded in the membership log
+const (
+	GroupActionJoin    = "join"
+	GroupActionLeave   = "leave"
+	GroupActionPromote = "promote"
+	GroupActionDemote  = "demote"
+)
+
+// Group participant roles
+const (
+	GroupRoleMember     = "member"
+	GroupRoleAdmin      = "admin"
+	GroupRoleSuperAdmin = "superadmin"
+)
+
+// Sources of a membership log entry
+const (
+	GroupEventSourceEvent = "event" // a group notification from WhatsApp
+	GroupEventSourceSync  = "sync"  // a difference found when comparing a fresh group snapshot
+)
+
+// Group represents the last known metadata of a group
+type Group struct {
+	JID            string     `db:"jid"`
+	Name           string     `db:"name"`
+	Topic          string     `db:"topic"`
+	OwnerJID       string     `db:"owner_jid"`
+	IsAnnounce     bool       `db:"is_announce"`
+	IsLocked       bool       `db:"is_locked"`
+	IsCommunity    bool       `db:"is_community"`
+	GroupCreatedAt *time.Time `db:"group_created_at"`
+	CreatedAt      time.Time  `db:"created_at"`
+	UpdatedAt      time.Time  `db:"updated_at"`
+}
+
+// GroupParticipant represents a current member of a group
+type GroupParticipant struct {
+	GroupJID       string    `db:"group_jid"`
+	ParticipantJID string    `db:"participant_jid"`
+	Role           string    `db:"role"`
+	JoinedAt       time.Time `db:"joined_at"`
+	UpdatedAt      time.Time `db:"updated_at"`
+}
+
+// GroupMembershipEvent is an append-only entry of the membership log. Role is
+// the participant's role after the change.
+type GroupMembershipEvent struct {
+	ID             int64     `db:"id"`
+	GroupJID       string    `db:"group_jid"`
+	ParticipantJID string    `db:"participant_jid"`
+	Action         string    `db:"action"`
+	Role           string    `db:"role"`
+	ActorJID       string    `db:"actor_jid"`
+	Reason         string    `db:"reason"`
+	Source         string    `db:"source"`
+	Timestamp      time.Time `db:"timestamp"`
+}
+
+// GroupMembershipFilter represents query filters for the membership log
+type GroupMembershipFilter struct {
+	GroupJID       string
+	ParticipantJID string
+	Action         string
+	Since          *time.Time
+	Until          *time.Time
+	Limit          int
+	Offset         int
+}
+
+// IdempotencyRecord remembers the response of a send made with an idempotency
+// key so a retried request can be answered without sending again
+type IdempotencyRecord struct {
+	Key       string    `db:"key"`
+	Operation string    `db:"operation"`
+	Recipient string    `db:"recipient"`
+	MessageID string    `db:"message_id"`
+	Status    string    `db:"status"`
+	CreatedAt time.Time `db:"created_at"`
+}
+
+// Send queue statuses. Queued is the only pending state; the others are final.
+const (
+	QueueStatusQueued    = "queued"
+	QueueStatusSent      = "sent"
+	QueueStatusFailed    = "failed"
+	QueueStatusExpired   = "expired"
+	QueueStatusCancelled = "cancelled"
+)
+
+// QueuedMessage is a send made while disconnected. Payload is the marshalled
+// message proto, delivered with the reserved MessageID after reconnect.
+type QueuedMessage struct {
+	ID        int64      `db:"id"`
+	MessageID string     `db:"message_id"`
+	Recipient string     `db:"recipient"`
+	Content   string     `db:"content"`
+	Payload   []byte     `db:"payload"`
+	Status    string     `db:"status"`
+	Error     string     `db:"error"`
+	ExpiresAt time.Time  `db:"expires_at"`
+	SentAt    *time.Time `db:"sent_at"`
+	CreatedAt time.Time  `db:"created_at"`
+	UpdatedAt time.Time  `db:"updated_at"`
+}
+
+// QueuedMessageFilter represents query filters for the send queue
+type QueuedMessageFilter struct {
+	Status string
+	Limit  int
+	Offset int
+}
+
+// Label is a WhatsApp Business label. Labels deleted on any device are kept
+// with Deleted set so a late association can't bring them back.
+type Label struct {
+	ID        string    `db:"id"`
+	Name      string    `db:"name"`
+	Color     int32     `db:"color"`
+	Deleted   bool      `db:"deleted"`
+	ChatCount int64     `db:"chat_count"`
+	CreatedAt time.Time `db:"created_at"`
+	UpdatedAt time.Time `db:"updated_at"`
+}
+
+// MessageLabel is a label assigned to a single message
+type MessageLabel struct {
+	LabelID   string    `db:"label_id"`
+	ChatJID   string    `db:"chat_jid"`
+	MessageID string    `db:"message_id"`
+	CreatedAt time.Time `db:"created_at"`
 }
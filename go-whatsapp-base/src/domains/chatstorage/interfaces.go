@@ -29,13 +29,74 @@ type IChatStorageRepository interface {
 	GetChatMessageCount(chatJID string) (int64, error)
 	GetTotalMessageCount() (int64, error)
 	GetTotalChatCount() (int64, error)
+	GetChatCount(filter *ChatFilter) (int64, error)
 	GetChatNameWithPushName(jid types.JID, chatJID string, senderUser string, pushName string) string
 	GetStorageStatistics() (chatCount int64, messageCount int64, err error)
 
+	// Auto-reply operations
+	StoreAutoReplyRule(rule *AutoReplyRule) error
+	GetAutoReplyRule(id int64) (*AutoReplyRule, error)
+	GetAutoReplyRules(enabledOnly bool) ([]*AutoReplyRule, error)
+	DeleteAutoReplyRule(id int64) error
+	StoreAutoReplyHit(hit *AutoReplyHit) error
+	GetAutoReplyHits(filter *AutoReplyHitFilter) ([]*AutoReplyHit, error)
+	GetLastAutoReplyTime(ruleID int64, senderJID string) (time.Time, error)
+
+	// Message template operations
+	StoreMessageTemplate(template *MessageTemplate) error
+	GetMessageTemplate(id string) (*MessageTemplate, error)
+	GetMessageTemplates() ([]*MessageTemplate, error)
+	DeleteMessageTemplate(id string) error
+
+	// Contact operations
+	StoreContact(contact *Contact) error
+	GetContact(jid string) (*Contact, error)
+	GetContacts(filter *ContactFilter) ([]*Contact, error)
+	GetContactCount(filter *ContactFilter) (int64, error)
+
+	// Group operations
+	StoreGroup(group *Group) error
+	GetGroup(jid string) (*Group, error)
+	StoreGroupMembershipEvents(events []*GroupMembershipEvent) error
+	SyncGroupParticipants(groupJID string, participants []*GroupParticipant, at time.Time) ([]*GroupMembershipEvent, error)
+	GetGroupParticipants(groupJID string) ([]*GroupParticipant, error)
+	GetGroupParticipantsAt(groupJID string, asOf time.Time) ([]*GroupParticipant, error)
+	GetGroupMembershipEvents(filter *GroupMembershipFilter) ([]*GroupMembershipEvent, error)
+	GetGroupMembershipEventCount(filter *GroupMembershipFilter) (int64, error)
+
+	// Idempotency operations
+	GetIdempotencyRecord(key string, since time.Time) (*IdempotencyRecord, error)
+	StoreIdempotencyRecord(record *IdempotencyRecord) error
+	DeleteIdempotencyRecordsBefore(cutoff time.Time) (int64, error)
+
+	// Send queue operations
+	EnqueueMessage(message *QueuedMessage) error
+	GetQueuedMessage(id int64) (*QueuedMessage, error)
+	GetQueuedMessages(filter *QueuedMessageFilter) ([]*QueuedMessage, error)
+	GetQueuedMessageCount(filter *QueuedMessageFilter) (int64, error)
+	UpdateQueuedMessageStatus(id int64, fromStatus, status, errMessage string) (bool, error)
+
+	// Label operations
+	StoreLabel(label *Label) error
+	GetLabel(id string) (*Label, error)
+	GetLabels(includeDeleted bool) ([]*Label, error)
+	SetChatLabel(labelID, chatJID string, labeled bool) error
+	GetChatLabels(chatJIDs []string) (map[string][]string, error)
+	SetMessageLabel(labelID, chatJID, messageID string, labeled bool) error
+	GetMessageLabels(chatJID, messageID string) ([]string, error)
+
+	// History backfill operations
+	GetOldestMessage(chatJID string) (*Message, error)
+	StoreChatBackfill(backfill *ChatBackfill) error
+	GetChatBackfill(chatJID string) (*ChatBackfill, error)
+
 	// Cleanup operations
 	TruncateAllChats() error
 	TruncateAllDataWithLogging(logPrefix string) error
 
+	// Health operations
+	CheckWritable(ctx context.Context) error
+
 	// Schema operations
 	InitializeSchema() error
 }
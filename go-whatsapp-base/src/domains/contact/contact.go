@@ -0,0 +1,58 @@
+package contact
+
+import "mime/multipart"
+
+const (
+	FormatVCard = "vcard"
+	FormatCSV   = "csv"
+)
+
+type ListContactsRequest struct {
+	Limit  int    `json:"limit" query:"limit"`
+	Offset int    `json:"offset" query:"offset"`
+	Search string `json:"search" query:"search"`
+}
+
+type ListContactsResponse struct {
+	Data       []ContactInfo      `json:"data"`
+	Pagination PaginationResponse `json:"pagination"`
+}
+
+type ContactInfo struct {
+	JID          string `json:"jid"`
+	PhoneJID     string `json:"phone_jid,omitempty"`
+	LIDJID       string `json:"lid_jid,omitempty"`
+	Name         string `json:"name"`
+	SavedName    string `json:"saved_name,omitempty"`
+	PushName     string `json:"push_name,omitempty"`
+	BusinessName string `json:"business_name,omitempty"`
+	FirstSeen    string `json:"first_seen,omitempty"`
+	LastSeen     string `json:"last_seen,omitempty"`
+	UpdatedAt    string `json:"updated_at"`
+}
+
+type PaginationResponse struct {
+	Limit  int `json:"limit"`
+	Offset int `json:"offset"`
+	Total  int `json:"total"`
+}
+
+type ImportContactsRequest struct {
+	File   *multipart.FileHeader `json:"file" form:"file"`
+	Format string                `json:"format" form:"format"`
+}
+
+type ImportContactsResponse struct {
+	Imported int `json:"imported"`
+	Skipped  int `json:"skipped"`
+}
+
+type ExportContactsRequest struct {
+	Format string `json:"format" query:"format"`
+}
+
+type ExportContactsResponse struct {
+	FileName    string
+	ContentType string
+	Data        []byte
+}
@@ -0,0 +1,12 @@
+package contact
+
+import (
+	"context"
+)
+
+// IContactUsecase defines the interface for the stored contact book
+type IContactUsecase interface {
+	ListContacts(ctx context.Context, request ListContactsRequest) (response ListContactsResponse, err error)
+	ImportContacts(ctx context.Context, request ImportContactsRequest) (response ImportContactsResponse, err error)
+	ExportContacts(ctx context.Context, request ExportContactsRequest) (response ExportContactsResponse, err error)
+}
@@ -131,3 +131,90 @@ type GetGroupInviteLinkResponse struct {
 type GroupInfoResponse struct {
 	Data any `json:"data"`
 }
+
+type CreateCommunityRequest struct {
+	Title        string   `json:"title" form:"title"`
+	Participants []string `json:"participants" form:"participants"`
+}
+
+type CommunityGroupsRequest struct {
+	CommunityID string   `json:"community_id" form:"community_id"`
+	GroupIDs    []string `json:"group_ids" form:"group_ids"`
+}
+
+type CommunityGroupStatus struct {
+	GroupID string `json:"group_id"`
+	Status  string `json:"status"`
+	Message string `json:"message"`
+}
+
+type ListCommunityGroupsRequest struct {
+	CommunityID string `json:"community_id" query:"community_id"`
+}
+
+type CommunityGroup struct {
+	GroupID        string `json:"group_id"`
+	Name           string `json:"name"`
+	IsAnnouncement bool   `json:"is_announcement"`
+}
+
+type ListCommunityGroupsResponse struct {
+	CommunityID string           `json:"community_id"`
+	Groups      []CommunityGroup `json:"groups"`
+}
+
+type GetAnnouncementGroupRequest struct {
+	CommunityID string `json:"community_id" form:"community_id" query:"community_id"`
+}
+
+// Membership history operations
+type GroupHistoryRequest struct {
+	GroupID     string `json:"group_id" query:"group_id"`
+	Participant string `json:"participant" query:"participant"`
+	Action      string `json:"action" query:"action"`
+	Since       string `json:"since" query:"since"`
+	Until       string `json:"until" query:"until"`
+	Limit       int    `json:"limit" query:"limit"`
+	Offset      int    `json:"offset" query:"offset"`
+}
+
+type GroupMembershipEvent struct {
+	ID             int64  `json:"id"`
+	GroupID        string `json:"group_id"`
+	ParticipantJID string `json:"participant_jid"`
+	Action         string `json:"action"`
+	Role           string `json:"role"`
+	ActorJID       string `json:"actor_jid,omitempty"`
+	Reason         string `json:"reason,omitempty"`
+	Source         string `json:"source"`
+	Timestamp      string `json:"timestamp"`
+}
+
+type GroupHistoryResponse struct {
+	Data       []GroupMembershipEvent `json:"data"`
+	Pagination PaginationResponse     `json:"pagination"`
+}
+
+type PaginationResponse struct {
+	Limit  int `json:"limit"`
+	Offset int `json:"offset"`
+	Total  int `json:"total"`
+}
+
+type GroupMembersRequest struct {
+	GroupID string `json:"group_id" query:"group_id"`
+	AsOf    string `json:"as_of" query:"as_of"`
+}
+
+type GroupMember struct {
+	JID      string `json:"jid"`
+	Role     string `json:"role"`
+	JoinedAt string `json:"joined_at"`
+}
+
+type GroupMembersResponse struct {
+	GroupID string        `json:"group_id"`
+	Name    string        `json:"name,omitempty"`
+	AsOf    string        `json:"as_of,omitempty"`
+	Members []GroupMember `json:"members"`
+}
@@ -31,9 +31,27 @@ type IGroupSettings interface {
 	SetGroupTopic(ctx context.Context, request SetGroupTopicRequest) (err error)
 }
 
+// IGroupCommunity handles community operations. A community is a parent group
+// whose default sub-group is the announcement group.
+type IGroupCommunity interface {
+	CreateCommunity(ctx context.Context, request CreateCommunityRequest) (communityID string, err error)
+	LinkCommunityGroups(ctx context.Context, request CommunityGroupsRequest) (result []CommunityGroupStatus, err error)
+	UnlinkCommunityGroups(ctx context.Context, request CommunityGroupsRequest) (result []CommunityGroupStatus, err error)
+	ListCommunityGroups(ctx context.Context, request ListCommunityGroupsRequest) (response ListCommunityGroupsResponse, err error)
+	GetAnnouncementGroup(ctx context.Context, request GetAnnouncementGroupRequest) (response CommunityGroup, err error)
+}
+
+// IGroupHistory reads the stored group members and membership log
+type IGroupHistory interface {
+	GetGroupHistory(ctx context.Context, request GroupHistoryRequest) (response GroupHistoryResponse, err error)
+	GetGroupMembers(ctx context.Context, request GroupMembersRequest) (response GroupMembersResponse, err error)
+}
+
 // IGroupUsecase combines all group interfaces for backward compatibility
 type IGroupUsecase interface {
 	IGroupManagement
 	IGroupParticipants
 	IGroupSettings
+	IGroupCommunity
+	IGroupHistory
 }
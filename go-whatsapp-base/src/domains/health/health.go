@@ -0,0 +1,27 @@
+package health
+
+// Status values reported for the process, the readiness and each check
+const (
+	StatusOK   = "ok"
+	StatusFail = "fail"
+)
+
+type LivenessResponse struct {
+	Status        string `json:"status"`
+	Version       string `json:"version"`
+	UptimeSeconds int64  `json:"uptime_seconds"`
+}
+
+type CheckResult struct {
+	Name       string         `json:"name"`
+	Status     string         `json:"status"`
+	Message    string         `json:"message,omitempty"`
+	Details    map[string]any `json:"details,omitempty"`
+	DurationMs int64          `json:"duration_ms"`
+}
+
+type ReadinessResponse struct {
+	// Status is ok only when every check passed
+	Status string        `json:"status"`
+	Checks []CheckResult `json:"checks"`
+}
@@ -0,0 +1,11 @@
+package health
+
+import (
+	"context"
+)
+
+// IHealthUsecase reports whether the process is alive and ready for traffic
+type IHealthUsecase interface {
+	Liveness(ctx context.Context) LivenessResponse
+	Readiness(ctx context.Context) ReadinessResponse
+}
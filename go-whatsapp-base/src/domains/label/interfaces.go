@@ -0,0 +1,15 @@
+package label
+
+import (
+	"context"
+)
+
+// ILabelUsecase defines the interface for WhatsApp Business label management
+type ILabelUsecase interface {
+	ListLabels(ctx context.Context) (response ListLabelsResponse, err error)
+	CreateLabel(ctx context.Context, request LabelRequest) (response LabelInfo, err error)
+	UpdateLabel(ctx context.Context, request LabelRequest) (response LabelInfo, err error)
+	DeleteLabel(ctx context.Context, request DeleteLabelRequest) (err error)
+	LabelChat(ctx context.Context, request ChatLabelRequest) (response ChatLabelResponse, err error)
+	LabelMessage(ctx context.Context, request MessageLabelRequest) (response MessageLabelResponse, err error)
+}
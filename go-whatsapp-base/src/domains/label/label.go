@@ -0,0 +1,58 @@
+package label
+
+// Request and Response structures for WhatsApp Business labels
+
+// MaxColor is the highest label color index the WhatsApp apps display
+const MaxColor = 19
+
+type LabelRequest struct {
+	LabelID string `json:"label_id" uri:"label_id"`
+	Name    string `json:"name"`
+	Color   *int32 `json:"color"`
+}
+
+type DeleteLabelRequest struct {
+	LabelID string `json:"label_id" uri:"label_id"`
+}
+
+type ChatLabelRequest struct {
+	ChatJID   string `json:"chat_jid" uri:"chat_jid"`
+	LabelID   string `json:"label_id"`
+	LabelName string `json:"label_name"`
+	Labeled   bool   `json:"labeled"`
+}
+
+type MessageLabelRequest struct {
+	MessageID string `json:"message_id" uri:"message_id"`
+	Phone     string `json:"phone"`
+	LabelID   string `json:"label_id"`
+	Labeled   bool   `json:"labeled"`
+}
+
+type LabelInfo struct {
+	ID        string `json:"id"`
+	Name      string `json:"name"`
+	Color     int32  `json:"color"`
+	ChatCount int64  `json:"chat_count"`
+	CreatedAt string `json:"created_at"`
+	UpdatedAt string `json:"updated_at"`
+}
+
+type ListLabelsResponse struct {
+	Data []LabelInfo `json:"data"`
+}
+
+type ChatLabelResponse struct {
+	Status  string   `json:"status"`
+	Message string   `json:"message"`
+	ChatJID string   `json:"chat_jid"`
+	LabelID string   `json:"label_id"`
+	Labeled bool     `json:"labeled"`
+	Labels  []string `json:"labels"`
+}
+
+type MessageLabelResponse struct {
+	ChatJID   string   `json:"chat_jid"`
+	MessageID string   `json:"message_id"`
+	Labels    []string `json:"labels"`
+}
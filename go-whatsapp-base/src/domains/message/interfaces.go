@@ -10,6 +10,7 @@ type IMessageActions interface {
 	ReactMessage(ctx context.Context, request ReactionRequest) (response GenericResponse, err error)
 	RevokeMessage(ctx context.Context, request RevokeRequest) (response GenericResponse, err error)
 	UpdateMessage(ctx context.Context, request UpdateMessageRequest) (response GenericResponse, err error)
+	ForwardMessage(ctx context.Context, request ForwardMessageRequest) (response ForwardMessageResponse, err error)
 }
 
 // IMessageManagement handles message management operations
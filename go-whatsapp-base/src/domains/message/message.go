@@ -51,3 +51,22 @@ type DownloadMediaResponse struct {
 	FilePath  string `json:"file_path"`
 	FileSize  int64  `json:"file_size"`
 }
+
+type ForwardMessageRequest struct {
+	MessageID string   `json:"message_id" uri:"message_id"`
+	Phone     string   `json:"phone" form:"phone"`
+	Targets   []string `json:"targets" form:"targets"`
+}
+
+type ForwardMessageResult struct {
+	Target    string `json:"target"`
+	MessageID string `json:"message_id,omitempty"`
+	Status    string `json:"status"`
+	Message   string `json:"message,omitempty"`
+}
+
+type ForwardMessageResponse struct {
+	MessageID string                 `json:"message_id"`
+	Status    string                 `json:"status"`
+	Results   []ForwardMessageResult `json:"results"`
+}
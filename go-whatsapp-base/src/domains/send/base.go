@@ -4,4 +4,12 @@ type BaseRequest struct {
 	Phone       string `json:"phone" form:"phone"`
 	Duration    *int   `json:"duration,omitempty" form:"duration"`
 	IsForwarded bool   `json:"is_forwarded,omitempty" form:"is_forwarded"`
+	// Mentions are phone numbers or JIDs to tag, in addition to @number patterns in the text
+	Mentions []string `json:"mentions,omitempty" form:"mentions"`
+	// MentionAll tags every participant when sending to a group
+	MentionAll bool `json:"mention_all,omitempty" form:"mention_all"`
+	// IdempotencyKey makes a retried request return the first response instead of sending again
+	IdempotencyKey string `json:"idempotency_key,omitempty" form:"idempotency_key"`
+	// QueueTTL is how many seconds a send queued while disconnected waits before it expires
+	QueueTTL *int `json:"queue_ttl,omitempty" form:"queue_ttl"`
 }
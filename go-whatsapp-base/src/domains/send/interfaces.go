@@ -26,16 +26,31 @@ type IInteractionSender interface {
 	SendPoll(ctx context.Context, request PollRequest) (response GenericResponse, err error)
 }
 
+// IProductSender handles messages sharing products from our catalog
+type IProductSender interface {
+	SendProduct(ctx context.Context, request ProductRequest) (response GenericResponse, err error)
+	SendProductList(ctx context.Context, request ProductListRequest) (response GenericResponse, err error)
+}
+
 // IPresenceSender handles presence-related operations
 type IPresenceSender interface {
 	SendPresence(ctx context.Context, request PresenceRequest) (response GenericResponse, err error)
 	SendChatPresence(ctx context.Context, request ChatPresenceRequest) (response GenericResponse, err error)
 }
 
+// ISendQueue handles sends queued while disconnected
+type ISendQueue interface {
+	GetSendQueue(ctx context.Context, request QueueListRequest) (response QueueListResponse, err error)
+	CancelQueuedSend(ctx context.Context, id int64) (response QueueItem, err error)
+	FlushSendQueue(ctx context.Context) (response QueueFlushResponse, err error)
+}
+
 // ISendUsecase combines all sender interfaces for backward compatibility
 type ISendUsecase interface {
 	ITextSender
 	IMediaSender
 	IInteractionSender
+	IProductSender
 	IPresenceSender
+	ISendQueue
 }
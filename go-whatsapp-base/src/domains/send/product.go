@@ -0,0 +1,25 @@
+package send
+
+type ProductRequest struct {
+	BaseRequest
+	// ProductID is the id of a product in our own catalog
+	ProductID string `json:"product_id" form:"product_id"`
+	Body      string `json:"body" form:"body"`
+	Footer    string `json:"footer" form:"footer"`
+}
+
+type ProductListSection struct {
+	Title      string   `json:"title"`
+	ProductIDs []string `json:"product_ids"`
+}
+
+type ProductListRequest struct {
+	BaseRequest
+	Title       string `json:"title"`
+	Description string `json:"description"`
+	ButtonText  string `json:"button_text"`
+	Footer      string `json:"footer"`
+	// HeaderProductID picks the product whose image is shown above the list, the first listed product by default
+	HeaderProductID string               `json:"header_product_id"`
+	Sections        []ProductListSection `json:"sections"`
+}
@@ -0,0 +1,38 @@
+package send
+
+// Send queue operations
+type QueueListRequest struct {
+	Status string `json:"status" query:"status"`
+	Limit  int    `json:"limit" query:"limit"`
+	Offset int    `json:"offset" query:"offset"`
+}
+
+type QueueItem struct {
+	ID        int64  `json:"id"`
+	MessageID string `json:"message_id"`
+	Recipient string `json:"recipient"`
+	Content   string `json:"content"`
+	Status    string `json:"status"`
+	Error     string `json:"error,omitempty"`
+	ExpiresAt string `json:"expires_at"`
+	SentAt    string `json:"sent_at,omitempty"`
+	CreatedAt string `json:"created_at"`
+}
+
+type QueueListResponse struct {
+	Data       []QueueItem        `json:"data"`
+	Pagination PaginationResponse `json:"pagination"`
+}
+
+type PaginationResponse struct {
+	Limit  int `json:"limit"`
+	Offset int `json:"offset"`
+	Total  int `json:"total"`
+}
+
+type QueueFlushResponse struct {
+	Sent      int `json:"sent"`
+	Failed    int `json:"failed"`
+	Expired   int `json:"expired"`
+	Remaining int `json:"remaining"`
+}
@@ -4,4 +4,9 @@ type MessageRequest struct {
 	BaseRequest
 	Message        string  `json:"message" form:"message"`
 	ReplyMessageID *string `json:"reply_message_id" form:"reply_message_id"`
+
+	// TemplateID renders a stored message template instead of Message
+	TemplateID string            `json:"template_id,omitempty" form:"template_id"`
+	Variables  map[string]string `json:"variables,omitempty"`
+	Language   string            `json:"language,omitempty" form:"language"`
 }
@@ -0,0 +1,15 @@
+package template
+
+import (
+	"context"
+)
+
+// ITemplateUsecase defines the interface for message template management
+type ITemplateUsecase interface {
+	ListTemplates(ctx context.Context) (response ListTemplatesResponse, err error)
+	GetTemplate(ctx context.Context, request GetTemplateRequest) (response TemplateInfo, err error)
+	CreateTemplate(ctx context.Context, request TemplateRequest) (response TemplateInfo, err error)
+	UpdateTemplate(ctx context.Context, request TemplateRequest) (response TemplateInfo, err error)
+	DeleteTemplate(ctx context.Context, request DeleteTemplateRequest) (err error)
+	RenderTemplate(ctx context.Context, request RenderTemplateRequest) (response RenderTemplateResponse, err error)
+}
@@ -0,0 +1,57 @@
+package template
+
+// Request and Response structures for message template management
+
+type Variable struct {
+	Name     string `json:"name"`
+	Type     string `json:"type"`
+	Required bool   `json:"required"`
+	Default  string `json:"default"`
+}
+
+type Variant struct {
+	Language string `json:"language"`
+	Body     string `json:"body"`
+}
+
+type TemplateRequest struct {
+	TemplateID      string     `json:"template_id" uri:"template_id"`
+	Description     string     `json:"description"`
+	DefaultLanguage string     `json:"default_language"`
+	Variables       []Variable `json:"variables"`
+	Variants        []Variant  `json:"variants"`
+}
+
+type GetTemplateRequest struct {
+	TemplateID string `json:"template_id" uri:"template_id"`
+}
+
+type DeleteTemplateRequest struct {
+	TemplateID string `json:"template_id" uri:"template_id"`
+}
+
+type RenderTemplateRequest struct {
+	TemplateID string            `json:"template_id" uri:"template_id"`
+	Language   string            `json:"language"`
+	Variables  map[string]string `json:"variables"`
+}
+
+type TemplateInfo struct {
+	ID              string     `json:"id"`
+	Description     string     `json:"description"`
+	DefaultLanguage string     `json:"default_language"`
+	Variables       []Variable `json:"variables"`
+	Variants        []Variant  `json:"variants"`
+	CreatedAt       string     `json:"created_at"`
+	UpdatedAt       string     `json:"updated_at"`
+}
+
+type ListTemplatesResponse struct {
+	Data []TemplateInfo `json:"data"`
+}
+
+type RenderTemplateResponse struct {
+	TemplateID string `json:"template_id"`
+	Language   string `json:"language"`
+	Message    string `json:"message"`
+}
@@ -0,0 +1,45 @@
+package user
+
+type BusinessCatalogRequest struct {
+	Phone  string `json:"phone" query:"phone"`
+	Limit  int    `json:"limit" query:"limit"`
+	Cursor string `json:"cursor" query:"cursor"`
+}
+
+type BusinessProduct struct {
+	ID              string   `json:"id"`
+	RetailerID      string   `json:"retailer_id,omitempty"`
+	Name            string   `json:"name"`
+	Description     string   `json:"description,omitempty"`
+	URL             string   `json:"url,omitempty"`
+	Currency        string   `json:"currency,omitempty"`
+	PriceAmount1000 int64    `json:"price_amount_1000"`
+	Price           float64  `json:"price"`
+	ImageURLs       []string `json:"image_urls,omitempty"`
+	Hidden          bool     `json:"hidden"`
+	ReviewStatus    string   `json:"review_status,omitempty"`
+}
+
+type BusinessCatalogResponse struct {
+	JID      string            `json:"jid"`
+	Products []BusinessProduct `json:"products"`
+	// Cursor fetches the next page when passed back, empty on the last page
+	Cursor string `json:"cursor,omitempty"`
+}
+
+type BusinessCollectionsRequest struct {
+	Phone string `json:"phone" query:"phone"`
+	Limit int    `json:"limit" query:"limit"`
+}
+
+type BusinessCollection struct {
+	ID           string            `json:"id"`
+	Name         string            `json:"name"`
+	ReviewStatus string            `json:"review_status,omitempty"`
+	Products     []BusinessProduct `json:"products"`
+}
+
+type BusinessCollectionsResponse struct {
+	JID         string               `json:"jid"`
+	Collections []BusinessCollection `json:"collections"`
+}
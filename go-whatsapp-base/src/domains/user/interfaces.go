@@ -9,6 +9,8 @@ type IUserInfo interface {
 	Info(ctx context.Context, request InfoRequest) (response InfoResponse, err error)
 	IsOnWhatsApp(ctx context.Context, request CheckRequest) (response CheckResponse, err error)
 	BusinessProfile(ctx context.Context, request BusinessProfileRequest) (response BusinessProfileResponse, err error)
+	BusinessCatalog(ctx context.Context, request BusinessCatalogRequest) (response BusinessCatalogResponse, err error)
+	BusinessCollections(ctx context.Context, request BusinessCollectionsRequest) (response BusinessCollectionsResponse, err error)
 }
 
 // IUserProfile handles user profile operations
@@ -0,0 +1,241 @@
+package chatstorage
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	domainChatStorage "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/chatstorage"
+)
+
+const autoReplyRuleColumns = `id, name, enabled, priority, keywords, pattern, senders, chat_type,
+	response_type, response_text, media_url, cooldown_seconds, created_at, updated_at`
+
+// StoreAutoReplyRule creates a rule when its ID is zero, otherwise updates it
+func (r *SQLiteRepository) StoreAutoReplyRule(rule *domainChatStorage.AutoReplyRule) error {
+	keywords, err := json.Marshal(rule.Keywords)
+	if err != nil {
+		return fmt.Errorf("failed to encode keywords: %w", err)
+	}
+	senders, err := json.Marshal(rule.Senders)
+	if err != nil {
+		return fmt.Errorf("failed to encode senders: %w", err)
+	}
+
+	now := time.Now()
+	rule.UpdatedAt = now
+
+	if rule.ID == 0 {
+		rule.CreatedAt = now
+		result, err := r.db.Exec(`
+			INSERT INTO auto_reply_rules (
+				name, enabled, priority, keywords, pattern, senders, chat_type,
+				response_type, response_text, media_url, cooldown_seconds, created_at, updated_at
+			) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+		`, rule.Name, rule.Enabled, rule.Priority, string(keywords), rule.Pattern, string(senders), rule.ChatType,
+			rule.ResponseType, rule.ResponseText, rule.MediaURL, rule.CooldownSeconds, rule.CreatedAt, rule.UpdatedAt)
+		if err != nil {
+			return err
+		}
+
+		rule.ID, err = result.LastInsertId()
+		return err
+	}
+
+	result, err := r.db.Exec(`
+		UPDATE auto_reply_rules SET
+			name = ?, enabled = ?, priority = ?, keywords = ?, pattern = ?, senders = ?, chat_type = ?,
+			response_type = ?, response_text = ?, media_url = ?, cooldown_seconds = ?, updated_at = ?
+		WHERE id = ?
+	`, rule.Name, rule.Enabled, rule.Priority, string(keywords), rule.Pattern, string(senders), rule.ChatType,
+		rule.ResponseType, rule.ResponseText, rule.MediaURL, rule.CooldownSeconds, rule.UpdatedAt, rule.ID)
+	if err != nil {
+		return err
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return fmt.Errorf("auto-reply rule %d not found", rule.ID)
+	}
+
+	return nil
+}
+
+// GetAutoReplyRule retrieves a rule by ID
+func (r *SQLiteRepository) GetAutoReplyRule(id int64) (*domainChatStorage.AutoReplyRule, error) {
+	query := `SELECT ` + autoReplyRuleColumns + ` FROM auto_reply_rules WHERE id = ?`
+
+	rule, err := r.scanAutoReplyRule(r.db.QueryRow(query, id))
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+
+	return rule, err
+}
+
+// GetAutoReplyRules retrieves rules in evaluation order
+func (r *SQLiteRepository) GetAutoReplyRules(enabledOnly bool) ([]*domainChatStorage.AutoReplyRule, error) {
+	query := `SELECT ` + autoReplyRuleColumns + ` FROM auto_reply_rules`
+	if enabledOnly {
+		query += " WHERE enabled = TRUE"
+	}
+	query += " ORDER BY priority ASC, id ASC"
+
+	rows, err := r.db.Query(query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var rules []*domainChatStorage.AutoReplyRule
+	for rows.Next() {
+		rule, err := r.scanAutoReplyRule(rows)
+		if err != nil {
+			return nil, err
+		}
+		rules = append(rules, rule)
+	}
+
+	return rules, rows.Err()
+}
+
+// DeleteAutoReplyRule deletes a rule. Its hits are kept for auditing.
+func (r *SQLiteRepository) DeleteAutoReplyRule(id int64) error {
+	_, err := r.db.Exec("DELETE FROM auto_reply_rules WHERE id = ?", id)
+	return err
+}
+
+// StoreAutoReplyHit appends an entry to the auto-reply hit log
+func (r *SQLiteRepository) StoreAutoReplyHit(hit *domainChatStorage.AutoReplyHit) error {
+	if hit.CreatedAt.IsZero() {
+		hit.CreatedAt = time.Now()
+	}
+
+	result, err := r.db.Exec(`
+		INSERT INTO auto_reply_hits (rule_id, chat_jid, sender_jid, message_id, reply_message_id, status, error, created_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?)
+	`, hit.RuleID, hit.ChatJID, hit.SenderJID, hit.MessageID, hit.ReplyMessageID, hit.Status, hit.Error, hit.CreatedAt)
+	if err != nil {
+		return err
+	}
+
+	hit.ID, err = result.LastInsertId()
+	return err
+}
+
+// GetAutoReplyHits retrieves logged rule hits, newest first
+func (r *SQLiteRepository) GetAutoReplyHits(filter *domainChatStorage.AutoReplyHitFilter) ([]*domainChatStorage.AutoReplyHit, error) {
+	var conditions []string
+	var args []any
+
+	if filter.RuleID > 0 {
+		conditions = append(conditions, "rule_id = ?")
+		args = append(args, filter.RuleID)
+	}
+
+	if filter.SenderJID != "" {
+		conditions = append(conditions, "sender_jid = ?")
+		args = append(args, filter.SenderJID)
+	}
+
+	query := `
+		SELECT id, rule_id, chat_jid, sender_jid, message_id, reply_message_id, status, error, created_at
+		FROM auto_reply_hits
+	`
+
+	if len(conditions) > 0 {
+		query += " WHERE " + strings.Join(conditions, " AND ")
+	}
+
+	query += " ORDER BY created_at DESC, id DESC"
+
+	if filter.Limit > 0 {
+		if filter.Limit > 1000 {
+			filter.Limit = 1000
+		}
+		query += " LIMIT ?"
+		args = append(args, filter.Limit)
+
+		if filter.Offset > 0 {
+			query += " OFFSET ?"
+			args = append(args, filter.Offset)
+		}
+	}
+
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var hits []*domainChatStorage.AutoReplyHit
+	for rows.Next() {
+		hit := &domainChatStorage.AutoReplyHit{}
+		var replyMessageID, errorText sql.NullString
+		if err := rows.Scan(
+			&hit.ID, &hit.RuleID, &hit.ChatJID, &hit.SenderJID, &hit.MessageID,
+			&replyMessageID, &hit.Status, &errorText, &hit.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		hit.ReplyMessageID = replyMessageID.String
+		hit.Error = errorText.String
+		hits = append(hits, hit)
+	}
+
+	return hits, rows.Err()
+}
+
+// GetLastAutoReplyTime returns when a rule last replied to a sender, or the zero time
+func (r *SQLiteRepository) GetLastAutoReplyTime(ruleID int64, senderJID string) (time.Time, error) {
+	var lastReply sql.NullTime
+	err := r.db.QueryRow(`
+		SELECT created_at FROM auto_reply_hits
+		WHERE rule_id = ? AND sender_jid = ? AND status = ?
+		ORDER BY created_at DESC
+		LIMIT 1
+	`, ruleID, senderJID, domainChatStorage.AutoReplyStatusSent).Scan(&lastReply)
+	if err == sql.ErrNoRows {
+		return time.Time{}, nil
+	}
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	return lastReply.Time, nil
+}
+
+// scanAutoReplyRule is a private helper for scanning auto-reply rule rows
+func (r *SQLiteRepository) scanAutoReplyRule(scanner interface{ Scan(...any) error }) (*domainChatStorage.AutoReplyRule, error) {
+	rule := &domainChatStorage.AutoReplyRule{}
+	var keywords, pattern, senders, responseText, mediaURL sql.NullString
+	err := scanner.Scan(
+		&rule.ID, &rule.Name, &rule.Enabled, &rule.Priority, &keywords, &pattern, &senders, &rule.ChatType,
+		&rule.ResponseType, &responseText, &mediaURL, &rule.CooldownSeconds, &rule.CreatedAt, &rule.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	rule.Pattern = pattern.String
+	rule.ResponseText = responseText.String
+	rule.MediaURL = mediaURL.String
+
+	if keywords.String != "" {
+		if err := json.Unmarshal([]byte(keywords.String), &rule.Keywords); err != nil {
+			return nil, fmt.Errorf("failed to decode keywords of rule %d: %w", rule.ID, err)
+		}
+	}
+	if senders.String != "" {
+		if err := json.Unmarshal([]byte(senders.String), &rule.Senders); err != nil {
+			return nil, fmt.Errorf("failed to decode senders of rule %d: %w", rule.ID, err)
+		}
+	}
+
+	return rule, nil
+}
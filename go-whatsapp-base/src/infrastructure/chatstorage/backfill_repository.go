@@ -0,0 +1,73 @@
+package chatstorage
+
+import (
+	"database/sql"
+
+	domainChatStorage "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/chatstorage"
+)
+
+const chatBackfillColumns = `chat_jid, status, requested_count, anchor_message_id, anchor_timestamp,
+	received_messages, stored_messages, total_stored, requests, requested_at, completed_at`
+
+// GetOldestMessage retrieves the oldest stored message of a chat
+func (r *SQLiteRepository) GetOldestMessage(chatJID string) (*domainChatStorage.Message, error) {
+	query := `
+		SELECT id, chat_jid, sender, content, timestamp, is_from_me,
+			media_type, filename, url, media_key, file_sha256,
+			file_enc_sha256, file_length, view_once, interactive_type,
+			selected_id, selected_title, original_message_id, mimetype, forwarding_score,
+			created_at, updated_at
+		FROM messages
+		WHERE chat_jid = ?
+		ORDER BY timestamp ASC
+		LIMIT 1
+	`
+
+	message, err := r.scanMessage(r.db.QueryRow(query, chatJID))
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+
+	return message, err
+}
+
+// StoreChatBackfill creates or replaces the backfill progress of a chat
+func (r *SQLiteRepository) StoreChatBackfill(backfill *domainChatStorage.ChatBackfill) error {
+	_, err := r.db.Exec(`
+		INSERT INTO chat_backfills (`+chatBackfillColumns+`)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+		ON CONFLICT(chat_jid) DO UPDATE SET
+			status = excluded.status,
+			requested_count = excluded.requested_count,
+			anchor_message_id = excluded.anchor_message_id,
+			anchor_timestamp = excluded.anchor_timestamp,
+			received_messages = excluded.received_messages,
+			stored_messages = excluded.stored_messages,
+			total_stored = excluded.total_stored,
+			requests = excluded.requests,
+			requested_at = excluded.requested_at,
+			completed_at = excluded.completed_at
+	`, backfill.ChatJID, backfill.Status, backfill.RequestedCount, backfill.AnchorMessageID, backfill.AnchorTimestamp,
+		backfill.ReceivedMessages, backfill.StoredMessages, backfill.TotalStored, backfill.Requests,
+		backfill.RequestedAt, backfill.CompletedAt)
+
+	return err
+}
+
+// GetChatBackfill retrieves the backfill progress of a chat
+func (r *SQLiteRepository) GetChatBackfill(chatJID string) (*domainChatStorage.ChatBackfill, error) {
+	backfill := &domainChatStorage.ChatBackfill{}
+	err := r.db.QueryRow(`SELECT `+chatBackfillColumns+` FROM chat_backfills WHERE chat_jid = ?`, chatJID).Scan(
+		&backfill.ChatJID, &backfill.Status, &backfill.RequestedCount, &backfill.AnchorMessageID, &backfill.AnchorTimestamp,
+		&backfill.ReceivedMessages, &backfill.StoredMessages, &backfill.TotalStored, &backfill.Requests,
+		&backfill.RequestedAt, &backfill.CompletedAt,
+	)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return backfill, nil
+}
@@ -0,0 +1,186 @@
+package chatstorage
+
+import (
+	"database/sql"
+	"strings"
+	"time"
+
+	domainChatStorage "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/chatstorage"
+)
+
+const contactColumns = `jid, phone_jid, lid_jid, saved_name, push_name, business_name,
+	first_seen, last_seen, created_at, updated_at`
+
+// StoreContact merges a contact into the store. Rows known under its phone
+// number JID or its LID are folded into one row keyed by the phone number JID
+// when it is known. Non-empty fields of contact win over stored values and the
+// seen times are widened. contact is updated with the merged result.
+func (r *SQLiteRepository) StoreContact(contact *domainChatStorage.Contact) error {
+	var keys []any
+	for _, key := range []string{contact.JID, contact.PhoneJID, contact.LIDJID} {
+		if key != "" {
+			keys = append(keys, key)
+		}
+	}
+	if len(keys) == 0 {
+		return nil
+	}
+
+	tx, err := r.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	placeholders := strings.TrimSuffix(strings.Repeat("?,", len(keys)), ",")
+	args := append(append(append([]any{}, keys...), keys...), keys...)
+	rows, err := tx.Query(`SELECT `+contactColumns+` FROM contacts
+		WHERE jid IN (`+placeholders+`) OR phone_jid IN (`+placeholders+`) OR lid_jid IN (`+placeholders+`)`, args...)
+	if err != nil {
+		return err
+	}
+
+	merged := *contact
+	var existingJIDs []any
+	for rows.Next() {
+		existing, err := r.scanContact(rows)
+		if err != nil {
+			rows.Close()
+			return err
+		}
+		mergeContact(&merged, existing)
+		existingJIDs = append(existingJIDs, existing.JID)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	switch {
+	case merged.PhoneJID != "":
+		merged.JID = merged.PhoneJID
+	case merged.LIDJID != "":
+		merged.JID = merged.LIDJID
+	}
+
+	now := time.Now()
+	if merged.CreatedAt.IsZero() {
+		merged.CreatedAt = now
+	}
+	merged.UpdatedAt = now
+
+	if len(existingJIDs) > 0 {
+		placeholders := strings.TrimSuffix(strings.Repeat("?,", len(existingJIDs)), ",")
+		if _, err := tx.Exec(`DELETE FROM contacts WHERE jid IN (`+placeholders+`)`, existingJIDs...); err != nil {
+			return err
+		}
+	}
+
+	_, err = tx.Exec(`INSERT INTO contacts (`+contactColumns+`) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		merged.JID, merged.PhoneJID, merged.LIDJID, merged.SavedName, merged.PushName, merged.BusinessName,
+		merged.FirstSeen, merged.LastSeen, merged.CreatedAt, merged.UpdatedAt)
+	if err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+
+	*contact = merged
+	return nil
+}
+
+// mergeContact fills the empty fields of target from existing and widens the seen times
+func mergeContact(target, existing *domainChatStorage.Contact) {
+	fill := func(value *string, fallback string) {
+		if *value == "" {
+			*value = fallback
+		}
+	}
+	fill(&target.PhoneJID, existing.PhoneJID)
+	fill(&target.LIDJID, existing.LIDJID)
+	fill(&target.SavedName, existing.SavedName)
+	fill(&target.PushName, existing.PushName)
+	fill(&target.BusinessName, existing.BusinessName)
+
+	if existing.FirstSeen != nil && (target.FirstSeen == nil || existing.FirstSeen.Before(*target.FirstSeen)) {
+		target.FirstSeen = existing.FirstSeen
+	}
+	if existing.LastSeen != nil && (target.LastSeen == nil || existing.LastSeen.After(*target.LastSeen)) {
+		target.LastSeen = existing.LastSeen
+	}
+	if target.CreatedAt.IsZero() || (!existing.CreatedAt.IsZero() && existing.CreatedAt.Before(target.CreatedAt)) {
+		target.CreatedAt = existing.CreatedAt
+	}
+}
+
+// GetContact retrieves a contact by its phone number JID or LID
+func (r *SQLiteRepository) GetContact(jid string) (*domainChatStorage.Contact, error) {
+	query := `SELECT ` + contactColumns + ` FROM contacts
+		WHERE jid = ? OR phone_jid = ? OR lid_jid = ?
+		LIMIT 1`
+
+	contact, err := r.scanContact(r.db.QueryRow(query, jid, jid, jid))
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+
+	return contact, err
+}
+
+// GetContacts retrieves contacts matching the filter ordered by name
+func (r *SQLiteRepository) GetContacts(filter *domainChatStorage.ContactFilter) ([]*domainChatStorage.Contact, error) {
+	where, args := contactFilterConditions(filter)
+	query := `SELECT ` + contactColumns + ` FROM contacts` + where + `
+		ORDER BY COALESCE(NULLIF(saved_name, ''), NULLIF(push_name, ''), NULLIF(business_name, ''), jid) COLLATE NOCASE ASC`
+
+	if filter != nil && filter.Limit > 0 {
+		query += " LIMIT ? OFFSET ?"
+		args = append(args, filter.Limit, filter.Offset)
+	}
+
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var contacts []*domainChatStorage.Contact
+	for rows.Next() {
+		contact, err := r.scanContact(rows)
+		if err != nil {
+			return nil, err
+		}
+		contacts = append(contacts, contact)
+	}
+
+	return contacts, rows.Err()
+}
+
+// GetContactCount returns the number of contacts matching the filter
+func (r *SQLiteRepository) GetContactCount(filter *domainChatStorage.ContactFilter) (int64, error) {
+	where, args := contactFilterConditions(filter)
+	return r.getCount(`SELECT COUNT(*) FROM contacts`+where, args...)
+}
+
+// contactFilterConditions matches the search text against names and addresses
+func contactFilterConditions(filter *domainChatStorage.ContactFilter) (string, []any) {
+	if filter == nil || filter.Search == "" {
+		return "", nil
+	}
+
+	pattern := "%" + filter.Search + "%"
+	return ` WHERE saved_name LIKE ? OR push_name LIKE ? OR business_name LIKE ? OR phone_jid LIKE ? OR lid_jid LIKE ?`,
+		[]any{pattern, pattern, pattern, pattern, pattern}
+}
+
+// scanContact is a private helper for scanning contact rows
+func (r *SQLiteRepository) scanContact(scanner interface{ Scan(...any) error }) (*domainChatStorage.Contact, error) {
+	contact := &domainChatStorage.Contact{}
+	err := scanner.Scan(
+		&contact.JID, &contact.PhoneJID, &contact.LIDJID, &contact.SavedName, &contact.PushName,
+		&contact.BusinessName, &contact.FirstSeen, &contact.LastSeen, &contact.CreatedAt, &contact.UpdatedAt,
+	)
+	return contact, err
+}
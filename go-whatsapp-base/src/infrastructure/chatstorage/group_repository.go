@@ -0,0 +1,348 @@
+package chatstorage
+
+import (
+	"database/sql"
+	"sort"
+	"strings"
+	"time"
+
+	domainChatStorage "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/chatstorage"
+)
+
+const groupMembershipEventColumns = `id, group_jid, participant_jid, action, role,
+	COALESCE(actor_jid, ''), COALESCE(reason, ''), source, timestamp`
+
+// StoreGroup creates or updates the metadata of a group
+func (r *SQLiteRepository) StoreGroup(group *domainChatStorage.Group) error {
+	now := time.Now()
+	group.UpdatedAt = now
+	if group.CreatedAt.IsZero() {
+		group.CreatedAt = now
+	}
+
+	query := `
+		INSERT INTO groups (jid, name, topic, owner_jid, is_announce, is_locked, is_community, group_created_at, created_at, updated_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+		ON CONFLICT(jid) DO UPDATE SET
+			name = excluded.name,
+			topic = excluded.topic,
+			owner_jid = excluded.owner_jid,
+			is_announce = excluded.is_announce,
+			is_locked = excluded.is_locked,
+			is_community = excluded.is_community,
+			group_created_at = COALESCE(excluded.group_created_at, groups.group_created_at),
+			updated_at = excluded.updated_at
+	`
+
+	_, err := r.db.Exec(query, group.JID, group.Name, group.Topic, group.OwnerJID, group.IsAnnounce,
+		group.IsLocked, group.IsCommunity, group.GroupCreatedAt, group.CreatedAt, group.UpdatedAt)
+	return err
+}
+
+// GetGroup retrieves the stored metadata of a group
+func (r *SQLiteRepository) GetGroup(jid string) (*domainChatStorage.Group, error) {
+	query := `
+		SELECT jid, COALESCE(name, ''), COALESCE(topic, ''), COALESCE(owner_jid, ''), is_announce, is_locked,
+			is_community, group_created_at, created_at, updated_at
+		FROM groups
+		WHERE jid = ?
+	`
+
+	group := &domainChatStorage.Group{}
+	err := r.db.QueryRow(query, jid).Scan(
+		&group.JID, &group.Name, &group.Topic, &group.OwnerJID, &group.IsAnnounce, &group.IsLocked,
+		&group.IsCommunity, &group.GroupCreatedAt, &group.CreatedAt, &group.UpdatedAt,
+	)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+
+	return group, err
+}
+
+// StoreGroupMembershipEvents appends events to the membership log and applies
+// them to the current member list in one transaction
+func (r *SQLiteRepository) StoreGroupMembershipEvents(events []*domainChatStorage.GroupMembershipEvent) error {
+	if len(events) == 0 {
+		return nil
+	}
+
+	tx, err := r.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	for _, event := range events {
+		if err := r.applyGroupMembershipEvent(tx, event); err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
+// SyncGroupParticipants compares a fresh member list with the stored one,
+// records every difference in the membership log and returns those entries
+func (r *SQLiteRepository) SyncGroupParticipants(groupJID string, participants []*domainChatStorage.GroupParticipant, at time.Time) ([]*domainChatStorage.GroupMembershipEvent, error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	current, err := r.queryGroupParticipants(tx, groupJID)
+	if err != nil {
+		return nil, err
+	}
+
+	currentRoles := make(map[string]string, len(current))
+	for _, participant := range current {
+		currentRoles[participant.ParticipantJID] = participant.Role
+	}
+
+	var events []*domainChatStorage.GroupMembershipEvent
+	newEvent := func(participantJID, action, role string) {
+		events = append(events, &domainChatStorage.GroupMembershipEvent{
+			GroupJID:       groupJID,
+			ParticipantJID: participantJID,
+			Action:         action,
+			Role:           role,
+			Source:         domainChatStorage.GroupEventSourceSync,
+			Timestamp:      at,
+		})
+	}
+
+	seen := make(map[string]bool, len(participants))
+	for _, participant := range participants {
+		seen[participant.ParticipantJID] = true
+		role, isMember := currentRoles[participant.ParticipantJID]
+		switch {
+		case !isMember:
+			newEvent(participant.ParticipantJID, domainChatStorage.GroupActionJoin, participant.Role)
+		case role == participant.Role:
+		case participant.Role == domainChatStorage.GroupRoleMember:
+			newEvent(participant.ParticipantJID, domainChatStorage.GroupActionDemote, participant.Role)
+		default:
+			newEvent(participant.ParticipantJID, domainChatStorage.GroupActionPromote, participant.Role)
+		}
+	}
+	for _, participant := range current {
+		if !seen[participant.ParticipantJID] {
+			newEvent(participant.ParticipantJID, domainChatStorage.GroupActionLeave, participant.Role)
+		}
+	}
+
+	for _, event := range events {
+		if err := r.applyGroupMembershipEvent(tx, event); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	return events, nil
+}
+
+// applyGroupMembershipEvent appends one log entry and updates the member list
+func (r *SQLiteRepository) applyGroupMembershipEvent(tx *sql.Tx, event *domainChatStorage.GroupMembershipEvent) error {
+	if event.Role == "" {
+		event.Role = domainChatStorage.GroupRoleMember
+		if event.Action == domainChatStorage.GroupActionPromote {
+			event.Role = domainChatStorage.GroupRoleAdmin
+		}
+	}
+
+	// Timestamps are stored in UTC so they compare correctly as text
+	event.Timestamp = event.Timestamp.UTC()
+
+	result, err := tx.Exec(`
+		INSERT INTO group_membership_events (group_jid, participant_jid, action, role, actor_jid, reason, source, timestamp)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?)
+	`, event.GroupJID, event.ParticipantJID, event.Action, event.Role, event.ActorJID, event.Reason, event.Source, event.Timestamp)
+	if err != nil {
+		return err
+	}
+	if event.ID, err = result.LastInsertId(); err != nil {
+		return err
+	}
+
+	if event.Action == domainChatStorage.GroupActionLeave {
+		_, err = tx.Exec(`DELETE FROM group_participants WHERE group_jid = ? AND participant_jid = ?`,
+			event.GroupJID, event.ParticipantJID)
+		return err
+	}
+
+	// A role change for someone not known yet still means they are a member
+	_, err = tx.Exec(`
+		INSERT INTO group_participants (group_jid, participant_jid, role, joined_at, updated_at)
+		VALUES (?, ?, ?, ?, ?)
+		ON CONFLICT(group_jid, participant_jid) DO UPDATE SET
+			role = excluded.role,
+			updated_at = excluded.updated_at
+	`, event.GroupJID, event.ParticipantJID, event.Role, event.Timestamp, time.Now())
+
+	return err
+}
+
+// GetGroupParticipants retrieves the current members of a group
+func (r *SQLiteRepository) GetGroupParticipants(groupJID string) ([]*domainChatStorage.GroupParticipant, error) {
+	return r.queryGroupParticipants(r.db, groupJID)
+}
+
+func (r *SQLiteRepository) queryGroupParticipants(querier interface {
+	Query(query string, args ...any) (*sql.Rows, error)
+}, groupJID string) ([]*domainChatStorage.GroupParticipant, error) {
+	rows, err := querier.Query(`
+		SELECT group_jid, participant_jid, role, joined_at, updated_at
+		FROM group_participants
+		WHERE group_jid = ?
+		ORDER BY joined_at ASC, participant_jid ASC
+	`, groupJID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var participants []*domainChatStorage.GroupParticipant
+	for rows.Next() {
+		participant := &domainChatStorage.GroupParticipant{}
+		if err := rows.Scan(&participant.GroupJID, &participant.ParticipantJID, &participant.Role,
+			&participant.JoinedAt, &participant.UpdatedAt); err != nil {
+			return nil, err
+		}
+		participants = append(participants, participant)
+	}
+
+	return participants, rows.Err()
+}
+
+// GetGroupParticipantsAt rebuilds the member list of a group at a point in
+// time by replaying the membership log up to it
+func (r *SQLiteRepository) GetGroupParticipantsAt(groupJID string, asOf time.Time) ([]*domainChatStorage.GroupParticipant, error) {
+	rows, err := r.db.Query(`SELECT `+groupMembershipEventColumns+`
+		FROM group_membership_events
+		WHERE group_jid = ? AND timestamp <= ?
+		ORDER BY timestamp ASC, id ASC`, groupJID, asOf.UTC())
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	members := make(map[string]*domainChatStorage.GroupParticipant)
+	for rows.Next() {
+		event, err := r.scanGroupMembershipEvent(rows)
+		if err != nil {
+			return nil, err
+		}
+
+		member, isMember := members[event.ParticipantJID]
+		switch {
+		case event.Action == domainChatStorage.GroupActionLeave:
+			delete(members, event.ParticipantJID)
+		case !isMember:
+			members[event.ParticipantJID] = &domainChatStorage.GroupParticipant{
+				GroupJID:       groupJID,
+				ParticipantJID: event.ParticipantJID,
+				Role:           event.Role,
+				JoinedAt:       event.Timestamp,
+				UpdatedAt:      event.Timestamp,
+			}
+		default:
+			member.Role = event.Role
+			member.UpdatedAt = event.Timestamp
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	participants := make([]*domainChatStorage.GroupParticipant, 0, len(members))
+	for _, member := range members {
+		participants = append(participants, member)
+	}
+	sort.Slice(participants, func(i, j int) bool {
+		if !participants[i].JoinedAt.Equal(participants[j].JoinedAt) {
+			return participants[i].JoinedAt.Before(participants[j].JoinedAt)
+		}
+		return participants[i].ParticipantJID < participants[j].ParticipantJID
+	})
+
+	return participants, nil
+}
+
+// GetGroupMembershipEvents retrieves membership log entries, newest first
+func (r *SQLiteRepository) GetGroupMembershipEvents(filter *domainChatStorage.GroupMembershipFilter) ([]*domainChatStorage.GroupMembershipEvent, error) {
+	where, args := groupMembershipFilterConditions(filter)
+	query := `SELECT ` + groupMembershipEventColumns + ` FROM group_membership_events` + where +
+		` ORDER BY timestamp DESC, id DESC`
+
+	if filter.Limit > 0 {
+		query += " LIMIT ? OFFSET ?"
+		args = append(args, filter.Limit, filter.Offset)
+	}
+
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var events []*domainChatStorage.GroupMembershipEvent
+	for rows.Next() {
+		event, err := r.scanGroupMembershipEvent(rows)
+		if err != nil {
+			return nil, err
+		}
+		events = append(events, event)
+	}
+
+	return events, rows.Err()
+}
+
+// GetGroupMembershipEventCount returns the number of log entries matching the filter
+func (r *SQLiteRepository) GetGroupMembershipEventCount(filter *domainChatStorage.GroupMembershipFilter) (int64, error) {
+	where, args := groupMembershipFilterConditions(filter)
+	return r.getCount(`SELECT COUNT(*) FROM group_membership_events`+where, args...)
+}
+
+func groupMembershipFilterConditions(filter *domainChatStorage.GroupMembershipFilter) (string, []any) {
+	var conditions []string
+	var args []any
+
+	if filter.GroupJID != "" {
+		conditions = append(conditions, "group_jid = ?")
+		args = append(args, filter.GroupJID)
+	}
+	if filter.ParticipantJID != "" {
+		conditions = append(conditions, "participant_jid = ?")
+		args = append(args, filter.ParticipantJID)
+	}
+	if filter.Action != "" {
+		conditions = append(conditions, "action = ?")
+		args = append(args, filter.Action)
+	}
+	if filter.Since != nil {
+		conditions = append(conditions, "timestamp >= ?")
+		args = append(args, filter.Since.UTC())
+	}
+	if filter.Until != nil {
+		conditions = append(conditions, "timestamp <= ?")
+		args = append(args, filter.Until.UTC())
+	}
+
+	if len(conditions) == 0 {
+		return "", nil
+	}
+	return " WHERE " + strings.Join(conditions, " AND "), args
+}
+
+// scanGroupMembershipEvent is a private helper for scanning membership log rows
+func (r *SQLiteRepository) scanGroupMembershipEvent(scanner interface{ Scan(...any) error }) (*domainChatStorage.GroupMembershipEvent, error) {
+	event := &domainChatStorage.GroupMembershipEvent{}
+	err := scanner.Scan(&event.ID, &event.GroupJID, &event.ParticipantJID, &event.Action, &event.Role,
+		&event.ActorJID, &event.Reason, &event.Source, &event.Timestamp)
+	return event, err
+}
@@ -0,0 +1,58 @@
+package chatstorage
+
+import (
+	"database/sql"
+	"time"
+
+	domainChatStorage "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/chatstorage"
+)
+
+// GetIdempotencyRecord retrieves the record for a key if it was stored at or after since
+func (r *SQLiteRepository) GetIdempotencyRecord(key string, since time.Time) (*domainChatStorage.IdempotencyRecord, error) {
+	query := `
+		SELECT key, operation, recipient, message_id, COALESCE(status, ''), created_at
+		FROM idempotency_keys
+		WHERE key = ? AND created_at >= ?
+	`
+
+	record := &domainChatStorage.IdempotencyRecord{}
+	err := r.db.QueryRow(query, key, since.UTC()).Scan(
+		&record.Key, &record.Operation, &record.Recipient, &record.MessageID, &record.Status, &record.CreatedAt,
+	)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+
+	return record, err
+}
+
+// StoreIdempotencyRecord stores the response of a send, replacing an expired record for the same key
+func (r *SQLiteRepository) StoreIdempotencyRecord(record *domainChatStorage.IdempotencyRecord) error {
+	if record.CreatedAt.IsZero() {
+		record.CreatedAt = time.Now()
+	}
+
+	query := `
+		INSERT INTO idempotency_keys (key, operation, recipient, message_id, status, created_at)
+		VALUES (?, ?, ?, ?, ?, ?)
+		ON CONFLICT(key) DO UPDATE SET
+			operation = excluded.operation,
+			recipient = excluded.recipient,
+			message_id = excluded.message_id,
+			status = excluded.status,
+			created_at = excluded.created_at
+	`
+
+	_, err := r.db.Exec(query, record.Key, record.Operation, record.Recipient, record.MessageID,
+		record.Status, record.CreatedAt.UTC())
+	return err
+}
+
+// DeleteIdempotencyRecordsBefore removes records stored before cutoff
+func (r *SQLiteRepository) DeleteIdempotencyRecordsBefore(cutoff time.Time) (int64, error) {
+	result, err := r.db.Exec(`DELETE FROM idempotency_keys WHERE created_at < ?`, cutoff.UTC())
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
@@ -0,0 +1,181 @@
+package chatstorage
+
+import (
+	"database/sql"
+	"strings"
+	"time"
+
+	domainChatStorage "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/chatstorage"
+)
+
+const labelColumns = `l.id, l.name, l.color, l.deleted,
+	(SELECT COUNT(*) FROM chat_labels cl WHERE cl.label_id = l.id), l.created_at, l.updated_at`
+
+// StoreLabel creates or updates a label. Deleting a label also removes its
+// chat and message assignments.
+func (r *SQLiteRepository) StoreLabel(label *domainChatStorage.Label) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	now := time.Now().UTC()
+	if label.CreatedAt.IsZero() {
+		label.CreatedAt = now
+	}
+	label.UpdatedAt = now
+
+	_, err = tx.Exec(`
+		INSERT INTO labels (id, name, color, deleted, created_at, updated_at)
+		VALUES (?, ?, ?, ?, ?, ?)
+		ON CONFLICT(id) DO UPDATE SET
+			name = excluded.name,
+			color = excluded.color,
+			deleted = excluded.deleted,
+			updated_at = excluded.updated_at
+	`, label.ID, label.Name, label.Color, label.Deleted, label.CreatedAt, label.UpdatedAt)
+	if err != nil {
+		return err
+	}
+
+	if label.Deleted {
+		for _, table := range []string{"chat_labels", "message_labels"} {
+			if _, err := tx.Exec("DELETE FROM "+table+" WHERE label_id = ?", label.ID); err != nil {
+				return err
+			}
+		}
+		label.ChatCount = 0
+	}
+
+	return tx.Commit()
+}
+
+// GetLabel retrieves a label by ID, including deleted labels
+func (r *SQLiteRepository) GetLabel(id string) (*domainChatStorage.Label, error) {
+	label, err := r.scanLabel(r.db.QueryRow(`SELECT `+labelColumns+` FROM labels l WHERE l.id = ?`, id))
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return label, nil
+}
+
+// GetLabels retrieves labels ordered by ID
+func (r *SQLiteRepository) GetLabels(includeDeleted bool) ([]*domainChatStorage.Label, error) {
+	query := `SELECT ` + labelColumns + ` FROM labels l`
+	if !includeDeleted {
+		query += ` WHERE l.deleted = FALSE`
+	}
+	query += ` ORDER BY CAST(l.id AS INTEGER), l.id`
+
+	rows, err := r.db.Query(query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var labels []*domainChatStorage.Label
+	for rows.Next() {
+		label, err := r.scanLabel(rows)
+		if err != nil {
+			return nil, err
+		}
+		labels = append(labels, label)
+	}
+
+	return labels, rows.Err()
+}
+
+// SetChatLabel assigns a label to a chat or removes it
+func (r *SQLiteRepository) SetChatLabel(labelID, chatJID string, labeled bool) error {
+	if !labeled {
+		_, err := r.db.Exec(`DELETE FROM chat_labels WHERE label_id = ? AND chat_jid = ?`, labelID, chatJID)
+		return err
+	}
+
+	_, err := r.db.Exec(`INSERT OR IGNORE INTO chat_labels (label_id, chat_jid, created_at) VALUES (?, ?, ?)`,
+		labelID, chatJID, time.Now().UTC())
+	return err
+}
+
+// GetChatLabels returns the label IDs assigned to each of the chats
+func (r *SQLiteRepository) GetChatLabels(chatJIDs []string) (map[string][]string, error) {
+	labels := make(map[string][]string)
+	if len(chatJIDs) == 0 {
+		return labels, nil
+	}
+
+	args := make([]any, len(chatJIDs))
+	for i, jid := range chatJIDs {
+		args[i] = jid
+	}
+	placeholders := strings.TrimSuffix(strings.Repeat("?,", len(chatJIDs)), ",")
+
+	rows, err := r.db.Query(`SELECT chat_jid, label_id FROM chat_labels
+		WHERE chat_jid IN (`+placeholders+`)
+		ORDER BY CAST(label_id AS INTEGER), label_id`, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var chatJID, labelID string
+		if err := rows.Scan(&chatJID, &labelID); err != nil {
+			return nil, err
+		}
+		labels[chatJID] = append(labels[chatJID], labelID)
+	}
+
+	return labels, rows.Err()
+}
+
+// SetMessageLabel assigns a label to a message or removes it
+func (r *SQLiteRepository) SetMessageLabel(labelID, chatJID, messageID string, labeled bool) error {
+	if !labeled {
+		_, err := r.db.Exec(`DELETE FROM message_labels WHERE label_id = ? AND chat_jid = ? AND message_id = ?`,
+			labelID, chatJID, messageID)
+		return err
+	}
+
+	_, err := r.db.Exec(`INSERT OR IGNORE INTO message_labels (label_id, chat_jid, message_id, created_at) VALUES (?, ?, ?, ?)`,
+		labelID, chatJID, messageID, time.Now().UTC())
+	return err
+}
+
+// GetMessageLabels returns the label IDs assigned to a message
+func (r *SQLiteRepository) GetMessageLabels(chatJID, messageID string) ([]string, error) {
+	rows, err := r.db.Query(`SELECT label_id FROM message_labels
+		WHERE chat_jid = ? AND message_id = ?
+		ORDER BY CAST(label_id AS INTEGER), label_id`, chatJID, messageID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var labels []string
+	for rows.Next() {
+		var labelID string
+		if err := rows.Scan(&labelID); err != nil {
+			return nil, err
+		}
+		labels = append(labels, labelID)
+	}
+
+	return labels, rows.Err()
+}
+
+func (r *SQLiteRepository) scanLabel(scanner interface{ Scan(...any) error }) (*domainChatStorage.Label, error) {
+	label := &domainChatStorage.Label{}
+	err := scanner.Scan(&label.ID, &label.Name, &label.Color, &label.Deleted, &label.ChatCount,
+		&label.CreatedAt, &label.UpdatedAt)
+	if err != nil {
+		return nil, err
+	}
+
+	return label, nil
+}
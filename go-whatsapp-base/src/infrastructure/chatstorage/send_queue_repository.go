@@ -0,0 +1,119 @@
+package chatstorage
+
+import (
+	"database/sql"
+	"time"
+
+	domainChatStorage "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/chatstorage"
+)
+
+const queuedMessageColumns = `id, message_id, recipient, COALESCE(content, ''), payload, status,
+	COALESCE(error, ''), expires_at, sent_at, created_at, updated_at`
+
+// EnqueueMessage stores a send to deliver after reconnect and sets its ID
+func (r *SQLiteRepository) EnqueueMessage(message *domainChatStorage.QueuedMessage) error {
+	now := time.Now().UTC()
+	if message.Status == "" {
+		message.Status = domainChatStorage.QueueStatusQueued
+	}
+	message.CreatedAt = now
+	message.UpdatedAt = now
+
+	result, err := r.db.Exec(`
+		INSERT INTO send_queue (message_id, recipient, content, payload, status, error, expires_at, created_at, updated_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)
+	`, message.MessageID, message.Recipient, message.Content, message.Payload, message.Status, message.Error,
+		message.ExpiresAt.UTC(), message.CreatedAt, message.UpdatedAt)
+	if err != nil {
+		return err
+	}
+
+	message.ID, err = result.LastInsertId()
+	return err
+}
+
+// GetQueuedMessage retrieves a queued send by ID
+func (r *SQLiteRepository) GetQueuedMessage(id int64) (*domainChatStorage.QueuedMessage, error) {
+	message, err := r.scanQueuedMessage(r.db.QueryRow(`SELECT `+queuedMessageColumns+` FROM send_queue WHERE id = ?`, id))
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return message, nil
+}
+
+// GetQueuedMessages retrieves queued sends in the order they were made
+func (r *SQLiteRepository) GetQueuedMessages(filter *domainChatStorage.QueuedMessageFilter) ([]*domainChatStorage.QueuedMessage, error) {
+	where, args := queuedMessageFilterConditions(filter)
+	query := `SELECT ` + queuedMessageColumns + ` FROM send_queue` + where + ` ORDER BY id ASC`
+
+	if filter != nil && filter.Limit > 0 {
+		query += " LIMIT ? OFFSET ?"
+		args = append(args, filter.Limit, filter.Offset)
+	}
+
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var messages []*domainChatStorage.QueuedMessage
+	for rows.Next() {
+		message, err := r.scanQueuedMessage(rows)
+		if err != nil {
+			return nil, err
+		}
+		messages = append(messages, message)
+	}
+
+	return messages, rows.Err()
+}
+
+// GetQueuedMessageCount returns the number of queued sends matching the filter
+func (r *SQLiteRepository) GetQueuedMessageCount(filter *domainChatStorage.QueuedMessageFilter) (int64, error) {
+	where, args := queuedMessageFilterConditions(filter)
+	return r.getCount(`SELECT COUNT(*) FROM send_queue`+where, args...)
+}
+
+// UpdateQueuedMessageStatus moves a queued send from fromStatus to status. It
+// reports false when the send was no longer in fromStatus, so a send cancelled
+// during a flush is never delivered and a delivered one can't be cancelled.
+func (r *SQLiteRepository) UpdateQueuedMessageStatus(id int64, fromStatus, status, errMessage string) (bool, error) {
+	now := time.Now().UTC()
+	var sentAt *time.Time
+	if status == domainChatStorage.QueueStatusSent {
+		sentAt = &now
+	}
+
+	result, err := r.db.Exec(`
+		UPDATE send_queue SET status = ?, error = ?, sent_at = COALESCE(?, sent_at), updated_at = ?
+		WHERE id = ? AND status = ?
+	`, status, errMessage, sentAt, now, id, fromStatus)
+	if err != nil {
+		return false, err
+	}
+
+	affected, err := result.RowsAffected()
+	return affected > 0, err
+}
+
+func queuedMessageFilterConditions(filter *domainChatStorage.QueuedMessageFilter) (string, []any) {
+	if filter == nil || filter.Status == "" {
+		return "", nil
+	}
+	return ` WHERE status = ?`, []any{filter.Status}
+}
+
+// scanQueuedMessage is a private helper for scanning send queue rows
+func (r *SQLiteRepository) scanQueuedMessage(scanner interface{ Scan(...any) error }) (*domainChatStorage.QueuedMessage, error) {
+	message := &domainChatStorage.QueuedMessage{}
+	err := scanner.Scan(
+		&message.ID, &message.MessageID, &message.Recipient, &message.Content, &message.Payload, &message.Status,
+		&message.Error, &message.ExpiresAt, &message.SentAt, &message.CreatedAt, &message.UpdatedAt,
+	)
+	return message, err
+}
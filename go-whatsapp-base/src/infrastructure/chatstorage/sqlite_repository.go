@@ -65,7 +65,9 @@ func (r *SQLiteRepository) GetMessageByID(id string) (*domainChatStorage.Message
 	query := `
 		SELECT id, chat_jid, sender, content, timestamp, is_from_me,
 			media_type, filename, url, media_key, file_sha256,
-			file_enc_sha256, file_length, created_at, updated_at
+			file_enc_sha256, file_length, view_once, interactive_type,
+			selected_id, selected_title, original_message_id, mimetype, forwarding_score,
+			created_at, updated_at
 		FROM messages
 		WHERE id = ?
 		LIMIT 1
@@ -81,27 +83,10 @@ func (r *SQLiteRepository) GetMessageByID(id string) (*domainChatStorage.Message
 
 // GetChats retrieves chats with filtering
 func (r *SQLiteRepository) GetChats(filter *domainChatStorage.ChatFilter) ([]*domainChatStorage.Chat, error) {
-	var conditions []string
-	var args []any
-
+	from, args := chatFilterConditions(filter)
 	query := `
 		SELECT c.jid, c.name, c.last_message_time, c.ephemeral_expiration, c.created_at, c.updated_at
-		FROM chats c
-	`
-
-	if filter.SearchName != "" {
-		conditions = append(conditions, "c.name LIKE ?")
-		args = append(args, "%"+filter.SearchName+"%")
-	}
-
-	if filter.HasMedia {
-		query += " INNER JOIN messages m ON c.jid = m.chat_jid"
-		conditions = append(conditions, "m.media_type != ''")
-	}
-
-	if len(conditions) > 0 {
-		query += " WHERE " + strings.Join(conditions, " AND ")
-	}
+	` + from
 
 	query += " ORDER BY c.last_message_time DESC"
 
@@ -158,6 +143,18 @@ func (r *SQLiteRepository) DeleteChat(jid string) error {
 		return err
 	}
 
+	// Backfill progress is anchored on the deleted messages
+	_, err = tx.Exec("DELETE FROM chat_backfills WHERE chat_jid = ?", jid)
+	if err != nil {
+		return err
+	}
+
+	// The chat stays labeled on WhatsApp, but its message labels went with the messages
+	_, err = tx.Exec("DELETE FROM message_labels WHERE chat_jid = ?", jid)
+	if err != nil {
+		return err
+	}
+
 	return tx.Commit()
 }
 
@@ -177,8 +174,10 @@ func (r *SQLiteRepository) StoreMessage(message *domainChatStorage.Message) erro
 		INSERT INTO messages (
 			id, chat_jid, sender, content, timestamp, is_from_me, 
 			media_type, filename, url, media_key, file_sha256, 
-			file_enc_sha256, file_length, created_at, updated_at
-		) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+			file_enc_sha256, file_length, view_once, interactive_type,
+			selected_id, selected_title, original_message_id, mimetype, forwarding_score,
+			created_at, updated_at
+		) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
 		ON CONFLICT(id, chat_jid) DO UPDATE SET
 			sender = excluded.sender,
 			content = excluded.content,
@@ -191,6 +190,13 @@ func (r *SQLiteRepository) StoreMessage(message *domainChatStorage.Message) erro
 			file_sha256 = excluded.file_sha256,
 			file_enc_sha256 = excluded.file_enc_sha256,
 			file_length = excluded.file_length,
+			view_once = excluded.view_once,
+			interactive_type = excluded.interactive_type,
+			selected_id = excluded.selected_id,
+			selected_title = excluded.selected_title,
+			original_message_id = excluded.original_message_id,
+			mimetype = excluded.mimetype,
+			forwarding_score = excluded.forwarding_score,
 			updated_at = excluded.updated_at
 	`
 
@@ -198,7 +204,10 @@ func (r *SQLiteRepository) StoreMessage(message *domainChatStorage.Message) erro
 		message.ID, message.ChatJID, message.Sender, message.Content,
 		message.Timestamp, message.IsFromMe, message.MediaType, message.Filename,
 		message.URL, message.MediaKey, message.FileSHA256, message.FileEncSHA256,
-		message.FileLength, message.CreatedAt, message.UpdatedAt,
+		message.FileLength, message.ViewOnce, message.InteractiveType,
+		message.SelectedID, message.SelectedTitle, message.OriginalMessageID,
+		message.Mimetype, message.ForwardingScore,
+		message.CreatedAt, message.UpdatedAt,
 	)
 
 	return err
@@ -221,8 +230,10 @@ func (r *SQLiteRepository) StoreMessagesBatch(messages []*domainChatStorage.Mess
 		INSERT INTO messages (
 			id, chat_jid, sender, content, timestamp, is_from_me, 
 			media_type, filename, url, media_key, file_sha256, 
-			file_enc_sha256, file_length, created_at, updated_at
-		) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+			file_enc_sha256, file_length, view_once, interactive_type,
+			selected_id, selected_title, original_message_id, mimetype, forwarding_score,
+			created_at, updated_at
+		) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
 		ON CONFLICT(id, chat_jid) DO UPDATE SET
 			sender = excluded.sender,
 			content = excluded.content,
@@ -235,6 +246,13 @@ func (r *SQLiteRepository) StoreMessagesBatch(messages []*domainChatStorage.Mess
 			file_sha256 = excluded.file_sha256,
 			file_enc_sha256 = excluded.file_enc_sha256,
 			file_length = excluded.file_length,
+			view_once = excluded.view_once,
+			interactive_type = excluded.interactive_type,
+			selected_id = excluded.selected_id,
+			selected_title = excluded.selected_title,
+			original_message_id = excluded.original_message_id,
+			mimetype = excluded.mimetype,
+			forwarding_score = excluded.forwarding_score,
 			updated_at = excluded.updated_at
 	`)
 	if err != nil {
@@ -256,7 +274,10 @@ func (r *SQLiteRepository) StoreMessagesBatch(messages []*domainChatStorage.Mess
 			message.ID, message.ChatJID, message.Sender, message.Content,
 			message.Timestamp, message.IsFromMe, message.MediaType, message.Filename,
 			message.URL, message.MediaKey, message.FileSHA256, message.FileEncSHA256,
-			message.FileLength, message.CreatedAt, message.UpdatedAt,
+			message.FileLength, message.ViewOnce, message.InteractiveType,
+			message.SelectedID, message.SelectedTitle, message.OriginalMessageID,
+			message.Mimetype, message.ForwardingScore,
+			message.CreatedAt, message.UpdatedAt,
 		)
 		if err != nil {
 			return fmt.Errorf("failed to store message %s: %w", message.ID, err)
@@ -296,7 +317,9 @@ func (r *SQLiteRepository) GetMessages(filter *domainChatStorage.MessageFilter)
 	query := `
 		SELECT id, chat_jid, sender, content, timestamp, is_from_me,
 			media_type, filename, url, media_key, file_sha256,
-			file_enc_sha256, file_length, created_at, updated_at
+			file_enc_sha256, file_length, view_once, interactive_type,
+			selected_id, selected_title, original_message_id, mimetype, forwarding_score,
+			created_at, updated_at
 		FROM messages
 		WHERE ` + strings.Join(conditions, " AND ") + `
 		ORDER BY timestamp DESC
@@ -356,7 +379,9 @@ func (r *SQLiteRepository) SearchMessages(chatJID, searchText string, limit int)
 	query := `
 		SELECT id, chat_jid, sender, content, timestamp, is_from_me,
 			media_type, filename, url, media_key, file_sha256,
-			file_enc_sha256, file_length, created_at, updated_at
+			file_enc_sha256, file_length, view_once, interactive_type,
+			selected_id, selected_title, original_message_id, mimetype, forwarding_score,
+			created_at, updated_at
 		FROM messages
 		WHERE ` + strings.Join(conditions, " AND ") + `
 		ORDER BY timestamp DESC
@@ -414,7 +439,10 @@ func (r *SQLiteRepository) scanMessage(scanner interface{ Scan(...any) error })
 		&message.ID, &message.ChatJID, &message.Sender, &message.Content,
 		&message.Timestamp, &message.IsFromMe, &message.MediaType, &message.Filename,
 		&message.URL, &message.MediaKey, &message.FileSHA256, &message.FileEncSHA256,
-		&message.FileLength, &message.CreatedAt, &message.UpdatedAt,
+		&message.FileLength, &message.ViewOnce, &message.InteractiveType,
+		&message.SelectedID, &message.SelectedTitle, &message.OriginalMessageID,
+		&message.Mimetype, &message.ForwardingScore,
+		&message.CreatedAt, &message.UpdatedAt,
 	)
 	return message, err
 }
@@ -444,6 +472,41 @@ func (r *SQLiteRepository) GetTotalChatCount() (int64, error) {
 	return r.getCount("SELECT COUNT(*) FROM chats")
 }
 
+// GetChatCount returns the number of chats matching the filter
+func (r *SQLiteRepository) GetChatCount(filter *domainChatStorage.ChatFilter) (int64, error) {
+	from, args := chatFilterConditions(filter)
+	return r.getCount(`SELECT COUNT(DISTINCT c.jid) `+from, args...)
+}
+
+// chatFilterConditions builds the FROM and WHERE clauses for a chat filter
+func chatFilterConditions(filter *domainChatStorage.ChatFilter) (string, []any) {
+	var conditions []string
+	var args []any
+
+	query := " FROM chats c"
+
+	if filter.SearchName != "" {
+		conditions = append(conditions, "c.name LIKE ?")
+		args = append(args, "%"+filter.SearchName+"%")
+	}
+
+	if filter.HasMedia {
+		query += " INNER JOIN messages m ON c.jid = m.chat_jid"
+		conditions = append(conditions, "m.media_type != ''")
+	}
+
+	if filter.LabelID != "" {
+		conditions = append(conditions, "c.jid IN (SELECT chat_jid FROM chat_labels WHERE label_id = ?)")
+		args = append(args, filter.LabelID)
+	}
+
+	if len(conditions) > 0 {
+		query += " WHERE " + strings.Join(conditions, " AND ")
+	}
+
+	return query, args
+}
+
 // TruncateAllChats deletes all chats from the database
 // Note: Due to foreign key constraints, messages must be deleted first
 func (r *SQLiteRepository) TruncateAllChats() error {
@@ -465,6 +528,22 @@ func (r *SQLiteRepository) TruncateAllChats() error {
 		return fmt.Errorf("failed to delete chats: %w", err)
 	}
 
+	_, err = tx.Exec("DELETE FROM chat_backfills")
+	if err != nil {
+		return fmt.Errorf("failed to delete chat backfills: %w", err)
+	}
+
+	_, err = tx.Exec("DELETE FROM contacts")
+	if err != nil {
+		return fmt.Errorf("failed to delete contacts: %w", err)
+	}
+
+	for _, table := range []string{"group_membership_events", "group_participants", "groups", "idempotency_keys", "send_queue", "labels", "chat_labels", "message_labels"} {
+		if _, err = tx.Exec("DELETE FROM " + table); err != nil {
+			return fmt.Errorf("failed to delete %s: %w", table, err)
+		}
+	}
+
 	return tx.Commit()
 }
 
@@ -573,6 +652,15 @@ func (r *SQLiteRepository) CreateMessage(ctx context.Context, evt *events.Messag
 		FileSHA256:    fileSHA256,
 		FileEncSHA256: fileEncSHA256,
 		FileLength:    fileLength,
+		ViewOnce:      evt.IsViewOnce || utils.IsViewOnceMedia(evt.Message),
+	}
+	message.Mimetype = utils.ExtractMediaMimetype(evt.Message)
+	message.ForwardingScore = utils.ExtractForwardingScore(evt.Message)
+	if reply := utils.ExtractInteractiveReply(evt.Message); reply != nil {
+		message.InteractiveType = reply.Type
+		message.SelectedID = reply.SelectedID
+		message.SelectedTitle = reply.SelectedTitle
+		message.OriginalMessageID = reply.OriginalMessageID
 	}
 
 	// Store the message
@@ -596,6 +684,21 @@ func (r *SQLiteRepository) GetStorageStatistics() (chatCount int64, messageCount
 	return chatCount, messageCount, nil
 }
 
+// CheckWritable verifies the database accepts writes by updating the schema
+// version in a transaction that is rolled back
+func (r *SQLiteRepository) CheckWritable(ctx context.Context) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx, "UPDATE schema_info SET version = version"); err != nil {
+		return fmt.Errorf("failed to write to chat storage: %w", err)
+	}
+	return nil
+}
+
 // TruncateAllDataWithLogging performs truncation with detailed logging
 func (r *SQLiteRepository) TruncateAllDataWithLogging(logPrefix string) error {
 	// Get statistics before truncation
@@ -810,5 +913,208 @@ func (r *SQLiteRepository) getMigrations() []string {
 		`
 		CREATE INDEX IF NOT EXISTS idx_messages_id ON messages(id);
 		`,
+
+		// Migration 3: Auto-reply rules and the log of rule hits
+		`
+		CREATE TABLE IF NOT EXISTS auto_reply_rules (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			name TEXT NOT NULL,
+			enabled BOOLEAN DEFAULT TRUE,
+			priority INTEGER DEFAULT 0,
+			keywords TEXT,
+			pattern TEXT,
+			senders TEXT,
+			chat_type TEXT NOT NULL DEFAULT 'private',
+			response_type TEXT NOT NULL DEFAULT 'text',
+			response_text TEXT,
+			media_url TEXT,
+			cooldown_seconds INTEGER DEFAULT 0,
+			created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+			updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+		);
+
+		CREATE TABLE IF NOT EXISTS auto_reply_hits (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			rule_id INTEGER NOT NULL,
+			chat_jid TEXT NOT NULL,
+			sender_jid TEXT NOT NULL,
+			message_id TEXT NOT NULL,
+			reply_message_id TEXT,
+			status TEXT NOT NULL,
+			error TEXT,
+			created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+		);
+
+		CREATE INDEX IF NOT EXISTS idx_auto_reply_rules_priority ON auto_reply_rules(priority);
+		CREATE INDEX IF NOT EXISTS idx_auto_reply_hits_rule_sender ON auto_reply_hits(rule_id, sender_jid, created_at);
+		`,
+
+		// Migration 4: Message templates
+		`
+		CREATE TABLE IF NOT EXISTS message_templates (
+			id TEXT PRIMARY KEY,
+			description TEXT,
+			default_language TEXT NOT NULL,
+			variables TEXT,
+			variants TEXT NOT NULL,
+			created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+			updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+		);
+		`,
+
+		// Migration 5: On-demand history backfill progress per chat
+		`
+		CREATE TABLE IF NOT EXISTS chat_backfills (
+			chat_jid TEXT PRIMARY KEY,
+			status TEXT NOT NULL,
+			requested_count INTEGER NOT NULL DEFAULT 0,
+			anchor_message_id TEXT NOT NULL,
+			anchor_timestamp TIMESTAMP NOT NULL,
+			received_messages INTEGER NOT NULL DEFAULT 0,
+			stored_messages INTEGER NOT NULL DEFAULT 0,
+			total_stored INTEGER NOT NULL DEFAULT 0,
+			requests INTEGER NOT NULL DEFAULT 0,
+			requested_at TIMESTAMP NOT NULL,
+			completed_at TIMESTAMP
+		);
+		`,
+
+		// Migration 6: Contacts with names and phone number / LID mapping
+		`
+		CREATE TABLE IF NOT EXISTS contacts (
+			jid TEXT PRIMARY KEY,
+			phone_jid TEXT,
+			lid_jid TEXT,
+			saved_name TEXT,
+			push_name TEXT,
+			business_name TEXT,
+			first_seen TIMESTAMP,
+			last_seen TIMESTAMP,
+			created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+			updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+		);
+
+		CREATE INDEX IF NOT EXISTS idx_contacts_phone_jid ON contacts(phone_jid);
+		CREATE INDEX IF NOT EXISTS idx_contacts_lid_jid ON contacts(lid_jid);
+		`,
+
+		// Migration 7: Group metadata, current members and the membership log
+		`
+		CREATE TABLE IF NOT EXISTS groups (
+			jid TEXT PRIMARY KEY,
+			name TEXT,
+			topic TEXT,
+			owner_jid TEXT,
+			is_announce BOOLEAN DEFAULT FALSE,
+			is_locked BOOLEAN DEFAULT FALSE,
+			is_community BOOLEAN DEFAULT FALSE,
+			group_created_at TIMESTAMP,
+			created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+			updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+		);
+
+		CREATE TABLE IF NOT EXISTS group_participants (
+			group_jid TEXT NOT NULL,
+			participant_jid TEXT NOT NULL,
+			role TEXT NOT NULL,
+			joined_at TIMESTAMP NOT NULL,
+			updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+			PRIMARY KEY (group_jid, participant_jid)
+		);
+
+		CREATE TABLE IF NOT EXISTS group_membership_events (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			group_jid TEXT NOT NULL,
+			participant_jid TEXT NOT NULL,
+			action TEXT NOT NULL,
+			role TEXT NOT NULL,
+			actor_jid TEXT,
+			reason TEXT,
+			source TEXT NOT NULL,
+			timestamp TIMESTAMP NOT NULL
+		);
+
+		CREATE INDEX IF NOT EXISTS idx_group_membership_events_group ON group_membership_events(group_jid, timestamp);
+		CREATE INDEX IF NOT EXISTS idx_group_membership_events_participant ON group_membership_events(participant_jid);
+		`,
+
+		// Migration 8: Responses of sends made with an idempotency key
+		`
+		CREATE TABLE IF NOT EXISTS idempotency_keys (
+			key TEXT PRIMARY KEY,
+			operation TEXT NOT NULL,
+			recipient TEXT NOT NULL,
+			message_id TEXT NOT NULL,
+			status TEXT,
+			created_at TIMESTAMP NOT NULL
+		);
+
+		CREATE INDEX IF NOT EXISTS idx_idempotency_keys_created_at ON idempotency_keys(created_at);
+		`,
+
+		// Migration 9: Sends queued while disconnected
+		`
+		CREATE TABLE IF NOT EXISTS send_queue (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			message_id TEXT NOT NULL,
+			recipient TEXT NOT NULL,
+			content TEXT,
+			payload BLOB NOT NULL,
+			status TEXT NOT NULL,
+			error TEXT,
+			expires_at TIMESTAMP NOT NULL,
+			sent_at TIMESTAMP,
+			created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+			updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+		);
+
+		CREATE INDEX IF NOT EXISTS idx_send_queue_status ON send_queue(status, id);
+		`,
+
+		// Migration 10: WhatsApp Business labels and their chat and message assignments
+		`
+		CREATE TABLE IF NOT EXISTS labels (
+			id TEXT PRIMARY KEY,
+			name TEXT NOT NULL,
+			color INTEGER NOT NULL DEFAULT 0,
+			deleted BOOLEAN NOT NULL DEFAULT FALSE,
+			created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+			updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+		);
+
+		CREATE TABLE IF NOT EXISTS chat_labels (
+			label_id TEXT NOT NULL,
+			chat_jid TEXT NOT NULL,
+			created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+			PRIMARY KEY (label_id, chat_jid)
+		);
+
+		CREATE INDEX IF NOT EXISTS idx_chat_labels_chat ON chat_labels(chat_jid);
+
+		CREATE TABLE IF NOT EXISTS message_labels (
+			label_id TEXT NOT NULL,
+			chat_jid TEXT NOT NULL,
+			message_id TEXT NOT NULL,
+			created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+			PRIMARY KEY (label_id, chat_jid, message_id)
+		);
+
+		CREATE INDEX IF NOT EXISTS idx_message_labels_message ON message_labels(chat_jid, message_id);
+		`,
+
+		// Migration 11: View-once flag and the choice made in button, list and template replies
+		`
+		ALTER TABLE messages ADD COLUMN view_once BOOLEAN NOT NULL DEFAULT FALSE;
+		ALTER TABLE messages ADD COLUMN interactive_type TEXT NOT NULL DEFAULT '';
+		ALTER TABLE messages ADD COLUMN selected_id TEXT NOT NULL DEFAULT '';
+		ALTER TABLE messages ADD COLUMN selected_title TEXT NOT NULL DEFAULT '';
+		ALTER TABLE messages ADD COLUMN original_message_id TEXT NOT NULL DEFAULT '';
+		`,
+
+		// Migration 12: Media mimetype and forwarding score, so forwards keep both
+		`
+		ALTER TABLE messages ADD COLUMN mimetype TEXT NOT NULL DEFAULT '';
+		ALTER TABLE messages ADD COLUMN forwarding_score INTEGER NOT NULL DEFAULT 0;
+		`,
 	}
 }
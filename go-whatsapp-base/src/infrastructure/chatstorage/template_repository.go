@@ -0,0 +1,109 @@
+package chatstorage
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	domainChatStorage "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/chatstorage"
+)
+
+const messageTemplateColumns = `id, description, default_language, variables, variants, created_at, updated_at`
+
+// StoreMessageTemplate creates or replaces a template, keeping its original creation time
+func (r *SQLiteRepository) StoreMessageTemplate(template *domainChatStorage.MessageTemplate) error {
+	variables, err := json.Marshal(template.Variables)
+	if err != nil {
+		return fmt.Errorf("failed to encode variables: %w", err)
+	}
+	variants, err := json.Marshal(template.Variants)
+	if err != nil {
+		return fmt.Errorf("failed to encode variants: %w", err)
+	}
+
+	now := time.Now()
+	if template.CreatedAt.IsZero() {
+		template.CreatedAt = now
+	}
+	template.UpdatedAt = now
+
+	_, err = r.db.Exec(`
+		INSERT INTO message_templates (`+messageTemplateColumns+`)
+		VALUES (?, ?, ?, ?, ?, ?, ?)
+		ON CONFLICT(id) DO UPDATE SET
+			description = excluded.description,
+			default_language = excluded.default_language,
+			variables = excluded.variables,
+			variants = excluded.variants,
+			updated_at = excluded.updated_at
+	`, template.ID, template.Description, template.DefaultLanguage, string(variables), string(variants),
+		template.CreatedAt, template.UpdatedAt)
+
+	return err
+}
+
+// GetMessageTemplate retrieves a template by ID
+func (r *SQLiteRepository) GetMessageTemplate(id string) (*domainChatStorage.MessageTemplate, error) {
+	query := `SELECT ` + messageTemplateColumns + ` FROM message_templates WHERE id = ?`
+
+	template, err := r.scanMessageTemplate(r.db.QueryRow(query, id))
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+
+	return template, err
+}
+
+// GetMessageTemplates retrieves all templates ordered by ID
+func (r *SQLiteRepository) GetMessageTemplates() ([]*domainChatStorage.MessageTemplate, error) {
+	rows, err := r.db.Query(`SELECT ` + messageTemplateColumns + ` FROM message_templates ORDER BY id ASC`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var templates []*domainChatStorage.MessageTemplate
+	for rows.Next() {
+		template, err := r.scanMessageTemplate(rows)
+		if err != nil {
+			return nil, err
+		}
+		templates = append(templates, template)
+	}
+
+	return templates, rows.Err()
+}
+
+// DeleteMessageTemplate deletes a template
+func (r *SQLiteRepository) DeleteMessageTemplate(id string) error {
+	_, err := r.db.Exec("DELETE FROM message_templates WHERE id = ?", id)
+	return err
+}
+
+// scanMessageTemplate is a private helper for scanning message template rows
+func (r *SQLiteRepository) scanMessageTemplate(scanner interface{ Scan(...any) error }) (*domainChatStorage.MessageTemplate, error) {
+	template := &domainChatStorage.MessageTemplate{}
+	var description, variables sql.NullString
+	var variants string
+	err := scanner.Scan(
+		&template.ID, &description, &template.DefaultLanguage, &variables, &variants,
+		&template.CreatedAt, &template.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	template.Description = description.String
+
+	if variables.String != "" {
+		if err := json.Unmarshal([]byte(variables.String), &template.Variables); err != nil {
+			return nil, fmt.Errorf("failed to decode variables of template %s: %w", template.ID, err)
+		}
+	}
+	if err := json.Unmarshal([]byte(variants), &template.Variants); err != nil {
+		return nil, fmt.Errorf("failed to decode variants of template %s: %w", template.ID, err)
+	}
+
+	return template, nil
+}
@@ -0,0 +1,444 @@
+package whatsapp
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/config"
+	domainChatStorage "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/chatstorage"
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/utils"
+	"github.com/sirupsen/logrus"
+	"go.mau.fi/whatsmeow"
+	"go.mau.fi/whatsmeow/proto/waE2E"
+	"go.mau.fi/whatsmeow/types"
+	"go.mau.fi/whatsmeow/types/events"
+	"google.golang.org/protobuf/proto"
+)
+
+// LegacyAutoReplyRuleName names the rule that mirrors the deprecated --autoreply flag
+const LegacyAutoReplyRuleName = "legacy-autoreply"
+
+// autoReplyPatterns caches compiled rule patterns by their source
+var autoReplyPatterns sync.Map
+
+// autoReplySenderLocks serializes the cooldown check of each sender, so two
+// messages arriving together cannot both pass it
+var autoReplySenderLocks = utils.NewKeyLocks()
+
+// autoReplyReservations holds the replies being sent, by rule and sender. A
+// reservation counts for the cooldown until the hit of the reply is logged.
+var (
+	autoReplyReservations   = make(map[string]time.Time)
+	autoReplyReservationsMu sync.Mutex
+)
+
+// autoReplyInput holds the parts of an incoming message that rules are matched against
+type autoReplyInput struct {
+	Text     string
+	Sender   types.JID
+	PushName string
+	IsGroup  bool
+}
+
+// handleAutoReply evaluates the stored auto-reply rules in priority order and
+// answers with the first rule that matches the incoming message.
+func handleAutoReply(ctx context.Context, evt *events.Message, chatStorageRepo domainChatStorage.IChatStorageRepository) {
+	if chatStorageRepo == nil || evt.Info.IsFromMe {
+		return
+	}
+
+	// Skip broadcasts, status updates and newsletters
+	source := evt.Info.SourceString()
+	if evt.Info.IsIncomingBroadcast() ||
+		strings.Contains(source, "broadcast") ||
+		strings.HasSuffix(evt.Info.Chat.String(), "@broadcast") ||
+		strings.HasPrefix(evt.Info.Chat.String(), "status@") {
+		return
+	}
+
+	isGroup := evt.Info.Chat.Server == types.GroupServer
+	if !isGroup && evt.Info.Chat.Server != types.DefaultUserServer {
+		return
+	}
+
+	// Require actual typed text (not captions or synthetic labels)
+	text := extractTypedText(evt.Message)
+	if text == "" {
+		return
+	}
+
+	rules, err := chatStorageRepo.GetAutoReplyRules(true)
+	if err != nil {
+		log.Errorf("Failed to load auto-reply rules: %v", err)
+		return
+	}
+
+	input := autoReplyInput{
+		Text:     text,
+		Sender:   autoReplySender(evt),
+		PushName: evt.Info.PushName,
+		IsGroup:  isGroup,
+	}
+
+	for _, rule := range rules {
+		if matchAutoReplyRule(rule, input) {
+			applyAutoReplyRule(ctx, evt, rule, input, chatStorageRepo)
+			return
+		}
+	}
+}
+
+// extractTypedText returns the typed text of a message, unwrapping view-once
+// and ephemeral wrappers. Captions are not considered typed text.
+func extractTypedText(msg *waE2E.Message) string {
+	innerMsg := msg
+	for i := 0; i < 3; i++ { // safeguard against excessively nested wrappers
+		if vm := innerMsg.GetViewOnceMessage(); vm != nil && vm.GetMessage() != nil {
+			innerMsg = vm.GetMessage()
+			continue
+		}
+		if em := innerMsg.GetEphemeralMessage(); em != nil && em.GetMessage() != nil {
+			innerMsg = em.GetMessage()
+			continue
+		}
+		if vm2 := innerMsg.GetViewOnceMessageV2(); vm2 != nil && vm2.GetMessage() != nil {
+			innerMsg = vm2.GetMessage()
+			continue
+		}
+		if vm2e := innerMsg.GetViewOnceMessageV2Extension(); vm2e != nil && vm2e.GetMessage() != nil {
+			innerMsg = vm2e.GetMessage()
+			continue
+		}
+		break
+	}
+
+	if conv := innerMsg.GetConversation(); conv != "" {
+		return conv
+	}
+	if ext := innerMsg.GetExtendedTextMessage(); ext != nil && ext.GetText() != "" {
+		return ext.GetText()
+	}
+	if protoMsg := innerMsg.GetProtocolMessage(); protoMsg != nil {
+		if edited := protoMsg.GetEditedMessage(); edited != nil {
+			if ext := edited.GetExtendedTextMessage(); ext != nil && ext.GetText() != "" {
+				return ext.GetText()
+			}
+			return edited.GetConversation()
+		}
+	}
+	return ""
+}
+
+// autoReplySender returns the phone number JID of the sender when WhatsApp
+// addressed the message with a LID, so sender rules and cooldowns stay stable.
+func autoReplySender(evt *events.Message) types.JID {
+	sender := evt.Info.Sender.ToNonAD()
+	if sender.Server == types.HiddenUserServer && evt.Info.SenderAlt.Server == types.DefaultUserServer {
+		return evt.Info.SenderAlt.ToNonAD()
+	}
+	return sender
+}
+
+// matchAutoReplyRule reports whether every matcher configured on the rule matches the input
+func matchAutoReplyRule(rule *domainChatStorage.AutoReplyRule, input autoReplyInput) bool {
+	switch rule.ChatType {
+	case domainChatStorage.AutoReplyChatAll:
+	case domainChatStorage.AutoReplyChatGroup:
+		if !input.IsGroup {
+			return false
+		}
+	default:
+		if input.IsGroup {
+			return false
+		}
+	}
+
+	if len(rule.Senders) > 0 {
+		matched := false
+		for _, sender := range rule.Senders {
+			if normalizeAutoReplySender(sender) == input.Sender.User {
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			return false
+		}
+	}
+
+	if len(rule.Keywords) > 0 {
+		text := strings.ToLower(input.Text)
+		matched := false
+		for _, keyword := range rule.Keywords {
+			if keyword != "" && strings.Contains(text, strings.ToLower(keyword)) {
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			return false
+		}
+	}
+
+	if rule.Pattern != "" {
+		pattern, err := compileAutoReplyPattern(rule.Pattern)
+		if err != nil {
+			logrus.Warnf("Skipping auto-reply rule %d with invalid pattern: %v", rule.ID, err)
+			return false
+		}
+		if !pattern.MatchString(input.Text) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// normalizeAutoReplySender reduces a configured sender to its phone number
+func normalizeAutoReplySender(sender string) string {
+	sender = strings.TrimSpace(sender)
+	if idx := strings.Index(sender, "@"); idx >= 0 {
+		sender = sender[:idx]
+	}
+	if idx := strings.Index(sender, ":"); idx >= 0 {
+		sender = sender[:idx]
+	}
+	return strings.TrimPrefix(sender, "+")
+}
+
+func compileAutoReplyPattern(source string) (*regexp.Regexp, error) {
+	if cached, ok := autoReplyPatterns.Load(source); ok {
+		return cached.(*regexp.Regexp), nil
+	}
+
+	pattern, err := regexp.Compile(source)
+	if err != nil {
+		return nil, err
+	}
+
+	autoReplyPatterns.Store(source, pattern)
+	return pattern, nil
+}
+
+// applyAutoReplyRule sends the rule response unless the sender is still in the
+// rule cooldown window. Every hit is logged, including skipped ones.
+func applyAutoReplyRule(ctx context.Context, evt *events.Message, rule *domainChatStorage.AutoReplyRule, input autoReplyInput, chatStorageRepo domainChatStorage.IChatStorageRepository) {
+	hit := &domainChatStorage.AutoReplyHit{
+		RuleID:    rule.ID,
+		ChatJID:   evt.Info.Chat.String(),
+		SenderJID: input.Sender.String(),
+		MessageID: evt.Info.ID,
+	}
+
+	if !reserveAutoReply(rule, hit, chatStorageRepo) {
+		hit.Status = domainChatStorage.AutoReplyStatusCooldown
+		logAutoReplyHit(chatStorageRepo, rule, hit)
+		return
+	}
+	defer releaseAutoReply(rule, hit)
+
+	// Reply in the group for group rules, otherwise directly to the sender
+	recipient := evt.Info.Chat
+	if !input.IsGroup {
+		recipient = utils.FormatJID(evt.Info.Sender.String())
+	}
+
+	msg, content, err := buildAutoReplyMessage(ctx, rule, input)
+	if err == nil {
+		var response whatsmeow.SendResponse
+		response, err = cli.SendMessage(ctx, recipient, msg)
+		if err == nil {
+			hit.ReplyMessageID = response.ID
+			storeAutoReplyMessage(ctx, chatStorageRepo, response, recipient, content)
+		}
+	}
+
+	if err != nil {
+		hit.Status = domainChatStorage.AutoReplyStatusFailed
+		hit.Error = err.Error()
+	} else {
+		hit.Status = domainChatStorage.AutoReplyStatusSent
+	}
+
+	logAutoReplyHit(chatStorageRepo, rule, hit)
+}
+
+// reserveAutoReply reports whether the rule may reply to the sender, and if
+// so holds the cooldown for the reply until releaseAutoReply. Only the check
+// runs under the sender lock; the reply is built and sent outside it.
+func reserveAutoReply(rule *domainChatStorage.AutoReplyRule, hit *domainChatStorage.AutoReplyHit, chatStorageRepo domainChatStorage.IChatStorageRepository) bool {
+	autoReplySenderLocks.Lock(hit.SenderJID)
+	defer autoReplySenderLocks.Unlock(hit.SenderJID)
+
+	key := fmt.Sprintf("%d:%s", rule.ID, hit.SenderJID)
+	if rule.CooldownSeconds > 0 {
+		cooldown := time.Duration(rule.CooldownSeconds) * time.Second
+
+		autoReplyReservationsMu.Lock()
+		reservedAt, reserved := autoReplyReservations[key]
+		autoReplyReservationsMu.Unlock()
+		if reserved && time.Since(reservedAt) < cooldown {
+			return false
+		}
+
+		lastReply, err := chatStorageRepo.GetLastAutoReplyTime(rule.ID, hit.SenderJID)
+		if err != nil {
+			log.Warnf("Failed to read auto-reply cooldown for rule %d: %v", rule.ID, err)
+		} else if !lastReply.IsZero() && time.Since(lastReply) < cooldown {
+			return false
+		}
+	}
+
+	autoReplyReservationsMu.Lock()
+	autoReplyReservations[key] = time.Now()
+	autoReplyReservationsMu.Unlock()
+	return true
+}
+
+// releaseAutoReply drops the reservation once the hit is logged. A sent reply
+// is in the hit log by then, and a failed one must not hold the cooldown.
+func releaseAutoReply(rule *domainChatStorage.AutoReplyRule, hit *domainChatStorage.AutoReplyHit) {
+	autoReplyReservationsMu.Lock()
+	delete(autoReplyReservations, fmt.Sprintf("%d:%s", rule.ID, hit.SenderJID))
+	autoReplyReservationsMu.Unlock()
+}
+
+// buildAutoReplyMessage builds the response of a rule and returns the text
+// content to keep in chat storage.
+func buildAutoReplyMessage(ctx context.Context, rule *domainChatStorage.AutoReplyRule, input autoReplyInput) (*waE2E.Message, string, error) {
+	body := rule.ResponseText
+	if rule.ResponseType == domainChatStorage.AutoReplyResponseTemplate {
+		body = renderAutoReplyTemplate(body, input)
+	}
+
+	var caption *string
+	if body != "" {
+		caption = proto.String(body)
+	}
+
+	switch rule.ResponseType {
+	case domainChatStorage.AutoReplyResponseImage:
+		data, _, err := utils.DownloadImageFromURL(rule.MediaURL)
+		if err != nil {
+			return nil, "", fmt.Errorf("failed to download image: %w", err)
+		}
+		uploaded, err := cli.Upload(ctx, data, whatsmeow.MediaImage)
+		if err != nil {
+			return nil, "", fmt.Errorf("failed to upload image: %w", err)
+		}
+		return &waE2E.Message{ImageMessage: &waE2E.ImageMessage{
+			URL:           proto.String(uploaded.URL),
+			DirectPath:    proto.String(uploaded.DirectPath),
+			MediaKey:      uploaded.MediaKey,
+			FileEncSHA256: uploaded.FileEncSHA256,
+			FileSHA256:    uploaded.FileSHA256,
+			FileLength:    proto.Uint64(uploaded.FileLength),
+			Mimetype:      proto.String(http.DetectContentType(data)),
+			Caption:       caption,
+		}}, body, nil
+	case domainChatStorage.AutoReplyResponseVideo:
+		data, _, err := utils.DownloadVideoFromURL(rule.MediaURL)
+		if err != nil {
+			return nil, "", fmt.Errorf("failed to download video: %w", err)
+		}
+		uploaded, err := cli.Upload(ctx, data, whatsmeow.MediaVideo)
+		if err != nil {
+			return nil, "", fmt.Errorf("failed to upload video: %w", err)
+		}
+		return &waE2E.Message{VideoMessage: &waE2E.VideoMessage{
+			URL:           proto.String(uploaded.URL),
+			DirectPath:    proto.String(uploaded.DirectPath),
+			MediaKey:      uploaded.MediaKey,
+			FileEncSHA256: uploaded.FileEncSHA256,
+			FileSHA256:    uploaded.FileSHA256,
+			FileLength:    proto.Uint64(uploaded.FileLength),
+			Mimetype:      proto.String(http.DetectContentType(data)),
+			Caption:       caption,
+		}}, body, nil
+	default:
+		return &waE2E.Message{Conversation: proto.String(body)}, body, nil
+	}
+}
+
+// renderAutoReplyTemplate fills the {name}, {phone} and {message} placeholders
+func renderAutoReplyTemplate(body string, input autoReplyInput) string {
+	name := input.PushName
+	if name == "" {
+		name = input.Sender.User
+	}
+
+	return strings.NewReplacer(
+		"{name}", name,
+		"{phone}", input.Sender.User,
+		"{message}", input.Text,
+	).Replace(body)
+}
+
+func storeAutoReplyMessage(ctx context.Context, chatStorageRepo domainChatStorage.IChatStorageRepository, response whatsmeow.SendResponse, recipient types.JID, content string) {
+	senderJID := ""
+	if cli.Store.ID != nil {
+		senderJID = cli.Store.ID.String()
+	}
+
+	if err := chatStorageRepo.StoreSentMessageWithContext(ctx, response.ID, senderJID, recipient.String(), content, response.Timestamp); err != nil {
+		// Log storage error but don't fail the auto-reply
+		log.Errorf("Failed to store auto-reply message in chat storage: %v", err)
+	}
+}
+
+func logAutoReplyHit(chatStorageRepo domainChatStorage.IChatStorageRepository, rule *domainChatStorage.AutoReplyRule, hit *domainChatStorage.AutoReplyHit) {
+	logrus.WithFields(logrus.Fields{
+		"rule_id":          rule.ID,
+		"rule_name":        rule.Name,
+		"chat":             hit.ChatJID,
+		"sender":           hit.SenderJID,
+		"message_id":       hit.MessageID,
+		"reply_message_id": hit.ReplyMessageID,
+		"status":           hit.Status,
+		"error":            hit.Error,
+	}).Info("Auto-reply rule matched")
+
+	if err := chatStorageRepo.StoreAutoReplyHit(hit); err != nil {
+		log.Errorf("Failed to log auto-reply hit for rule %d: %v", rule.ID, err)
+	}
+}
+
+// SyncLegacyAutoReplyRule keeps a catch-all rule in sync with the deprecated
+// --autoreply flag, so deployments that still set it keep their behaviour.
+func SyncLegacyAutoReplyRule(chatStorageRepo domainChatStorage.IChatStorageRepository) error {
+	if config.WhatsappAutoReplyMessage == "" {
+		return nil
+	}
+
+	rules, err := chatStorageRepo.GetAutoReplyRules(false)
+	if err != nil {
+		return err
+	}
+
+	for _, rule := range rules {
+		if rule.Name != LegacyAutoReplyRuleName {
+			continue
+		}
+		if rule.ResponseText == config.WhatsappAutoReplyMessage {
+			return nil
+		}
+		rule.ResponseText = config.WhatsappAutoReplyMessage
+		return chatStorageRepo.StoreAutoReplyRule(rule)
+	}
+
+	logrus.Warn("--autoreply is deprecated, manage auto-reply rules with the /auto-reply/rules endpoints instead")
+
+	return chatStorageRepo.StoreAutoReplyRule(&domainChatStorage.AutoReplyRule{
+		Name:         LegacyAutoReplyRuleName,
+		Enabled:      true,
+		Priority:     1000,
+		ChatType:     domainChatStorage.AutoReplyChatPrivate,
+		ResponseType: domainChatStorage.AutoReplyResponseText,
+		ResponseText: config.WhatsappAutoReplyMessage,
+	})
+}
@@ -0,0 +1,169 @@
+package whatsapp
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	domainChatStorage "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/chatstorage"
+	"go.mau.fi/whatsmeow/types"
+)
+
+func TestMatchAutoReplyRule(t *testing.T) {
+	sender := types.NewJID("6281234567890", types.DefaultUserServer)
+	private := autoReplyInput{Text: "What is the PRICE of this?", Sender: sender}
+	group := autoReplyInput{Text: "hello team", Sender: sender, IsGroup: true}
+
+	tests := []struct {
+		name  string
+		rule  domainChatStorage.AutoReplyRule
+		input autoReplyInput
+		want  bool
+	}{
+		{
+			name:  "catch-all rule matches private chats",
+			rule:  domainChatStorage.AutoReplyRule{},
+			input: private,
+			want:  true,
+		},
+		{
+			name:  "private rule skips groups",
+			rule:  domainChatStorage.AutoReplyRule{ChatType: domainChatStorage.AutoReplyChatPrivate},
+			input: group,
+			want:  false,
+		},
+		{
+			name:  "group rule matches groups",
+			rule:  domainChatStorage.AutoReplyRule{ChatType: domainChatStorage.AutoReplyChatGroup},
+			input: group,
+			want:  true,
+		},
+		{
+			name:  "keywords are case insensitive",
+			rule:  domainChatStorage.AutoReplyRule{Keywords: []string{"price", "cost"}},
+			input: private,
+			want:  true,
+		},
+		{
+			name:  "keywords must appear in text",
+			rule:  domainChatStorage.AutoReplyRule{Keywords: []string{"refund"}},
+			input: private,
+			want:  false,
+		},
+		{
+			name:  "pattern matches text",
+			rule:  domainChatStorage.AutoReplyRule{Pattern: `(?i)^what is`},
+			input: private,
+			want:  true,
+		},
+		{
+			name:  "invalid pattern never matches",
+			rule:  domainChatStorage.AutoReplyRule{Pattern: `([a-z`},
+			input: private,
+			want:  false,
+		},
+		{
+			name:  "sender accepts phone and JID forms",
+			rule:  domainChatStorage.AutoReplyRule{Senders: []string{"+6289999999999", "6281234567890@s.whatsapp.net"}},
+			input: private,
+			want:  true,
+		},
+		{
+			name:  "sender must be listed",
+			rule:  domainChatStorage.AutoReplyRule{Senders: []string{"6289999999999"}},
+			input: private,
+			want:  false,
+		},
+		{
+			name: "all matchers must match",
+			rule: domainChatStorage.AutoReplyRule{
+				Keywords: []string{"price"},
+				Senders:  []string{"6289999999999"},
+			},
+			input: private,
+			want:  false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := matchAutoReplyRule(&tt.rule, tt.input); got != tt.want {
+				t.Fatalf("matchAutoReplyRule() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRenderAutoReplyTemplate(t *testing.T) {
+	input := autoReplyInput{
+		Text:     "hi",
+		Sender:   types.NewJID("6281234567890", types.DefaultUserServer),
+		PushName: "Budi",
+	}
+
+	got := renderAutoReplyTemplate("Hello {name} ({phone}), you said: {message}", input)
+	want := "Hello Budi (6281234567890), you said: hi"
+	if got != want {
+		t.Fatalf("renderAutoReplyTemplate() = %q, want %q", got, want)
+	}
+
+	input.PushName = ""
+	if got := renderAutoReplyTemplate("Hello {name}", input); got != "Hello 6281234567890" {
+		t.Fatalf("renderAutoReplyTemplate() without push name = %q", got)
+	}
+}
+
+// cooldownRepo answers the cooldown lookup of reserveAutoReply
+type cooldownRepo struct {
+	domainChatStorage.IChatStorageRepository
+	lastReply time.Time
+}
+
+func (r cooldownRepo) GetLastAutoReplyTime(int64, string) (time.Time, error) {
+	return r.lastReply, nil
+}
+
+func TestReserveAutoReply(t *testing.T) {
+	rule := &domainChatStorage.AutoReplyRule{ID: 42, CooldownSeconds: 60}
+	hit := &domainChatStorage.AutoReplyHit{RuleID: rule.ID, SenderJID: "6281234567890@s.whatsapp.net"}
+
+	// Messages arriving together get one reply between them
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	reserved := 0
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if reserveAutoReply(rule, hit, cooldownRepo{}) {
+				mu.Lock()
+				reserved++
+				mu.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+	if reserved != 1 {
+		t.Fatalf("reserved %d replies, want 1", reserved)
+	}
+
+	// A failed reply gives the cooldown back
+	releaseAutoReply(rule, hit)
+	if !reserveAutoReply(rule, hit, cooldownRepo{}) {
+		t.Fatal("reservation refused after the previous one was released")
+	}
+	releaseAutoReply(rule, hit)
+
+	// A logged reply within the cooldown keeps refusing
+	if reserveAutoReply(rule, hit, cooldownRepo{lastReply: time.Now().Add(-time.Minute / 2)}) {
+		t.Fatal("reservation allowed within the cooldown of a sent reply")
+	}
+	if !reserveAutoReply(rule, hit, cooldownRepo{lastReply: time.Now().Add(-2 * time.Minute)}) {
+		t.Fatal("reservation refused after the cooldown of a sent reply")
+	}
+	releaseAutoReply(rule, hit)
+
+	if autoReplySenderLocks.Len() != 0 {
+		t.Fatalf("%d sender locks left, want 0", autoReplySenderLocks.Len())
+	}
+}
@@ -0,0 +1,229 @@
+package whatsapp
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	domainUser "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/user"
+	"go.mau.fi/whatsmeow"
+	waBinary "go.mau.fi/whatsmeow/binary"
+	"go.mau.fi/whatsmeow/types"
+)
+
+// catalogImageSize is the width and height requested for product images
+const catalogImageSize = "100"
+
+// OrderDetails is an order placed in chat, as returned by the seller's order query
+type OrderDetails struct {
+	Products        []OrderProduct `json:"products"`
+	Currency        string         `json:"currency,omitempty"`
+	TotalAmount1000 int64          `json:"total_amount_1000"`
+	Total           float64        `json:"total"`
+}
+
+// OrderProduct is one line of an order
+type OrderProduct struct {
+	ID              string  `json:"id"`
+	Name            string  `json:"name"`
+	ImageURL        string  `json:"image_url,omitempty"`
+	Currency        string  `json:"currency,omitempty"`
+	PriceAmount1000 int64   `json:"price_amount_1000"`
+	Price           float64 `json:"price"`
+	Quantity        int     `json:"quantity"`
+}
+
+// GetBusinessCatalog fetches one page of products from the catalog of a
+// business account. The returned cursor is empty on the last page.
+func GetBusinessCatalog(ctx context.Context, client *whatsmeow.Client, jid types.JID, limit int, cursor string) ([]domainUser.BusinessProduct, string, error) {
+	content := []waBinary.Node{
+		{Tag: "limit", Content: []byte(strconv.Itoa(limit))},
+		{Tag: "width", Content: []byte(catalogImageSize)},
+		{Tag: "height", Content: []byte(catalogImageSize)},
+	}
+	if cursor != "" {
+		content = append(content, waBinary.Node{Tag: "after", Content: []byte(cursor)})
+	}
+
+	resp, err := client.DangerousInternals().SendIQ(ctx, whatsmeow.DangerousInfoQuery{
+		Namespace: "w:biz:catalog",
+		Type:      "get",
+		To:        types.ServerJID,
+		Content: []waBinary.Node{{
+			Tag:     "product_catalog",
+			Attrs:   waBinary.Attrs{"jid": jid, "allow_shop_source": "true"},
+			Content: content,
+		}},
+	})
+	if err != nil {
+		return nil, "", err
+	}
+	return parseCatalogNode(resp)
+}
+
+// GetBusinessCollections fetches the product collections of a business account
+func GetBusinessCollections(ctx context.Context, client *whatsmeow.Client, jid types.JID, limit int) ([]domainUser.BusinessCollection, error) {
+	resp, err := client.DangerousInternals().SendIQ(ctx, whatsmeow.DangerousInfoQuery{
+		Namespace: "w:biz:catalog",
+		Type:      "get",
+		To:        types.ServerJID,
+		SMaxID:    "35",
+		Content: []waBinary.Node{{
+			Tag:   "collections",
+			Attrs: waBinary.Attrs{"biz_jid": jid},
+			Content: []waBinary.Node{
+				{Tag: "collection_limit", Content: []byte(strconv.Itoa(limit))},
+				{Tag: "item_limit", Content: []byte(strconv.Itoa(limit))},
+				{Tag: "width", Content: []byte(catalogImageSize)},
+				{Tag: "height", Content: []byte(catalogImageSize)},
+			},
+		}},
+	})
+	if err != nil {
+		return nil, err
+	}
+	return parseCollectionsNode(resp)
+}
+
+// GetOrderDetails fetches the products of an order. Only the seller can read
+// an order, using the token carried by the order message.
+func GetOrderDetails(ctx context.Context, client *whatsmeow.Client, orderID, token string) (*OrderDetails, error) {
+	resp, err := client.DangerousInternals().SendIQ(ctx, whatsmeow.DangerousInfoQuery{
+		Namespace: "fb:thrift_iq",
+		Type:      "get",
+		To:        types.ServerJID,
+		SMaxID:    "5",
+		Content: []waBinary.Node{{
+			Tag:   "order",
+			Attrs: waBinary.Attrs{"op": "get", "id": orderID},
+			Content: []waBinary.Node{
+				{Tag: "image_dimensions", Content: []waBinary.Node{
+					{Tag: "width", Content: []byte(catalogImageSize)},
+					{Tag: "height", Content: []byte(catalogImageSize)},
+				}},
+				{Tag: "token", Content: []byte(token)},
+			},
+		}},
+	})
+	if err != nil {
+		return nil, err
+	}
+	return parseOrderNode(resp)
+}
+
+func parseCatalogNode(node *waBinary.Node) ([]domainUser.BusinessProduct, string, error) {
+	catalog, ok := node.GetOptionalChildByTag("product_catalog")
+	if !ok {
+		return nil, "", fmt.Errorf("product_catalog element missing in catalog response")
+	}
+
+	products := make([]domainUser.BusinessProduct, 0)
+	for _, productNode := range catalog.GetChildrenByTag("product") {
+		products = append(products, parseProductNode(productNode))
+	}
+
+	var cursor string
+	if paging, ok := catalog.GetOptionalChildByTag("paging"); ok {
+		cursor = nodeChildString(paging, "after")
+	}
+	return products, cursor, nil
+}
+
+func parseCollectionsNode(node *waBinary.Node) ([]domainUser.BusinessCollection, error) {
+	collectionsNode, ok := node.GetOptionalChildByTag("collections")
+	if !ok {
+		return nil, fmt.Errorf("collections element missing in collections response")
+	}
+
+	collections := make([]domainUser.BusinessCollection, 0)
+	for _, collectionNode := range collectionsNode.GetChildrenByTag("collection") {
+		collection := domainUser.BusinessCollection{
+			ID:           nodeChildString(collectionNode, "id"),
+			Name:         nodeChildString(collectionNode, "name"),
+			ReviewStatus: nodeChildString(collectionNode, "status_info", "status"),
+			Products:     make([]domainUser.BusinessProduct, 0),
+		}
+		for _, productNode := range collectionNode.GetChildrenByTag("product") {
+			collection.Products = append(collection.Products, parseProductNode(productNode))
+		}
+		collections = append(collections, collection)
+	}
+	return collections, nil
+}
+
+func parseProductNode(node waBinary.Node) domainUser.BusinessProduct {
+	price := nodeChildInt(node, "price")
+	product := domainUser.BusinessProduct{
+		ID:              nodeChildString(node, "id"),
+		RetailerID:      nodeChildString(node, "retailer_id"),
+		Name:            nodeChildString(node, "name"),
+		Description:     nodeChildString(node, "description"),
+		URL:             nodeChildString(node, "url"),
+		Currency:        nodeChildString(node, "currency"),
+		PriceAmount1000: price,
+		Price:           float64(price) / 1000,
+		Hidden:          node.AttrGetter().OptionalString("is_hidden") == "true",
+		ReviewStatus:    nodeChildString(node, "status_info", "status"),
+	}
+
+	if media, ok := node.GetOptionalChildByTag("media"); ok {
+		for _, image := range media.GetChildrenByTag("image") {
+			url := nodeChildString(image, "original_image_url")
+			if url == "" {
+				url = nodeChildString(image, "request_image_url")
+			}
+			if url != "" {
+				product.ImageURLs = append(product.ImageURLs, url)
+			}
+		}
+	}
+	return product
+}
+
+func parseOrderNode(node *waBinary.Node) (*OrderDetails, error) {
+	orderNode, ok := node.GetOptionalChildByTag("order")
+	if !ok {
+		return nil, fmt.Errorf("order element missing in order response")
+	}
+
+	order := &OrderDetails{Products: make([]OrderProduct, 0)}
+	for _, productNode := range orderNode.GetChildrenByTag("product") {
+		price := nodeChildInt(productNode, "price")
+		order.Products = append(order.Products, OrderProduct{
+			ID:              nodeChildString(productNode, "id"),
+			Name:            nodeChildString(productNode, "name"),
+			ImageURL:        nodeChildString(productNode, "image", "url"),
+			Currency:        nodeChildString(productNode, "currency"),
+			PriceAmount1000: price,
+			Price:           float64(price) / 1000,
+			Quantity:        int(nodeChildInt(productNode, "quantity")),
+		})
+	}
+
+	if priceNode, ok := orderNode.GetOptionalChildByTag("price"); ok {
+		order.TotalAmount1000 = nodeChildInt(priceNode, "total")
+		order.Total = float64(order.TotalAmount1000) / 1000
+		order.Currency = nodeChildString(priceNode, "currency")
+	}
+	return order, nil
+}
+
+// nodeChildString returns the text content of the child found by following tags
+func nodeChildString(node waBinary.Node, tags ...string) string {
+	child, ok := node.GetOptionalChildByTag(tags...)
+	if !ok {
+		return ""
+	}
+	switch content := child.Content.(type) {
+	case []byte:
+		return string(content)
+	case string:
+		return content
+	}
+	return ""
+}
+
+func nodeChildInt(node waBinary.Node, tags ...string) int64 {
+	value, _ := strconv.ParseInt(nodeChildString(node, tags...), 10, 64)
+	return value
+}
@@ -0,0 +1,188 @@
+package whatsapp
+
+import (
+	"testing"
+
+	waBinary "go.mau.fi/whatsmeow/binary"
+	"go.mau.fi/whatsmeow/proto/waE2E"
+	"google.golang.org/protobuf/proto"
+)
+
+func textNode(tag, content string) waBinary.Node {
+	return waBinary.Node{Tag: tag, Content: []byte(content)}
+}
+
+func productNode(id, name, price string, attrs waBinary.Attrs) waBinary.Node {
+	return waBinary.Node{Tag: "product", Attrs: attrs, Content: []waBinary.Node{
+		textNode("id", id),
+		textNode("name", name),
+		textNode("retailer_id", "SKU-"+id),
+		textNode("price", price),
+		textNode("currency", "IDR"),
+		{Tag: "media", Content: []waBinary.Node{
+			{Tag: "image", Content: []waBinary.Node{
+				textNode("request_image_url", "https://cdn.example.com/"+id+"-100.jpg"),
+				textNode("original_image_url", "https://cdn.example.com/"+id+".jpg"),
+			}},
+		}},
+		{Tag: "status_info", Content: []waBinary.Node{textNode("status", "APPROVED")}},
+	}}
+}
+
+func TestParseCatalogNode(t *testing.T) {
+	resp := &waBinary.Node{Tag: "iq", Content: []waBinary.Node{{
+		Tag: "product_catalog",
+		Content: []waBinary.Node{
+			productNode("7282471485125876", "Batik shirt", "150000000", nil),
+			productNode("6142739482374611", "Sarong", "85500000", waBinary.Attrs{"is_hidden": "true"}),
+			{Tag: "paging", Content: []waBinary.Node{textNode("after", "QVFIUnR4")}},
+		},
+	}}}
+
+	products, cursor, err := parseCatalogNode(resp)
+	if err != nil {
+		t.Fatalf("parseCatalogNode returned error: %v", err)
+	}
+	if cursor != "QVFIUnR4" {
+		t.Fatalf("cursor = %q, want QVFIUnR4", cursor)
+	}
+	if len(products) != 2 {
+		t.Fatalf("got %d products, want 2", len(products))
+	}
+
+	shirt := products[0]
+	if shirt.ID != "7282471485125876" || shirt.Name != "Batik shirt" || shirt.RetailerID != "SKU-7282471485125876" {
+		t.Fatalf("unexpected product: %+v", shirt)
+	}
+	if shirt.PriceAmount1000 != 150000000 || shirt.Price != 150000 || shirt.Currency != "IDR" {
+		t.Fatalf("unexpected price: %+v", shirt)
+	}
+	if len(shirt.ImageURLs) != 1 || shirt.ImageURLs[0] != "https://cdn.example.com/7282471485125876.jpg" {
+		t.Fatalf("unexpected image urls: %v", shirt.ImageURLs)
+	}
+	if shirt.Hidden || shirt.ReviewStatus != "APPROVED" {
+		t.Fatalf("unexpected status: %+v", shirt)
+	}
+	if !products[1].Hidden || products[1].Price != 85500 {
+		t.Fatalf("unexpected hidden product: %+v", products[1])
+	}
+}
+
+func TestParseCatalogNodeLastPage(t *testing.T) {
+	resp := &waBinary.Node{Tag: "iq", Content: []waBinary.Node{{Tag: "product_catalog"}}}
+
+	products, cursor, err := parseCatalogNode(resp)
+	if err != nil {
+		t.Fatalf("parseCatalogNode returned error: %v", err)
+	}
+	if len(products) != 0 || cursor != "" {
+		t.Fatalf("got %d products and cursor %q, want an empty last page", len(products), cursor)
+	}
+
+	if _, _, err := parseCatalogNode(&waBinary.Node{Tag: "iq"}); err == nil {
+		t.Fatal("expected error for response without product_catalog")
+	}
+}
+
+func TestParseCollectionsNode(t *testing.T) {
+	resp := &waBinary.Node{Tag: "iq", Content: []waBinary.Node{{
+		Tag: "collections",
+		Content: []waBinary.Node{{
+			Tag: "collection",
+			Content: []waBinary.Node{
+				textNode("id", "512"),
+				textNode("name", "Batik"),
+				productNode("7282471485125876", "Batik shirt", "150000000", nil),
+				{Tag: "status_info", Content: []waBinary.Node{textNode("status", "PENDING")}},
+			},
+		}},
+	}}}
+
+	collections, err := parseCollectionsNode(resp)
+	if err != nil {
+		t.Fatalf("parseCollectionsNode returned error: %v", err)
+	}
+	if len(collections) != 1 {
+		t.Fatalf("got %d collections, want 1", len(collections))
+	}
+	if collections[0].ID != "512" || collections[0].Name != "Batik" || collections[0].ReviewStatus != "PENDING" {
+		t.Fatalf("unexpected collection: %+v", collections[0])
+	}
+	if len(collections[0].Products) != 1 || collections[0].Products[0].ID != "7282471485125876" {
+		t.Fatalf("unexpected collection products: %+v", collections[0].Products)
+	}
+}
+
+func TestParseOrderNode(t *testing.T) {
+	resp := &waBinary.Node{Tag: "iq", Content: []waBinary.Node{{
+		Tag: "order",
+		Content: []waBinary.Node{
+			{Tag: "product", Content: []waBinary.Node{
+				textNode("id", "7282471485125876"),
+				textNode("name", "Batik shirt"),
+				{Tag: "image", Content: []waBinary.Node{textNode("url", "https://cdn.example.com/shirt.jpg")}},
+				textNode("price", "150000000"),
+				textNode("currency", "IDR"),
+				textNode("quantity", "2"),
+			}},
+			{Tag: "price", Content: []waBinary.Node{
+				textNode("total", "300000000"),
+				textNode("currency", "IDR"),
+			}},
+		},
+	}}}
+
+	order, err := parseOrderNode(resp)
+	if err != nil {
+		t.Fatalf("parseOrderNode returned error: %v", err)
+	}
+	if order.TotalAmount1000 != 300000000 || order.Total != 300000 || order.Currency != "IDR" {
+		t.Fatalf("unexpected order total: %+v", order)
+	}
+	if len(order.Products) != 1 {
+		t.Fatalf("got %d products, want 1", len(order.Products))
+	}
+	product := order.Products[0]
+	if product.Quantity != 2 || product.Price != 150000 || product.ImageURL != "https://cdn.example.com/shirt.jpg" {
+		t.Fatalf("unexpected order product: %+v", product)
+	}
+}
+
+func TestBuildOrderPayload(t *testing.T) {
+	order := &waE2E.OrderMessage{
+		OrderID:           proto.String("1093846571234567"),
+		OrderTitle:        proto.String("Batik Store"),
+		ItemCount:         proto.Int32(2),
+		Status:            waE2E.OrderMessage_INQUIRY.Enum(),
+		Surface:           waE2E.OrderMessage_CATALOG.Enum(),
+		Message:           proto.String("Please deliver tomorrow"),
+		SellerJID:         proto.String("6289685028129@s.whatsapp.net"),
+		Token:             proto.String("AR5VSKVkCk"),
+		TotalAmount1000:   proto.Int64(300000000),
+		TotalCurrencyCode: proto.String("IDR"),
+	}
+
+	payload := buildOrderPayload(order, nil)
+	if payload["order_id"] != "1093846571234567" || payload["status"] != "inquiry" || payload["surface"] != "catalog" {
+		t.Fatalf("unexpected payload: %v", payload)
+	}
+	if payload["total"] != float64(300000) || payload["currency"] != "IDR" || payload["item_count"] != int32(2) {
+		t.Fatalf("unexpected payload totals: %v", payload)
+	}
+	if payload["message"] != "Please deliver tomorrow" {
+		t.Fatalf("message = %v, want the buyer note", payload["message"])
+	}
+	if _, ok := payload["products"]; ok {
+		t.Fatal("products should be omitted without order details")
+	}
+	if _, ok := payload["token"]; ok {
+		t.Fatal("order token must not be forwarded")
+	}
+
+	details := &OrderDetails{Products: []OrderProduct{{ID: "7282471485125876", Quantity: 2}}}
+	payload = buildOrderPayload(order, details)
+	products, ok := payload["products"].([]OrderProduct)
+	if !ok || len(products) != 1 || products[0].Quantity != 2 {
+		t.Fatalf("unexpected products: %v", payload["products"])
+	}
+}
@@ -0,0 +1,147 @@
+package whatsapp
+
+import (
+	"context"
+
+	domainChatStorage "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/chatstorage"
+	"go.mau.fi/whatsmeow/appstate"
+	"go.mau.fi/whatsmeow/types"
+	"go.mau.fi/whatsmeow/types/events"
+)
+
+// recordContact stores what was learned about a user. jid and alt may be a
+// phone number JID or a LID in either order; a missing side is looked up in
+// the device's LID map so both addresses end up on the same contact.
+func recordContact(ctx context.Context, chatStorageRepo domainChatStorage.IChatStorageRepository, jid, alt types.JID, contact domainChatStorage.Contact) {
+	if chatStorageRepo == nil {
+		return
+	}
+
+	for _, address := range []types.JID{jid, alt} {
+		switch address.Server {
+		case types.DefaultUserServer:
+			contact.PhoneJID = address.ToNonAD().String()
+		case types.HiddenUserServer:
+			contact.LIDJID = address.ToNonAD().String()
+		}
+	}
+	if contact.PhoneJID == "" && contact.LIDJID == "" {
+		return
+	}
+
+	if (contact.PhoneJID == "" || contact.LIDJID == "") && cli != nil && cli.Store != nil {
+		if altJID, err := cli.Store.GetAltJID(ctx, jid.ToNonAD()); err != nil {
+			log.Debugf("Failed to resolve alternate address for %s: %v", jid, err)
+		} else if !altJID.IsEmpty() {
+			if altJID.Server == types.DefaultUserServer {
+				contact.PhoneJID = altJID.String()
+			} else {
+				contact.LIDJID = altJID.String()
+			}
+		}
+	}
+
+	contact.JID = contact.PhoneJID
+	if contact.JID == "" {
+		contact.JID = contact.LIDJID
+	}
+
+	if err := chatStorageRepo.StoreContact(&contact); err != nil {
+		log.Warnf("Failed to store contact %s: %v", contact.JID, err)
+	}
+}
+
+// recordMessageSender updates the sender's contact from an incoming message
+func recordMessageSender(ctx context.Context, evt *events.Message, chatStorageRepo domainChatStorage.IChatStorageRepository) {
+	if evt.Info.IsFromMe {
+		return
+	}
+
+	seen := evt.Info.Timestamp
+	contact := domainChatStorage.Contact{
+		PushName:  evt.Info.PushName,
+		FirstSeen: &seen,
+		LastSeen:  &seen,
+	}
+	if evt.Info.VerifiedName != nil && evt.Info.VerifiedName.Details != nil {
+		contact.BusinessName = evt.Info.VerifiedName.Details.GetVerifiedName()
+	}
+
+	recordContact(ctx, chatStorageRepo, evt.Info.Sender, evt.Info.SenderAlt, contact)
+}
+
+func handlePushName(ctx context.Context, evt *events.PushName, chatStorageRepo domainChatStorage.IChatStorageRepository) {
+	recordContact(ctx, chatStorageRepo, evt.JID, evt.JIDAlt, domainChatStorage.Contact{PushName: evt.NewPushName})
+}
+
+func handleBusinessName(ctx context.Context, evt *events.BusinessName, chatStorageRepo domainChatStorage.IChatStorageRepository) {
+	recordContact(ctx, chatStorageRepo, evt.JID, types.EmptyJID, domainChatStorage.Contact{BusinessName: evt.NewBusinessName})
+}
+
+// handleContact stores the name a contact was saved under in the phone's address book
+func handleContact(ctx context.Context, evt *events.Contact, chatStorageRepo domainChatStorage.IChatStorageRepository) {
+	savedName := evt.Action.GetFullName()
+	if savedName == "" {
+		savedName = evt.Action.GetFirstName()
+	}
+
+	alt := types.EmptyJID
+	for _, raw := range []string{evt.Action.GetPnJID(), evt.Action.GetLidJID()} {
+		if raw == "" {
+			continue
+		}
+		if parsed, err := types.ParseJID(raw); err == nil && parsed.User != evt.JID.User {
+			alt = parsed
+		}
+	}
+
+	recordContact(ctx, chatStorageRepo, evt.JID, alt, domainChatStorage.Contact{SavedName: savedName})
+}
+
+// syncStoredContacts seeds the contact store from the device's address book once
+// the app state that carries saved contacts has been synced
+func syncStoredContacts(ctx context.Context, evt *events.AppStateSyncComplete, chatStorageRepo domainChatStorage.IChatStorageRepository) {
+	if chatStorageRepo == nil || evt.Name != appstate.WAPatchCriticalUnblockLow {
+		return
+	}
+
+	contacts, err := cli.Store.Contacts.GetAllContacts(ctx)
+	if err != nil {
+		log.Warnf("Failed to load contacts from device store: %v", err)
+		return
+	}
+
+	for jid, info := range contacts {
+		recordContact(ctx, chatStorageRepo, jid, types.EmptyJID, domainChatStorage.Contact{
+			SavedName:    info.FullName,
+			PushName:     info.PushName,
+			BusinessName: info.BusinessName,
+		})
+	}
+	log.Infof("Synced %d contacts from device store", len(contacts))
+}
+
+// resolveContactName returns the best known name for a user, or an empty string
+func resolveContactName(chatStorageRepo domainChatStorage.IChatStorageRepository, jid types.JID) string {
+	if chatStorageRepo == nil || jid.IsEmpty() {
+		return ""
+	}
+
+	contact, err := chatStorageRepo.GetContact(jid.ToNonAD().String())
+	if err != nil || contact == nil {
+		return ""
+	}
+
+	return contact.DisplayName()
+}
+
+// resolveContactNames maps each JID with a known name to that name
+func resolveContactNames(chatStorageRepo domainChatStorage.IChatStorageRepository, jids []types.JID) map[string]string {
+	names := make(map[string]string)
+	for _, jid := range jids {
+		if name := resolveContactName(chatStorageRepo, jid); name != "" {
+			names[jid.String()] = name
+		}
+	}
+	return names
+}
@@ -7,13 +7,14 @@ import (
 	"time"
 
 	"github.com/aldinokemal/go-whatsapp-web-multidevice/config"
+	domainChatStorage "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/chatstorage"
 	"github.com/sirupsen/logrus"
 	"go.mau.fi/whatsmeow/types"
 	"go.mau.fi/whatsmeow/types/events"
 )
 
 // createGroupInfoPayload creates a webhook payload for group information events
-func createGroupInfoPayload(evt *events.GroupInfo, actionType string, jids []types.JID) map[string]any {
+func createGroupInfoPayload(evt *events.GroupInfo, actionType string, jids []types.JID, names map[string]string) map[string]any {
 	body := make(map[string]any)
 
 	// Create payload structure matching the expected format
@@ -25,6 +26,9 @@ func createGroupInfoPayload(evt *events.GroupInfo, actionType string, jids []typ
 	// Add action type and affected users
 	payload["type"] = actionType
 	payload["jids"] = jidsToStrings(jids)
+	if len(names) > 0 {
+		payload["names"] = names
+	}
 
 	// Wrap in payload structure
 	body["payload"] = payload
@@ -50,7 +54,7 @@ func jidsToStrings(jids []types.JID) []string {
 }
 
 // forwardGroupInfoToWebhook forwards group information events to the configured webhook URLs
-func forwardGroupInfoToWebhook(ctx context.Context, evt *events.GroupInfo) error {
+func forwardGroupInfoToWebhook(ctx context.Context, evt *events.GroupInfo, chatStorageRepo domainChatStorage.IChatStorageRepository) error {
 	logrus.Infof("Forwarding group info event to %d configured webhook(s)", len(config.WhatsappWebhook))
 
 	// Send separate webhook events for each action type
@@ -66,7 +70,7 @@ func forwardGroupInfoToWebhook(ctx context.Context, evt *events.GroupInfo) error
 
 	for _, action := range actions {
 		if len(action.jids) > 0 {
-			payload := createGroupInfoPayload(evt, action.actionType, action.jids)
+			payload := createGroupInfoPayload(evt, action.actionType, action.jids, resolveContactNames(chatStorageRepo, action.jids))
 
 			// Collect errors from all webhook URLs instead of failing fast
 			var errors []error
@@ -94,5 +98,40 @@ func forwardGroupInfoToWebhook(ctx context.Context, evt *events.GroupInfo) error
 		}
 	}
 
+	// Community link changes are reported as their own event
+	if evt.Link != nil {
+		if err := forwardPayloadToConfiguredWebhooks(ctx, createGroupLinkPayload(evt, "link", evt.Link), "community link event"); err != nil {
+			return err
+		}
+	}
+	if evt.Unlink != nil {
+		if err := forwardPayloadToConfiguredWebhooks(ctx, createGroupLinkPayload(evt, "unlink", evt.Unlink), "community unlink event"); err != nil {
+			return err
+		}
+	}
+
 	return nil
 }
+
+// createGroupLinkPayload creates a webhook payload for a community link or unlink.
+// chat_id is the group that received the event; link_type tells whether the other
+// side is its parent community (parent_group) or a sub-group (sub_group).
+func createGroupLinkPayload(evt *events.GroupInfo, actionType string, change *types.GroupLinkChange) map[string]any {
+	payload := map[string]any{
+		"chat_id":         evt.JID.String(),
+		"type":            actionType,
+		"link_type":       string(change.Type),
+		"group_id":        change.Group.JID.String(),
+		"group_name":      change.Group.Name,
+		"is_announcement": change.Group.IsDefaultSubGroup,
+	}
+	if change.UnlinkReason != "" {
+		payload["unlink_reason"] = string(change.UnlinkReason)
+	}
+
+	return map[string]any{
+		"payload":   payload,
+		"event":     "community.groups",
+		"timestamp": evt.Timestamp.Format(time.RFC3339),
+	}
+}
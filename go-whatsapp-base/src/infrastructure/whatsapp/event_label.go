@@ -0,0 +1,58 @@
+package whatsapp
+
+import (
+	"context"
+
+	domainChatStorage "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/chatstorage"
+	"go.mau.fi/whatsmeow/types/events"
+)
+
+// handleLabelEdit stores a label created, edited or deleted on any device
+func handleLabelEdit(_ context.Context, evt *events.LabelEdit, chatStorageRepo domainChatStorage.IChatStorageRepository) {
+	if chatStorageRepo == nil || evt.Action == nil {
+		return
+	}
+
+	label, err := chatStorageRepo.GetLabel(evt.LabelID)
+	if err != nil {
+		log.Errorf("Failed to get label %s: %v", evt.LabelID, err)
+		return
+	}
+	if label == nil {
+		label = &domainChatStorage.Label{ID: evt.LabelID}
+	}
+
+	label.Name = evt.Action.GetName()
+	label.Color = evt.Action.GetColor()
+	label.Deleted = evt.Action.GetDeleted()
+
+	if err := chatStorageRepo.StoreLabel(label); err != nil {
+		log.Errorf("Failed to store label %s: %v", evt.LabelID, err)
+		return
+	}
+	if !evt.FromFullSync {
+		log.Infof("Label %s (%s) updated, deleted: %t", label.ID, label.Name, label.Deleted)
+	}
+}
+
+// handleLabelAssociationChat stores a chat labeled or unlabeled on any device
+func handleLabelAssociationChat(_ context.Context, evt *events.LabelAssociationChat, chatStorageRepo domainChatStorage.IChatStorageRepository) {
+	if chatStorageRepo == nil || evt.Action == nil {
+		return
+	}
+
+	if err := chatStorageRepo.SetChatLabel(evt.LabelID, evt.JID.String(), evt.Action.GetLabeled()); err != nil {
+		log.Errorf("Failed to store label %s on chat %s: %v", evt.LabelID, evt.JID.String(), err)
+	}
+}
+
+// handleLabelAssociationMessage stores a message labeled or unlabeled on any device
+func handleLabelAssociationMessage(_ context.Context, evt *events.LabelAssociationMessage, chatStorageRepo domainChatStorage.IChatStorageRepository) {
+	if chatStorageRepo == nil || evt.Action == nil {
+		return
+	}
+
+	if err := chatStorageRepo.SetMessageLabel(evt.LabelID, evt.JID.String(), evt.MessageID, evt.Action.GetLabeled()); err != nil {
+		log.Errorf("Failed to store label %s on message %s: %v", evt.LabelID, evt.MessageID, err)
+	}
+}
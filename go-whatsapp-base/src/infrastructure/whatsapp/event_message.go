@@ -10,6 +10,7 @@ import (
 	"go.mau.fi/whatsmeow/types"
 
 	"github.com/aldinokemal/go-whatsapp-web-multidevice/config"
+	domainChatStorage "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/chatstorage"
 	pkgError "github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/error"
 	"github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/utils"
 	"github.com/sirupsen/logrus"
@@ -17,8 +18,8 @@ import (
 )
 
 // forwardMessageToWebhook is a helper function to forward message event to webhook url
-func forwardMessageToWebhook(ctx context.Context, evt *events.Message) error {
-	payload, err := createMessagePayload(ctx, evt)
+func forwardMessageToWebhook(ctx context.Context, evt *events.Message, chatStorageRepo domainChatStorage.IChatStorageRepository) error {
+	payload, err := createMessagePayload(ctx, evt, chatStorageRepo)
 	if err != nil {
 		return err
 	}
@@ -26,7 +27,7 @@ func forwardMessageToWebhook(ctx context.Context, evt *events.Message) error {
 	return forwardPayloadToConfiguredWebhooks(ctx, payload, "message event")
 }
 
-func createMessagePayload(ctx context.Context, evt *events.Message) (map[string]any, error) {
+func createMessagePayload(ctx context.Context, evt *events.Message, chatStorageRepo domainChatStorage.IChatStorageRepository) (map[string]any, error) {
 	message := utils.BuildEventMessage(evt)
 	waReaction := utils.BuildEventReaction(evt)
 	forwarded := utils.BuildForwarded(evt)
@@ -90,12 +91,21 @@ func createMessagePayload(ctx context.Context, evt *events.Message) (map[string]
 	if pushname := evt.Info.PushName; pushname != "" {
 		body["pushname"] = pushname
 	}
+	if senderName := resolveContactName(chatStorageRepo, evt.Info.Sender); senderName != "" {
+		body["sender_name"] = senderName
+	}
 	if waReaction.Message != "" {
 		body["reaction"] = waReaction
 	}
-	if evt.IsViewOnce {
-		body["view_once"] = evt.IsViewOnce
+	if reply := utils.ExtractInteractiveReply(evt.Message); reply != nil {
+		body["interactive_reply"] = reply
+	}
+	// View-once media is never downloaded, so it is not kept against the sender's intent
+	viewOnce := evt.IsViewOnce || utils.IsViewOnceMedia(evt.Message)
+	if viewOnce {
+		body["view_once"] = true
 	}
+	autoDownload := config.WhatsappAutoDownloadMedia && !viewOnce
 	if forwarded {
 		body["forwarded"] = forwarded
 	}
@@ -134,7 +144,7 @@ func createMessagePayload(ctx context.Context, evt *events.Message) (map[string]
 	}
 
 	if audioMedia := evt.Message.GetAudioMessage(); audioMedia != nil {
-		if config.WhatsappAutoDownloadMedia {
+		if autoDownload {
 			path, err := utils.ExtractMedia(ctx, cli, config.PathMedia, audioMedia)
 			if err != nil {
 				logrus.Errorf("Failed to download audio from %s: %v", evt.Info.SourceString(), err)
@@ -153,7 +163,7 @@ func createMessagePayload(ctx context.Context, evt *events.Message) (map[string]
 	}
 
 	if documentMedia := evt.Message.GetDocumentMessage(); documentMedia != nil {
-		if config.WhatsappAutoDownloadMedia {
+		if autoDownload {
 			path, err := utils.ExtractMedia(ctx, cli, config.PathMedia, documentMedia)
 			if err != nil {
 				logrus.Errorf("Failed to download document from %s: %v", evt.Info.SourceString(), err)
@@ -169,7 +179,7 @@ func createMessagePayload(ctx context.Context, evt *events.Message) (map[string]
 	}
 
 	if imageMedia := evt.Message.GetImageMessage(); imageMedia != nil {
-		if config.WhatsappAutoDownloadMedia {
+		if autoDownload {
 			path, err := utils.ExtractMedia(ctx, cli, config.PathMedia, imageMedia)
 			if err != nil {
 				logrus.Errorf("Failed to download image from %s: %v", evt.Info.SourceString(), err)
@@ -197,11 +207,11 @@ func createMessagePayload(ctx context.Context, evt *events.Message) (map[string]
 	}
 
 	if orderMessage := evt.Message.GetOrderMessage(); orderMessage != nil {
-		body["order"] = orderMessage
+		body["order"] = createOrderPayload(ctx, cli, orderMessage)
 	}
 
 	if stickerMedia := evt.Message.GetStickerMessage(); stickerMedia != nil {
-		if config.WhatsappAutoDownloadMedia {
+		if autoDownload {
 			path, err := utils.ExtractMedia(ctx, cli, config.PathMedia, stickerMedia)
 			if err != nil {
 				logrus.Errorf("Failed to download sticker from %s: %v", evt.Info.SourceString(), err)
@@ -216,7 +226,7 @@ func createMessagePayload(ctx context.Context, evt *events.Message) (map[string]
 	}
 
 	if videoMedia := evt.Message.GetVideoMessage(); videoMedia != nil {
-		if config.WhatsappAutoDownloadMedia {
+		if autoDownload {
 			path, err := utils.ExtractMedia(ctx, cli, config.PathMedia, videoMedia)
 			if err != nil {
 				logrus.Errorf("Failed to download video from %s: %v", evt.Info.SourceString(), err)
@@ -0,0 +1,68 @@
+package whatsapp
+
+import (
+	"context"
+	"strings"
+
+	"go.mau.fi/whatsmeow"
+	"go.mau.fi/whatsmeow/proto/waE2E"
+	"go.mau.fi/whatsmeow/types"
+)
+
+// createOrderPayload turns an order placed from a catalog into the order
+// object of the message webhook. When the order was placed with us, the
+// ordered products are fetched from the server; the payload is still sent
+// without them if that fails.
+func createOrderPayload(ctx context.Context, client *whatsmeow.Client, order *waE2E.OrderMessage) map[string]any {
+	var details *OrderDetails
+	if order.GetToken() != "" && isOwnJID(client, order.GetSellerJID()) {
+		var err error
+		details, err = GetOrderDetails(ctx, client, order.GetOrderID(), order.GetToken())
+		if err != nil {
+			log.Warnf("Failed to get details of order %s: %v", order.GetOrderID(), err)
+		}
+	}
+	return buildOrderPayload(order, details)
+}
+
+func buildOrderPayload(order *waE2E.OrderMessage, details *OrderDetails) map[string]any {
+	payload := map[string]any{
+		"order_id":          order.GetOrderID(),
+		"title":             order.GetOrderTitle(),
+		"item_count":        order.GetItemCount(),
+		"status":            strings.ToLower(order.GetStatus().String()),
+		"seller_jid":        order.GetSellerJID(),
+		"currency":          order.GetTotalCurrencyCode(),
+		"total_amount_1000": order.GetTotalAmount1000(),
+		"total":             float64(order.GetTotalAmount1000()) / 1000,
+	}
+	if order.Message != nil {
+		payload["message"] = order.GetMessage()
+	}
+	if order.Surface != nil {
+		payload["surface"] = strings.ToLower(order.GetSurface().String())
+	}
+	if order.CatalogType != nil {
+		payload["catalog_type"] = order.GetCatalogType()
+	}
+	if details != nil {
+		payload["products"] = details.Products
+		if order.TotalCurrencyCode == nil && details.Currency != "" {
+			payload["currency"] = details.Currency
+			payload["total_amount_1000"] = details.TotalAmount1000
+			payload["total"] = details.Total
+		}
+	}
+	return payload
+}
+
+func isOwnJID(client *whatsmeow.Client, jid string) bool {
+	if client == nil || client.Store == nil || client.Store.ID == nil {
+		return false
+	}
+	parsed, err := types.ParseJID(jid)
+	if err != nil {
+		return false
+	}
+	return parsed.User == client.Store.ID.User || (!client.Store.LID.IsEmpty() && parsed.User == client.Store.LID.User)
+}
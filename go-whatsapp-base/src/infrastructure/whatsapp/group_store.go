@@ -0,0 +1,164 @@
+package whatsapp
+
+import (
+	"context"
+	"time"
+
+	domainChatStorage "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/chatstorage"
+	"go.mau.fi/whatsmeow/types"
+	"go.mau.fi/whatsmeow/types/events"
+)
+
+// SyncGroupInfo stores a full group snapshot, such as one returned by
+// GetJoinedGroups. Members that differ from the stored list are recorded in
+// the membership log as sync entries.
+func SyncGroupInfo(ctx context.Context, chatStorageRepo domainChatStorage.IChatStorageRepository, info *types.GroupInfo) {
+	if chatStorageRepo == nil || info == nil {
+		return
+	}
+
+	group := &domainChatStorage.Group{
+		JID:         info.JID.String(),
+		Name:        info.Name,
+		Topic:       info.Topic,
+		OwnerJID:    groupMemberJID(ctx, info.OwnerJID, info.OwnerPN),
+		IsAnnounce:  info.IsAnnounce,
+		IsLocked:    info.IsLocked,
+		IsCommunity: info.IsParent,
+	}
+	if !info.GroupCreated.IsZero() {
+		created := info.GroupCreated
+		group.GroupCreatedAt = &created
+	}
+	if existing, err := chatStorageRepo.GetGroup(group.JID); err == nil && existing != nil {
+		group.CreatedAt = existing.CreatedAt
+	}
+	if err := chatStorageRepo.StoreGroup(group); err != nil {
+		log.Warnf("Failed to store group %s: %v", group.JID, err)
+		return
+	}
+
+	participants := make([]*domainChatStorage.GroupParticipant, 0, len(info.Participants))
+	for _, participant := range info.Participants {
+		role := domainChatStorage.GroupRoleMember
+		if participant.IsSuperAdmin {
+			role = domainChatStorage.GroupRoleSuperAdmin
+		} else if participant.IsAdmin {
+			role = domainChatStorage.GroupRoleAdmin
+		}
+		participants = append(participants, &domainChatStorage.GroupParticipant{
+			GroupJID:       group.JID,
+			ParticipantJID: groupMemberJID(ctx, participant.JID, participant.PhoneNumber),
+			Role:           role,
+		})
+	}
+
+	changes, err := chatStorageRepo.SyncGroupParticipants(group.JID, participants, time.Now())
+	if err != nil {
+		log.Warnf("Failed to sync participants of group %s: %v", group.JID, err)
+		return
+	}
+	if len(changes) > 0 {
+		log.Debugf("Group %s: recorded %d membership changes from snapshot", group.JID, len(changes))
+	}
+}
+
+// recordGroupInfo applies a group notification to the stored metadata and
+// appends its membership changes to the log
+func recordGroupInfo(ctx context.Context, evt *events.GroupInfo, chatStorageRepo domainChatStorage.IChatStorageRepository) {
+	if chatStorageRepo == nil {
+		return
+	}
+
+	groupJID := evt.JID.String()
+	if evt.Name != nil || evt.Topic != nil || evt.Locked != nil || evt.Announce != nil {
+		group, err := chatStorageRepo.GetGroup(groupJID)
+		if err != nil {
+			log.Warnf("Failed to load group %s: %v", groupJID, err)
+			return
+		}
+		if group == nil {
+			group = &domainChatStorage.Group{JID: groupJID}
+		}
+		if evt.Name != nil {
+			group.Name = evt.Name.Name
+		}
+		if evt.Topic != nil {
+			group.Topic = evt.Topic.Topic
+		}
+		if evt.Locked != nil {
+			group.IsLocked = evt.Locked.IsLocked
+		}
+		if evt.Announce != nil {
+			group.IsAnnounce = evt.Announce.IsAnnounce
+		}
+		if err := chatStorageRepo.StoreGroup(group); err != nil {
+			log.Warnf("Failed to store group %s: %v", groupJID, err)
+		}
+	}
+
+	var actorJID string
+	if evt.Sender != nil {
+		var senderPN types.JID
+		if evt.SenderPN != nil {
+			senderPN = *evt.SenderPN
+		}
+		actorJID = groupMemberJID(ctx, *evt.Sender, senderPN)
+	}
+
+	timestamp := evt.Timestamp
+	if timestamp.IsZero() {
+		timestamp = time.Now()
+	}
+
+	var membershipEvents []*domainChatStorage.GroupMembershipEvent
+	for _, change := range []struct {
+		action string
+		role   string
+		jids   []types.JID
+	}{
+		{domainChatStorage.GroupActionJoin, domainChatStorage.GroupRoleMember, evt.Join},
+		{domainChatStorage.GroupActionLeave, domainChatStorage.GroupRoleMember, evt.Leave},
+		{domainChatStorage.GroupActionPromote, domainChatStorage.GroupRoleAdmin, evt.Promote},
+		{domainChatStorage.GroupActionDemote, domainChatStorage.GroupRoleMember, evt.Demote},
+	} {
+		for _, jid := range change.jids {
+			event := &domainChatStorage.GroupMembershipEvent{
+				GroupJID:       groupJID,
+				ParticipantJID: groupMemberJID(ctx, jid, types.EmptyJID),
+				Action:         change.action,
+				Role:           change.role,
+				ActorJID:       actorJID,
+				Source:         domainChatStorage.GroupEventSourceEvent,
+				Timestamp:      timestamp,
+			}
+			if change.action == domainChatStorage.GroupActionJoin {
+				event.Reason = evt.JoinReason
+			}
+			membershipEvents = append(membershipEvents, event)
+		}
+	}
+
+	if err := chatStorageRepo.StoreGroupMembershipEvents(membershipEvents); err != nil {
+		log.Warnf("Failed to store membership changes of group %s: %v", groupJID, err)
+	}
+}
+
+// groupMemberJID returns the phone number JID of a member when it is known, so
+// the same person is stored under one address whichever one WhatsApp used
+func groupMemberJID(ctx context.Context, jid, phoneJID types.JID) string {
+	if !phoneJID.IsEmpty() {
+		return phoneJID.ToNonAD().String()
+	}
+	if jid.IsEmpty() {
+		return ""
+	}
+
+	jid = jid.ToNonAD()
+	if jid.Server == types.HiddenUserServer && cli != nil && cli.Store != nil {
+		if pn, err := cli.Store.LIDs.GetPNForLID(ctx, jid); err == nil && !pn.IsEmpty() {
+			return pn.String()
+		}
+	}
+	return jid.String()
+}
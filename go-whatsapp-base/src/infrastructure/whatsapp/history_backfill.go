@@ -0,0 +1,105 @@
+package whatsapp
+
+import (
+	"context"
+	"time"
+
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/config"
+	domainChatStorage "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/chatstorage"
+	"github.com/sirupsen/logrus"
+	"go.mau.fi/whatsmeow/proto/waHistorySync"
+)
+
+// processOnDemandHistory stores an on-demand history sync and completes the
+// pending backfill of every chat it contains
+func processOnDemandHistory(ctx context.Context, data *waHistorySync.HistorySync, chatStorageRepo domainChatStorage.IChatStorageRepository) error {
+	conversations := data.GetConversations()
+	log.Infof("Processing %d conversations from on-demand history sync", len(conversations))
+
+	// Messages are upserted by ID, so the count difference is what was new
+	before := make(map[string]int64, len(conversations))
+	for _, conv := range conversations {
+		if chatJID := conv.GetID(); chatJID != "" {
+			count, err := chatStorageRepo.GetChatMessageCount(chatJID)
+			if err != nil {
+				log.Warnf("Failed to count messages for chat %s: %v", chatJID, err)
+			}
+			before[chatJID] = count
+		}
+	}
+
+	if err := processConversationMessages(ctx, data, chatStorageRepo); err != nil {
+		return err
+	}
+
+	for _, conv := range conversations {
+		chatJID := conv.GetID()
+		if chatJID == "" {
+			continue
+		}
+
+		after, err := chatStorageRepo.GetChatMessageCount(chatJID)
+		if err != nil {
+			log.Warnf("Failed to count messages for chat %s: %v", chatJID, err)
+			after = before[chatJID]
+		}
+
+		completeChatBackfill(ctx, chatStorageRepo, chatJID, len(conv.GetMessages()), int(after-before[chatJID]))
+	}
+
+	return nil
+}
+
+// completeChatBackfill records the result of a pending backfill and notifies webhooks
+func completeChatBackfill(ctx context.Context, chatStorageRepo domainChatStorage.IChatStorageRepository, chatJID string, received, stored int) {
+	backfill, err := chatStorageRepo.GetChatBackfill(chatJID)
+	if err != nil {
+		log.Warnf("Failed to load backfill for chat %s: %v", chatJID, err)
+		return
+	}
+	if backfill == nil || backfill.Status != domainChatStorage.BackfillStatusPending {
+		return
+	}
+
+	if stored < 0 {
+		stored = 0
+	}
+	completedAt := time.Now()
+	backfill.Status = domainChatStorage.BackfillStatusCompleted
+	backfill.ReceivedMessages = received
+	backfill.StoredMessages = stored
+	backfill.TotalStored += stored
+	backfill.CompletedAt = &completedAt
+
+	if err := chatStorageRepo.StoreChatBackfill(backfill); err != nil {
+		log.Warnf("Failed to store backfill for chat %s: %v", chatJID, err)
+		return
+	}
+
+	log.Infof("Backfill for chat %s completed: %d received, %d new", chatJID, received, stored)
+
+	if len(config.WhatsappWebhook) > 0 {
+		go func(b domainChatStorage.ChatBackfill) {
+			if err := forwardPayloadToConfiguredWebhooks(ctx, createBackfillPayload(&b), "chat backfill event"); err != nil {
+				logrus.Errorf("Failed to forward chat backfill event to webhook: %v", err)
+			}
+		}(*backfill)
+	}
+}
+
+// createBackfillPayload creates a webhook payload for a completed chat backfill
+func createBackfillPayload(backfill *domainChatStorage.ChatBackfill) map[string]any {
+	return map[string]any{
+		"event": "chat.backfill",
+		"payload": map[string]any{
+			"chat_id":           backfill.ChatJID,
+			"status":            backfill.Status,
+			"requested_count":   backfill.RequestedCount,
+			"anchor_message_id": backfill.AnchorMessageID,
+			"received_messages": backfill.ReceivedMessages,
+			"stored_messages":   backfill.StoredMessages,
+			"total_stored":      backfill.TotalStored,
+		},
+		"timestamp": backfill.CompletedAt.Format(time.RFC3339),
+	}
+}
@@ -0,0 +1,37 @@
+package whatsapp
+
+import (
+	"testing"
+	"time"
+
+	domainChatStorage "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/chatstorage"
+)
+
+func TestCreateBackfillPayload(t *testing.T) {
+	completedAt := time.Date(2025, 7, 28, 10, 30, 0, 0, time.UTC)
+	payload := createBackfillPayload(&domainChatStorage.ChatBackfill{
+		ChatJID:          "6289685028129@s.whatsapp.net",
+		Status:           domainChatStorage.BackfillStatusCompleted,
+		RequestedCount:   50,
+		AnchorMessageID:  "3EB0B430B6F8F1D0E053AC120E0A9E5C",
+		ReceivedMessages: 50,
+		StoredMessages:   48,
+		TotalStored:      98,
+		CompletedAt:      &completedAt,
+	})
+
+	if payload["event"] != "chat.backfill" {
+		t.Fatalf("event = %v, want chat.backfill", payload["event"])
+	}
+	if payload["timestamp"] != "2025-07-28T10:30:00Z" {
+		t.Fatalf("timestamp = %v, want 2025-07-28T10:30:00Z", payload["timestamp"])
+	}
+
+	body, ok := payload["payload"].(map[string]any)
+	if !ok {
+		t.Fatalf("payload has unexpected type %T", payload["payload"])
+	}
+	if body["chat_id"] != "6289685028129@s.whatsapp.net" || body["stored_messages"] != 48 || body["total_stored"] != 98 {
+		t.Fatalf("unexpected payload body: %v", body)
+	}
+}
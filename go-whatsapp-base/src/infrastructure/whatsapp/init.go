@@ -2,11 +2,13 @@ package whatsapp
 
 import (
 	"context"
+	"database/sql"
 	"encoding/json"
 	"fmt"
 	"os"
 	"path/filepath"
 	"strings"
+	"sync"
 	"sync/atomic"
 	"time"
 
@@ -20,13 +22,11 @@ import (
 	"github.com/sirupsen/logrus"
 	"go.mau.fi/whatsmeow"
 	"go.mau.fi/whatsmeow/appstate"
-	"go.mau.fi/whatsmeow/proto/waE2E"
 	"go.mau.fi/whatsmeow/store"
 	"go.mau.fi/whatsmeow/store/sqlstore"
 	"go.mau.fi/whatsmeow/types"
 	"go.mau.fi/whatsmeow/types/events"
 	waLog "go.mau.fi/whatsmeow/util/log"
-	"google.golang.org/protobuf/proto"
 )
 
 // Type definitions
@@ -44,6 +44,10 @@ var (
 	log           waLog.Logger
 	historySyncID int32
 	startupTime   = time.Now().Unix()
+
+	// storeDBs keeps the connections behind the store containers by URI
+	storeDBs   = map[string]*sql.DB{}
+	storeDBsMu sync.Mutex
 )
 
 // InitWaDB initializes the WhatsApp database connection
@@ -62,13 +66,52 @@ func InitWaDB(ctx context.Context, DBURI string) *sqlstore.Container {
 
 // initDatabase creates and returns a database store container based on the configured URI
 func initDatabase(ctx context.Context, dbLog waLog.Logger, DBURI string) (*sqlstore.Container, error) {
+	var dialect string
 	if strings.HasPrefix(DBURI, "file:") {
-		return sqlstore.New(ctx, "sqlite3", DBURI, dbLog)
+		dialect = "sqlite3"
 	} else if strings.HasPrefix(DBURI, "postgres:") {
-		return sqlstore.New(ctx, "postgres", DBURI, dbLog)
+		dialect = "postgres"
+	} else {
+		return nil, fmt.Errorf("unknown database type: %s. Currently only sqlite3(file:) and postgres are supported", DBURI)
 	}
 
-	return nil, fmt.Errorf("unknown database type: %s. Currently only sqlite3(file:) and postgres are supported", DBURI)
+	// Open the connection ourselves so the health checks can probe it, the
+	// container does not expose its handle
+	sqlDB, err := sql.Open(dialect, DBURI)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open database: %w", err)
+	}
+	container := sqlstore.NewWithDB(sqlDB, dialect, dbLog)
+	if err := container.Upgrade(ctx); err != nil {
+		return nil, fmt.Errorf("failed to upgrade database: %w", err)
+	}
+
+	storeDBsMu.Lock()
+	storeDBs[DBURI] = sqlDB
+	storeDBsMu.Unlock()
+	return container, nil
+}
+
+// CheckStoreWritable verifies the device or keys database opened for the URI
+// accepts writes, using a transaction that is rolled back
+func CheckStoreWritable(ctx context.Context, DBURI string) error {
+	storeDBsMu.Lock()
+	sqlDB := storeDBs[DBURI]
+	storeDBsMu.Unlock()
+	if sqlDB == nil {
+		return fmt.Errorf("database is not initialized")
+	}
+
+	tx, err := sqlDB.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx, "UPDATE whatsmeow_version SET version = version"); err != nil {
+		return fmt.Errorf("failed to write to database: %w", err)
+	}
+	return nil
 }
 
 func syncKeysDevice(ctx context.Context, db, keysDB *sqlstore.Container) {
@@ -139,6 +182,9 @@ func InitWaCLI(ctx context.Context, storeContainer, keysStoreContainer *sqlstore
 	cli = whatsmeow.NewClient(device, waLog.Stdout("Client", config.WhatsappLogLevel, true))
 	cli.EnableAutoReconnect = true
 	cli.AutoTrustIdentity = true
+	// Labels only arrive as app state events, and the ones made before pairing
+	// only in the initial full sync
+	cli.EmitAppStateEventsOnFullSync = true
 
 	cli.AddEventHandler(func(rawEvt interface{}) {
 		handler(ctx, rawEvt, chatStorageRepo)
@@ -447,11 +493,15 @@ func handler(ctx context.Context, rawEvt any, chatStorageRepo domainChatStorage.
 		handleDeleteForMe(ctx, evt, chatStorageRepo)
 	case *events.AppStateSyncComplete:
 		handleAppStateSyncComplete(ctx, evt)
+		syncStoredContacts(ctx, evt, chatStorageRepo)
 	case *events.PairSuccess:
 		handlePairSuccess(ctx, evt)
 	case *events.LoggedOut:
 		handleLoggedOut(ctx, chatStorageRepo)
-	case *events.Connected, *events.PushNameSetting:
+	case *events.Connected:
+		handleConnectionEvents(ctx)
+		go flushSendQueueOnConnect(ctx, chatStorageRepo)
+	case *events.PushNameSetting:
 		handleConnectionEvents(ctx)
 	case *events.StreamReplaced:
 		handleStreamReplaced(ctx)
@@ -466,13 +516,32 @@ func handler(ctx context.Context, rawEvt any, chatStorageRepo domainChatStorage.
 	case *events.AppState:
 		handleAppState(ctx, evt)
 	case *events.GroupInfo:
-		handleGroupInfo(ctx, evt)
+		handleGroupInfo(ctx, evt, chatStorageRepo)
+	case *events.JoinedGroup:
+		SyncGroupInfo(ctx, chatStorageRepo, &evt.GroupInfo)
+	case *events.PushName:
+		handlePushName(ctx, evt, chatStorageRepo)
+	case *events.BusinessName:
+		handleBusinessName(ctx, evt, chatStorageRepo)
+	case *events.Contact:
+		handleContact(ctx, evt, chatStorageRepo)
+	case *events.LabelEdit:
+		handleLabelEdit(ctx, evt, chatStorageRepo)
+	case *events.LabelAssociationChat:
+		handleLabelAssociationChat(ctx, evt, chatStorageRepo)
+	case *events.LabelAssociationMessage:
+		handleLabelAssociationMessage(ctx, evt, chatStorageRepo)
 	}
 }
 
 // Event handler functions
 
 func handleDeleteForMe(ctx context.Context, evt *events.DeleteForMe, chatStorageRepo domainChatStorage.IChatStorageRepository) {
+	// A full sync replays deletions that happened before pairing
+	if evt.FromFullSync {
+		return
+	}
+
 	log.Infof("Deleted message %s for %s", evt.MessageID, evt.SenderJID.String())
 
 	// Find the message to get its chat JID
@@ -569,6 +638,9 @@ func handleMessage(ctx context.Context, evt *events.Message, chatStorageRepo dom
 		log.Errorf("Failed to store incoming message %s: %v", evt.Info.ID, err)
 	}
 
+	// Keep the sender's contact details current
+	recordMessageSender(ctx, evt, chatStorageRepo)
+
 	// Handle image message if present
 	handleImageMessage(ctx, evt)
 
@@ -579,7 +651,7 @@ func handleMessage(ctx context.Context, evt *events.Message, chatStorageRepo dom
 	handleAutoReply(ctx, evt, chatStorageRepo)
 
 	// Forward to webhook if configured
-	handleWebhookForward(ctx, evt)
+	handleWebhookForward(ctx, evt, chatStorageRepo)
 }
 
 func buildMessageMetaParts(evt *events.Message) []string {
@@ -600,7 +672,7 @@ func buildMessageMetaParts(evt *events.Message) []string {
 }
 
 func handleImageMessage(ctx context.Context, evt *events.Message) {
-	if !config.WhatsappAutoDownloadMedia {
+	if !config.WhatsappAutoDownloadMedia || evt.IsViewOnce || utils.IsViewOnceMedia(evt.Message) {
 		return
 	}
 	if img := evt.Message.GetImageMessage(); img != nil {
@@ -631,113 +703,7 @@ func handleAutoMarkRead(_ context.Context, evt *events.Message) {
 	}
 }
 
-func handleAutoReply(ctx context.Context, evt *events.Message, chatStorageRepo domainChatStorage.IChatStorageRepository) {
-	if config.WhatsappAutoReplyMessage == "" {
-		return
-	}
-
-	// Skip groups, broadcasts, and self messages
-	if utils.IsGroupJID(evt.Info.Chat.String()) || evt.Info.IsIncomingBroadcast() || evt.Info.IsFromMe {
-		return
-	}
-
-	// Only reply to direct 1:1 chats (e.g., *@s.whatsapp.net)
-	if evt.Info.Chat.Server != types.DefaultUserServer {
-		return
-	}
-
-	// Extra safety: skip any broadcast/status contexts
-	source := evt.Info.SourceString()
-	if strings.Contains(source, "broadcast") ||
-		strings.HasSuffix(evt.Info.Chat.String(), "@broadcast") ||
-		strings.HasPrefix(evt.Info.Chat.String(), "status@") {
-		return
-	}
-
-	// Require actual typed text (not captions or synthetic labels)
-	hasText := false
-
-	// Unwrap FutureProof wrappers to access the inner message content first
-	innerMsg := evt.Message
-	for i := 0; i < 3; i++ { // safeguard against excessively nested wrappers
-		if vm := innerMsg.GetViewOnceMessage(); vm != nil && vm.GetMessage() != nil {
-			innerMsg = vm.GetMessage()
-			continue
-		}
-		if em := innerMsg.GetEphemeralMessage(); em != nil && em.GetMessage() != nil {
-			innerMsg = em.GetMessage()
-			continue
-		}
-		if vm2 := innerMsg.GetViewOnceMessageV2(); vm2 != nil && vm2.GetMessage() != nil {
-			innerMsg = vm2.GetMessage()
-			continue
-		}
-		if vm2e := innerMsg.GetViewOnceMessageV2Extension(); vm2e != nil && vm2e.GetMessage() != nil {
-			innerMsg = vm2e.GetMessage()
-			continue
-		}
-		break
-	}
-
-	// Check for genuine typed text on the unwrapped content
-	if conv := innerMsg.GetConversation(); conv != "" {
-		hasText = true
-	} else if ext := innerMsg.GetExtendedTextMessage(); ext != nil && ext.GetText() != "" {
-		hasText = true
-	} else if protoMsg := innerMsg.GetProtocolMessage(); protoMsg != nil {
-		if edited := protoMsg.GetEditedMessage(); edited != nil {
-			if ext := edited.GetExtendedTextMessage(); ext != nil && ext.GetText() != "" {
-				hasText = true
-			} else if conv := edited.GetConversation(); conv != "" {
-				hasText = true
-			}
-		}
-	}
-	if !hasText {
-		return
-	}
-
-	// Format recipient JID
-	recipientJID := utils.FormatJID(evt.Info.Sender.String())
-
-	// Send the auto-reply message
-	response, err := cli.SendMessage(
-		ctx,
-		recipientJID,
-		&waE2E.Message{Conversation: proto.String(config.WhatsappAutoReplyMessage)},
-	)
-
-	if err != nil {
-		log.Errorf("Failed to send auto-reply message: %v", err)
-		return
-	}
-
-	// Store the auto-reply message in chat storage if send was successful
-	if chatStorageRepo != nil {
-		// Get our own JID as sender
-		senderJID := ""
-		if cli.Store.ID != nil {
-			senderJID = cli.Store.ID.String()
-		}
-
-		// Store the sent auto-reply message
-		if err := chatStorageRepo.StoreSentMessageWithContext(
-			ctx,
-			response.ID,                     // Message ID from WhatsApp response
-			senderJID,                       // Our JID as sender
-			recipientJID.String(),           // Recipient JID
-			config.WhatsappAutoReplyMessage, // Auto-reply content
-			response.Timestamp,              // Timestamp from response
-		); err != nil {
-			// Log storage error but don't fail the auto-reply
-			log.Errorf("Failed to store auto-reply message in chat storage: %v", err)
-		} else {
-			log.Debugf("Auto-reply message %s stored successfully in chat storage", response.ID)
-		}
-	}
-}
-
-func handleWebhookForward(ctx context.Context, evt *events.Message) {
+func handleWebhookForward(ctx context.Context, evt *events.Message, chatStorageRepo domainChatStorage.IChatStorageRepository) {
 	// Skip webhook for specific protocol messages that shouldn't trigger webhooks
 	if protocolMessage := evt.Message.GetProtocolMessage(); protocolMessage != nil {
 		protocolType := protocolMessage.GetType().String()
@@ -751,7 +717,7 @@ func handleWebhookForward(ctx context.Context, evt *events.Message) {
 	if len(config.WhatsappWebhook) > 0 &&
 		!strings.Contains(evt.Info.SourceString(), "broadcast") {
 		go func(evt *events.Message) {
-			if err := forwardMessageToWebhook(ctx, evt); err != nil {
+			if err := forwardMessageToWebhook(ctx, evt, chatStorageRepo); err != nil {
 				logrus.Error("Failed forward to webhook: ", err)
 			}
 		}(evt)
@@ -793,6 +759,24 @@ func handlePresence(_ context.Context, evt *events.Presence) {
 }
 
 func handleHistorySync(ctx context.Context, evt *events.HistorySync, chatStorageRepo domainChatStorage.IChatStorageRepository) {
+	if config.WhatsappHistorySyncDump {
+		writeHistorySyncDump(evt)
+	}
+
+	// Process history sync data to database
+	if chatStorageRepo != nil {
+		if err := processHistorySync(ctx, evt.Data, chatStorageRepo); err != nil {
+			log.Errorf("Failed to process history sync to database: %v", err)
+		}
+	}
+}
+
+func handleAppState(_ context.Context, evt *events.AppState) {
+	log.Debugf("App state event: %+v / %+v", evt.Index, evt.SyncActionValue)
+}
+
+// writeHistorySyncDump writes the raw history sync to PathStorages as JSON
+func writeHistorySyncDump(evt *events.HistorySync) {
 	id := atomic.AddInt32(&historySyncID, 1)
 	fileName := fmt.Sprintf("%s/history-%d-%s-%d-%s.json",
 		config.PathStorages,
@@ -817,17 +801,6 @@ func handleHistorySync(ctx context.Context, evt *events.HistorySync, chatStorage
 	}
 
 	log.Infof("Wrote history sync to %s", fileName)
-
-	// Process history sync data to database
-	if chatStorageRepo != nil {
-		if err := processHistorySync(ctx, evt.Data, chatStorageRepo); err != nil {
-			log.Errorf("Failed to process history sync to database: %v", err)
-		}
-	}
-}
-
-func handleAppState(_ context.Context, evt *events.AppState) {
-	log.Debugf("App state event: %+v / %+v", evt.Index, evt.SyncActionValue)
 }
 
 // processHistorySync processes history sync data and stores messages in the database
@@ -843,6 +816,9 @@ func processHistorySync(ctx context.Context, data *waHistorySync.HistorySync, ch
 	case waHistorySync.HistorySync_INITIAL_BOOTSTRAP, waHistorySync.HistorySync_RECENT:
 		// Process conversation messages
 		return processConversationMessages(ctx, data, chatStorageRepo)
+	case waHistorySync.HistorySync_ON_DEMAND:
+		// Answer to a /chat/:chat_jid/backfill request
+		return processOnDemandHistory(ctx, data, chatStorageRepo)
 	case waHistorySync.HistorySync_PUSH_NAME:
 		// Process push names to update chat names
 		return processPushNames(ctx, data, chatStorageRepo)
@@ -970,6 +946,15 @@ func processConversationMessages(_ context.Context, data *waHistorySync.HistoryS
 				FileSHA256:    fileSHA256,
 				FileEncSHA256: fileEncSHA256,
 				FileLength:    fileLength,
+				ViewOnce:      utils.IsViewOnceMedia(msg.GetMessage()),
+			}
+			message.Mimetype = utils.ExtractMediaMimetype(msg.GetMessage())
+			message.ForwardingScore = utils.ExtractForwardingScore(msg.GetMessage())
+			if reply := utils.ExtractInteractiveReply(msg.GetMessage()); reply != nil {
+				message.InteractiveType = reply.Type
+				message.SelectedID = reply.SelectedID
+				message.SelectedTitle = reply.SelectedTitle
+				message.OriginalMessageID = reply.OriginalMessageID
 			}
 
 			messageBatch = append(messageBatch, message)
@@ -977,6 +962,11 @@ func processConversationMessages(_ context.Context, data *waHistorySync.HistoryS
 
 		// Store or update the chat with latest message time
 		if len(messageBatch) > 0 {
+			// Older history (e.g. a backfill) must not move the last message time back
+			if existing, err := chatStorageRepo.GetChat(chatJID); err == nil && existing != nil && existing.LastMessageTime.After(latestTimestamp) {
+				latestTimestamp = existing.LastMessageTime
+			}
+
 			chat := &domainChatStorage.Chat{
 				JID:                 chatJID,
 				Name:                chatName,
@@ -997,13 +987,14 @@ func processConversationMessages(_ context.Context, data *waHistorySync.HistoryS
 				log.Debugf("Stored %d messages for chat %s", len(messageBatch), chatJID)
 			}
 		}
+
 	}
 
 	return nil
 }
 
-// processPushNames processes push names from history sync to update chat names
-func processPushNames(_ context.Context, data *waHistorySync.HistorySync, chatStorageRepo domainChatStorage.IChatStorageRepository) error {
+// processPushNames processes push names from history sync to update contacts and chat names
+func processPushNames(ctx context.Context, data *waHistorySync.HistorySync, chatStorageRepo domainChatStorage.IChatStorageRepository) error {
 	pushnames := data.GetPushnames()
 	log.Infof("Processing %d push names from history sync", len(pushnames))
 
@@ -1015,6 +1006,10 @@ func processPushNames(_ context.Context, data *waHistorySync.HistorySync, chatSt
 			continue
 		}
 
+		if jid, err := types.ParseJID(jidStr); err == nil {
+			recordContact(ctx, chatStorageRepo, jid, types.EmptyJID, domainChatStorage.Contact{PushName: name})
+		}
+
 		// Check if chat exists
 		existingChat, err := chatStorageRepo.GetChat(jidStr)
 		if err != nil || existingChat == nil {
@@ -1022,6 +1017,11 @@ func processPushNames(_ context.Context, data *waHistorySync.HistorySync, chatSt
 			continue
 		}
 
+		// Prefer the saved contact name over the push name
+		if contact, err := chatStorageRepo.GetContact(jidStr); err == nil && contact != nil {
+			name = contact.DisplayName()
+		}
+
 		// Update chat name if it's different
 		if existingChat.Name != name {
 			existingChat.Name = name
@@ -1036,10 +1036,11 @@ func processPushNames(_ context.Context, data *waHistorySync.HistorySync, chatSt
 	return nil
 }
 
-func handleGroupInfo(ctx context.Context, evt *events.GroupInfo) {
+func handleGroupInfo(ctx context.Context, evt *events.GroupInfo, chatStorageRepo domainChatStorage.IChatStorageRepository) {
 	// Only process events that have actual changes
 	hasChanges := len(evt.Join) > 0 || len(evt.Leave) > 0 || len(evt.Promote) > 0 || len(evt.Demote) > 0 ||
-		evt.Name != nil || evt.Topic != nil || evt.Locked != nil || evt.Announce != nil
+		evt.Name != nil || evt.Topic != nil || evt.Locked != nil || evt.Announce != nil ||
+		evt.Link != nil || evt.Unlink != nil
 
 	if !hasChanges {
 		return
@@ -1058,11 +1059,20 @@ func handleGroupInfo(ctx context.Context, evt *events.GroupInfo) {
 	if len(evt.Demote) > 0 {
 		log.Infof("Group %s: %d users demoted at %s", evt.JID, len(evt.Demote), evt.Timestamp)
 	}
+	if evt.Link != nil {
+		log.Infof("Group %s: linked %s (%s) at %s", evt.JID, evt.Link.Group.JID, evt.Link.Type, evt.Timestamp)
+	}
+	if evt.Unlink != nil {
+		log.Infof("Group %s: unlinked %s (%s) at %s", evt.JID, evt.Unlink.Group.JID, evt.Unlink.Type, evt.Timestamp)
+	}
+
+	// Keep the stored group and its membership log current
+	recordGroupInfo(ctx, evt, chatStorageRepo)
 
 	// Forward group info event to webhook if configured
 	if len(config.WhatsappWebhook) > 0 {
 		go func(e *events.GroupInfo) {
-			if err := forwardGroupInfoToWebhook(ctx, e); err != nil {
+			if err := forwardGroupInfoToWebhook(ctx, e, chatStorageRepo); err != nil {
 				logrus.Errorf("Failed to forward group info event to webhook: %v", err)
 			}
 		}(evt)
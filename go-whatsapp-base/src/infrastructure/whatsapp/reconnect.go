@@ -0,0 +1,32 @@
+package whatsapp
+
+import (
+	"sync"
+	"time"
+)
+
+// ReconnectAttempt is the outcome of the last reconnect made by the
+// connection watchdog
+type ReconnectAttempt struct {
+	At  time.Time
+	Err error
+}
+
+var (
+	lastReconnect   ReconnectAttempt
+	lastReconnectMu sync.RWMutex
+)
+
+// RecordReconnectAttempt stores the outcome of a reconnect for the readiness check
+func RecordReconnectAttempt(err error) {
+	lastReconnectMu.Lock()
+	defer lastReconnectMu.Unlock()
+	lastReconnect = ReconnectAttempt{At: time.Now(), Err: err}
+}
+
+// LastReconnectAttempt returns the last recorded reconnect, zero when none was made
+func LastReconnectAttempt() ReconnectAttempt {
+	lastReconnectMu.RLock()
+	defer lastReconnectMu.RUnlock()
+	return lastReconnect
+}
@@ -0,0 +1,366 @@
+package whatsapp
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/config"
+	domainChatStorage "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/chatstorage"
+	"github.com/sirupsen/logrus"
+	"go.mau.fi/whatsmeow"
+	"go.mau.fi/whatsmeow/proto/waE2E"
+	"go.mau.fi/whatsmeow/types"
+	"google.golang.org/protobuf/proto"
+)
+
+// ErrSendQueueDisconnected is returned when the queue can't be flushed because the client is offline
+var ErrSendQueueDisconnected = errors.New("whatsapp client is not connected")
+
+// queuedMediaGrace keeps media files the flush finds unreferenced for a while,
+// since a send may have stored its media but not queued its message yet
+const queuedMediaGrace = time.Hour
+
+// sendQueueMu makes sure only one flush delivers queued sends at a time
+var sendQueueMu sync.Mutex
+
+// SendQueueResult counts what a flush did with the queued sends
+type SendQueueResult struct {
+	Sent      int
+	Failed    int
+	Expired   int
+	Remaining int
+}
+
+// FlushSendQueue delivers queued sends in the order they were made. Expired
+// sends are marked expired instead. When the connection drops mid-flush it
+// stops, leaving the rest queued for the next reconnect.
+func FlushSendQueue(ctx context.Context, chatStorageRepo domainChatStorage.IChatStorageRepository) (result SendQueueResult, err error) {
+	sendQueueMu.Lock()
+	defer sendQueueMu.Unlock()
+
+	if cli == nil || !cli.IsConnected() {
+		return result, ErrSendQueueDisconnected
+	}
+
+	queued, err := chatStorageRepo.GetQueuedMessages(&domainChatStorage.QueuedMessageFilter{
+		Status: domainChatStorage.QueueStatusQueued,
+	})
+	if err != nil {
+		return result, err
+	}
+	if len(queued) > 0 {
+		log.Infof("Flushing %d queued send(s)", len(queued))
+	}
+
+	for i, item := range queued {
+		if time.Now().After(item.ExpiresAt) {
+			if finishQueuedSend(ctx, chatStorageRepo, item, domainChatStorage.QueueStatusExpired, "expired before the client reconnected") {
+				result.Expired++
+			}
+			continue
+		}
+
+		if !cli.IsConnected() {
+			result.Remaining = len(queued) - i
+			log.Warnf("Connection lost while flushing the send queue, %d send(s) left queued", result.Remaining)
+			return result, nil
+		}
+
+		msg := &waE2E.Message{}
+		if err := proto.Unmarshal(item.Payload, msg); err != nil {
+			if finishQueuedSend(ctx, chatStorageRepo, item, domainChatStorage.QueueStatusFailed, "invalid queued message: "+err.Error()) {
+				result.Failed++
+			}
+			continue
+		}
+		recipient, err := types.ParseJID(item.Recipient)
+		if err != nil {
+			if finishQueuedSend(ctx, chatStorageRepo, item, domainChatStorage.QueueStatusFailed, "invalid recipient: "+err.Error()) {
+				result.Failed++
+			}
+			continue
+		}
+
+		if err := uploadQueuedMedia(ctx, recipient, msg); err != nil {
+			if !cli.IsConnected() {
+				result.Remaining = len(queued) - i
+				log.Warnf("Connection lost while flushing the send queue, %d send(s) left queued", result.Remaining)
+				return result, nil
+			}
+			if finishQueuedSend(ctx, chatStorageRepo, item, domainChatStorage.QueueStatusFailed, "failed to upload queued media: "+err.Error()) {
+				result.Failed++
+			}
+			continue
+		}
+
+		resp, err := cli.SendMessage(ctx, recipient, msg, whatsmeow.SendRequestExtra{ID: types.MessageID(item.MessageID)})
+		if err != nil {
+			if !cli.IsConnected() {
+				result.Remaining = len(queued) - i
+				log.Warnf("Connection lost while flushing the send queue, %d send(s) left queued", result.Remaining)
+				return result, nil
+			}
+			if finishQueuedSend(ctx, chatStorageRepo, item, domainChatStorage.QueueStatusFailed, err.Error()) {
+				result.Failed++
+			}
+			continue
+		}
+
+		senderJID := ""
+		if cli.Store.ID != nil {
+			senderJID = cli.Store.ID.String()
+		}
+		if err := chatStorageRepo.StoreSentMessageWithContext(ctx, resp.ID, senderJID, item.Recipient, item.Content, resp.Timestamp); err != nil {
+			log.Warnf("Failed to store queued message %s: %v", resp.ID, err)
+		}
+
+		// A send cancelled while it was being delivered still went out, so record that
+		if !finishQueuedSend(ctx, chatStorageRepo, item, domainChatStorage.QueueStatusSent, "") {
+			item.Status = domainChatStorage.QueueStatusCancelled
+			finishQueuedSend(ctx, chatStorageRepo, item, domainChatStorage.QueueStatusSent, "")
+		}
+		result.Sent++
+	}
+
+	removeUnusedQueuedMedia(chatStorageRepo)
+
+	log.Infof("Send queue flushed: %d sent, %d failed, %d expired", result.Sent, result.Failed, result.Expired)
+	return result, nil
+}
+
+// queuedMediaDir holds the media of sends queued while disconnected, each file
+// named by the SHA-256 of its content
+func queuedMediaDir() string {
+	return filepath.Join(config.PathMedia, "queued")
+}
+
+// QueueMedia keeps media of a send made while disconnected until the flush
+// uploads it. The returned upload only carries the file hash and length; the
+// message built from it goes out once uploadQueuedMedia filled in the rest.
+func QueueMedia(media []byte) (uploaded whatsmeow.UploadResponse, err error) {
+	hash := sha256.Sum256(media)
+	path := filepath.Join(queuedMediaDir(), hex.EncodeToString(hash[:]))
+
+	if _, err := os.Stat(path); err == nil {
+		// Already queued by an earlier send, keep it from being cleaned up
+		now := time.Now()
+		if err := os.Chtimes(path, now, now); err != nil {
+			return uploaded, fmt.Errorf("failed to touch queued media: %w", err)
+		}
+	} else {
+		if err := os.MkdirAll(queuedMediaDir(), 0755); err != nil {
+			return uploaded, fmt.Errorf("failed to create queued media directory: %w", err)
+		}
+		tmpFile := path + ".tmp"
+		if err := os.WriteFile(tmpFile, media, 0644); err != nil {
+			return uploaded, fmt.Errorf("failed to write queued media: %w", err)
+		}
+		if err := os.Rename(tmpFile, path); err != nil {
+			return uploaded, fmt.Errorf("failed to store queued media: %w", err)
+		}
+	}
+
+	uploaded.FileSHA256 = hash[:]
+	uploaded.FileLength = uint64(len(media))
+	return uploaded, nil
+}
+
+// uploadQueuedMedia uploads the media a queued message was built with and
+// fills in where it went. Media without a direct path was queued by
+// QueueMedia; anything else was uploaded before queueing and is left alone.
+func uploadQueuedMedia(ctx context.Context, recipient types.JID, msg *waE2E.Message) error {
+	upload := func(fileSHA256 []byte, mediaType whatsmeow.MediaType) (whatsmeow.UploadResponse, error) {
+		data, err := os.ReadFile(filepath.Join(queuedMediaDir(), hex.EncodeToString(fileSHA256)))
+		if err != nil {
+			return whatsmeow.UploadResponse{}, err
+		}
+		if recipient.Server == types.NewsletterServer {
+			return cli.UploadNewsletter(ctx, data, mediaType)
+		}
+		return cli.Upload(ctx, data, mediaType)
+	}
+
+	if m := msg.GetImageMessage(); m != nil && m.GetDirectPath() == "" && len(m.GetFileSHA256()) > 0 {
+		uploaded, err := upload(m.GetFileSHA256(), whatsmeow.MediaImage)
+		if err != nil {
+			return err
+		}
+		m.URL, m.DirectPath = proto.String(uploaded.URL), proto.String(uploaded.DirectPath)
+		m.MediaKey, m.FileEncSHA256, m.FileSHA256 = uploaded.MediaKey, uploaded.FileEncSHA256, uploaded.FileSHA256
+	}
+	if m := msg.GetVideoMessage(); m != nil && m.GetDirectPath() == "" && len(m.GetFileSHA256()) > 0 {
+		uploaded, err := upload(m.GetFileSHA256(), whatsmeow.MediaVideo)
+		if err != nil {
+			return err
+		}
+		m.URL, m.DirectPath = proto.String(uploaded.URL), proto.String(uploaded.DirectPath)
+		m.MediaKey, m.FileEncSHA256, m.FileSHA256 = uploaded.MediaKey, uploaded.FileEncSHA256, uploaded.FileSHA256
+		m.ThumbnailDirectPath = proto.String(uploaded.DirectPath)
+	}
+	if m := msg.GetAudioMessage(); m != nil && m.GetDirectPath() == "" && len(m.GetFileSHA256()) > 0 {
+		uploaded, err := upload(m.GetFileSHA256(), whatsmeow.MediaAudio)
+		if err != nil {
+			return err
+		}
+		m.URL, m.DirectPath = proto.String(uploaded.URL), proto.String(uploaded.DirectPath)
+		m.MediaKey, m.FileEncSHA256, m.FileSHA256 = uploaded.MediaKey, uploaded.FileEncSHA256, uploaded.FileSHA256
+	}
+	if m := msg.GetDocumentMessage(); m != nil && m.GetDirectPath() == "" && len(m.GetFileSHA256()) > 0 {
+		uploaded, err := upload(m.GetFileSHA256(), whatsmeow.MediaDocument)
+		if err != nil {
+			return err
+		}
+		m.URL, m.DirectPath = proto.String(uploaded.URL), proto.String(uploaded.DirectPath)
+		m.MediaKey, m.FileEncSHA256, m.FileSHA256 = uploaded.MediaKey, uploaded.FileEncSHA256, uploaded.FileSHA256
+	}
+	if m := msg.GetStickerMessage(); m != nil && m.GetDirectPath() == "" && len(m.GetFileSHA256()) > 0 {
+		uploaded, err := upload(m.GetFileSHA256(), whatsmeow.MediaImage)
+		if err != nil {
+			return err
+		}
+		m.URL, m.DirectPath = proto.String(uploaded.URL), proto.String(uploaded.DirectPath)
+		m.MediaKey, m.FileEncSHA256, m.FileSHA256 = uploaded.MediaKey, uploaded.FileEncSHA256, uploaded.FileSHA256
+	}
+	if m := msg.GetExtendedTextMessage(); m != nil && m.GetThumbnailDirectPath() == "" && len(m.GetThumbnailSHA256()) > 0 {
+		uploaded, err := upload(m.GetThumbnailSHA256(), whatsmeow.MediaLinkThumbnail)
+		if err != nil {
+			return err
+		}
+		m.ThumbnailDirectPath = proto.String(uploaded.DirectPath)
+		m.MediaKey, m.ThumbnailEncSHA256, m.ThumbnailSHA256 = uploaded.MediaKey, uploaded.FileEncSHA256, uploaded.FileSHA256
+	}
+	return nil
+}
+
+// queuedMediaHashes returns the files of the media a queued message still
+// has to upload
+func queuedMediaHashes(msg *waE2E.Message) []string {
+	var hashes []string
+	add := func(directPath string, fileSHA256 []byte) {
+		if directPath == "" && len(fileSHA256) > 0 {
+			hashes = append(hashes, hex.EncodeToString(fileSHA256))
+		}
+	}
+	add(msg.GetImageMessage().GetDirectPath(), msg.GetImageMessage().GetFileSHA256())
+	add(msg.GetVideoMessage().GetDirectPath(), msg.GetVideoMessage().GetFileSHA256())
+	add(msg.GetAudioMessage().GetDirectPath(), msg.GetAudioMessage().GetFileSHA256())
+	add(msg.GetDocumentMessage().GetDirectPath(), msg.GetDocumentMessage().GetFileSHA256())
+	add(msg.GetStickerMessage().GetDirectPath(), msg.GetStickerMessage().GetFileSHA256())
+	add(msg.GetExtendedTextMessage().GetThumbnailDirectPath(), msg.GetExtendedTextMessage().GetThumbnailSHA256())
+	return hashes
+}
+
+// removeUnusedQueuedMedia deletes queued media no queued send refers to any
+// more, once it is older than queuedMediaGrace
+func removeUnusedQueuedMedia(chatStorageRepo domainChatStorage.IChatStorageRepository) {
+	entries, err := os.ReadDir(queuedMediaDir())
+	if err != nil || len(entries) == 0 {
+		return
+	}
+
+	queued, err := chatStorageRepo.GetQueuedMessages(&domainChatStorage.QueuedMessageFilter{
+		Status: domainChatStorage.QueueStatusQueued,
+	})
+	if err != nil {
+		log.Warnf("Failed to list queued sends for media cleanup: %v", err)
+		return
+	}
+	inUse := make(map[string]bool)
+	for _, item := range queued {
+		msg := &waE2E.Message{}
+		if err := proto.Unmarshal(item.Payload, msg); err != nil {
+			continue
+		}
+		for _, hash := range queuedMediaHashes(msg) {
+			inUse[hash] = true
+		}
+	}
+
+	for _, entry := range entries {
+		if inUse[entry.Name()] {
+			continue
+		}
+		info, err := entry.Info()
+		if err != nil || time.Since(info.ModTime()) < queuedMediaGrace {
+			continue
+		}
+		if err := os.Remove(filepath.Join(queuedMediaDir(), entry.Name())); err != nil {
+			log.Warnf("Failed to remove queued media %s: %v", entry.Name(), err)
+		}
+	}
+}
+
+// flushSendQueueOnConnect delivers sends queued while the client was offline
+func flushSendQueueOnConnect(ctx context.Context, chatStorageRepo domainChatStorage.IChatStorageRepository) {
+	if _, err := FlushSendQueue(ctx, chatStorageRepo); err != nil {
+		log.Warnf("Failed to flush the send queue: %v", err)
+	}
+}
+
+// finishQueuedSend moves a queued send to its final status and reports it to
+// webhooks. It returns false when the send had already left its status.
+func finishQueuedSend(ctx context.Context, chatStorageRepo domainChatStorage.IChatStorageRepository, item *domainChatStorage.QueuedMessage, status, errMessage string) bool {
+	updated, err := chatStorageRepo.UpdateQueuedMessageStatus(item.ID, item.Status, status, errMessage)
+	if err != nil {
+		log.Warnf("Failed to update queued send %d: %v", item.ID, err)
+		return false
+	}
+	if !updated {
+		return false
+	}
+
+	item.Status = status
+	item.Error = errMessage
+	if status != domainChatStorage.QueueStatusSent && errMessage != "" {
+		log.Warnf("Queued send %d to %s %s: %s", item.ID, item.Recipient, status, errMessage)
+	}
+
+	if len(config.WhatsappWebhook) > 0 {
+		go func(q domainChatStorage.QueuedMessage) {
+			if err := forwardPayloadToConfiguredWebhooks(ctx, createSendQueuePayload(&q), "send queue event"); err != nil {
+				logrus.Errorf("Failed to forward send queue event to webhook: %v", err)
+			}
+		}(*item)
+	}
+	return true
+}
+
+// NotifyQueuedSendCancelled reports a cancelled queued send to webhooks
+func NotifyQueuedSendCancelled(ctx context.Context, item *domainChatStorage.QueuedMessage) {
+	if len(config.WhatsappWebhook) == 0 {
+		return
+	}
+	go func(q domainChatStorage.QueuedMessage) {
+		if err := forwardPayloadToConfiguredWebhooks(ctx, createSendQueuePayload(&q), "send queue event"); err != nil {
+			logrus.Errorf("Failed to forward send queue event to webhook: %v", err)
+		}
+	}(*item)
+}
+
+// createSendQueuePayload creates a webhook payload for the final result of a queued send
+func createSendQueuePayload(item *domainChatStorage.QueuedMessage) map[string]any {
+	payload := map[string]any{
+		"id":         item.ID,
+		"message_id": item.MessageID,
+		"recipient":  item.Recipient,
+		"status":     item.Status,
+		"queued_at":  item.CreatedAt.Format(time.RFC3339),
+		"expires_at": item.ExpiresAt.Format(time.RFC3339),
+	}
+	if item.Error != "" {
+		payload["error"] = item.Error
+	}
+
+	return map[string]any{
+		"event":     "send.queue",
+		"payload":   payload,
+		"timestamp": time.Now().Format(time.RFC3339),
+	}
+}
@@ -0,0 +1,107 @@
+package whatsapp
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/config"
+	domainChatStorage "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/chatstorage"
+	"go.mau.fi/whatsmeow/proto/waE2E"
+	"google.golang.org/protobuf/proto"
+)
+
+func TestCreateSendQueuePayload(t *testing.T) {
+	queuedAt := time.Date(2025, 7, 28, 10, 30, 0, 0, time.UTC)
+	item := &domainChatStorage.QueuedMessage{
+		ID:        7,
+		MessageID: "3EB0B430B6F8F1D0E053AC120E0A9E5C",
+		Recipient: "6289685028129@s.whatsapp.net",
+		Status:    domainChatStorage.QueueStatusSent,
+		ExpiresAt: queuedAt.Add(24 * time.Hour),
+		CreatedAt: queuedAt,
+	}
+
+	payload := createSendQueuePayload(item)
+	if payload["event"] != "send.queue" {
+		t.Fatalf("event = %v, want send.queue", payload["event"])
+	}
+
+	body, ok := payload["payload"].(map[string]any)
+	if !ok {
+		t.Fatalf("payload has unexpected type %T", payload["payload"])
+	}
+	if body["id"] != int64(7) || body["status"] != "sent" || body["queued_at"] != "2025-07-28T10:30:00Z" {
+		t.Fatalf("unexpected payload body: %v", body)
+	}
+	if _, exists := body["error"]; exists {
+		t.Fatalf("sent item should not carry an error: %v", body)
+	}
+
+	item.Status = domainChatStorage.QueueStatusExpired
+	item.Error = "expired before the client reconnected"
+	body = createSendQueuePayload(item)["payload"].(map[string]any)
+	if body["status"] != "expired" || body["error"] != item.Error {
+		t.Fatalf("unexpected payload body: %v", body)
+	}
+}
+
+func TestQueueMedia(t *testing.T) {
+	original := config.PathMedia
+	config.PathMedia = t.TempDir()
+	defer func() { config.PathMedia = original }()
+
+	media := []byte("queued image bytes")
+	uploaded, err := QueueMedia(media)
+	if err != nil {
+		t.Fatalf("QueueMedia returned error: %v", err)
+	}
+	if uploaded.FileLength != uint64(len(media)) || uploaded.DirectPath != "" {
+		t.Fatalf("unexpected pending upload: %+v", uploaded)
+	}
+
+	// The queued message survives the round trip through storage
+	msg := &waE2E.Message{ImageMessage: &waE2E.ImageMessage{
+		URL:        proto.String(uploaded.URL),
+		DirectPath: proto.String(uploaded.DirectPath),
+		FileSHA256: uploaded.FileSHA256,
+		FileLength: proto.Uint64(uploaded.FileLength),
+	}}
+	payload, err := proto.Marshal(msg)
+	if err != nil {
+		t.Fatalf("failed to encode message: %v", err)
+	}
+	decoded := &waE2E.Message{}
+	if err := proto.Unmarshal(payload, decoded); err != nil {
+		t.Fatalf("failed to decode message: %v", err)
+	}
+
+	hashes := queuedMediaHashes(decoded)
+	if len(hashes) != 1 {
+		t.Fatalf("queuedMediaHashes = %v, want one file", hashes)
+	}
+	stored, err := os.ReadFile(filepath.Join(queuedMediaDir(), hashes[0]))
+	if err != nil {
+		t.Fatalf("queued media not stored: %v", err)
+	}
+	if !bytes.Equal(stored, media) {
+		t.Fatalf("stored media = %q, want %q", stored, media)
+	}
+
+	// Queueing the same media again reuses the file
+	if _, err := QueueMedia(media); err != nil {
+		t.Fatalf("QueueMedia returned error on the same media: %v", err)
+	}
+	entries, err := os.ReadDir(queuedMediaDir())
+	if err != nil || len(entries) != 1 {
+		t.Fatalf("queued media dir has %d entries (%v), want 1", len(entries), err)
+	}
+
+	// Uploaded media has a direct path and is not pending any more
+	decoded.ImageMessage.DirectPath = proto.String("/v/t62.7118-24/uploaded")
+	if hashes := queuedMediaHashes(decoded); len(hashes) != 0 {
+		t.Fatalf("queuedMediaHashes = %v after upload, want none", hashes)
+	}
+}
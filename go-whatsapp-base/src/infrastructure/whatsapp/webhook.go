@@ -7,6 +7,7 @@ import (
 	"fmt"
 	"io"
 	"net/http"
+	"sync/atomic"
 	"time"
 
 	"github.com/aldinokemal/go-whatsapp-web-multidevice/config"
@@ -15,7 +16,18 @@ import (
 	"github.com/sirupsen/logrus"
 )
 
+// webhookInFlight counts deliveries still being attempted, retries included
+var webhookInFlight atomic.Int64
+
+// WebhookBacklog returns the number of webhook deliveries not yet finished
+func WebhookBacklog() int64 {
+	return webhookInFlight.Load()
+}
+
 func submitWebhook(ctx context.Context, payload map[string]any, url string) error {
+	webhookInFlight.Add(1)
+	defer webhookInFlight.Add(-1)
+
 	client := &http.Client{Timeout: 10 * time.Second}
 
 	postBody, err := json.Marshal(payload)
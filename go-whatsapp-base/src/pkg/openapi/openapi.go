@@ -0,0 +1,463 @@
+// Package openapi builds an OpenAPI 3 document from the domain request and
+// response structs. Field names come from the json (or form/query) tags, and
+// required fields come from running the request validation on a zero value.
+package openapi
+
+import (
+	"context"
+	"encoding"
+	"fmt"
+	"mime/multipart"
+	"reflect"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+)
+
+const Version = "3.0.3"
+
+// Info describes the API in the generated document
+type Info struct {
+	Title       string
+	Description string
+	Version     string
+	BasePath    string
+}
+
+// Header is a request header an operation accepts
+type Header struct {
+	Name        string
+	Description string
+}
+
+// Operation describes one route. Query and Body are zero values of the
+// request struct; Response is a zero value of the results field in the
+// response envelope.
+type Operation struct {
+	Method      string
+	Path        string // fiber style, e.g. /chat/:chat_jid/messages
+	Tag         string
+	Summary     string
+	Description string
+	Query       any
+	Body        any
+	Headers     []Header
+	Response    any
+
+	// ContentType is set for routes that return a file instead of JSON
+	ContentType string
+
+	// Omit lists request fields the handler fills in itself, e.g. from the route
+	Omit []string
+
+	// Required lists fields the validation checks before it gets to blank
+	// fields, so running it on a zero value doesn't report them
+	Required []string
+
+	// Validate runs the request validation on a zero value. Fields it
+	// reports as blank are marked required.
+	Validate func(ctx context.Context) error
+}
+
+var (
+	pathParamRegex = regexp.MustCompile(`:([A-Za-z0-9_]+)`)
+	blankRuleRegex = regexp.MustCompile(`^([A-Za-z0-9_.]+): (cannot be blank|is required)$`)
+
+	timeType        = reflect.TypeOf(time.Time{})
+	fileHeaderType  = reflect.TypeOf(multipart.FileHeader{})
+	textMarshalType = reflect.TypeOf((*encoding.TextMarshaler)(nil)).Elem()
+)
+
+// Build generates the OpenAPI document for the operations
+func Build(info Info, operations []Operation) map[string]any {
+	g := &generator{
+		schemas: make(map[string]any),
+		names:   make(map[reflect.Type]string),
+	}
+
+	paths := make(map[string]any)
+	for _, op := range operations {
+		path := pathParamRegex.ReplaceAllString(op.Path, "{$1}")
+		item, _ := paths[path].(map[string]any)
+		if item == nil {
+			item = make(map[string]any)
+			paths[path] = item
+		}
+		item[strings.ToLower(op.Method)] = g.operation(op)
+	}
+
+	g.schemas["ErrorResponse"] = map[string]any{
+		"type": "object",
+		"properties": map[string]any{
+			"code":    map[string]any{"type": "string", "example": "VALIDATION_ERROR"},
+			"message": map[string]any{"type": "string"},
+			"results": map[string]any{},
+		},
+	}
+
+	serverURL := info.BasePath
+	if serverURL == "" {
+		serverURL = "/"
+	}
+
+	return map[string]any{
+		"openapi": Version,
+		"info": map[string]any{
+			"title":       info.Title,
+			"description": info.Description,
+			"version":     info.Version,
+		},
+		"servers":  []any{map[string]any{"url": serverURL}},
+		"security": []any{map[string]any{"basicAuth": []any{}}},
+		"paths":    paths,
+		"components": map[string]any{
+			"securitySchemes": map[string]any{
+				"basicAuth": map[string]any{"type": "http", "scheme": "basic"},
+			},
+			"schemas": g.schemas,
+		},
+	}
+}
+
+// RequiredFields runs a validation on the zero request and returns the
+// fields it reported as blank. A validation that panics reports nothing.
+func RequiredFields(validate func(ctx context.Context) error) (required map[string]bool) {
+	required = make(map[string]bool)
+	if validate == nil {
+		return required
+	}
+	defer func() {
+		if recover() != nil {
+			required = make(map[string]bool)
+		}
+	}()
+
+	err := validate(context.Background())
+	if err == nil {
+		return required
+	}
+
+	// ozzo-validation joins field errors as "a: cannot be blank; b: ..."
+	for _, part := range strings.Split(strings.TrimSuffix(err.Error(), "."), "; ") {
+		if match := blankRuleRegex.FindStringSubmatch(strings.TrimSpace(part)); match != nil {
+			required[match[1]] = true
+		}
+	}
+	return required
+}
+
+type generator struct {
+	schemas map[string]any
+	names   map[reflect.Type]string
+}
+
+func (g *generator) operation(op Operation) map[string]any {
+	required := RequiredFields(op.Validate)
+	for _, name := range op.Required {
+		required[name] = true
+	}
+
+	// Path params and omitted fields are left out of the query and body
+	pathParams := make(map[string]bool)
+	for _, name := range op.Omit {
+		pathParams[name] = true
+	}
+	var parameters []any
+	for _, match := range pathParamRegex.FindAllStringSubmatch(op.Path, -1) {
+		pathParams[match[1]] = true
+		parameters = append(parameters, map[string]any{
+			"name":     match[1],
+			"in":       "path",
+			"required": true,
+			"schema":   map[string]any{"type": "string"},
+		})
+	}
+
+	if op.Query != nil {
+		for _, field := range g.fields(reflect.TypeOf(op.Query)) {
+			if pathParams[field.name] || field.path {
+				continue
+			}
+			parameters = append(parameters, map[string]any{
+				"name":     field.name,
+				"in":       "query",
+				"required": required[field.name],
+				"schema":   field.schema,
+			})
+		}
+	}
+
+	for _, header := range op.Headers {
+		parameters = append(parameters, map[string]any{
+			"name":        header.Name,
+			"in":          "header",
+			"required":    false,
+			"description": header.Description,
+			"schema":      map[string]any{"type": "string"},
+		})
+	}
+
+	result := map[string]any{
+		"operationId": operationID(op),
+		"tags":        []any{op.Tag},
+		"summary":     op.Summary,
+		"responses":   g.responses(op),
+	}
+	if op.Description != "" {
+		result["description"] = op.Description
+	}
+	if len(parameters) > 0 {
+		result["parameters"] = parameters
+	}
+	if op.Body != nil {
+		result["requestBody"] = g.requestBody(reflect.TypeOf(op.Body), pathParams, required)
+	}
+
+	return result
+}
+
+func (g *generator) requestBody(t reflect.Type, pathParams, required map[string]bool) map[string]any {
+	properties := make(map[string]any)
+	var requiredNames []string
+	hasFile := false
+
+	for _, field := range g.fields(t) {
+		if pathParams[field.name] || field.path {
+			continue
+		}
+		properties[field.name] = field.schema
+		if field.file {
+			hasFile = true
+		}
+		if required[field.name] {
+			requiredNames = append(requiredNames, field.name)
+		}
+	}
+
+	schema := map[string]any{"type": "object", "properties": properties}
+	if len(requiredNames) > 0 {
+		sort.Strings(requiredNames)
+		schema["required"] = requiredNames
+	}
+
+	contentType := "application/json"
+	if hasFile {
+		contentType = "multipart/form-data"
+	}
+
+	return map[string]any{
+		"required": true,
+		"content": map[string]any{
+			contentType: map[string]any{"schema": schema},
+		},
+	}
+}
+
+func (g *generator) responses(op Operation) map[string]any {
+	errorResponse := func(description string) map[string]any {
+		return map[string]any{
+			"description": description,
+			"content": map[string]any{
+				"application/json": map[string]any{
+					"schema": map[string]any{"$ref": "#/components/schemas/ErrorResponse"},
+				},
+			},
+		}
+	}
+
+	var success map[string]any
+	if op.ContentType != "" {
+		success = map[string]any{
+			"description": "OK",
+			"content": map[string]any{
+				op.ContentType: map[string]any{
+					"schema": map[string]any{"type": "string", "format": "binary"},
+				},
+			},
+		}
+	} else {
+		envelope := map[string]any{
+			"code":    map[string]any{"type": "string", "example": "SUCCESS"},
+			"message": map[string]any{"type": "string"},
+		}
+		if op.Response != nil {
+			envelope["results"] = g.schemaOf(reflect.TypeOf(op.Response))
+		}
+		success = map[string]any{
+			"description": "OK",
+			"content": map[string]any{
+				"application/json": map[string]any{
+					"schema": map[string]any{"type": "object", "properties": envelope},
+				},
+			},
+		}
+	}
+
+	return map[string]any{
+		"200": success,
+		"400": errorResponse("Bad Request"),
+		"401": errorResponse("Unauthorized"),
+		"500": errorResponse("Internal Server Error"),
+	}
+}
+
+type field struct {
+	name   string
+	schema map[string]any
+	file   bool
+	path   bool
+}
+
+// fields lists the serialized fields of a struct, flattening embedded structs
+func (g *generator) fields(t reflect.Type) []field {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	if t.Kind() != reflect.Struct {
+		return nil
+	}
+
+	// Like encoding/json, fields of the struct itself shadow promoted fields
+	var result, promoted []field
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if f.Anonymous && f.Tag.Get("json") == "" {
+			embedded := f.Type
+			for embedded.Kind() == reflect.Pointer {
+				embedded = embedded.Elem()
+			}
+			if embedded.Kind() == reflect.Struct {
+				promoted = append(promoted, g.fields(embedded)...)
+				continue
+			}
+		}
+
+		name, ok := fieldName(f)
+		if !ok {
+			continue
+		}
+
+		fieldType := f.Type
+		for fieldType.Kind() == reflect.Pointer {
+			fieldType = fieldType.Elem()
+		}
+		result = append(result, field{
+			name:   name,
+			schema: g.schemaOf(f.Type),
+			file:   fieldType == fileHeaderType,
+			path:   f.Tag.Get("uri") != "",
+		})
+	}
+
+	for _, p := range promoted {
+		shadowed := false
+		for _, f := range result {
+			if f.name == p.name {
+				shadowed = true
+				break
+			}
+		}
+		if !shadowed {
+			result = append(result, p)
+		}
+	}
+	return result
+}
+
+// fieldName returns the wire name of a struct field from its json, form or query tag
+func fieldName(f reflect.StructField) (string, bool) {
+	if !f.IsExported() {
+		return "", false
+	}
+	for _, key := range []string{"json", "form", "query"} {
+		tag := f.Tag.Get(key)
+		if tag == "-" {
+			return "", false
+		}
+		if name := strings.Split(tag, ",")[0]; name != "" {
+			return name, true
+		}
+	}
+	return f.Name, true
+}
+
+// schemaOf returns the schema of a type. Named structs become components.
+func (g *generator) schemaOf(t reflect.Type) map[string]any {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+
+	switch {
+	case t == timeType:
+		return map[string]any{"type": "string", "format": "date-time"}
+	case t == fileHeaderType:
+		return map[string]any{"type": "string", "format": "binary"}
+	case t.Implements(textMarshalType) || reflect.PointerTo(t).Implements(textMarshalType):
+		return map[string]any{"type": "string"}
+	}
+
+	switch t.Kind() {
+	case reflect.Bool:
+		return map[string]any{"type": "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32:
+		return map[string]any{"type": "integer"}
+	case reflect.Int64, reflect.Uint64:
+		return map[string]any{"type": "integer", "format": "int64"}
+	case reflect.Float32, reflect.Float64:
+		return map[string]any{"type": "number"}
+	case reflect.String:
+		return map[string]any{"type": "string"}
+	case reflect.Slice, reflect.Array:
+		if t.Elem().Kind() == reflect.Uint8 {
+			return map[string]any{"type": "string", "format": "byte"}
+		}
+		return map[string]any{"type": "array", "items": g.schemaOf(t.Elem())}
+	case reflect.Map:
+		return map[string]any{"type": "object", "additionalProperties": g.schemaOf(t.Elem())}
+	case reflect.Struct:
+		if t.Name() == "" {
+			return g.structSchema(t)
+		}
+		return map[string]any{"$ref": "#/components/schemas/" + g.component(t)}
+	default:
+		// interfaces and anything else without a fixed shape
+		return map[string]any{}
+	}
+}
+
+// component registers a named struct under components/schemas and returns its name
+func (g *generator) component(t reflect.Type) string {
+	if name, exists := g.names[t]; exists {
+		return name
+	}
+
+	name := t.Name()
+	if _, taken := g.schemas[name]; taken {
+		pkg := t.PkgPath()[strings.LastIndex(t.PkgPath(), "/")+1:]
+		name = strings.ToUpper(pkg[:1]) + pkg[1:] + name
+	}
+
+	// Register before building so self-referencing types terminate
+	g.names[t] = name
+	g.schemas[name] = map[string]any{"type": "object"}
+	g.schemas[name] = g.structSchema(t)
+	return name
+}
+
+func (g *generator) structSchema(t reflect.Type) map[string]any {
+	properties := make(map[string]any)
+	for _, f := range g.fields(t) {
+		properties[f.name] = f.schema
+	}
+	return map[string]any{"type": "object", "properties": properties}
+}
+
+// operationID derives a stable operation ID such as get_chat_chat_jid_messages
+func operationID(op Operation) string {
+	cleaned := strings.NewReplacer(":", "", "-", "_", "/", "_").Replace(strings.Trim(op.Path, "/"))
+	if cleaned == "" {
+		cleaned = "root"
+	}
+	return fmt.Sprintf("%s_%s", strings.ToLower(op.Method), cleaned)
+}
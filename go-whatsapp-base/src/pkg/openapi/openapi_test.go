@@ -0,0 +1,123 @@
+package openapi
+
+import (
+	"context"
+	"mime/multipart"
+	"testing"
+	"time"
+
+	validation "github.com/go-ozzo/ozzo-validation/v4"
+	"github.com/stretchr/testify/assert"
+)
+
+type testBase struct {
+	Phone string `json:"phone" form:"phone"`
+	Note  string `json:"note"`
+}
+
+type testRequest struct {
+	testBase
+	Note      string                `json:"note" form:"note"`
+	MessageID string                `json:"message_id" uri:"message_id"`
+	Count     *int                  `json:"count,omitempty"`
+	Tags      []string              `json:"tags"`
+	Hidden    string                `json:"-"`
+	File      *multipart.FileHeader `form:"file"`
+}
+
+type testResponse struct {
+	ID        int64            `json:"id"`
+	CreatedAt time.Time        `json:"created_at"`
+	Payload   []byte           `json:"payload"`
+	Meta      map[string]any   `json:"meta"`
+	Children  []testResponse   `json:"children"`
+	Extra     map[string]int32 `json:"extra"`
+}
+
+func validateTestRequest(ctx context.Context) error {
+	request := testRequest{}
+	return validation.ValidateStructWithContext(ctx, &request,
+		validation.Field(&request.testBase.Phone, validation.Required),
+		validation.Field(&request.Tags, validation.Required),
+		validation.Field(&request.Note, validation.Length(0, 10)),
+	)
+}
+
+func TestRequiredFields(t *testing.T) {
+	assert.Equal(t, map[string]bool{"phone": true, "tags": true}, RequiredFields(validateTestRequest))
+	assert.Empty(t, RequiredFields(nil))
+	assert.Empty(t, RequiredFields(func(context.Context) error { panic("boom") }))
+}
+
+func TestBuildRequestBody(t *testing.T) {
+	document := Build(Info{Title: "Test", Version: "v1"}, []Operation{{
+		Method:   "POST",
+		Path:     "/message/:message_id/send",
+		Tag:      "message",
+		Body:     testRequest{},
+		Validate: validateTestRequest,
+		Response: testResponse{},
+	}})
+
+	assert.Equal(t, Version, document["openapi"])
+	assert.Equal(t, []any{map[string]any{"url": "/"}}, document["servers"])
+
+	operation := document["paths"].(map[string]any)["/message/{message_id}/send"].(map[string]any)["post"].(map[string]any)
+	assert.Equal(t, "post_message_message_id_send", operation["operationId"])
+
+	parameters := operation["parameters"].([]any)
+	assert.Len(t, parameters, 1)
+	assert.Equal(t, "message_id", parameters[0].(map[string]any)["name"])
+	assert.Equal(t, "path", parameters[0].(map[string]any)["in"])
+
+	content := operation["requestBody"].(map[string]any)["content"].(map[string]any)
+	schema := content["multipart/form-data"].(map[string]any)["schema"].(map[string]any)
+	properties := schema["properties"].(map[string]any)
+
+	assert.ElementsMatch(t, []string{"phone", "note", "count", "tags", "file"}, keys(properties))
+	assert.Equal(t, []string{"phone", "tags"}, schema["required"])
+	assert.Equal(t, map[string]any{"type": "integer"}, properties["count"])
+	assert.Equal(t, map[string]any{"type": "string", "format": "binary"}, properties["file"])
+	assert.Equal(t, map[string]any{"type": "array", "items": map[string]any{"type": "string"}}, properties["tags"])
+}
+
+func TestBuildComponents(t *testing.T) {
+	document := Build(Info{BasePath: "/api"}, []Operation{{
+		Method:   "GET",
+		Path:     "/items",
+		Query:    testBase{},
+		Response: []testResponse{},
+	}, {
+		Method:      "GET",
+		Path:        "/items/export",
+		ContentType: "text/csv",
+	}})
+
+	assert.Equal(t, []any{map[string]any{"url": "/api"}}, document["servers"])
+
+	schemas := document["components"].(map[string]any)["schemas"].(map[string]any)
+	response := schemas["testResponse"].(map[string]any)["properties"].(map[string]any)
+	assert.Equal(t, map[string]any{"type": "string", "format": "date-time"}, response["created_at"])
+	assert.Equal(t, map[string]any{"type": "string", "format": "byte"}, response["payload"])
+	assert.Equal(t, map[string]any{"type": "object", "additionalProperties": map[string]any{}}, response["meta"])
+	assert.Equal(t, map[string]any{
+		"type":  "array",
+		"items": map[string]any{"$ref": "#/components/schemas/testResponse"},
+	}, response["children"])
+
+	paths := document["paths"].(map[string]any)
+	query := paths["/items"].(map[string]any)["get"].(map[string]any)["parameters"].([]any)
+	assert.Len(t, query, 2)
+
+	export := paths["/items/export"].(map[string]any)["get"].(map[string]any)
+	content := export["responses"].(map[string]any)["200"].(map[string]any)["content"].(map[string]any)
+	assert.Contains(t, content, "text/csv")
+}
+
+func keys(m map[string]any) []string {
+	result := make([]string, 0, len(m))
+	for key := range m {
+		result = append(result, key)
+	}
+	return result
+}
@@ -0,0 +1,201 @@
+package utils
+
+import (
+	"bytes"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// ContactCard is a name and phone number read from or written to an address book file
+type ContactCard struct {
+	Name  string
+	Phone string
+}
+
+// ParseVCards reads every contact in a vCard file. The WhatsApp number (waid)
+// is preferred over other phone numbers of a card; cards without a phone
+// number are returned with an empty Phone so callers can report them.
+func ParseVCards(data []byte) []ContactCard {
+	var (
+		cards     []ContactCard
+		current   *ContactCard
+		givenName string
+		hasWaID   bool
+	)
+
+	for _, line := range unfoldVCardLines(string(data)) {
+		name, params, value, ok := splitVCardLine(line)
+		if !ok {
+			continue
+		}
+
+		switch name {
+		case "BEGIN":
+			if strings.EqualFold(value, "VCARD") {
+				current, givenName, hasWaID = &ContactCard{}, "", false
+			}
+		case "END":
+			if current != nil && strings.EqualFold(value, "VCARD") {
+				if current.Name == "" {
+					current.Name = givenName
+				}
+				cards = append(cards, *current)
+				current = nil
+			}
+		case "FN":
+			if current != nil {
+				current.Name = unescapeVCardValue(value)
+			}
+		case "N":
+			if current != nil {
+				// N is family;given;additional;prefix;suffix
+				parts := strings.Split(value, ";")
+				var nameParts []string
+				for _, index := range []int{3, 1, 2, 0, 4} {
+					if index < len(parts) && strings.TrimSpace(parts[index]) != "" {
+						nameParts = append(nameParts, unescapeVCardValue(strings.TrimSpace(parts[index])))
+					}
+				}
+				givenName = strings.Join(nameParts, " ")
+			}
+		case "TEL":
+			if current == nil || hasWaID {
+				continue
+			}
+			for _, param := range params {
+				if key, waID, found := strings.Cut(param, "="); found && strings.EqualFold(key, "waid") {
+					if phone := NormalizeContactPhone(waID); phone != "" {
+						current.Phone, hasWaID = phone, true
+					}
+				}
+			}
+			if current.Phone == "" {
+				current.Phone = NormalizeContactPhone(value)
+			}
+		}
+	}
+
+	return cards
+}
+
+// FormatVCard writes contacts as a vCard 3.0 file that WhatsApp recognises
+func FormatVCard(cards []ContactCard) []byte {
+	var buffer bytes.Buffer
+	for _, card := range cards {
+		name := escapeVCardValue(card.Name)
+		buffer.WriteString("BEGIN:VCARD\r\nVERSION:3.0\r\n")
+		buffer.WriteString(fmt.Sprintf("N:;%s;;;\r\nFN:%s\r\n", name, name))
+		buffer.WriteString(fmt.Sprintf("TEL;type=CELL;waid=%s:+%s\r\n", card.Phone, card.Phone))
+		buffer.WriteString("END:VCARD\r\n")
+	}
+	return buffer.Bytes()
+}
+
+// ParseContactsCSV reads contacts from a CSV file with a header row. The name is
+// taken from a "name" or "saved_name" column and the number from a "phone",
+// "phone_number" or "phone_jid" column.
+func ParseContactsCSV(data []byte) ([]ContactCard, error) {
+	reader := csv.NewReader(bytes.NewReader(data))
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if err == io.EOF {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read CSV header: %w", err)
+	}
+
+	nameColumn, phoneColumn := -1, -1
+	for index, column := range header {
+		switch strings.ToLower(strings.TrimSpace(strings.TrimPrefix(column, "\ufeff"))) {
+		case "name", "saved_name":
+			if nameColumn < 0 {
+				nameColumn = index
+			}
+		case "phone", "phone_number", "phone_jid":
+			if phoneColumn < 0 {
+				phoneColumn = index
+			}
+		}
+	}
+	if phoneColumn < 0 {
+		return nil, fmt.Errorf("CSV header must contain a phone column")
+	}
+
+	var cards []ContactCard
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read CSV record: %w", err)
+		}
+
+		var card ContactCard
+		if nameColumn >= 0 && nameColumn < len(record) {
+			card.Name = strings.TrimSpace(record[nameColumn])
+		}
+		if phoneColumn < len(record) {
+			card.Phone = NormalizeContactPhone(record[phoneColumn])
+		}
+		cards = append(cards, card)
+	}
+
+	return cards, nil
+}
+
+// NormalizeContactPhone reduces a phone number or user JID to its digits
+func NormalizeContactPhone(phone string) string {
+	phone, _, _ = strings.Cut(phone, "@")
+	phone, _, _ = strings.Cut(phone, ":")
+
+	var digits strings.Builder
+	for _, r := range phone {
+		if r >= '0' && r <= '9' {
+			digits.WriteRune(r)
+		}
+	}
+	return digits.String()
+}
+
+// unfoldVCardLines joins continuation lines, which start with a space or tab
+func unfoldVCardLines(data string) []string {
+	var lines []string
+	for _, line := range strings.Split(strings.ReplaceAll(data, "\r\n", "\n"), "\n") {
+		if (strings.HasPrefix(line, " ") || strings.HasPrefix(line, "\t")) && len(lines) > 0 {
+			lines[len(lines)-1] += line[1:]
+			continue
+		}
+		lines = append(lines, strings.TrimRight(line, "\r"))
+	}
+	return lines
+}
+
+// splitVCardLine splits "group.NAME;param=x:value" into its parts
+func splitVCardLine(line string) (name string, params []string, value string, ok bool) {
+	property, value, ok := strings.Cut(line, ":")
+	if !ok {
+		return "", nil, "", false
+	}
+
+	parts := strings.Split(property, ";")
+	name = strings.ToUpper(strings.TrimSpace(parts[0]))
+	if dot := strings.LastIndex(name, "."); dot >= 0 {
+		name = name[dot+1:]
+	}
+
+	return name, parts[1:], strings.TrimSpace(value), true
+}
+
+func unescapeVCardValue(value string) string {
+	return strings.NewReplacer(`\n`, " ", `\N`, " ", `\,`, ",", `\;`, ";", `\\`, `\`).Replace(value)
+}
+
+func escapeVCardValue(value string) string {
+	return strings.NewReplacer(`\`, `\\`, ",", `\,`, ";", `\;`, "\n", `\n`).Replace(value)
+}
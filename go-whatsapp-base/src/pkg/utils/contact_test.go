@@ -0,0 +1,79 @@
+package utils_test
+
+import (
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/utils"
+)
+
+func (suite *UtilsTestSuite) TestParseVCards() {
+	data := "BEGIN:VCARD\r\nVERSION:3.0\r\nN:Doe;John;;;\r\nFN:John Doe\r\n" +
+		"TEL;TYPE=HOME:+1 555 0100\r\nitem1.TEL;type=CELL;waid=628123456789:+62 812-3456-789\r\nEND:VCARD\r\n" +
+		"BEGIN:VCARD\nVERSION:3.0\nN:Smith;Jane;;;\nTEL:+44 20 7946\n 0958\nEND:VCARD\n" +
+		"BEGIN:VCARD\nVERSION:3.0\nFN:No Phone\\, Ltd\nEND:VCARD\n"
+
+	cards := utils.ParseVCards([]byte(data))
+
+	suite.Equal([]utils.ContactCard{
+		{Name: "John Doe", Phone: "628123456789"},
+		{Name: "Jane Smith", Phone: "442079460958"},
+		{Name: "No Phone, Ltd", Phone: ""},
+	}, cards)
+}
+
+func (suite *UtilsTestSuite) TestFormatVCardRoundTrip() {
+	cards := []utils.ContactCard{
+		{Name: "Doe, John", Phone: "628123456789"},
+		{Name: "Jane", Phone: "6281987654"},
+	}
+
+	suite.Equal(cards, utils.ParseVCards(utils.FormatVCard(cards)))
+}
+
+func (suite *UtilsTestSuite) TestParseContactsCSV() {
+	tests := []struct {
+		name    string
+		data    string
+		want    []utils.ContactCard
+		wantErr bool
+	}{
+		{
+			name: "should read name and phone columns",
+			data: "name,phone\nJohn,+62 812 3456 789\nJane,6281987654\n",
+			want: []utils.ContactCard{
+				{Name: "John", Phone: "628123456789"},
+				{Name: "Jane", Phone: "6281987654"},
+			},
+		},
+		{
+			name: "should read exported contact columns",
+			data: "jid,phone_jid,saved_name\n628123@s.whatsapp.net,628123@s.whatsapp.net,John\n",
+			want: []utils.ContactCard{{Name: "John", Phone: "628123"}},
+		},
+		{
+			name:    "should fail without phone column",
+			data:    "name,email\nJohn,john@example.com\n",
+			wantErr: true,
+		},
+		{
+			name: "should return nothing for empty file",
+			data: "",
+		},
+	}
+
+	for _, tt := range tests {
+		suite.Run(tt.name, func() {
+			got, err := utils.ParseContactsCSV([]byte(tt.data))
+			if tt.wantErr {
+				suite.Error(err)
+				return
+			}
+			suite.NoError(err)
+			suite.Equal(tt.want, got)
+		})
+	}
+}
+
+func (suite *UtilsTestSuite) TestNormalizeContactPhone() {
+	suite.Equal("628123", utils.NormalizeContactPhone("628123:12@s.whatsapp.net"))
+	suite.Equal("628123", utils.NormalizeContactPhone("+62 (812) 3"))
+	suite.Equal("", utils.NormalizeContactPhone("unknown"))
+}
@@ -0,0 +1,19 @@
+//go:build windows
+
+package utils
+
+import "golang.org/x/sys/windows"
+
+// DiskFreeBytes returns the bytes available to the current user on the
+// volume holding path
+func DiskFreeBytes(path string) (uint64, error) {
+	pathPtr, err := windows.UTF16PtrFromString(path)
+	if err != nil {
+		return 0, err
+	}
+	var freeBytes uint64
+	if err := windows.GetDiskFreeSpaceEx(pathPtr, &freeBytes, nil, nil); err != nil {
+		return 0, err
+	}
+	return freeBytes, nil
+}
@@ -232,6 +232,33 @@ func ContainsMention(message string) []string {
 	return phoneNumbers
 }
 
+// templatePlaceholderRegex matches message template placeholders such as {{name}} or {{ name }}
+var templatePlaceholderRegex = regexp.MustCompile(`\{\{\s*([A-Za-z_][A-Za-z0-9_]*)\s*\}\}`)
+
+// TemplatePlaceholders returns the variable names referenced by a template body, in order of first use
+func TemplatePlaceholders(body string) []string {
+	var names []string
+	seen := make(map[string]bool)
+	for _, match := range templatePlaceholderRegex.FindAllStringSubmatch(body, -1) {
+		if !seen[match[1]] {
+			seen[match[1]] = true
+			names = append(names, match[1])
+		}
+	}
+	return names
+}
+
+// RenderTemplatePlaceholders replaces {{name}} placeholders with their values. Unknown placeholders are kept as-is.
+func RenderTemplatePlaceholders(body string, values map[string]string) string {
+	return templatePlaceholderRegex.ReplaceAllStringFunc(body, func(placeholder string) string {
+		name := templatePlaceholderRegex.FindStringSubmatch(placeholder)[1]
+		if value, ok := values[name]; ok {
+			return value
+		}
+		return placeholder
+	})
+}
+
 func DownloadImageFromURL(url string) ([]byte, string, error) {
 	client := &http.Client{
 		Timeout: 30 * time.Second,
@@ -53,6 +53,20 @@ func (suite *UtilsTestSuite) TestContainsMention() {
 	}
 }
 
+func (suite *UtilsTestSuite) TestTemplatePlaceholders() {
+	got := utils.TemplatePlaceholders("Hi {{name}}, order {{ order_id }} ships {{date}}. Thanks {{name}}! {{not valid}}")
+	assert.Equal(suite.T(), []string{"name", "order_id", "date"}, got)
+	assert.Nil(suite.T(), utils.TemplatePlaceholders("no placeholders {name}"))
+}
+
+func (suite *UtilsTestSuite) TestRenderTemplatePlaceholders() {
+	got := utils.RenderTemplatePlaceholders("Hi {{name}}, order {{ order_id }} is {{status}}", map[string]string{
+		"name":     "Budi",
+		"order_id": "A-1",
+	})
+	assert.Equal(suite.T(), "Hi Budi, order A-1 is {{status}}", got)
+}
+
 func (suite *UtilsTestSuite) TestRemoveFile() {
 	tempFile, err := os.CreateTemp("", "testfile")
 	assert.NoError(suite.T(), err)
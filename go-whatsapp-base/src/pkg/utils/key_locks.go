@@ -0,0 +1,55 @@
+package utils
+
+import "sync"
+
+// KeyLocks hands out one mutex per key and forgets it once nobody holds or
+// waits for it, so waiters never end up on a mutex a newcomer doesn't see and
+// the set of keys does not grow without bound
+type KeyLocks struct {
+	mu    sync.Mutex
+	locks map[string]*keyLock
+}
+
+type keyLock struct {
+	sync.Mutex
+	refs int
+}
+
+// NewKeyLocks creates an empty set of per-key locks
+func NewKeyLocks() *KeyLocks {
+	return &KeyLocks{locks: make(map[string]*keyLock)}
+}
+
+// Lock blocks until the lock of key is free and takes it
+func (k *KeyLocks) Lock(key string) {
+	k.mu.Lock()
+	lock, ok := k.locks[key]
+	if !ok {
+		lock = &keyLock{}
+		k.locks[key] = lock
+	}
+	lock.refs++
+	k.mu.Unlock()
+
+	lock.Lock()
+}
+
+// Unlock releases the lock of key taken by Lock
+func (k *KeyLocks) Unlock(key string) {
+	k.mu.Lock()
+	lock := k.locks[key]
+	lock.refs--
+	if lock.refs == 0 {
+		delete(k.locks, key)
+	}
+	k.mu.Unlock()
+
+	lock.Unlock()
+}
+
+// Len returns how many keys are locked or waited for
+func (k *KeyLocks) Len() int {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+	return len(k.locks)
+}
@@ -0,0 +1,32 @@
+package utils_test
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/utils"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestKeyLocks(t *testing.T) {
+	locks := utils.NewKeyLocks()
+
+	var wg sync.WaitGroup
+	counters := map[string]*int{"a": new(int), "b": new(int)}
+	for i := 0; i < 50; i++ {
+		for key, counter := range counters {
+			wg.Add(1)
+			go func(key string, counter *int) {
+				defer wg.Done()
+				locks.Lock(key)
+				defer locks.Unlock(key)
+				*counter++
+			}(key, counter)
+		}
+	}
+	wg.Wait()
+
+	assert.Equal(t, 50, *counters["a"])
+	assert.Equal(t, 50, *counters["b"])
+	assert.Equal(t, 0, locks.Len(), "released keys should be forgotten")
+}
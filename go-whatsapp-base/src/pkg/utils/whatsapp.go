@@ -119,24 +119,106 @@ func ExtractMessageTextFromProto(msg *waE2E.Message) string {
 		return doc.GetCaption()
 	}
 
-	// Check for buttons response message
+	// Check for button, list and template replies
+	if reply := ExtractInteractiveReply(msg); reply != nil {
+		if reply.SelectedTitle != "" {
+			return reply.SelectedTitle
+		}
+		return reply.SelectedID
+	}
+
+	return ""
+}
+
+// ExtractInteractiveReply returns the choice made in a reply to a buttons, list
+// or template message, or nil when the message is not such a reply
+func ExtractInteractiveReply(msg *waE2E.Message) *EvtInteractiveReply {
+	if msg == nil {
+		return nil
+	}
+
 	if buttonsResponse := msg.GetButtonsResponseMessage(); buttonsResponse != nil {
-		return buttonsResponse.GetSelectedDisplayText()
+		return &EvtInteractiveReply{
+			Type:              InteractiveReplyButton,
+			SelectedID:        buttonsResponse.GetSelectedButtonID(),
+			SelectedTitle:     buttonsResponse.GetSelectedDisplayText(),
+			OriginalMessageID: buttonsResponse.GetContextInfo().GetStanzaID(),
+		}
 	}
 
-	// Check for list response message
 	if listResponse := msg.GetListResponseMessage(); listResponse != nil {
-		return listResponse.GetTitle()
+		return &EvtInteractiveReply{
+			Type:              InteractiveReplyList,
+			SelectedID:        listResponse.GetSingleSelectReply().GetSelectedRowID(),
+			SelectedTitle:     listResponse.GetTitle(),
+			Description:       listResponse.GetDescription(),
+			OriginalMessageID: listResponse.GetContextInfo().GetStanzaID(),
+		}
 	}
 
-	// Check for template button reply
 	if templateButtonReply := msg.GetTemplateButtonReplyMessage(); templateButtonReply != nil {
-		return templateButtonReply.GetSelectedDisplayText()
+		return &EvtInteractiveReply{
+			Type:              InteractiveReplyTemplate,
+			SelectedID:        templateButtonReply.GetSelectedID(),
+			SelectedTitle:     templateButtonReply.GetSelectedDisplayText(),
+			OriginalMessageID: templateButtonReply.GetContextInfo().GetStanzaID(),
+		}
 	}
 
+	return nil
+}
+
+// IsViewOnceMedia reports whether the image, video or audio of a message can only
+// be viewed once, either flagged on the media or wrapped as in history sync
+func IsViewOnceMedia(msg *waE2E.Message) bool {
+	if msg == nil {
+		return false
+	}
+	return msg.GetViewOnceMessage() != nil ||
+		msg.GetViewOnceMessageV2() != nil ||
+		msg.GetViewOnceMessageV2Extension() != nil ||
+		msg.GetImageMessage().GetViewOnce() ||
+		msg.GetVideoMessage().GetViewOnce() ||
+		msg.GetAudioMessage().GetViewOnce()
+}
+
+// ExtractMediaMimetype returns the mimetype the sender gave the media of a message
+func ExtractMediaMimetype(msg *waE2E.Message) string {
+	switch {
+	case msg.GetImageMessage() != nil:
+		return msg.GetImageMessage().GetMimetype()
+	case msg.GetVideoMessage() != nil:
+		return msg.GetVideoMessage().GetMimetype()
+	case msg.GetAudioMessage() != nil:
+		return msg.GetAudioMessage().GetMimetype()
+	case msg.GetDocumentMessage() != nil:
+		return msg.GetDocumentMessage().GetMimetype()
+	case msg.GetStickerMessage() != nil:
+		return msg.GetStickerMessage().GetMimetype()
+	}
 	return ""
 }
 
+// ExtractForwardingScore returns how many times a message was already forwarded
+func ExtractForwardingScore(msg *waE2E.Message) uint32 {
+	var ctxInfo *waE2E.ContextInfo
+	switch {
+	case msg.GetExtendedTextMessage() != nil:
+		ctxInfo = msg.GetExtendedTextMessage().GetContextInfo()
+	case msg.GetImageMessage() != nil:
+		ctxInfo = msg.GetImageMessage().GetContextInfo()
+	case msg.GetVideoMessage() != nil:
+		ctxInfo = msg.GetVideoMessage().GetContextInfo()
+	case msg.GetAudioMessage() != nil:
+		ctxInfo = msg.GetAudioMessage().GetContextInfo()
+	case msg.GetDocumentMessage() != nil:
+		ctxInfo = msg.GetDocumentMessage().GetContextInfo()
+	case msg.GetStickerMessage() != nil:
+		ctxInfo = msg.GetStickerMessage().GetContextInfo()
+	}
+	return ctxInfo.GetForwardingScore()
+}
+
 // ExtractMessageTextFromEvent extracts text content from a WhatsApp event message with emojis
 func ExtractMessageTextFromEvent(evt *events.Message) string {
 	messageText := evt.Message.GetConversation()
@@ -198,6 +280,11 @@ func ExtractMessageTextFromEvent(evt *events.Message) string {
 		} else {
 			messageText = "👤 " + messageText
 		}
+	} else if reply := ExtractInteractiveReply(evt.Message); reply != nil {
+		messageText = "🔘 " + reply.SelectedTitle
+		if reply.SelectedTitle == "" {
+			messageText = "🔘 " + reply.SelectedID
+		}
 	} else if listMessage := evt.Message.GetListMessage(); listMessage != nil {
 		messageText = listMessage.GetTitle()
 		if messageText == "" {
@@ -650,6 +737,22 @@ type EvtMessage struct {
 	QuotedMessage string `json:"quoted_message"`
 }
 
+// Interactive reply types
+const (
+	InteractiveReplyButton   = "button"
+	InteractiveReplyList     = "list"
+	InteractiveReplyTemplate = "template_button"
+)
+
+// EvtInteractiveReply is the choice made in a reply to a buttons, list or template message
+type EvtInteractiveReply struct {
+	Type              string `json:"type"`
+	SelectedID        string `json:"selected_id"`
+	SelectedTitle     string `json:"selected_title"`
+	Description       string `json:"description,omitempty"`
+	OriginalMessageID string `json:"original_message_id,omitempty"`
+}
+
 type EvtReaction struct {
 	Message string `json:"message"`
 	ID      string `json:"id"`
@@ -682,6 +785,9 @@ func BuildEventMessage(evt *events.Message) (message EvtMessage) {
 				message.QuotedMessage = extendedText.ContextInfo.GetQuotedMessage().GetConversation()
 			}
 		}
+	} else if reply := ExtractInteractiveReply(evt.Message); reply != nil {
+		message.Text = reply.SelectedTitle
+		message.RepliedId = reply.OriginalMessageID
 	}
 
 	return message
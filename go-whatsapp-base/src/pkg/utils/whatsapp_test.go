@@ -1,6 +1,13 @@
 package utils
 
-import "testing"
+import (
+	"testing"
+
+	"go.mau.fi/whatsmeow/proto/waE2E"
+	"go.mau.fi/whatsmeow/types"
+	"go.mau.fi/whatsmeow/types/events"
+	"google.golang.org/protobuf/proto"
+)
 
 func TestDetermineMediaExtension(t *testing.T) {
 	tests := []struct {
@@ -50,3 +57,99 @@ func TestDetermineMediaExtension(t *testing.T) {
 		})
 	}
 }
+
+func TestExtractInteractiveReply(t *testing.T) {
+	quoted := &waE2E.ContextInfo{StanzaID: proto.String("3EB0B430B6F8F1D0E053AC120E0A9E5C")}
+	tests := []struct {
+		name string
+		msg  *waE2E.Message
+		want *EvtInteractiveReply
+	}{
+		{
+			name: "ButtonsResponse",
+			msg: &waE2E.Message{ButtonsResponseMessage: &waE2E.ButtonsResponseMessage{
+				SelectedButtonID: proto.String("confirm"),
+				Response:         &waE2E.ButtonsResponseMessage_SelectedDisplayText{SelectedDisplayText: "Yes, confirm"},
+				ContextInfo:      quoted,
+			}},
+			want: &EvtInteractiveReply{Type: InteractiveReplyButton, SelectedID: "confirm", SelectedTitle: "Yes, confirm",
+				OriginalMessageID: "3EB0B430B6F8F1D0E053AC120E0A9E5C"},
+		},
+		{
+			name: "ListResponse",
+			msg: &waE2E.Message{ListResponseMessage: &waE2E.ListResponseMessage{
+				Title:             proto.String("Medium"),
+				Description:       proto.String("Fits 2-3 people"),
+				SingleSelectReply: &waE2E.ListResponseMessage_SingleSelectReply{SelectedRowID: proto.String("size_m")},
+				ContextInfo:       quoted,
+			}},
+			want: &EvtInteractiveReply{Type: InteractiveReplyList, SelectedID: "size_m", SelectedTitle: "Medium",
+				Description: "Fits 2-3 people", OriginalMessageID: "3EB0B430B6F8F1D0E053AC120E0A9E5C"},
+		},
+		{
+			name: "TemplateButtonReply",
+			msg: &waE2E.Message{TemplateButtonReplyMessage: &waE2E.TemplateButtonReplyMessage{
+				SelectedID:          proto.String("track_order"),
+				SelectedDisplayText: proto.String("Track order"),
+				ContextInfo:         quoted,
+			}},
+			want: &EvtInteractiveReply{Type: InteractiveReplyTemplate, SelectedID: "track_order", SelectedTitle: "Track order",
+				OriginalMessageID: "3EB0B430B6F8F1D0E053AC120E0A9E5C"},
+		},
+		{
+			name: "PlainText",
+			msg:  &waE2E.Message{Conversation: proto.String("hello")},
+			want: nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := ExtractInteractiveReply(tt.msg)
+			if (got == nil) != (tt.want == nil) || (got != nil && *got != *tt.want) {
+				t.Fatalf("ExtractInteractiveReply() = %+v, want %+v", got, tt.want)
+			}
+			if tt.want != nil && ExtractMessageTextFromProto(tt.msg) != tt.want.SelectedTitle {
+				t.Fatalf("ExtractMessageTextFromProto() = %q, want %q", ExtractMessageTextFromProto(tt.msg), tt.want.SelectedTitle)
+			}
+		})
+	}
+}
+
+func TestBuildEventMessageInteractiveReply(t *testing.T) {
+	evt := &events.Message{
+		Info: types.MessageInfo{ID: "3EB0C127D7BACC83D6A9"},
+		Message: &waE2E.Message{ListResponseMessage: &waE2E.ListResponseMessage{
+			Title:             proto.String("Medium"),
+			SingleSelectReply: &waE2E.ListResponseMessage_SingleSelectReply{SelectedRowID: proto.String("size_m")},
+			ContextInfo:       &waE2E.ContextInfo{StanzaID: proto.String("3EB0B430B6F8F1D0E053AC120E0A9E5C")},
+		}},
+	}
+
+	message := BuildEventMessage(evt)
+	if message.Text != "Medium" || message.RepliedId != "3EB0B430B6F8F1D0E053AC120E0A9E5C" {
+		t.Fatalf("BuildEventMessage() = %+v, want the selected title replying to the list message", message)
+	}
+}
+
+func TestIsViewOnceMedia(t *testing.T) {
+	tests := []struct {
+		name string
+		msg  *waE2E.Message
+		want bool
+	}{
+		{"FlaggedImage", &waE2E.Message{ImageMessage: &waE2E.ImageMessage{ViewOnce: proto.Bool(true)}}, true},
+		{"FlaggedVideo", &waE2E.Message{VideoMessage: &waE2E.VideoMessage{ViewOnce: proto.Bool(true)}}, true},
+		{"WrappedV2", &waE2E.Message{ViewOnceMessageV2: &waE2E.FutureProofMessage{Message: &waE2E.Message{}}}, true},
+		{"RegularImage", &waE2E.Message{ImageMessage: &waE2E.ImageMessage{}}, false},
+		{"Nil", nil, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := IsViewOnceMedia(tt.msg); got != tt.want {
+				t.Fatalf("IsViewOnceMedia() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
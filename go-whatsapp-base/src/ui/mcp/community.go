@@ -0,0 +1,251 @@
+package mcp
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	domainGroup "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/group"
+	domainSend "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/send"
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/utils"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+type CommunityHandler struct {
+	groupService domainGroup.IGroupUsecase
+	sendService  domainSend.ISendUsecase
+}
+
+func InitMcpCommunity(groupService domainGroup.IGroupUsecase, sendService domainSend.ISendUsecase) *CommunityHandler {
+	return &CommunityHandler{groupService: groupService, sendService: sendService}
+}
+
+func (h *CommunityHandler) AddCommunityTools(mcpServer *server.MCPServer) {
+	mcpServer.AddTool(h.toolCreateCommunity(), h.handleCreateCommunity)
+	mcpServer.AddTool(h.toolListCommunityGroups(), h.handleListCommunityGroups)
+	mcpServer.AddTool(h.toolCommunityGroups("whatsapp_community_link_groups", "Link existing groups into a community as sub-groups.", "Link Community Groups"), h.handleLinkCommunityGroups)
+	mcpServer.AddTool(h.toolCommunityGroups("whatsapp_community_unlink_groups", "Unlink sub-groups from a community. The groups themselves are kept.", "Unlink Community Groups"), h.handleUnlinkCommunityGroups)
+	mcpServer.AddTool(h.toolSendAnnouncement(), h.handleSendAnnouncement)
+}
+
+func (h *CommunityHandler) toolCreateCommunity() mcp.Tool {
+	return mcp.NewTool(
+		"whatsapp_community_create",
+		mcp.WithDescription("Create a WhatsApp community. WhatsApp creates its announcement group automatically."),
+		mcp.WithTitleAnnotation("Create Community"),
+		mcp.WithReadOnlyHintAnnotation(false),
+		mcp.WithDestructiveHintAnnotation(true),
+		mcp.WithIdempotentHintAnnotation(false),
+		mcp.WithString("title",
+			mcp.Description("Community name."),
+			mcp.Required(),
+		),
+		mcp.WithArray("participants",
+			mcp.Description("Phone numbers to add during creation (without @s.whatsapp.net suffix)."),
+			mcp.WithStringItems(),
+		),
+	)
+}
+
+func (h *CommunityHandler) handleCreateCommunity(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	title, err := request.RequireString("title")
+	if err != nil {
+		return nil, err
+	}
+
+	var participants []string
+	if args := request.GetArguments(); args != nil {
+		if raw, ok := args["participants"]; ok {
+			participants, err = toStringSlice(raw)
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	communityID, err := h.groupService.CreateCommunity(ctx, domainGroup.CreateCommunityRequest{
+		Title:        strings.TrimSpace(title),
+		Participants: participants,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	structured := map[string]any{
+		"community_id": communityID,
+		"title":        strings.TrimSpace(title),
+		"members":      len(participants),
+	}
+
+	fallback := fmt.Sprintf("Created community %s with %d members", communityID, len(participants))
+	return mcp.NewToolResultStructured(structured, fallback), nil
+}
+
+func (h *CommunityHandler) toolListCommunityGroups() mcp.Tool {
+	return mcp.NewTool(
+		"whatsapp_community_list_groups",
+		mcp.WithDescription("List the groups linked to a community, including its announcement group."),
+		mcp.WithTitleAnnotation("List Community Groups"),
+		mcp.WithReadOnlyHintAnnotation(true),
+		mcp.WithIdempotentHintAnnotation(true),
+		mcp.WithString("community_id",
+			mcp.Description("Community JID or numeric ID."),
+			mcp.Required(),
+		),
+	)
+}
+
+func (h *CommunityHandler) handleListCommunityGroups(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	communityID, err := request.RequireString("community_id")
+	if err != nil {
+		return nil, err
+	}
+
+	trimmed := strings.TrimSpace(communityID)
+	utils.SanitizePhone(&trimmed)
+
+	resp, err := h.groupService.ListCommunityGroups(ctx, domainGroup.ListCommunityGroupsRequest{CommunityID: trimmed})
+	if err != nil {
+		return nil, err
+	}
+
+	fallback := fmt.Sprintf("Community %s has %d groups", resp.CommunityID, len(resp.Groups))
+	return mcp.NewToolResultStructured(resp, fallback), nil
+}
+
+func (h *CommunityHandler) toolCommunityGroups(name, description, title string) mcp.Tool {
+	return mcp.NewTool(
+		name,
+		mcp.WithDescription(description),
+		mcp.WithTitleAnnotation(title),
+		mcp.WithReadOnlyHintAnnotation(false),
+		mcp.WithDestructiveHintAnnotation(true),
+		mcp.WithIdempotentHintAnnotation(true),
+		mcp.WithString("community_id",
+			mcp.Description("Community JID or numeric ID."),
+			mcp.Required(),
+		),
+		mcp.WithArray("group_ids",
+			mcp.Description("Group JIDs or numeric IDs."),
+			mcp.Required(),
+			mcp.WithStringItems(),
+		),
+	)
+}
+
+func (h *CommunityHandler) handleLinkCommunityGroups(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	groupsRequest, err := communityGroupsRequest(request)
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := h.groupService.LinkCommunityGroups(ctx, groupsRequest)
+	if err != nil {
+		return nil, err
+	}
+
+	fallback := fmt.Sprintf("Linked %d groups to %s", len(groupsRequest.GroupIDs), groupsRequest.CommunityID)
+	return mcp.NewToolResultStructured(result, fallback), nil
+}
+
+func (h *CommunityHandler) handleUnlinkCommunityGroups(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	groupsRequest, err := communityGroupsRequest(request)
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := h.groupService.UnlinkCommunityGroups(ctx, groupsRequest)
+	if err != nil {
+		return nil, err
+	}
+
+	fallback := fmt.Sprintf("Unlinked %d groups from %s", len(groupsRequest.GroupIDs), groupsRequest.CommunityID)
+	return mcp.NewToolResultStructured(result, fallback), nil
+}
+
+func communityGroupsRequest(request mcp.CallToolRequest) (domainGroup.CommunityGroupsRequest, error) {
+	communityID, err := request.RequireString("community_id")
+	if err != nil {
+		return domainGroup.CommunityGroupsRequest{}, err
+	}
+
+	groupIDs, err := toStringSlice(request.GetArguments()["group_ids"])
+	if err != nil {
+		return domainGroup.CommunityGroupsRequest{}, err
+	}
+	if len(groupIDs) == 0 {
+		return domainGroup.CommunityGroupsRequest{}, fmt.Errorf("group_ids cannot be empty")
+	}
+
+	result := domainGroup.CommunityGroupsRequest{CommunityID: strings.TrimSpace(communityID)}
+	utils.SanitizePhone(&result.CommunityID)
+	for _, groupID := range groupIDs {
+		trimmed := strings.TrimSpace(groupID)
+		utils.SanitizePhone(&trimmed)
+		result.GroupIDs = append(result.GroupIDs, trimmed)
+	}
+
+	return result, nil
+}
+
+func (h *CommunityHandler) toolSendAnnouncement() mcp.Tool {
+	return mcp.NewTool(
+		"whatsapp_community_send_announcement",
+		mcp.WithDescription("Send a text message to a community's announcement group."),
+		mcp.WithTitleAnnotation("Send Community Announcement"),
+		mcp.WithReadOnlyHintAnnotation(false),
+		mcp.WithDestructiveHintAnnotation(false),
+		mcp.WithIdempotentHintAnnotation(false),
+		mcp.WithString("community_id",
+			mcp.Description("Community JID or numeric ID."),
+			mcp.Required(),
+		),
+		mcp.WithString("message",
+			mcp.Description("The text message to send (required unless template_id is set)."),
+		),
+		mcp.WithString("template_id",
+			mcp.Description("Stored message template to render instead of message (optional)."),
+		),
+		mcp.WithObject("variables",
+			mcp.Description("Template variable values keyed by variable name."),
+		),
+		mcp.WithString("language",
+			mcp.Description("Preferred template language variant, e.g. en or pt-BR (optional)."),
+		),
+	)
+}
+
+func (h *CommunityHandler) handleSendAnnouncement(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	communityID, err := request.RequireString("community_id")
+	if err != nil {
+		return nil, err
+	}
+
+	variables, err := toStringMap(request.GetArguments()["variables"])
+	if err != nil {
+		return nil, err
+	}
+
+	trimmed := strings.TrimSpace(communityID)
+	utils.SanitizePhone(&trimmed)
+
+	announcement, err := h.groupService.GetAnnouncementGroup(ctx, domainGroup.GetAnnouncementGroupRequest{CommunityID: trimmed})
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := h.sendService.SendText(ctx, domainSend.MessageRequest{
+		BaseRequest: domainSend.BaseRequest{Phone: announcement.GroupID},
+		Message:     request.GetString("message", ""),
+		TemplateID:  request.GetString("template_id", ""),
+		Variables:   variables,
+		Language:    request.GetString("language", ""),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	fallback := fmt.Sprintf("Announcement sent to %s (ID: %s)", announcement.GroupID, resp.MessageID)
+	return mcp.NewToolResultStructured(resp, fallback), nil
+}
@@ -79,6 +79,9 @@ func (h *QueryHandler) toolListChats() mcp.Tool {
 			mcp.Description("If true, return only chats that contain media messages."),
 			mcp.DefaultBool(false),
 		),
+		mcp.WithString("label_id",
+			mcp.Description("Return only chats that have this WhatsApp Business label."),
+		),
 	)
 }
 
@@ -100,6 +103,7 @@ func (h *QueryHandler) handleListChats(ctx context.Context, request mcp.CallTool
 		Offset:   request.GetInt("offset", 0),
 		Search:   request.GetString("search", ""),
 		HasMedia: hasMedia,
+		LabelID:  request.GetString("label_id", ""),
 	}
 
 	resp, err := h.chatService.ListChats(ctx, req)
@@ -32,13 +32,15 @@ func (s *SendHandler) AddSendTools(mcpServer *server.MCPServer) {
 func (s *SendHandler) toolSendText() mcp.Tool {
 	sendTextTool := mcp.NewTool("whatsapp_send_text",
 		mcp.WithDescription("Send a text message to a WhatsApp contact or group."),
+		mcp.WithString("idempotency_key",
+			mcp.Description("Unique key for this send. Retrying with the same key returns the first result instead of sending again (optional)"),
+		),
 		mcp.WithString("phone",
 			mcp.Required(),
 			mcp.Description("Phone number or group ID to send message to"),
 		),
 		mcp.WithString("message",
-			mcp.Required(),
-			mcp.Description("The text message to send"),
+			mcp.Description("The text message to send (required unless template_id is set)"),
 		),
 		mcp.WithBoolean("is_forwarded",
 			mcp.Description("Whether this message is being forwarded (default: false)"),
@@ -46,6 +48,15 @@ func (s *SendHandler) toolSendText() mcp.Tool {
 		mcp.WithString("reply_message_id",
 			mcp.Description("Message ID to reply to (optional)"),
 		),
+		mcp.WithString("template_id",
+			mcp.Description("Stored message template to render instead of message (optional)"),
+		),
+		mcp.WithObject("variables",
+			mcp.Description("Template variable values keyed by variable name"),
+		),
+		mcp.WithString("language",
+			mcp.Description("Preferred template language variant, e.g. en or pt-BR (optional)"),
+		),
 	)
 
 	return sendTextTool
@@ -57,8 +68,10 @@ func (s *SendHandler) handleSendText(ctx context.Context, request mcp.CallToolRe
 		return nil, errors.New("phone must be a string")
 	}
 
+	templateID := request.GetString("template_id", "")
+
 	message, ok := request.GetArguments()["message"].(string)
-	if !ok {
+	if !ok && templateID == "" {
 		return nil, errors.New("message must be a string")
 	}
 
@@ -72,13 +85,22 @@ func (s *SendHandler) handleSendText(ctx context.Context, request mcp.CallToolRe
 		replyMessageId = ""
 	}
 
+	variables, err := toStringMap(request.GetArguments()["variables"])
+	if err != nil {
+		return nil, err
+	}
+
 	res, err := s.sendService.SendText(ctx, domainSend.MessageRequest{
 		BaseRequest: domainSend.BaseRequest{
-			Phone:       phone,
-			IsForwarded: isForwarded,
+			IdempotencyKey: request.GetString("idempotency_key", ""),
+			Phone:          phone,
+			IsForwarded:    isForwarded,
 		},
 		Message:        message,
 		ReplyMessageID: &replyMessageId,
+		TemplateID:     templateID,
+		Variables:      variables,
+		Language:       request.GetString("language", ""),
 	})
 
 	if err != nil {
@@ -91,6 +113,9 @@ func (s *SendHandler) handleSendText(ctx context.Context, request mcp.CallToolRe
 func (s *SendHandler) toolSendContact() mcp.Tool {
 	sendContactTool := mcp.NewTool("whatsapp_send_contact",
 		mcp.WithDescription("Send a contact card to a WhatsApp contact or group."),
+		mcp.WithString("idempotency_key",
+			mcp.Description("Unique key for this send. Retrying with the same key returns the first result instead of sending again (optional)"),
+		),
 		mcp.WithString("phone",
 			mcp.Required(),
 			mcp.Description("Phone number or group ID to send contact to"),
@@ -134,8 +159,9 @@ func (s *SendHandler) handleSendContact(ctx context.Context, request mcp.CallToo
 
 	res, err := s.sendService.SendContact(ctx, domainSend.ContactRequest{
 		BaseRequest: domainSend.BaseRequest{
-			Phone:       phone,
-			IsForwarded: isForwarded,
+			IdempotencyKey: request.GetString("idempotency_key", ""),
+			Phone:          phone,
+			IsForwarded:    isForwarded,
 		},
 		ContactName:  contactName,
 		ContactPhone: contactPhone,
@@ -151,6 +177,9 @@ func (s *SendHandler) handleSendContact(ctx context.Context, request mcp.CallToo
 func (s *SendHandler) toolSendLink() mcp.Tool {
 	sendLinkTool := mcp.NewTool("whatsapp_send_link",
 		mcp.WithDescription("Send a link with caption to a WhatsApp contact or group."),
+		mcp.WithString("idempotency_key",
+			mcp.Description("Unique key for this send. Retrying with the same key returns the first result instead of sending again (optional)"),
+		),
 		mcp.WithString("phone",
 			mcp.Required(),
 			mcp.Description("Phone number or group ID to send link to"),
@@ -194,8 +223,9 @@ func (s *SendHandler) handleSendLink(ctx context.Context, request mcp.CallToolRe
 
 	res, err := s.sendService.SendLink(ctx, domainSend.LinkRequest{
 		BaseRequest: domainSend.BaseRequest{
-			Phone:       phone,
-			IsForwarded: isForwarded,
+			IdempotencyKey: request.GetString("idempotency_key", ""),
+			Phone:          phone,
+			IsForwarded:    isForwarded,
 		},
 		Link:    link,
 		Caption: caption,
@@ -211,6 +241,9 @@ func (s *SendHandler) handleSendLink(ctx context.Context, request mcp.CallToolRe
 func (s *SendHandler) toolSendLocation() mcp.Tool {
 	sendLocationTool := mcp.NewTool("whatsapp_send_location",
 		mcp.WithDescription("Send a location coordinates to a WhatsApp contact or group."),
+		mcp.WithString("idempotency_key",
+			mcp.Description("Unique key for this send. Retrying with the same key returns the first result instead of sending again (optional)"),
+		),
 		mcp.WithString("phone",
 			mcp.Required(),
 			mcp.Description("Phone number or group ID to send location to"),
@@ -254,8 +287,9 @@ func (s *SendHandler) handleSendLocation(ctx context.Context, request mcp.CallTo
 
 	res, err := s.sendService.SendLocation(ctx, domainSend.LocationRequest{
 		BaseRequest: domainSend.BaseRequest{
-			Phone:       phone,
-			IsForwarded: isForwarded,
+			IdempotencyKey: request.GetString("idempotency_key", ""),
+			Phone:          phone,
+			IsForwarded:    isForwarded,
 		},
 		Latitude:  latitude,
 		Longitude: longitude,
@@ -271,6 +305,9 @@ func (s *SendHandler) handleSendLocation(ctx context.Context, request mcp.CallTo
 func (s *SendHandler) toolSendImage() mcp.Tool {
 	sendImageTool := mcp.NewTool("whatsapp_send_image",
 		mcp.WithDescription("Send an image to a WhatsApp contact or group."),
+		mcp.WithString("idempotency_key",
+			mcp.Description("Unique key for this send. Retrying with the same key returns the first result instead of sending again (optional)"),
+		),
 		mcp.WithString("phone",
 			mcp.Required(),
 			mcp.Description("Phone number or group ID to send image to"),
@@ -329,8 +366,9 @@ func (s *SendHandler) handleSendImage(ctx context.Context, request mcp.CallToolR
 	// Create image request
 	imageRequest := domainSend.ImageRequest{
 		BaseRequest: domainSend.BaseRequest{
-			Phone:       phone,
-			IsForwarded: isForwarded,
+			IdempotencyKey: request.GetString("idempotency_key", ""),
+			Phone:          phone,
+			IsForwarded:    isForwarded,
 		},
 		Caption:  caption,
 		ViewOnce: viewOnce,
@@ -351,6 +389,9 @@ func (s *SendHandler) handleSendImage(ctx context.Context, request mcp.CallToolR
 func (s *SendHandler) toolSendSticker() mcp.Tool {
 	sendStickerTool := mcp.NewTool("whatsapp_send_sticker",
 		mcp.WithDescription("Send a sticker to a WhatsApp contact or group. Images are automatically converted to WebP sticker format."),
+		mcp.WithString("idempotency_key",
+			mcp.Description("Unique key for this send. Retrying with the same key returns the first result instead of sending again (optional)"),
+		),
 		mcp.WithString("phone",
 			mcp.Required(),
 			mcp.Description("Phone number or group ID to send sticker to"),
@@ -384,8 +425,9 @@ func (s *SendHandler) handleSendSticker(ctx context.Context, request mcp.CallToo
 
 	stickerRequest := domainSend.StickerRequest{
 		BaseRequest: domainSend.BaseRequest{
-			Phone:       phone,
-			IsForwarded: isForwarded,
+			IdempotencyKey: request.GetString("idempotency_key", ""),
+			Phone:          phone,
+			IsForwarded:    isForwarded,
 		},
 		StickerURL: &stickerURL,
 	}
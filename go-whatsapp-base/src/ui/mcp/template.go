@@ -0,0 +1,114 @@
+package mcp
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	domainTemplate "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/template"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+type TemplateHandler struct {
+	templateService domainTemplate.ITemplateUsecase
+}
+
+func InitMcpTemplate(templateService domainTemplate.ITemplateUsecase) *TemplateHandler {
+	return &TemplateHandler{templateService: templateService}
+}
+
+func (h *TemplateHandler) AddTemplateTools(mcpServer *server.MCPServer) {
+	mcpServer.AddTool(h.toolListTemplates(), h.handleListTemplates)
+	mcpServer.AddTool(h.toolRenderTemplate(), h.handleRenderTemplate)
+}
+
+func (h *TemplateHandler) toolListTemplates() mcp.Tool {
+	return mcp.NewTool(
+		"whatsapp_template_list",
+		mcp.WithDescription("List stored message templates with their variables and language variants."),
+		mcp.WithTitleAnnotation("List Templates"),
+		mcp.WithReadOnlyHintAnnotation(true),
+		mcp.WithIdempotentHintAnnotation(true),
+	)
+}
+
+func (h *TemplateHandler) handleListTemplates(ctx context.Context, _ mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	resp, err := h.templateService.ListTemplates(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	fallback := fmt.Sprintf("Found %d message templates", len(resp.Data))
+	return mcp.NewToolResultStructured(resp, fallback), nil
+}
+
+func (h *TemplateHandler) toolRenderTemplate() mcp.Tool {
+	return mcp.NewTool(
+		"whatsapp_template_render",
+		mcp.WithDescription("Preview a message template with variables without sending it."),
+		mcp.WithTitleAnnotation("Render Template"),
+		mcp.WithReadOnlyHintAnnotation(true),
+		mcp.WithIdempotentHintAnnotation(true),
+		mcp.WithString("template_id",
+			mcp.Description("ID of the stored template."),
+			mcp.Required(),
+		),
+		mcp.WithObject("variables",
+			mcp.Description("Template variable values keyed by variable name."),
+		),
+		mcp.WithString("language",
+			mcp.Description("Preferred language variant, e.g. en or pt-BR (defaults to the template default)."),
+		),
+	)
+}
+
+func (h *TemplateHandler) handleRenderTemplate(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	templateID, err := request.RequireString("template_id")
+	if err != nil {
+		return nil, err
+	}
+
+	variables, err := toStringMap(request.GetArguments()["variables"])
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := h.templateService.RenderTemplate(ctx, domainTemplate.RenderTemplateRequest{
+		TemplateID: strings.TrimSpace(templateID),
+		Language:   request.GetString("language", ""),
+		Variables:  variables,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return mcp.NewToolResultStructured(resp, resp.Message), nil
+}
+
+// toStringMap converts a tool object argument into template variable values
+func toStringMap(raw any) (map[string]string, error) {
+	if raw == nil {
+		return nil, nil
+	}
+
+	obj, ok := raw.(map[string]any)
+	if !ok {
+		return nil, fmt.Errorf("variables must be an object")
+	}
+
+	result := make(map[string]string, len(obj))
+	for key, value := range obj {
+		switch v := value.(type) {
+		case nil:
+			continue
+		case string:
+			result[key] = v
+		case bool, float64, int, int64:
+			result[key] = fmt.Sprint(v)
+		default:
+			return nil, fmt.Errorf("variables.%s must be a string, number or boolean", key)
+		}
+	}
+	return result, nil
+}
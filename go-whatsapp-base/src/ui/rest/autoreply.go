@@ -0,0 +1,133 @@
+package rest
+
+import (
+	"strconv"
+
+	domainAutoReply "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/autoreply"
+	pkgError "github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/error"
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/utils"
+	"github.com/gofiber/fiber/v2"
+)
+
+type AutoReply struct {
+	Service domainAutoReply.IAutoReplyUsecase
+}
+
+func InitRestAutoReply(app fiber.Router, service domainAutoReply.IAutoReplyUsecase) AutoReply {
+	rest := AutoReply{Service: service}
+
+	// Auto-reply rule endpoints
+	app.Get("/auto-reply/rules", rest.ListRules)
+	app.Post("/auto-reply/rules", rest.CreateRule)
+	app.Get("/auto-reply/rules/:rule_id", rest.GetRule)
+	app.Post("/auto-reply/rules/:rule_id/update", rest.UpdateRule)
+	app.Post("/auto-reply/rules/:rule_id/delete", rest.DeleteRule)
+	app.Get("/auto-reply/hits", rest.ListHits)
+
+	return rest
+}
+
+func (controller *AutoReply) ListRules(c *fiber.Ctx) error {
+	response, err := controller.Service.ListRules(c.UserContext())
+	utils.PanicIfNeeded(err)
+
+	return c.JSON(utils.ResponseData{
+		Status:  200,
+		Code:    "SUCCESS",
+		Message: "Success get auto-reply rules",
+		Results: response,
+	})
+}
+
+func (controller *AutoReply) GetRule(c *fiber.Ctx) error {
+	var request domainAutoReply.GetRuleRequest
+	request.RuleID = parseRuleID(c)
+
+	response, err := controller.Service.GetRule(c.UserContext(), request)
+	utils.PanicIfNeeded(err)
+
+	return c.JSON(utils.ResponseData{
+		Status:  200,
+		Code:    "SUCCESS",
+		Message: "Success get auto-reply rule",
+		Results: response,
+	})
+}
+
+func (controller *AutoReply) CreateRule(c *fiber.Ctx) error {
+	var request domainAutoReply.RuleRequest
+	err := c.BodyParser(&request)
+	utils.PanicIfNeeded(err)
+
+	response, err := controller.Service.CreateRule(c.UserContext(), request)
+	utils.PanicIfNeeded(err)
+
+	return c.JSON(utils.ResponseData{
+		Status:  200,
+		Code:    "SUCCESS",
+		Message: "Auto-reply rule created successfully",
+		Results: response,
+	})
+}
+
+func (controller *AutoReply) UpdateRule(c *fiber.Ctx) error {
+	var request domainAutoReply.RuleRequest
+	err := c.BodyParser(&request)
+	utils.PanicIfNeeded(err)
+
+	request.RuleID = parseRuleID(c)
+
+	response, err := controller.Service.UpdateRule(c.UserContext(), request)
+	utils.PanicIfNeeded(err)
+
+	return c.JSON(utils.ResponseData{
+		Status:  200,
+		Code:    "SUCCESS",
+		Message: "Auto-reply rule updated successfully",
+		Results: response,
+	})
+}
+
+func (controller *AutoReply) DeleteRule(c *fiber.Ctx) error {
+	var request domainAutoReply.DeleteRuleRequest
+	request.RuleID = parseRuleID(c)
+
+	err := controller.Service.DeleteRule(c.UserContext(), request)
+	utils.PanicIfNeeded(err)
+
+	return c.JSON(utils.ResponseData{
+		Status:  200,
+		Code:    "SUCCESS",
+		Message: "Auto-reply rule deleted successfully",
+		Results: nil,
+	})
+}
+
+func (controller *AutoReply) ListHits(c *fiber.Ctx) error {
+	var request domainAutoReply.ListHitsRequest
+
+	// Parse query parameters
+	request.RuleID = int64(c.QueryInt("rule_id", 0))
+	request.Sender = c.Query("sender", "")
+	request.Limit = c.QueryInt("limit", 50)
+	request.Offset = c.QueryInt("offset", 0)
+	utils.SanitizePhone(&request.Sender)
+
+	response, err := controller.Service.ListHits(c.UserContext(), request)
+	utils.PanicIfNeeded(err)
+
+	return c.JSON(utils.ResponseData{
+		Status:  200,
+		Code:    "SUCCESS",
+		Message: "Success get auto-reply hits",
+		Results: response,
+	})
+}
+
+func parseRuleID(c *fiber.Ctx) int64 {
+	ruleID, err := strconv.ParseInt(c.Params("rule_id"), 10, 64)
+	if err != nil {
+		panic(pkgError.ValidationError("rule_id: must be a number."))
+	}
+	return ruleID
+}
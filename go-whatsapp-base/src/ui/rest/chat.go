@@ -17,6 +17,8 @@ func InitRestChat(app fiber.Router, service domainChat.IChatUsecase) Chat {
 	app.Get("/chats", rest.ListChats)
 	app.Get("/chat/:chat_jid/messages", rest.GetChatMessages)
 	app.Post("/chat/:chat_jid/pin", rest.PinChat)
+	app.Post("/chat/:chat_jid/backfill", rest.BackfillChat)
+	app.Get("/chat/:chat_jid/backfill", rest.GetChatBackfill)
 
 	return rest
 }
@@ -29,6 +31,7 @@ func (controller *Chat) ListChats(c *fiber.Ctx) error {
 	request.Offset = c.QueryInt("offset", 0)
 	request.Search = c.Query("search", "")
 	request.HasMedia = c.QueryBool("has_media", false)
+	request.LabelID = c.Query("label_id", "")
 
 	response, err := controller.Service.ListChats(c.UserContext(), request)
 	utils.PanicIfNeeded(err)
@@ -104,3 +107,44 @@ func (controller *Chat) PinChat(c *fiber.Ctx) error {
 		Results: response,
 	})
 }
+
+func (controller *Chat) BackfillChat(c *fiber.Ctx) error {
+	var request domainChat.BackfillChatRequest
+
+	// The body is optional, count may also be given as a query parameter
+	request.Count = c.QueryInt("count", 0)
+	if len(c.Body()) > 0 {
+		err := c.BodyParser(&request)
+		utils.PanicIfNeeded(err)
+	}
+
+	// Parse path parameter
+	request.ChatJID = c.Params("chat_jid")
+
+	response, err := controller.Service.BackfillChat(c.UserContext(), request)
+	utils.PanicIfNeeded(err)
+
+	return c.JSON(utils.ResponseData{
+		Status:  200,
+		Code:    "SUCCESS",
+		Message: "Backfill requested from the primary device",
+		Results: response,
+	})
+}
+
+func (controller *Chat) GetChatBackfill(c *fiber.Ctx) error {
+	var request domainChat.GetChatBackfillRequest
+
+	// Parse path parameter
+	request.ChatJID = c.Params("chat_jid")
+
+	response, err := controller.Service.GetChatBackfill(c.UserContext(), request)
+	utils.PanicIfNeeded(err)
+
+	return c.JSON(utils.ResponseData{
+		Status:  200,
+		Code:    "SUCCESS",
+		Message: "Success get chat backfill",
+		Results: response,
+	})
+}
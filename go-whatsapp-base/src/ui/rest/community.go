@@ -0,0 +1,140 @@
+package rest
+
+import (
+	domainGroup "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/group"
+	domainSend "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/send"
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/utils"
+	"github.com/gofiber/fiber/v2"
+)
+
+type Community struct {
+	Service     domainGroup.IGroupUsecase
+	SendService domainSend.ISendUsecase
+}
+
+func InitRestCommunity(app fiber.Router, service domainGroup.IGroupUsecase, sendService domainSend.ISendUsecase) Community {
+	rest := Community{Service: service, SendService: sendService}
+	app.Post("/community", rest.CreateCommunity)
+	app.Get("/community/groups", rest.ListCommunityGroups)
+	app.Post("/community/groups/link", rest.LinkCommunityGroups)
+	app.Post("/community/groups/unlink", rest.UnlinkCommunityGroups)
+	app.Get("/community/announcement", rest.GetAnnouncementGroup)
+	app.Post("/community/announcement/send", rest.SendAnnouncement)
+	return rest
+}
+
+func (controller *Community) CreateCommunity(c *fiber.Ctx) error {
+	var request domainGroup.CreateCommunityRequest
+	err := c.BodyParser(&request)
+	utils.PanicIfNeeded(err)
+
+	communityID, err := controller.Service.CreateCommunity(c.UserContext(), request)
+	utils.PanicIfNeeded(err)
+
+	return c.JSON(utils.ResponseData{
+		Status:  200,
+		Code:    "SUCCESS",
+		Message: "Success created community",
+		Results: map[string]string{
+			"community_id": communityID,
+		},
+	})
+}
+
+func (controller *Community) ListCommunityGroups(c *fiber.Ctx) error {
+	var request domainGroup.ListCommunityGroupsRequest
+	err := c.QueryParser(&request)
+	utils.PanicIfNeeded(err)
+
+	utils.SanitizePhone(&request.CommunityID)
+
+	response, err := controller.Service.ListCommunityGroups(c.UserContext(), request)
+	utils.PanicIfNeeded(err)
+
+	return c.JSON(utils.ResponseData{
+		Status:  200,
+		Code:    "SUCCESS",
+		Message: "Success get community groups",
+		Results: response,
+	})
+}
+
+func (controller *Community) LinkCommunityGroups(c *fiber.Ctx) error {
+	return controller.changeCommunityGroups(c, "link")
+}
+
+func (controller *Community) UnlinkCommunityGroups(c *fiber.Ctx) error {
+	return controller.changeCommunityGroups(c, "unlink")
+}
+
+func (controller *Community) changeCommunityGroups(c *fiber.Ctx, action string) error {
+	var request domainGroup.CommunityGroupsRequest
+	err := c.BodyParser(&request)
+	utils.PanicIfNeeded(err)
+
+	utils.SanitizePhone(&request.CommunityID)
+	for i := range request.GroupIDs {
+		utils.SanitizePhone(&request.GroupIDs[i])
+	}
+
+	var result []domainGroup.CommunityGroupStatus
+	if action == "link" {
+		result, err = controller.Service.LinkCommunityGroups(c.UserContext(), request)
+	} else {
+		result, err = controller.Service.UnlinkCommunityGroups(c.UserContext(), request)
+	}
+	utils.PanicIfNeeded(err)
+
+	return c.JSON(utils.ResponseData{
+		Status:  200,
+		Code:    "SUCCESS",
+		Message: "Success " + action + " community groups",
+		Results: result,
+	})
+}
+
+func (controller *Community) GetAnnouncementGroup(c *fiber.Ctx) error {
+	var request domainGroup.GetAnnouncementGroupRequest
+	err := c.QueryParser(&request)
+	utils.PanicIfNeeded(err)
+
+	utils.SanitizePhone(&request.CommunityID)
+
+	response, err := controller.Service.GetAnnouncementGroup(c.UserContext(), request)
+	utils.PanicIfNeeded(err)
+
+	return c.JSON(utils.ResponseData{
+		Status:  200,
+		Code:    "SUCCESS",
+		Message: "Success get announcement group",
+		Results: response,
+	})
+}
+
+// SendAnnouncement accepts the /send/message body with community_id in place of phone
+func (controller *Community) SendAnnouncement(c *fiber.Ctx) error {
+	var community domainGroup.GetAnnouncementGroupRequest
+	err := c.BodyParser(&community)
+	utils.PanicIfNeeded(err)
+
+	var request domainSend.MessageRequest
+	err = c.BodyParser(&request)
+	utils.PanicIfNeeded(err)
+
+	utils.SanitizePhone(&community.CommunityID)
+
+	announcement, err := controller.Service.GetAnnouncementGroup(c.UserContext(), community)
+	utils.PanicIfNeeded(err)
+
+	request.Phone = announcement.GroupID
+	applyIdempotencyKey(c, &request.BaseRequest)
+	response, err := controller.SendService.SendText(c.UserContext(), request)
+	utils.PanicIfNeeded(err)
+
+	return c.JSON(utils.ResponseData{
+		Status:  200,
+		Code:    "SUCCESS",
+		Message: response.Status,
+		Results: response,
+	})
+}
@@ -0,0 +1,54 @@
+package rest
+
+import (
+	"context"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	domainGroup "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/group"
+	domainSend "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/send"
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// announcementGroup stands in for the group usecase and always finds the
+// same announcement group
+type announcementGroup struct {
+	domainGroup.IGroupUsecase
+}
+
+func (announcementGroup) GetAnnouncementGroup(_ context.Context, _ domainGroup.GetAnnouncementGroupRequest) (domainGroup.CommunityGroup, error) {
+	return domainGroup.CommunityGroup{GroupID: "120363024512399999@g.us"}, nil
+}
+
+// textRecorder stands in for the send usecase and keeps the text request
+type textRecorder struct {
+	domainSend.ISendUsecase
+	request domainSend.MessageRequest
+}
+
+func (r *textRecorder) SendText(_ context.Context, request domainSend.MessageRequest) (domainSend.GenericResponse, error) {
+	r.request = request
+	return domainSend.GenericResponse{MessageID: "3EB0ABC", Status: "sent"}, nil
+}
+
+func TestSendAnnouncementIdempotencyKey(t *testing.T) {
+	sendService := &textRecorder{}
+	app := fiber.New()
+	InitRestCommunity(app, announcementGroup{}, sendService)
+
+	body := `{"community_id":"120363024512300000","message":"hello"}`
+	req := httptest.NewRequest(fiber.MethodPost, "/community/announcement/send", strings.NewReader(body))
+	req.Header.Set(fiber.HeaderContentType, fiber.MIMEApplicationJSON)
+	req.Header.Set("Idempotency-Key", "notice-1")
+
+	resp, err := app.Test(req)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	require.Equal(t, fiber.StatusOK, resp.StatusCode)
+
+	assert.Equal(t, "120363024512399999@g.us", sendService.request.Phone)
+	assert.Equal(t, "notice-1", sendService.request.IdempotencyKey)
+}
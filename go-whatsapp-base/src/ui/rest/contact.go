@@ -0,0 +1,73 @@
+package rest
+
+import (
+	domainContact "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/contact"
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/utils"
+	"github.com/gofiber/fiber/v2"
+)
+
+type Contact struct {
+	Service domainContact.IContactUsecase
+}
+
+func InitRestContact(app fiber.Router, service domainContact.IContactUsecase) Contact {
+	rest := Contact{Service: service}
+
+	app.Get("/contacts", rest.ListContacts)
+	app.Get("/contacts/export", rest.ExportContacts)
+	app.Post("/contacts/import", rest.ImportContacts)
+
+	return rest
+}
+
+func (controller *Contact) ListContacts(c *fiber.Ctx) error {
+	var request domainContact.ListContactsRequest
+
+	request.Limit = c.QueryInt("limit", 25)
+	request.Offset = c.QueryInt("offset", 0)
+	request.Search = c.Query("search", "")
+
+	response, err := controller.Service.ListContacts(c.UserContext(), request)
+	utils.PanicIfNeeded(err)
+
+	return c.JSON(utils.ResponseData{
+		Status:  200,
+		Code:    "SUCCESS",
+		Message: "Success get contact list",
+		Results: response,
+	})
+}
+
+func (controller *Contact) ImportContacts(c *fiber.Ctx) error {
+	var request domainContact.ImportContactsRequest
+	err := c.BodyParser(&request)
+	utils.PanicIfNeeded(err)
+
+	file, err := c.FormFile("file")
+	utils.PanicIfNeeded(err)
+	request.File = file
+
+	response, err := controller.Service.ImportContacts(c.UserContext(), request)
+	utils.PanicIfNeeded(err)
+
+	return c.JSON(utils.ResponseData{
+		Status:  200,
+		Code:    "SUCCESS",
+		Message: "Success import contacts",
+		Results: response,
+	})
+}
+
+func (controller *Contact) ExportContacts(c *fiber.Ctx) error {
+	var request domainContact.ExportContactsRequest
+	err := c.QueryParser(&request)
+	utils.PanicIfNeeded(err)
+
+	response, err := controller.Service.ExportContacts(c.UserContext(), request)
+	utils.PanicIfNeeded(err)
+
+	c.Type(response.ContentType)
+	c.Attachment(response.FileName)
+
+	return c.Send(response.Data)
+}
@@ -40,6 +40,8 @@ func InitRestGroup(app fiber.Router, service domainGroup.IGroupUsecase) Group {
 	app.Post("/group/announce", rest.SetGroupAnnounce)
 	app.Post("/group/topic", rest.SetGroupTopic)
 	app.Get("/group/invite-link", rest.GetGroupInviteLink)
+	app.Get("/group/history", rest.GroupHistory)
+	app.Get("/group/members", rest.GroupMembers)
 	return rest
 }
 
@@ -443,3 +445,40 @@ func (controller *Group) GetGroupInviteLink(c *fiber.Ctx) error {
 		Results: response,
 	})
 }
+
+func (controller *Group) GroupHistory(c *fiber.Ctx) error {
+	var request domainGroup.GroupHistoryRequest
+	err := c.QueryParser(&request)
+	utils.PanicIfNeeded(err)
+
+	utils.SanitizePhone(&request.GroupID)
+	utils.SanitizePhone(&request.Participant)
+
+	response, err := controller.Service.GetGroupHistory(c.UserContext(), request)
+	utils.PanicIfNeeded(err)
+
+	return c.JSON(utils.ResponseData{
+		Status:  200,
+		Code:    "SUCCESS",
+		Message: "Success get group membership history",
+		Results: response,
+	})
+}
+
+func (controller *Group) GroupMembers(c *fiber.Ctx) error {
+	var request domainGroup.GroupMembersRequest
+	err := c.QueryParser(&request)
+	utils.PanicIfNeeded(err)
+
+	utils.SanitizePhone(&request.GroupID)
+
+	response, err := controller.Service.GetGroupMembers(c.UserContext(), request)
+	utils.PanicIfNeeded(err)
+
+	return c.JSON(utils.ResponseData{
+		Status:  200,
+		Code:    "SUCCESS",
+		Message: "Success get group members",
+		Results: response,
+	})
+}
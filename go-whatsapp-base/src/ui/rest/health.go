@@ -0,0 +1,50 @@
+package rest
+
+import (
+	domainHealth "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/health"
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/utils"
+	"github.com/gofiber/fiber/v2"
+)
+
+type Health struct {
+	Service domainHealth.IHealthUsecase
+}
+
+// InitRestHealth registers the probes. They are registered ahead of basic
+// auth so orchestrators can call them without credentials.
+func InitRestHealth(app fiber.Router, service domainHealth.IHealthUsecase) Health {
+	rest := Health{Service: service}
+	app.Get("/healthz", rest.Liveness)
+	app.Get("/readyz", rest.Readiness)
+	return rest
+}
+
+// Liveness answers as long as the process serves requests
+func (controller *Health) Liveness(c *fiber.Ctx) error {
+	return c.JSON(utils.ResponseData{
+		Status:  200,
+		Code:    "SUCCESS",
+		Message: "Service is alive",
+		Results: controller.Service.Liveness(c.UserContext()),
+	})
+}
+
+// Readiness runs the dependency checks and answers 503 when any of them fails
+func (controller *Health) Readiness(c *fiber.Ctx) error {
+	response := controller.Service.Readiness(c.UserContext())
+	if response.Status != domainHealth.StatusOK {
+		return c.Status(fiber.StatusServiceUnavailable).JSON(utils.ResponseData{
+			Status:  fiber.StatusServiceUnavailable,
+			Code:    "NOT_READY",
+			Message: "Service is not ready",
+			Results: response,
+		})
+	}
+
+	return c.JSON(utils.ResponseData{
+		Status:  200,
+		Code:    "SUCCESS",
+		Message: "Service is ready",
+		Results: response,
+	})
+}
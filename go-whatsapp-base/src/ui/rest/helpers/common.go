@@ -6,7 +6,8 @@ import (
 	"time"
 
 	domainApp "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/app"
-	"go.mau.fi/whatsmeow"
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/infrastructure/whatsapp"
+	"github.com/sirupsen/logrus"
 )
 
 func SetAutoConnectAfterBooting(service domainApp.IAppUsecase) {
@@ -14,14 +15,27 @@ func SetAutoConnectAfterBooting(service domainApp.IAppUsecase) {
 	_ = service.Reconnect(context.Background())
 }
 
-func SetAutoReconnectChecking(cli *whatsmeow.Client) {
+func SetAutoReconnectChecking() {
 	// Run every 5 minutes to check if the connection is still alive, if not, reconnect
 	go func() {
 		for {
 			time.Sleep(5 * time.Minute)
-			if !cli.IsConnected() {
-				_ = cli.Connect()
+			// Read the global client each time, it is replaced after a logout
+			cli := whatsapp.GetClient()
+			if cli == nil || cli.IsConnected() {
+				continue
 			}
+			// Without a session the client would only open a pairing socket
+			if cli.Store == nil || cli.Store.ID == nil {
+				continue
+			}
+			err := cli.Connect()
+			if err != nil {
+				logrus.Warnf("Auto reconnect failed: %v", err)
+			} else {
+				logrus.Info("Auto reconnect succeeded")
+			}
+			whatsapp.RecordReconnectAttempt(err)
 		}
 	}()
 }
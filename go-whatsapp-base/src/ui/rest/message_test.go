@@ -0,0 +1,54 @@
+package rest
+
+import (
+	"context"
+	"encoding/json"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	domainMessage "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/message"
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/utils"
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// forwardRecorder stands in for the message usecase and keeps the forward
+// request the handler built
+type forwardRecorder struct {
+	domainMessage.IMessageUsecase
+	request domainMessage.ForwardMessageRequest
+}
+
+func (f *forwardRecorder) ForwardMessage(_ context.Context, request domainMessage.ForwardMessageRequest) (domainMessage.ForwardMessageResponse, error) {
+	f.request = request
+	return domainMessage.ForwardMessageResponse{
+		MessageID: request.MessageID,
+		Status:    "Message forwarded to 2 of 2 targets",
+	}, nil
+}
+
+func TestForwardMessageHandler(t *testing.T) {
+	service := &forwardRecorder{}
+	app := fiber.New()
+	InitRestMessage(app, service)
+
+	body := `{"phone":"6281234567890","targets":["6289685028129","120363024512399999@g.us"]}`
+	req := httptest.NewRequest(fiber.MethodPost, "/message/3EB0ABC/forward", strings.NewReader(body))
+	req.Header.Set(fiber.HeaderContentType, fiber.MIMEApplicationJSON)
+
+	resp, err := app.Test(req)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	require.Equal(t, fiber.StatusOK, resp.StatusCode)
+
+	assert.Equal(t, "3EB0ABC", service.request.MessageID)
+	assert.Equal(t, "6281234567890@s.whatsapp.net", service.request.Phone)
+	assert.Equal(t, []string{"6289685028129@s.whatsapp.net", "120363024512399999@g.us"}, service.request.Targets)
+
+	var result utils.ResponseData
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&result))
+	assert.Equal(t, "SUCCESS", result.Code)
+	assert.Equal(t, "Message forwarded to 2 of 2 targets", result.Message)
+}
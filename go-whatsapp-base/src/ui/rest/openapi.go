@@ -0,0 +1,56 @@
+package rest
+
+import (
+	"context"
+
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/config"
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/openapi"
+	"github.com/gofiber/fiber/v2"
+)
+
+type OpenAPI struct {
+	Document map[string]any
+}
+
+func InitRestOpenAPI(app fiber.Router) OpenAPI {
+	rest := OpenAPI{
+		Document: openapi.Build(openapi.Info{
+			Title:       "WhatsApp API MultiDevice",
+			Description: "Generated from the REST routes, the domain request and response structs and the request validations.",
+			Version:     config.AppVersion,
+			BasePath:    config.AppBasePath,
+		}, openAPIOperations()),
+	}
+	app.Get("/openapi.json", rest.Spec)
+	app.Get("/docs", rest.Viewer)
+	return rest
+}
+
+// Spec serves the OpenAPI document generated from the route table
+func (controller *OpenAPI) Spec(c *fiber.Ctx) error {
+	return c.JSON(controller.Document)
+}
+
+// Viewer serves an interactive viewer for /openapi.json
+func (controller *OpenAPI) Viewer(c *fiber.Ctx) error {
+	return c.Render("views/docs", fiber.Map{
+		"AppBasePath": config.AppBasePath,
+		"AppVersion":  config.AppVersion,
+	})
+}
+
+// rules runs a validation on the zero request so the spec can read which fields it requires
+func rules[T any](validate func(context.Context, T) error) func(context.Context) error {
+	return func(ctx context.Context) error {
+		var request T
+		return validate(ctx, request)
+	}
+}
+
+// rulesPtr is rules for validations that take the request by pointer to fill in defaults
+func rulesPtr[T any](validate func(context.Context, *T) error) func(context.Context) error {
+	return func(ctx context.Context) error {
+		var request T
+		return validate(ctx, &request)
+	}
+}
@@ -0,0 +1,356 @@
+package rest
+
+import (
+	"context"
+
+	domainApp "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/app"
+	domainAutoReply "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/autoreply"
+	domainChat "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/chat"
+	domainContact "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/contact"
+	domainGroup "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/group"
+	domainHealth "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/health"
+	domainLabel "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/label"
+	domainMessage "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/message"
+	domainNewsletter "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/newsletter"
+	domainSend "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/send"
+	domainTemplate "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/template"
+	domainUser "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/user"
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/openapi"
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/validations"
+	"github.com/gofiber/fiber/v2"
+)
+
+// idempotencyHeader is accepted by every message send
+var idempotencyHeader = openapi.Header{
+	Name:        "Idempotency-Key",
+	Description: "Retrying with the same key returns the first response instead of sending again",
+}
+
+// openAPIOperations lists every REST route for /openapi.json. A route
+// registered in an Init function without an entry here fails the tests.
+func openAPIOperations() []openapi.Operation {
+	sent := []openapi.Header{idempotencyHeader}
+
+	return []openapi.Operation{
+		// App
+		{Method: fiber.MethodGet, Path: "/app/login", Tag: "app", Summary: "Login with QR code",
+			Response: struct {
+				QRLink     string `json:"qr_link"`
+				QRDuration int    `json:"qr_duration"`
+			}{}},
+		{Method: fiber.MethodGet, Path: "/app/login-with-code", Tag: "app", Summary: "Login with pairing code",
+			Query: struct {
+				Phone string `query:"phone"`
+			}{},
+			Response: struct {
+				PairCode string `json:"pair_code"`
+			}{}},
+		{Method: fiber.MethodGet, Path: "/app/logout", Tag: "app", Summary: "Remove the session and logout"},
+		{Method: fiber.MethodGet, Path: "/app/reconnect", Tag: "app", Summary: "Reconnect to WhatsApp"},
+		{Method: fiber.MethodGet, Path: "/app/devices", Tag: "app", Summary: "List connected devices",
+			Response: []domainApp.DevicesResponse{}},
+		{Method: fiber.MethodGet, Path: "/app/status", Tag: "app", Summary: "Connection status",
+			Response: struct {
+				IsConnected bool   `json:"is_connected"`
+				IsLoggedIn  bool   `json:"is_logged_in"`
+				DeviceID    string `json:"device_id"`
+			}{}},
+
+		// User
+		{Method: fiber.MethodGet, Path: "/user/info", Tag: "user", Summary: "User info",
+			Query: domainUser.InfoRequest{}, Validate: rules(validations.ValidateUserInfo),
+			Response: domainUser.InfoResponseData{}},
+		{Method: fiber.MethodGet, Path: "/user/avatar", Tag: "user", Summary: "User avatar",
+			Query: domainUser.AvatarRequest{}, Validate: rules(validations.ValidateUserAvatar),
+			Response: domainUser.AvatarResponse{}},
+		{Method: fiber.MethodPost, Path: "/user/avatar", Tag: "user", Summary: "Change avatar",
+			Body: domainUser.ChangeAvatarRequest{}},
+		{Method: fiber.MethodPost, Path: "/user/pushname", Tag: "user", Summary: "Change push name",
+			Body: domainUser.ChangePushNameRequest{}},
+		{Method: fiber.MethodGet, Path: "/user/my/privacy", Tag: "user", Summary: "My privacy settings",
+			Response: domainUser.MyPrivacySettingResponse{}},
+		{Method: fiber.MethodGet, Path: "/user/my/groups", Tag: "user", Summary: "My groups",
+			Response: domainUser.MyListGroupsResponse{}},
+		{Method: fiber.MethodGet, Path: "/user/my/newsletters", Tag: "user", Summary: "My newsletters",
+			Response: domainUser.MyListNewsletterResponse{}},
+		{Method: fiber.MethodGet, Path: "/user/my/contacts", Tag: "user", Summary: "My contacts",
+			Response: domainUser.MyListContactsResponse{}},
+		{Method: fiber.MethodGet, Path: "/user/check", Tag: "user", Summary: "Check whether a number is on WhatsApp",
+			Query: domainUser.CheckRequest{}, Response: domainUser.CheckResponse{}},
+		{Method: fiber.MethodGet, Path: "/user/business-profile", Tag: "user", Summary: "Business profile",
+			Query: domainUser.BusinessProfileRequest{}, Validate: rules(validations.ValidateBusinessProfile),
+			Response: domainUser.BusinessProfileResponse{}},
+		{Method: fiber.MethodGet, Path: "/user/business-catalog", Tag: "user", Summary: "Business catalog products",
+			Query: domainUser.BusinessCatalogRequest{}, Validate: rules(validations.ValidateBusinessCatalog),
+			Response: domainUser.BusinessCatalogResponse{}},
+		{Method: fiber.MethodGet, Path: "/user/business-collections", Tag: "user", Summary: "Business catalog collections",
+			Query: domainUser.BusinessCollectionsRequest{}, Validate: rules(validations.ValidateBusinessCollections),
+			Response: domainUser.BusinessCollectionsResponse{}},
+
+		// Send
+		{Method: fiber.MethodPost, Path: "/send/message", Tag: "send", Summary: "Send message",
+			Body: domainSend.MessageRequest{}, Headers: sent, Validate: rules(validations.ValidateSendMessage),
+			Response: domainSend.GenericResponse{}},
+		{Method: fiber.MethodPost, Path: "/send/image", Tag: "send", Summary: "Send image",
+			Body: domainSend.ImageRequest{}, Headers: sent, Validate: rules(validations.ValidateSendImage),
+			Response: domainSend.GenericResponse{}},
+		{Method: fiber.MethodPost, Path: "/send/file", Tag: "send", Summary: "Send file",
+			Body: domainSend.FileRequest{}, Headers: sent, Validate: rules(validations.ValidateSendFile),
+			Response: domainSend.GenericResponse{}},
+		{Method: fiber.MethodPost, Path: "/send/video", Tag: "send", Summary: "Send video",
+			Body: domainSend.VideoRequest{}, Headers: sent, Validate: rules(validations.ValidateSendVideo),
+			Response: domainSend.GenericResponse{}},
+		{Method: fiber.MethodPost, Path: "/send/sticker", Tag: "send", Summary: "Send sticker",
+			Body: domainSend.StickerRequest{}, Headers: sent, Validate: rules(validations.ValidateSendSticker),
+			Response: domainSend.GenericResponse{}},
+		{Method: fiber.MethodPost, Path: "/send/contact", Tag: "send", Summary: "Send contact",
+			Body: domainSend.ContactRequest{}, Headers: sent, Validate: rules(validations.ValidateSendContact),
+			Response: domainSend.GenericResponse{}},
+		{Method: fiber.MethodPost, Path: "/send/link", Tag: "send", Summary: "Send link",
+			Body: domainSend.LinkRequest{}, Headers: sent, Validate: rules(validations.ValidateSendLink),
+			Response: domainSend.GenericResponse{}},
+		{Method: fiber.MethodPost, Path: "/send/location", Tag: "send", Summary: "Send location",
+			Body: domainSend.LocationRequest{}, Headers: sent, Validate: rules(validations.ValidateSendLocation),
+			Response: domainSend.GenericResponse{}},
+		{Method: fiber.MethodPost, Path: "/send/audio", Tag: "send", Summary: "Send audio",
+			Body: domainSend.AudioRequest{}, Headers: sent, Validate: rules(validations.ValidateSendAudio),
+			Response: domainSend.GenericResponse{}},
+		{Method: fiber.MethodPost, Path: "/send/poll", Tag: "send", Summary: "Send poll",
+			Body: domainSend.PollRequest{}, Headers: sent, Required: []string{"options"},
+			Validate: func(ctx context.Context) error {
+				return validations.ValidateSendPoll(ctx, domainSend.PollRequest{Options: []string{"option"}})
+			},
+			Response: domainSend.GenericResponse{}},
+		{Method: fiber.MethodPost, Path: "/send/product", Tag: "send", Summary: "Send product from own catalog",
+			Body: domainSend.ProductRequest{}, Headers: sent, Validate: rules(validations.ValidateSendProduct),
+			Response: domainSend.GenericResponse{}},
+		{Method: fiber.MethodPost, Path: "/send/product-list", Tag: "send", Summary: "Send product list from own catalog",
+			Body: domainSend.ProductListRequest{}, Headers: sent, Required: []string{"sections"},
+			Validate: func(ctx context.Context) error {
+				return validations.ValidateSendProductList(ctx, domainSend.ProductListRequest{
+					Sections: []domainSend.ProductListSection{{Title: "section", ProductIDs: []string{"product"}}},
+				})
+			},
+			Response: domainSend.GenericResponse{}},
+		{Method: fiber.MethodPost, Path: "/send/presence", Tag: "send", Summary: "Send presence",
+			Body: domainSend.PresenceRequest{}, Validate: rules(validations.ValidateSendPresence),
+			Response: domainSend.GenericResponse{}},
+		{Method: fiber.MethodPost, Path: "/send/chat-presence", Tag: "send", Summary: "Send chat presence (typing indicator)",
+			Body: domainSend.ChatPresenceRequest{}, Validate: rules(validations.ValidateSendChatPresence),
+			Response: domainSend.GenericResponse{}},
+		{Method: fiber.MethodGet, Path: "/send/queue", Tag: "send", Summary: "List send queue",
+			Query: domainSend.QueueListRequest{}, Validate: rulesPtr(validations.ValidateSendQueueList),
+			Response: domainSend.QueueListResponse{}},
+		{Method: fiber.MethodPost, Path: "/send/queue/flush", Tag: "send", Summary: "Flush send queue",
+			Response: domainSend.QueueFlushResponse{}},
+		{Method: fiber.MethodPost, Path: "/send/queue/:queue_id/cancel", Tag: "send", Summary: "Cancel queued send",
+			Response: domainSend.QueueItem{}},
+
+		// Message
+		{Method: fiber.MethodPost, Path: "/message/:message_id/reaction", Tag: "message", Summary: "React to message",
+			Body: domainMessage.ReactionRequest{}, Validate: rules(validations.ValidateReactMessage),
+			Response: domainMessage.GenericResponse{}},
+		{Method: fiber.MethodPost, Path: "/message/:message_id/revoke", Tag: "message", Summary: "Revoke message",
+			Body: domainMessage.RevokeRequest{}, Validate: rules(validations.ValidateRevokeMessage),
+			Response: domainMessage.GenericResponse{}},
+		{Method: fiber.MethodPost, Path: "/message/:message_id/delete", Tag: "message", Summary: "Delete message for me",
+			Body: domainMessage.DeleteRequest{}, Validate: rules(validations.ValidateDeleteMessage)},
+		{Method: fiber.MethodPost, Path: "/message/:message_id/update", Tag: "message", Summary: "Edit message",
+			Body: domainMessage.UpdateMessageRequest{}, Validate: rules(validations.ValidateUpdateMessage),
+			Response: domainMessage.GenericResponse{}},
+		{Method: fiber.MethodPost, Path: "/message/:message_id/read", Tag: "message", Summary: "Mark message as read",
+			Body: domainMessage.MarkAsReadRequest{}, Validate: rules(validations.ValidateMarkAsRead),
+			Response: domainMessage.GenericResponse{}},
+		{Method: fiber.MethodPost, Path: "/message/:message_id/star", Tag: "message", Summary: "Star message",
+			Body: domainMessage.StarRequest{}, Validate: rules(validations.ValidateStarMessage)},
+		{Method: fiber.MethodPost, Path: "/message/:message_id/unstar", Tag: "message", Summary: "Unstar message",
+			Body: domainMessage.StarRequest{}, Validate: rules(validations.ValidateStarMessage)},
+		{Method: fiber.MethodPost, Path: "/message/:message_id/forward", Tag: "message", Summary: "Forward message",
+			Body: domainMessage.ForwardMessageRequest{}, Validate: rules(validations.ValidateForwardMessage),
+			Response: domainMessage.ForwardMessageResponse{}},
+		{Method: fiber.MethodGet, Path: "/message/:message_id/download", Tag: "message", Summary: "Download message media",
+			Query: domainMessage.DownloadMediaRequest{}, Validate: rules(validations.ValidateDownloadMedia),
+			Response: domainMessage.DownloadMediaResponse{}},
+
+		// Chat
+		{Method: fiber.MethodGet, Path: "/chats", Tag: "chat", Summary: "List chats",
+			Query: domainChat.ListChatsRequest{}, Validate: rulesPtr(validations.ValidateListChats),
+			Response: domainChat.ListChatsResponse{}},
+		{Method: fiber.MethodGet, Path: "/chat/:chat_jid/messages", Tag: "chat", Summary: "Chat messages",
+			Query: domainChat.GetChatMessagesRequest{}, Response: domainChat.GetChatMessagesResponse{}},
+		{Method: fiber.MethodPost, Path: "/chat/:chat_jid/pin", Tag: "chat", Summary: "Pin or unpin chat",
+			Body: domainChat.PinChatRequest{}, Response: domainChat.PinChatResponse{}},
+		{Method: fiber.MethodPost, Path: "/chat/:chat_jid/backfill", Tag: "chat", Summary: "Request older chat history",
+			Body: domainChat.BackfillChatRequest{}, Response: domainChat.BackfillInfo{}},
+		{Method: fiber.MethodGet, Path: "/chat/:chat_jid/backfill", Tag: "chat", Summary: "Chat backfill progress",
+			Response: domainChat.BackfillInfo{}},
+
+		// Group
+		{Method: fiber.MethodPost, Path: "/group", Tag: "group", Summary: "Create group",
+			Body: domainGroup.CreateGroupRequest{}, Validate: rules(validations.ValidateCreateGroup),
+			Response: struct {
+				GroupID string `json:"group_id"`
+			}{}},
+		{Method: fiber.MethodPost, Path: "/group/join-with-link", Tag: "group", Summary: "Join group with invite link",
+			Body: domainGroup.JoinGroupWithLinkRequest{}, Validate: rules(validations.ValidateJoinGroupWithLink),
+			Response: struct {
+				GroupID string `json:"group_id"`
+			}{}},
+		{Method: fiber.MethodGet, Path: "/group/info-from-link", Tag: "group", Summary: "Group info from invite link",
+			Query: domainGroup.GetGroupInfoFromLinkRequest{}, Validate: rules(validations.ValidateGetGroupInfoFromLink),
+			Response: domainGroup.GetGroupInfoFromLinkResponse{}},
+		{Method: fiber.MethodGet, Path: "/group/info", Tag: "group", Summary: "Group info",
+			Query: domainGroup.GroupInfoRequest{}, Validate: rules(validations.ValidateGroupInfo),
+			Response: map[string]any{}},
+		{Method: fiber.MethodPost, Path: "/group/leave", Tag: "group", Summary: "Leave group",
+			Body: domainGroup.LeaveGroupRequest{}, Validate: rules(validations.ValidateLeaveGroup)},
+		{Method: fiber.MethodGet, Path: "/group/participants", Tag: "group", Summary: "List participants",
+			Query: domainGroup.GetGroupParticipantsRequest{}, Validate: rules(validations.ValidateGetGroupParticipants),
+			Response: domainGroup.GetGroupParticipantsResponse{}},
+		{Method: fiber.MethodGet, Path: "/group/participants/export", Tag: "group", Summary: "Export participants as CSV",
+			Query: domainGroup.GetGroupParticipantsRequest{}, Validate: rules(validations.ValidateGetGroupParticipants),
+			ContentType: "text/csv"},
+		{Method: fiber.MethodPost, Path: "/group/participants", Tag: "group", Summary: "Add participants",
+			Body: domainGroup.ParticipantRequest{}, Validate: rules(validations.ValidateParticipant), Omit: []string{"action"},
+			Response: []domainGroup.ParticipantStatus{}},
+		{Method: fiber.MethodPost, Path: "/group/participants/remove", Tag: "group", Summary: "Remove participants",
+			Body: domainGroup.ParticipantRequest{}, Validate: rules(validations.ValidateParticipant), Omit: []string{"action"},
+			Response: []domainGroup.ParticipantStatus{}},
+		{Method: fiber.MethodPost, Path: "/group/participants/promote", Tag: "group", Summary: "Promote participants to admin",
+			Body: domainGroup.ParticipantRequest{}, Validate: rules(validations.ValidateParticipant), Omit: []string{"action"},
+			Response: []domainGroup.ParticipantStatus{}},
+		{Method: fiber.MethodPost, Path: "/group/participants/demote", Tag: "group", Summary: "Demote admins",
+			Body: domainGroup.ParticipantRequest{}, Validate: rules(validations.ValidateParticipant), Omit: []string{"action"},
+			Response: []domainGroup.ParticipantStatus{}},
+		{Method: fiber.MethodGet, Path: "/group/participant-requests", Tag: "group", Summary: "List join requests",
+			Query: domainGroup.GetGroupRequestParticipantsRequest{}, Validate: rules(validations.ValidateGetGroupRequestParticipants),
+			Response: []domainGroup.GetGroupRequestParticipantsResponse{}},
+		{Method: fiber.MethodPost, Path: "/group/participant-requests/approve", Tag: "group", Summary: "Approve join requests",
+			Body: domainGroup.GroupRequestParticipantsRequest{}, Validate: rules(validations.ValidateManageGroupRequestParticipants), Omit: []string{"action"},
+			Response: []domainGroup.ParticipantStatus{}},
+		{Method: fiber.MethodPost, Path: "/group/participant-requests/reject", Tag: "group", Summary: "Reject join requests",
+			Body: domainGroup.GroupRequestParticipantsRequest{}, Validate: rules(validations.ValidateManageGroupRequestParticipants), Omit: []string{"action"},
+			Response: []domainGroup.ParticipantStatus{}},
+		{Method: fiber.MethodPost, Path: "/group/photo", Tag: "group", Summary: "Set group photo",
+			Body: domainGroup.SetGroupPhotoRequest{}, Validate: rules(validations.ValidateSetGroupPhoto),
+			Response: domainGroup.SetGroupPhotoResponse{}},
+		{Method: fiber.MethodPost, Path: "/group/name", Tag: "group", Summary: "Set group name",
+			Body: domainGroup.SetGroupNameRequest{}, Validate: rules(validations.ValidateSetGroupName)},
+		{Method: fiber.MethodPost, Path: "/group/locked", Tag: "group", Summary: "Lock or unlock group info",
+			Body: domainGroup.SetGroupLockedRequest{}, Validate: rules(validations.ValidateSetGroupLocked)},
+		{Method: fiber.MethodPost, Path: "/group/announce", Tag: "group", Summary: "Set announcement mode",
+			Body: domainGroup.SetGroupAnnounceRequest{}, Validate: rules(validations.ValidateSetGroupAnnounce)},
+		{Method: fiber.MethodPost, Path: "/group/topic", Tag: "group", Summary: "Set group topic",
+			Body: domainGroup.SetGroupTopicRequest{}, Validate: rules(validations.ValidateSetGroupTopic)},
+		{Method: fiber.MethodGet, Path: "/group/invite-link", Tag: "group", Summary: "Get or reset invite link",
+			Query: domainGroup.GetGroupInviteLinkRequest{}, Validate: rules(validations.ValidateGetGroupInviteLink),
+			Response: domainGroup.GetGroupInviteLinkResponse{}},
+		{Method: fiber.MethodGet, Path: "/group/history", Tag: "group", Summary: "Group membership history",
+			Query: domainGroup.GroupHistoryRequest{}, Validate: rulesPtr(validations.ValidateGroupHistory),
+			Response: domainGroup.GroupHistoryResponse{}},
+		{Method: fiber.MethodGet, Path: "/group/members", Tag: "group", Summary: "Group members, now or at a point in time",
+			Query: domainGroup.GroupMembersRequest{}, Validate: rules(validations.ValidateGroupMembers),
+			Response: domainGroup.GroupMembersResponse{}},
+
+		// Community
+		{Method: fiber.MethodPost, Path: "/community", Tag: "community", Summary: "Create community",
+			Body: domainGroup.CreateCommunityRequest{}, Validate: rules(validations.ValidateCreateCommunity),
+			Response: struct {
+				CommunityID string `json:"community_id"`
+			}{}},
+		{Method: fiber.MethodGet, Path: "/community/groups", Tag: "community", Summary: "List community groups",
+			Query: domainGroup.ListCommunityGroupsRequest{}, Validate: rules(validations.ValidateListCommunityGroups),
+			Response: domainGroup.ListCommunityGroupsResponse{}},
+		{Method: fiber.MethodPost, Path: "/community/groups/link", Tag: "community", Summary: "Link groups to community",
+			Body: domainGroup.CommunityGroupsRequest{}, Validate: rules(validations.ValidateCommunityGroups),
+			Response: []domainGroup.CommunityGroupStatus{}},
+		{Method: fiber.MethodPost, Path: "/community/groups/unlink", Tag: "community", Summary: "Unlink groups from community",
+			Body: domainGroup.CommunityGroupsRequest{}, Validate: rules(validations.ValidateCommunityGroups),
+			Response: []domainGroup.CommunityGroupStatus{}},
+		{Method: fiber.MethodGet, Path: "/community/announcement", Tag: "community", Summary: "Community announcement group",
+			Query: domainGroup.GetAnnouncementGroupRequest{}, Validate: rules(validations.ValidateGetAnnouncementGroup),
+			Response: domainGroup.CommunityGroup{}},
+		{Method: fiber.MethodPost, Path: "/community/announcement/send", Tag: "community", Summary: "Send to the announcement group",
+			Body: struct {
+				domainSend.MessageRequest
+				CommunityID string `json:"community_id"`
+			}{},
+			Response: domainSend.GenericResponse{}},
+
+		// Newsletter
+		{Method: fiber.MethodPost, Path: "/newsletter/unfollow", Tag: "newsletter", Summary: "Unfollow newsletter",
+			Body: domainNewsletter.UnfollowRequest{}, Validate: rules(validations.ValidateUnfollowNewsletter)},
+
+		// Auto reply
+		{Method: fiber.MethodGet, Path: "/auto-reply/rules", Tag: "autoreply", Summary: "List auto-reply rules",
+			Response: domainAutoReply.ListRulesResponse{}},
+		{Method: fiber.MethodPost, Path: "/auto-reply/rules", Tag: "autoreply", Summary: "Create auto-reply rule",
+			Body: domainAutoReply.RuleRequest{}, Validate: rulesPtr(validations.ValidateAutoReplyRule),
+			Response: domainAutoReply.RuleInfo{}},
+		{Method: fiber.MethodGet, Path: "/auto-reply/rules/:rule_id", Tag: "autoreply", Summary: "Get auto-reply rule",
+			Response: domainAutoReply.RuleInfo{}},
+		{Method: fiber.MethodPost, Path: "/auto-reply/rules/:rule_id/update", Tag: "autoreply", Summary: "Update auto-reply rule",
+			Body: domainAutoReply.RuleRequest{}, Validate: rulesPtr(validations.ValidateUpdateAutoReplyRule),
+			Response: domainAutoReply.RuleInfo{}},
+		{Method: fiber.MethodPost, Path: "/auto-reply/rules/:rule_id/delete", Tag: "autoreply", Summary: "Delete auto-reply rule"},
+		{Method: fiber.MethodGet, Path: "/auto-reply/hits", Tag: "autoreply", Summary: "Auto-reply hits",
+			Query: domainAutoReply.ListHitsRequest{}, Validate: rulesPtr(validations.ValidateListAutoReplyHits),
+			Response: domainAutoReply.ListHitsResponse{}},
+
+		// Templates
+		{Method: fiber.MethodGet, Path: "/templates", Tag: "template", Summary: "List message templates",
+			Response: domainTemplate.ListTemplatesResponse{}},
+		{Method: fiber.MethodPost, Path: "/templates", Tag: "template", Summary: "Create message template",
+			Body: domainTemplate.TemplateRequest{}, Validate: rulesPtr(validations.ValidateTemplate),
+			Response: domainTemplate.TemplateInfo{}},
+		{Method: fiber.MethodGet, Path: "/templates/:template_id", Tag: "template", Summary: "Get message template",
+			Response: domainTemplate.TemplateInfo{}},
+		{Method: fiber.MethodPost, Path: "/templates/:template_id/update", Tag: "template", Summary: "Update message template",
+			Body: domainTemplate.TemplateRequest{}, Validate: rulesPtr(validations.ValidateUpdateTemplate),
+			Response: domainTemplate.TemplateInfo{}},
+		{Method: fiber.MethodPost, Path: "/templates/:template_id/delete", Tag: "template", Summary: "Delete message template"},
+		{Method: fiber.MethodPost, Path: "/templates/:template_id/render", Tag: "template", Summary: "Preview a rendered template",
+			Body: domainTemplate.RenderTemplateRequest{}, Response: domainTemplate.RenderTemplateResponse{}},
+
+		// Contacts
+		{Method: fiber.MethodGet, Path: "/contacts", Tag: "contact", Summary: "Search stored contacts",
+			Query: domainContact.ListContactsRequest{}, Validate: rulesPtr(validations.ValidateListContacts),
+			Response: domainContact.ListContactsResponse{}},
+		{Method: fiber.MethodGet, Path: "/contacts/export", Tag: "contact", Summary: "Export contacts",
+			Description: "Returns a vCard file, or CSV with format=csv.",
+			Query:       domainContact.ExportContactsRequest{}, ContentType: "text/vcard"},
+		{Method: fiber.MethodPost, Path: "/contacts/import", Tag: "contact", Summary: "Import contacts from vCard or CSV",
+			Body: domainContact.ImportContactsRequest{}, Response: domainContact.ImportContactsResponse{}},
+
+		// Labels
+		{Method: fiber.MethodGet, Path: "/labels", Tag: "label", Summary: "List WhatsApp Business labels",
+			Response: domainLabel.ListLabelsResponse{}},
+		{Method: fiber.MethodPost, Path: "/labels", Tag: "label", Summary: "Create label",
+			Body: domainLabel.LabelRequest{}, Validate: rulesPtr(validations.ValidateCreateLabel),
+			Response: domainLabel.LabelInfo{}},
+		{Method: fiber.MethodPost, Path: "/labels/:label_id/update", Tag: "label", Summary: "Rename or recolor label",
+			Body: domainLabel.LabelRequest{}, Validate: rulesPtr(validations.ValidateUpdateLabel),
+			Response: domainLabel.LabelInfo{}},
+		{Method: fiber.MethodPost, Path: "/labels/:label_id/delete", Tag: "label", Summary: "Delete label"},
+		{Method: fiber.MethodPost, Path: "/chat/:chat_jid/label", Tag: "label", Summary: "Label or unlabel chat",
+			Body: domainLabel.ChatLabelRequest{}, Validate: rulesPtr(validations.ValidateLabelChat),
+			Response: domainLabel.ChatLabelResponse{}},
+		{Method: fiber.MethodPost, Path: "/message/:message_id/label", Tag: "label", Summary: "Label or unlabel message",
+			Body: domainLabel.MessageLabelRequest{}, Validate: rulesPtr(validations.ValidateLabelMessage),
+			Response: domainLabel.MessageLabelResponse{}},
+
+		// Health
+		{Method: fiber.MethodGet, Path: "/healthz", Tag: "health", Summary: "Liveness probe",
+			Description: "Answers while the process serves requests. Needs no basic auth.",
+			Response:    domainHealth.LivenessResponse{}},
+		{Method: fiber.MethodGet, Path: "/readyz", Tag: "health", Summary: "Readiness probe",
+			Description: "Checks the WhatsApp connection and login, chat storage and keys database writability, the webhook backlog and free disk space under the media folder. Answers 503 with code NOT_READY and the same results when a check fails. Needs no basic auth.",
+			Response:    domainHealth.ReadinessResponse{}},
+
+		// Documentation
+		{Method: fiber.MethodGet, Path: "/openapi.json", Tag: "docs", Summary: "This OpenAPI document",
+			ContentType: "application/json"},
+		{Method: fiber.MethodGet, Path: "/docs", Tag: "docs", Summary: "API documentation viewer",
+			ContentType: "text/html"},
+	}
+}
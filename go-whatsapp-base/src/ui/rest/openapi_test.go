@@ -0,0 +1,104 @@
+package rest
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// registeredRoutes registers every REST controller on a fresh app and lists
+// its routes as "METHOD path"
+func registeredRoutes() map[string]bool {
+	app := fiber.New()
+	InitRestApp(app, nil)
+	InitRestChat(app, nil)
+	InitRestSend(app, nil)
+	InitRestUser(app, nil)
+	InitRestMessage(app, nil)
+	InitRestGroup(app, nil)
+	InitRestCommunity(app, nil, nil)
+	InitRestNewsletter(app, nil)
+	InitRestAutoReply(app, nil)
+	InitRestTemplate(app, nil)
+	InitRestContact(app, nil)
+	InitRestLabel(app, nil)
+	InitRestHealth(app, nil)
+	InitRestOpenAPI(app)
+
+	routes := make(map[string]bool)
+	for _, route := range app.GetRoutes(true) {
+		// fiber registers a HEAD route alongside every GET
+		if route.Method == fiber.MethodHead {
+			continue
+		}
+		routes[route.Method+" "+route.Path] = true
+	}
+	return routes
+}
+
+func TestOpenAPIOperationsCoverRoutes(t *testing.T) {
+	routes := registeredRoutes()
+
+	documented := make(map[string]bool)
+	for _, op := range openAPIOperations() {
+		key := op.Method + " " + op.Path
+		assert.False(t, documented[key], "%s is documented twice", key)
+		documented[key] = true
+	}
+
+	for route := range routes {
+		assert.True(t, documented[route], "%s has no entry in openAPIOperations", route)
+	}
+	for op := range documented {
+		assert.True(t, routes[op], "%s is documented but not registered", op)
+	}
+}
+
+func TestOpenAPIDocument(t *testing.T) {
+	document := InitRestOpenAPI(fiber.New()).Document
+
+	raw, err := json.Marshal(document)
+	require.NoError(t, err)
+
+	var spec struct {
+		Paths map[string]map[string]struct {
+			Parameters []struct {
+				Name     string `json:"name"`
+				In       string `json:"in"`
+				Required bool   `json:"required"`
+			} `json:"parameters"`
+			RequestBody struct {
+				Content map[string]struct {
+					Schema struct {
+						Required []string `json:"required"`
+					} `json:"schema"`
+				} `json:"content"`
+			} `json:"requestBody"`
+		} `json:"paths"`
+	}
+	require.NoError(t, json.Unmarshal(raw, &spec))
+
+	sendMessage := spec.Paths["/send/message"]["post"]
+	assert.Contains(t, sendMessage.RequestBody.Content["application/json"].Schema.Required, "phone")
+	assert.Contains(t, sendMessage.RequestBody.Content["application/json"].Schema.Required, "message")
+
+	var headers []string
+	for _, parameter := range sendMessage.Parameters {
+		if parameter.In == "header" {
+			headers = append(headers, parameter.Name)
+		}
+	}
+	assert.Equal(t, []string{"Idempotency-Key"}, headers)
+
+	_, multipart := spec.Paths["/send/image"]["post"].RequestBody.Content["multipart/form-data"]
+	assert.True(t, multipart, "uploads should be multipart/form-data")
+
+	messages := spec.Paths["/chat/{chat_jid}/messages"]["get"]
+	require.NotEmpty(t, messages.Parameters)
+	assert.Equal(t, "chat_jid", messages.Parameters[0].Name)
+	assert.Equal(t, "path", messages.Parameters[0].In)
+	assert.True(t, messages.Parameters[0].Required)
+}
@@ -1,7 +1,10 @@
 package rest
 
 import (
+	"strconv"
+
 	domainSend "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/send"
+	pkgError "github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/error"
 	"github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/utils"
 	"github.com/gofiber/fiber/v2"
 )
@@ -22,8 +25,13 @@ func InitRestSend(app fiber.Router, service domainSend.ISendUsecase) Send {
 	app.Post("/send/location", rest.SendLocation)
 	app.Post("/send/audio", rest.SendAudio)
 	app.Post("/send/poll", rest.SendPoll)
+	app.Post("/send/product", rest.SendProduct)
+	app.Post("/send/product-list", rest.SendProductList)
 	app.Post("/send/presence", rest.SendPresence)
 	app.Post("/send/chat-presence", rest.SendChatPresence)
+	app.Get("/send/queue", rest.ListQueue)
+	app.Post("/send/queue/flush", rest.FlushQueue)
+	app.Post("/send/queue/:queue_id/cancel", rest.CancelQueued)
 	return rest
 }
 
@@ -33,6 +41,7 @@ func (controller *Send) SendText(c *fiber.Ctx) error {
 	utils.PanicIfNeeded(err)
 
 	utils.SanitizePhone(&request.Phone)
+	applyIdempotencyKey(c, &request.BaseRequest)
 
 	response, err := controller.Service.SendText(c.UserContext(), request)
 	utils.PanicIfNeeded(err)
@@ -58,6 +67,7 @@ func (controller *Send) SendImage(c *fiber.Ctx) error {
 	}
 
 	utils.SanitizePhone(&request.Phone)
+	applyIdempotencyKey(c, &request.BaseRequest)
 
 	response, err := controller.Service.SendImage(c.UserContext(), request)
 	utils.PanicIfNeeded(err)
@@ -80,6 +90,7 @@ func (controller *Send) SendFile(c *fiber.Ctx) error {
 
 	request.File = file
 	utils.SanitizePhone(&request.Phone)
+	applyIdempotencyKey(c, &request.BaseRequest)
 
 	response, err := controller.Service.SendFile(c.UserContext(), request)
 	utils.PanicIfNeeded(err)
@@ -103,6 +114,7 @@ func (controller *Send) SendVideo(c *fiber.Ctx) error {
 	}
 
 	utils.SanitizePhone(&request.Phone)
+	applyIdempotencyKey(c, &request.BaseRequest)
 
 	response, err := controller.Service.SendVideo(c.UserContext(), request)
 	utils.PanicIfNeeded(err)
@@ -126,6 +138,7 @@ func (controller *Send) SendSticker(c *fiber.Ctx) error {
 	}
 
 	utils.SanitizePhone(&request.Phone)
+	applyIdempotencyKey(c, &request.BaseRequest)
 
 	response, err := controller.Service.SendSticker(c.UserContext(), request)
 	utils.PanicIfNeeded(err)
@@ -144,6 +157,7 @@ func (controller *Send) SendContact(c *fiber.Ctx) error {
 	utils.PanicIfNeeded(err)
 
 	utils.SanitizePhone(&request.Phone)
+	applyIdempotencyKey(c, &request.BaseRequest)
 
 	response, err := controller.Service.SendContact(c.UserContext(), request)
 	utils.PanicIfNeeded(err)
@@ -162,6 +176,7 @@ func (controller *Send) SendLink(c *fiber.Ctx) error {
 	utils.PanicIfNeeded(err)
 
 	utils.SanitizePhone(&request.Phone)
+	applyIdempotencyKey(c, &request.BaseRequest)
 
 	response, err := controller.Service.SendLink(c.UserContext(), request)
 	utils.PanicIfNeeded(err)
@@ -180,6 +195,7 @@ func (controller *Send) SendLocation(c *fiber.Ctx) error {
 	utils.PanicIfNeeded(err)
 
 	utils.SanitizePhone(&request.Phone)
+	applyIdempotencyKey(c, &request.BaseRequest)
 
 	response, err := controller.Service.SendLocation(c.UserContext(), request)
 	utils.PanicIfNeeded(err)
@@ -203,6 +219,7 @@ func (controller *Send) SendAudio(c *fiber.Ctx) error {
 	}
 
 	utils.SanitizePhone(&request.Phone)
+	applyIdempotencyKey(c, &request.BaseRequest)
 
 	response, err := controller.Service.SendAudio(c.UserContext(), request)
 	utils.PanicIfNeeded(err)
@@ -221,6 +238,7 @@ func (controller *Send) SendPoll(c *fiber.Ctx) error {
 	utils.PanicIfNeeded(err)
 
 	utils.SanitizePhone(&request.Phone)
+	applyIdempotencyKey(c, &request.BaseRequest)
 
 	response, err := controller.Service.SendPoll(c.UserContext(), request)
 	utils.PanicIfNeeded(err)
@@ -233,6 +251,44 @@ func (controller *Send) SendPoll(c *fiber.Ctx) error {
 	})
 }
 
+func (controller *Send) SendProduct(c *fiber.Ctx) error {
+	var request domainSend.ProductRequest
+	err := c.BodyParser(&request)
+	utils.PanicIfNeeded(err)
+
+	utils.SanitizePhone(&request.Phone)
+	applyIdempotencyKey(c, &request.BaseRequest)
+
+	response, err := controller.Service.SendProduct(c.UserContext(), request)
+	utils.PanicIfNeeded(err)
+
+	return c.JSON(utils.ResponseData{
+		Status:  200,
+		Code:    "SUCCESS",
+		Message: response.Status,
+		Results: response,
+	})
+}
+
+func (controller *Send) SendProductList(c *fiber.Ctx) error {
+	var request domainSend.ProductListRequest
+	err := c.BodyParser(&request)
+	utils.PanicIfNeeded(err)
+
+	utils.SanitizePhone(&request.Phone)
+	applyIdempotencyKey(c, &request.BaseRequest)
+
+	response, err := controller.Service.SendProductList(c.UserContext(), request)
+	utils.PanicIfNeeded(err)
+
+	return c.JSON(utils.ResponseData{
+		Status:  200,
+		Code:    "SUCCESS",
+		Message: response.Status,
+		Results: response,
+	})
+}
+
 func (controller *Send) SendPresence(c *fiber.Ctx) error {
 	var request domainSend.PresenceRequest
 	err := c.BodyParser(&request)
@@ -266,3 +322,55 @@ func (controller *Send) SendChatPresence(c *fiber.Ctx) error {
 		Results: response,
 	})
 }
+
+func (controller *Send) ListQueue(c *fiber.Ctx) error {
+	var request domainSend.QueueListRequest
+	err := c.QueryParser(&request)
+	utils.PanicIfNeeded(err)
+
+	response, err := controller.Service.GetSendQueue(c.UserContext(), request)
+	utils.PanicIfNeeded(err)
+
+	return c.JSON(utils.ResponseData{
+		Status:  200,
+		Code:    "SUCCESS",
+		Message: "Success get send queue",
+		Results: response,
+	})
+}
+
+func (controller *Send) CancelQueued(c *fiber.Ctx) error {
+	queueID, err := strconv.ParseInt(c.Params("queue_id"), 10, 64)
+	if err != nil {
+		panic(pkgError.ValidationError("queue_id: must be a number."))
+	}
+
+	response, err := controller.Service.CancelQueuedSend(c.UserContext(), queueID)
+	utils.PanicIfNeeded(err)
+
+	return c.JSON(utils.ResponseData{
+		Status:  200,
+		Code:    "SUCCESS",
+		Message: "Queued send cancelled",
+		Results: response,
+	})
+}
+
+func (controller *Send) FlushQueue(c *fiber.Ctx) error {
+	response, err := controller.Service.FlushSendQueue(c.UserContext())
+	utils.PanicIfNeeded(err)
+
+	return c.JSON(utils.ResponseData{
+		Status:  200,
+		Code:    "SUCCESS",
+		Message: "Send queue flushed",
+		Results: response,
+	})
+}
+
+// applyIdempotencyKey lets the Idempotency-Key header set the key of a send
+func applyIdempotencyKey(c *fiber.Ctx, request *domainSend.BaseRequest) {
+	if key := c.Get("Idempotency-Key"); key != "" {
+		request.IdempotencyKey = key
+	}
+}
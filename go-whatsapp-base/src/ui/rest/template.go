@@ -0,0 +1,119 @@
+package rest
+
+import (
+	domainTemplate "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/template"
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/utils"
+	"github.com/gofiber/fiber/v2"
+)
+
+type Template struct {
+	Service domainTemplate.ITemplateUsecase
+}
+
+func InitRestTemplate(app fiber.Router, service domainTemplate.ITemplateUsecase) Template {
+	rest := Template{Service: service}
+
+	// Message template endpoints
+	app.Get("/templates", rest.ListTemplates)
+	app.Post("/templates", rest.CreateTemplate)
+	app.Get("/templates/:template_id", rest.GetTemplate)
+	app.Post("/templates/:template_id/update", rest.UpdateTemplate)
+	app.Post("/templates/:template_id/delete", rest.DeleteTemplate)
+	app.Post("/templates/:template_id/render", rest.RenderTemplate)
+
+	return rest
+}
+
+func (controller *Template) ListTemplates(c *fiber.Ctx) error {
+	response, err := controller.Service.ListTemplates(c.UserContext())
+	utils.PanicIfNeeded(err)
+
+	return c.JSON(utils.ResponseData{
+		Status:  200,
+		Code:    "SUCCESS",
+		Message: "Success get templates",
+		Results: response,
+	})
+}
+
+func (controller *Template) GetTemplate(c *fiber.Ctx) error {
+	var request domainTemplate.GetTemplateRequest
+	request.TemplateID = c.Params("template_id")
+
+	response, err := controller.Service.GetTemplate(c.UserContext(), request)
+	utils.PanicIfNeeded(err)
+
+	return c.JSON(utils.ResponseData{
+		Status:  200,
+		Code:    "SUCCESS",
+		Message: "Success get template",
+		Results: response,
+	})
+}
+
+func (controller *Template) CreateTemplate(c *fiber.Ctx) error {
+	var request domainTemplate.TemplateRequest
+	err := c.BodyParser(&request)
+	utils.PanicIfNeeded(err)
+
+	response, err := controller.Service.CreateTemplate(c.UserContext(), request)
+	utils.PanicIfNeeded(err)
+
+	return c.JSON(utils.ResponseData{
+		Status:  200,
+		Code:    "SUCCESS",
+		Message: "Template created successfully",
+		Results: response,
+	})
+}
+
+func (controller *Template) UpdateTemplate(c *fiber.Ctx) error {
+	var request domainTemplate.TemplateRequest
+	err := c.BodyParser(&request)
+	utils.PanicIfNeeded(err)
+
+	request.TemplateID = c.Params("template_id")
+
+	response, err := controller.Service.UpdateTemplate(c.UserContext(), request)
+	utils.PanicIfNeeded(err)
+
+	return c.JSON(utils.ResponseData{
+		Status:  200,
+		Code:    "SUCCESS",
+		Message: "Template updated successfully",
+		Results: response,
+	})
+}
+
+func (controller *Template) DeleteTemplate(c *fiber.Ctx) error {
+	var request domainTemplate.DeleteTemplateRequest
+	request.TemplateID = c.Params("template_id")
+
+	err := controller.Service.DeleteTemplate(c.UserContext(), request)
+	utils.PanicIfNeeded(err)
+
+	return c.JSON(utils.ResponseData{
+		Status:  200,
+		Code:    "SUCCESS",
+		Message: "Template deleted successfully",
+		Results: nil,
+	})
+}
+
+func (controller *Template) RenderTemplate(c *fiber.Ctx) error {
+	var request domainTemplate.RenderTemplateRequest
+	err := c.BodyParser(&request)
+	utils.PanicIfNeeded(err)
+
+	request.TemplateID = c.Params("template_id")
+
+	response, err := controller.Service.RenderTemplate(c.UserContext(), request)
+	utils.PanicIfNeeded(err)
+
+	return c.JSON(utils.ResponseData{
+		Status:  200,
+		Code:    "SUCCESS",
+		Message: "Template rendered successfully",
+		Results: response,
+	})
+}
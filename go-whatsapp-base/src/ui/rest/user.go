@@ -22,6 +22,8 @@ func InitRestUser(app fiber.Router, service domainUser.IUserUsecase) User {
 	app.Get("/user/my/contacts", rest.UserMyListContacts)
 	app.Get("/user/check", rest.UserCheck)
 	app.Get("/user/business-profile", rest.UserBusinessProfile)
+	app.Get("/user/business-catalog", rest.UserBusinessCatalog)
+	app.Get("/user/business-collections", rest.UserBusinessCollections)
 
 	return rest
 }
@@ -176,3 +178,39 @@ func (controller *User) UserBusinessProfile(c *fiber.Ctx) error {
 		Results: response,
 	})
 }
+
+func (controller *User) UserBusinessCatalog(c *fiber.Ctx) error {
+	var request domainUser.BusinessCatalogRequest
+	err := c.QueryParser(&request)
+	utils.PanicIfNeeded(err)
+
+	utils.SanitizePhone(&request.Phone)
+
+	response, err := controller.Service.BusinessCatalog(c.UserContext(), request)
+	utils.PanicIfNeeded(err)
+
+	return c.JSON(utils.ResponseData{
+		Status:  200,
+		Code:    "SUCCESS",
+		Message: "Success get business catalog",
+		Results: response,
+	})
+}
+
+func (controller *User) UserBusinessCollections(c *fiber.Ctx) error {
+	var request domainUser.BusinessCollectionsRequest
+	err := c.QueryParser(&request)
+	utils.PanicIfNeeded(err)
+
+	utils.SanitizePhone(&request.Phone)
+
+	response, err := controller.Service.BusinessCollections(c.UserContext(), request)
+	utils.PanicIfNeeded(err)
+
+	return c.JSON(utils.ResponseData{
+		Status:  200,
+		Code:    "SUCCESS",
+		Message: "Success get business collections",
+		Results: response,
+	})
+}
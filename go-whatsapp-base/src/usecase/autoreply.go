@@ -0,0 +1,191 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	domainAutoReply "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/autoreply"
+	domainChatStorage "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/chatstorage"
+	pkgError "github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/error"
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/validations"
+	"github.com/sirupsen/logrus"
+)
+
+type serviceAutoReply struct {
+	chatStorageRepo domainChatStorage.IChatStorageRepository
+}
+
+func NewAutoReplyService(chatStorageRepo domainChatStorage.IChatStorageRepository) domainAutoReply.IAutoReplyUsecase {
+	return &serviceAutoReply{
+		chatStorageRepo: chatStorageRepo,
+	}
+}
+
+func (service serviceAutoReply) ListRules(_ context.Context) (response domainAutoReply.ListRulesResponse, err error) {
+	rules, err := service.chatStorageRepo.GetAutoReplyRules(false)
+	if err != nil {
+		logrus.WithError(err).Error("Failed to get auto-reply rules from storage")
+		return response, err
+	}
+
+	response.Data = make([]domainAutoReply.RuleInfo, 0, len(rules))
+	for _, rule := range rules {
+		response.Data = append(response.Data, toAutoReplyRuleInfo(rule))
+	}
+
+	return response, nil
+}
+
+func (service serviceAutoReply) GetRule(ctx context.Context, request domainAutoReply.GetRuleRequest) (response domainAutoReply.RuleInfo, err error) {
+	if err = validations.ValidateGetAutoReplyRule(ctx, request); err != nil {
+		return response, err
+	}
+
+	rule, err := service.getRule(request.RuleID)
+	if err != nil {
+		return response, err
+	}
+
+	return toAutoReplyRuleInfo(rule), nil
+}
+
+func (service serviceAutoReply) CreateRule(ctx context.Context, request domainAutoReply.RuleRequest) (response domainAutoReply.RuleInfo, err error) {
+	if err = validations.ValidateAutoReplyRule(ctx, &request); err != nil {
+		return response, err
+	}
+
+	rule := &domainChatStorage.AutoReplyRule{Enabled: true}
+	applyAutoReplyRuleRequest(rule, request)
+
+	if err = service.chatStorageRepo.StoreAutoReplyRule(rule); err != nil {
+		return response, pkgError.InternalServerError(fmt.Sprintf("failed to create auto-reply rule: %v", err))
+	}
+
+	logrus.WithFields(logrus.Fields{"rule_id": rule.ID, "name": rule.Name}).Info("Created auto-reply rule")
+	return toAutoReplyRuleInfo(rule), nil
+}
+
+func (service serviceAutoReply) UpdateRule(ctx context.Context, request domainAutoReply.RuleRequest) (response domainAutoReply.RuleInfo, err error) {
+	if err = validations.ValidateUpdateAutoReplyRule(ctx, &request); err != nil {
+		return response, err
+	}
+
+	rule, err := service.getRule(request.RuleID)
+	if err != nil {
+		return response, err
+	}
+
+	applyAutoReplyRuleRequest(rule, request)
+
+	if err = service.chatStorageRepo.StoreAutoReplyRule(rule); err != nil {
+		return response, pkgError.InternalServerError(fmt.Sprintf("failed to update auto-reply rule: %v", err))
+	}
+
+	logrus.WithFields(logrus.Fields{"rule_id": rule.ID, "name": rule.Name}).Info("Updated auto-reply rule")
+	return toAutoReplyRuleInfo(rule), nil
+}
+
+func (service serviceAutoReply) DeleteRule(ctx context.Context, request domainAutoReply.DeleteRuleRequest) (err error) {
+	if err = validations.ValidateDeleteAutoReplyRule(ctx, request); err != nil {
+		return err
+	}
+
+	if _, err = service.getRule(request.RuleID); err != nil {
+		return err
+	}
+
+	if err = service.chatStorageRepo.DeleteAutoReplyRule(request.RuleID); err != nil {
+		return pkgError.InternalServerError(fmt.Sprintf("failed to delete auto-reply rule: %v", err))
+	}
+
+	logrus.WithField("rule_id", request.RuleID).Info("Deleted auto-reply rule")
+	return nil
+}
+
+func (service serviceAutoReply) ListHits(ctx context.Context, request domainAutoReply.ListHitsRequest) (response domainAutoReply.ListHitsResponse, err error) {
+	if err = validations.ValidateListAutoReplyHits(ctx, &request); err != nil {
+		return response, err
+	}
+
+	filter := &domainChatStorage.AutoReplyHitFilter{
+		RuleID:    request.RuleID,
+		SenderJID: request.Sender,
+		Limit:     request.Limit,
+		Offset:    request.Offset,
+	}
+
+	hits, err := service.chatStorageRepo.GetAutoReplyHits(filter)
+	if err != nil {
+		logrus.WithError(err).Error("Failed to get auto-reply hits from storage")
+		return response, err
+	}
+
+	response.Data = make([]domainAutoReply.HitInfo, 0, len(hits))
+	for _, hit := range hits {
+		response.Data = append(response.Data, domainAutoReply.HitInfo{
+			ID:             hit.ID,
+			RuleID:         hit.RuleID,
+			ChatJID:        hit.ChatJID,
+			SenderJID:      hit.SenderJID,
+			MessageID:      hit.MessageID,
+			ReplyMessageID: hit.ReplyMessageID,
+			Status:         hit.Status,
+			Error:          hit.Error,
+			CreatedAt:      hit.CreatedAt.Format(time.RFC3339),
+		})
+	}
+
+	response.Pagination = domainAutoReply.PaginationResponse{
+		Limit:  request.Limit,
+		Offset: request.Offset,
+	}
+
+	return response, nil
+}
+
+func (service serviceAutoReply) getRule(id int64) (*domainChatStorage.AutoReplyRule, error) {
+	rule, err := service.chatStorageRepo.GetAutoReplyRule(id)
+	if err != nil {
+		return nil, err
+	}
+	if rule == nil {
+		return nil, pkgError.ValidationError(fmt.Sprintf("auto-reply rule %d not found", id))
+	}
+	return rule, nil
+}
+
+func applyAutoReplyRuleRequest(rule *domainChatStorage.AutoReplyRule, request domainAutoReply.RuleRequest) {
+	rule.Name = request.Name
+	if request.Enabled != nil {
+		rule.Enabled = *request.Enabled
+	}
+	rule.Priority = request.Priority
+	rule.Keywords = request.Keywords
+	rule.Pattern = request.Pattern
+	rule.Senders = request.Senders
+	rule.ChatType = request.ChatType
+	rule.ResponseType = request.ResponseType
+	rule.ResponseText = request.ResponseText
+	rule.MediaURL = request.MediaURL
+	rule.CooldownSeconds = request.CooldownSeconds
+}
+
+func toAutoReplyRuleInfo(rule *domainChatStorage.AutoReplyRule) domainAutoReply.RuleInfo {
+	return domainAutoReply.RuleInfo{
+		ID:              rule.ID,
+		Name:            rule.Name,
+		Enabled:         rule.Enabled,
+		Priority:        rule.Priority,
+		Keywords:        rule.Keywords,
+		Pattern:         rule.Pattern,
+		Senders:         rule.Senders,
+		ChatType:        rule.ChatType,
+		ResponseType:    rule.ResponseType,
+		ResponseText:    rule.ResponseText,
+		MediaURL:        rule.MediaURL,
+		CooldownSeconds: rule.CooldownSeconds,
+		CreatedAt:       rule.CreatedAt.Format(time.RFC3339),
+		UpdatedAt:       rule.UpdatedAt.Format(time.RFC3339),
+	}
+}
@@ -8,10 +8,13 @@ import (
 	domainChat "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/chat"
 	domainChatStorage "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/chatstorage"
 	"github.com/aldinokemal/go-whatsapp-web-multidevice/infrastructure/whatsapp"
+	pkgError "github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/error"
 	"github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/utils"
 	"github.com/aldinokemal/go-whatsapp-web-multidevice/validations"
 	"github.com/sirupsen/logrus"
+	"go.mau.fi/whatsmeow"
 	"go.mau.fi/whatsmeow/appstate"
+	"go.mau.fi/whatsmeow/types"
 )
 
 type serviceChat struct {
@@ -35,6 +38,7 @@ func (service serviceChat) ListChats(ctx context.Context, request domainChat.Lis
 		Offset:     request.Offset,
 		SearchName: request.Search,
 		HasMedia:   request.HasMedia,
+		LabelID:    request.LabelID,
 	}
 
 	// Get chats from storage
@@ -45,13 +49,24 @@ func (service serviceChat) ListChats(ctx context.Context, request domainChat.Lis
 	}
 
 	// Get total count for pagination
-	totalCount, err := service.chatStorageRepo.GetTotalChatCount()
+	totalCount, err := service.chatStorageRepo.GetChatCount(filter)
 	if err != nil {
 		logrus.WithError(err).Error("Failed to get total chat count")
 		// Continue with partial data
 		totalCount = 0
 	}
 
+	chatJIDs := make([]string, 0, len(chats))
+	for _, chat := range chats {
+		chatJIDs = append(chatJIDs, chat.JID)
+	}
+	chatLabels, err := service.chatStorageRepo.GetChatLabels(chatJIDs)
+	if err != nil {
+		logrus.WithError(err).Error("Failed to get chat labels")
+		// Continue without labels
+		chatLabels = nil
+	}
+
 	// Convert entities to domain objects
 	chatInfos := make([]domainChat.ChatInfo, 0, len(chats))
 	for _, chat := range chats {
@@ -60,6 +75,7 @@ func (service serviceChat) ListChats(ctx context.Context, request domainChat.Lis
 			Name:                chat.Name,
 			LastMessageTime:     chat.LastMessageTime.Format(time.RFC3339),
 			EphemeralExpiration: chat.EphemeralExpiration,
+			Labels:              chatLabels[chat.JID],
 			CreatedAt:           chat.CreatedAt.Format(time.RFC3339),
 			UpdatedAt:           chat.UpdatedAt.Format(time.RFC3339),
 		}
@@ -166,9 +182,18 @@ func (service serviceChat) GetChatMessages(ctx context.Context, request domainCh
 			Filename:   message.Filename,
 			URL:        message.URL,
 			FileLength: message.FileLength,
+			ViewOnce:   message.ViewOnce,
 			CreatedAt:  message.CreatedAt.Format(time.RFC3339),
 			UpdatedAt:  message.UpdatedAt.Format(time.RFC3339),
 		}
+		if message.InteractiveType != "" {
+			messageInfo.InteractiveReply = &domainChat.InteractiveReply{
+				Type:              message.InteractiveType,
+				SelectedID:        message.SelectedID,
+				SelectedTitle:     message.SelectedTitle,
+				OriginalMessageID: message.OriginalMessageID,
+			}
+		}
 		messageInfos = append(messageInfos, messageInfo)
 	}
 
@@ -244,3 +269,107 @@ func (service serviceChat) PinChat(ctx context.Context, request domainChat.PinCh
 
 	return response, nil
 }
+
+// BackfillChat asks the primary device for messages older than the oldest stored
+// message of the chat. The device answers asynchronously with an on-demand history
+// sync, which is merged into storage and completes the backfill.
+func (service serviceChat) BackfillChat(ctx context.Context, request domainChat.BackfillChatRequest) (response domainChat.BackfillInfo, err error) {
+	if err = validations.ValidateBackfillChat(ctx, &request); err != nil {
+		return response, err
+	}
+
+	client := whatsapp.GetClient()
+	chatJID, err := utils.ValidateJidWithLogin(client, request.ChatJID)
+	if err != nil {
+		return response, err
+	}
+
+	oldest, err := service.chatStorageRepo.GetOldestMessage(chatJID.String())
+	if err != nil {
+		return response, err
+	}
+	if oldest == nil {
+		return response, pkgError.ValidationError(fmt.Sprintf("chat %s has no stored messages to backfill from", chatJID.String()))
+	}
+
+	anchor := &types.MessageInfo{
+		MessageSource: types.MessageSource{Chat: chatJID, IsFromMe: oldest.IsFromMe},
+		ID:            oldest.ID,
+		Timestamp:     oldest.Timestamp,
+	}
+
+	backfill, err := service.chatStorageRepo.GetChatBackfill(chatJID.String())
+	if err != nil {
+		return response, err
+	}
+	if backfill == nil {
+		backfill = &domainChatStorage.ChatBackfill{ChatJID: chatJID.String()}
+	}
+
+	backfill.Status = domainChatStorage.BackfillStatusPending
+	backfill.RequestedCount = request.Count
+	backfill.AnchorMessageID = oldest.ID
+	backfill.AnchorTimestamp = oldest.Timestamp
+	backfill.ReceivedMessages = 0
+	backfill.StoredMessages = 0
+	backfill.Requests++
+	backfill.RequestedAt = time.Now()
+	backfill.CompletedAt = nil
+
+	// Store the request first, the device may answer before SendMessage returns
+	if err = service.chatStorageRepo.StoreChatBackfill(backfill); err != nil {
+		return response, err
+	}
+
+	historyRequest := client.BuildHistorySyncRequest(anchor, request.Count)
+	if _, err = client.SendMessage(ctx, client.Store.ID.ToNonAD(), historyRequest, whatsmeow.SendRequestExtra{Peer: true}); err != nil {
+		logrus.WithError(err).WithField("chat_jid", chatJID.String()).Error("Failed to request history backfill")
+		backfill.Status = domainChatStorage.BackfillStatusFailed
+		if storeErr := service.chatStorageRepo.StoreChatBackfill(backfill); storeErr != nil {
+			logrus.WithError(storeErr).WithField("chat_jid", chatJID.String()).Warn("Failed to mark history backfill as failed")
+		}
+		return response, err
+	}
+
+	return toBackfillInfo(backfill), nil
+}
+
+func (service serviceChat) GetChatBackfill(ctx context.Context, request domainChat.GetChatBackfillRequest) (response domainChat.BackfillInfo, err error) {
+	if err = validations.ValidateGetChatBackfill(ctx, &request); err != nil {
+		return response, err
+	}
+
+	chatJID, err := utils.ParseJID(request.ChatJID)
+	if err != nil {
+		return response, err
+	}
+
+	backfill, err := service.chatStorageRepo.GetChatBackfill(chatJID.String())
+	if err != nil {
+		return response, err
+	}
+	if backfill == nil {
+		return response, pkgError.ValidationError(fmt.Sprintf("no backfill requested for chat %s", chatJID.String()))
+	}
+
+	return toBackfillInfo(backfill), nil
+}
+
+func toBackfillInfo(backfill *domainChatStorage.ChatBackfill) domainChat.BackfillInfo {
+	info := domainChat.BackfillInfo{
+		ChatJID:          backfill.ChatJID,
+		Status:           backfill.Status,
+		RequestedCount:   backfill.RequestedCount,
+		AnchorMessageID:  backfill.AnchorMessageID,
+		AnchorTimestamp:  backfill.AnchorTimestamp.Format(time.RFC3339),
+		ReceivedMessages: backfill.ReceivedMessages,
+		StoredMessages:   backfill.StoredMessages,
+		TotalStored:      backfill.TotalStored,
+		Requests:         backfill.Requests,
+		RequestedAt:      backfill.RequestedAt.Format(time.RFC3339),
+	}
+	if backfill.CompletedAt != nil {
+		info.CompletedAt = backfill.CompletedAt.Format(time.RFC3339)
+	}
+	return info
+}
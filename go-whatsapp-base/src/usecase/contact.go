@@ -0,0 +1,192 @@
+package usecase
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"time"
+
+	domainChatStorage "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/chatstorage"
+	domainContact "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/contact"
+	pkgError "github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/error"
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/utils"
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/validations"
+	"github.com/sirupsen/logrus"
+	"go.mau.fi/whatsmeow/types"
+)
+
+type serviceContact struct {
+	chatStorageRepo domainChatStorage.IChatStorageRepository
+}
+
+func NewContactService(chatStorageRepo domainChatStorage.IChatStorageRepository) domainContact.IContactUsecase {
+	return &serviceContact{
+		chatStorageRepo: chatStorageRepo,
+	}
+}
+
+func (service serviceContact) ListContacts(ctx context.Context, request domainContact.ListContactsRequest) (response domainContact.ListContactsResponse, err error) {
+	if err = validations.ValidateListContacts(ctx, &request); err != nil {
+		return response, err
+	}
+
+	filter := &domainChatStorage.ContactFilter{
+		Search: request.Search,
+		Limit:  request.Limit,
+		Offset: request.Offset,
+	}
+
+	contacts, err := service.chatStorageRepo.GetContacts(filter)
+	if err != nil {
+		logrus.WithError(err).Error("Failed to get contacts from storage")
+		return response, err
+	}
+
+	totalCount, err := service.chatStorageRepo.GetContactCount(filter)
+	if err != nil {
+		logrus.WithError(err).Error("Failed to get contact count")
+		// Continue with partial data
+		totalCount = 0
+	}
+
+	response.Data = make([]domainContact.ContactInfo, 0, len(contacts))
+	for _, contact := range contacts {
+		response.Data = append(response.Data, toContactInfo(contact))
+	}
+	response.Pagination = domainContact.PaginationResponse{
+		Limit:  request.Limit,
+		Offset: request.Offset,
+		Total:  int(totalCount),
+	}
+
+	return response, nil
+}
+
+func (service serviceContact) ImportContacts(ctx context.Context, request domainContact.ImportContactsRequest) (response domainContact.ImportContactsResponse, err error) {
+	if err = validations.ValidateImportContacts(ctx, &request); err != nil {
+		return response, err
+	}
+
+	file, err := request.File.Open()
+	if err != nil {
+		return response, fmt.Errorf("failed to open contacts file: %w", err)
+	}
+	defer file.Close()
+
+	data, err := io.ReadAll(file)
+	if err != nil {
+		return response, fmt.Errorf("failed to read contacts file: %w", err)
+	}
+
+	var cards []utils.ContactCard
+	if request.Format == domainContact.FormatCSV {
+		cards, err = utils.ParseContactsCSV(data)
+		if err != nil {
+			return response, pkgError.ValidationError(err.Error())
+		}
+	} else {
+		cards = utils.ParseVCards(data)
+	}
+
+	for _, card := range cards {
+		if card.Phone == "" {
+			response.Skipped++
+			continue
+		}
+
+		phoneJID := types.NewJID(card.Phone, types.DefaultUserServer).String()
+		contact := &domainChatStorage.Contact{
+			JID:       phoneJID,
+			PhoneJID:  phoneJID,
+			SavedName: card.Name,
+		}
+		if err = service.chatStorageRepo.StoreContact(contact); err != nil {
+			return response, fmt.Errorf("failed to store contact %s: %w", phoneJID, err)
+		}
+		response.Imported++
+	}
+
+	logrus.WithFields(logrus.Fields{
+		"format":   request.Format,
+		"imported": response.Imported,
+		"skipped":  response.Skipped,
+	}).Info("Imported contacts")
+
+	return response, nil
+}
+
+func (service serviceContact) ExportContacts(ctx context.Context, request domainContact.ExportContactsRequest) (response domainContact.ExportContactsResponse, err error) {
+	if err = validations.ValidateExportContacts(ctx, &request); err != nil {
+		return response, err
+	}
+
+	contacts, err := service.chatStorageRepo.GetContacts(&domainChatStorage.ContactFilter{})
+	if err != nil {
+		return response, err
+	}
+
+	if request.Format == domainContact.FormatCSV {
+		var buffer bytes.Buffer
+		writer := csv.NewWriter(&buffer)
+		if err = writer.Write([]string{"jid", "phone_jid", "lid_jid", "name", "saved_name", "push_name", "business_name", "first_seen", "last_seen"}); err != nil {
+			return response, err
+		}
+		for _, contact := range contacts {
+			info := toContactInfo(contact)
+			record := []string{info.JID, info.PhoneJID, info.LIDJID, info.Name, info.SavedName, info.PushName, info.BusinessName, info.FirstSeen, info.LastSeen}
+			if err = writer.Write(record); err != nil {
+				return response, err
+			}
+		}
+		writer.Flush()
+		if err = writer.Error(); err != nil {
+			return response, err
+		}
+
+		response.FileName = "contacts.csv"
+		response.ContentType = "text/csv; charset=utf-8"
+		response.Data = buffer.Bytes()
+		return response, nil
+	}
+
+	// A vCard needs a phone number, so contacts only known by LID are left out
+	cards := make([]utils.ContactCard, 0, len(contacts))
+	for _, contact := range contacts {
+		phone := utils.NormalizeContactPhone(contact.PhoneJID)
+		if phone == "" {
+			continue
+		}
+		name := contact.DisplayName()
+		if name == "" {
+			name = "+" + phone
+		}
+		cards = append(cards, utils.ContactCard{Name: name, Phone: phone})
+	}
+
+	response.FileName = "contacts.vcf"
+	response.ContentType = "text/vcard; charset=utf-8"
+	response.Data = utils.FormatVCard(cards)
+	return response, nil
+}
+
+func toContactInfo(contact *domainChatStorage.Contact) domainContact.ContactInfo {
+	info := domainContact.ContactInfo{
+		JID:          contact.JID,
+		PhoneJID:     contact.PhoneJID,
+		LIDJID:       contact.LIDJID,
+		Name:         contact.DisplayName(),
+		SavedName:    contact.SavedName,
+		PushName:     contact.PushName,
+		BusinessName: contact.BusinessName,
+		UpdatedAt:    contact.UpdatedAt.Format(time.RFC3339),
+	}
+	if contact.FirstSeen != nil {
+		info.FirstSeen = contact.FirstSeen.Format(time.RFC3339)
+	}
+	if contact.LastSeen != nil {
+		info.LastSeen = contact.LastSeen.Format(time.RFC3339)
+	}
+	return info
+}
@@ -0,0 +1,140 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+
+	domainGroup "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/group"
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/infrastructure/whatsapp"
+	pkgError "github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/error"
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/utils"
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/validations"
+	"go.mau.fi/whatsmeow"
+	"go.mau.fi/whatsmeow/types"
+)
+
+func (service serviceGroup) CreateCommunity(ctx context.Context, request domainGroup.CreateCommunityRequest) (communityID string, err error) {
+	if err = validations.ValidateCreateCommunity(ctx, request); err != nil {
+		return communityID, err
+	}
+	utils.MustLogin(whatsapp.GetClient())
+
+	participantsJID, err := service.participantToJID(request.Participants)
+	if err != nil {
+		return
+	}
+
+	// WhatsApp creates the announcement group together with the community
+	groupConfig := whatsmeow.ReqCreateGroup{
+		Name:         request.Title,
+		Participants: participantsJID,
+		GroupParent:  types.GroupParent{IsParent: true},
+	}
+
+	groupInfo, err := whatsapp.GetClient().CreateGroup(ctx, groupConfig)
+	if err != nil {
+		return
+	}
+
+	return groupInfo.JID.String(), nil
+}
+
+func (service serviceGroup) LinkCommunityGroups(ctx context.Context, request domainGroup.CommunityGroupsRequest) (result []domainGroup.CommunityGroupStatus, err error) {
+	if err = validations.ValidateCommunityGroups(ctx, request); err != nil {
+		return result, err
+	}
+
+	return service.changeCommunityGroups(request, "link", func(community, group types.JID) error {
+		return whatsapp.GetClient().LinkGroup(ctx, community, group)
+	})
+}
+
+func (service serviceGroup) UnlinkCommunityGroups(ctx context.Context, request domainGroup.CommunityGroupsRequest) (result []domainGroup.CommunityGroupStatus, err error) {
+	if err = validations.ValidateCommunityGroups(ctx, request); err != nil {
+		return result, err
+	}
+
+	return service.changeCommunityGroups(request, "unlink", func(community, group types.JID) error {
+		return whatsapp.GetClient().UnlinkGroup(ctx, community, group)
+	})
+}
+
+// changeCommunityGroups applies a link or unlink to each group and reports the
+// outcome per group, so one rejected group doesn't hide the others
+func (service serviceGroup) changeCommunityGroups(request domainGroup.CommunityGroupsRequest, action string, change func(community, group types.JID) error) (result []domainGroup.CommunityGroupStatus, err error) {
+	communityJID, err := utils.ValidateJidWithLogin(whatsapp.GetClient(), request.CommunityID)
+	if err != nil {
+		return result, err
+	}
+
+	for _, groupID := range request.GroupIDs {
+		groupJID, err := utils.ValidateJidWithLogin(whatsapp.GetClient(), groupID)
+		if err == nil {
+			err = change(communityJID, groupJID)
+		}
+
+		if err != nil {
+			result = append(result, domainGroup.CommunityGroupStatus{
+				GroupID: groupID,
+				Status:  "error",
+				Message: fmt.Sprintf("Failed to %s group: %v", action, err),
+			})
+			continue
+		}
+
+		result = append(result, domainGroup.CommunityGroupStatus{
+			GroupID: groupJID.String(),
+			Status:  "success",
+			Message: fmt.Sprintf("Action %s success", action),
+		})
+	}
+
+	return result, nil
+}
+
+func (service serviceGroup) ListCommunityGroups(ctx context.Context, request domainGroup.ListCommunityGroupsRequest) (response domainGroup.ListCommunityGroupsResponse, err error) {
+	if err = validations.ValidateListCommunityGroups(ctx, request); err != nil {
+		return response, err
+	}
+
+	communityJID, err := utils.ValidateJidWithLogin(whatsapp.GetClient(), request.CommunityID)
+	if err != nil {
+		return response, err
+	}
+
+	subGroups, err := whatsapp.GetClient().GetSubGroups(ctx, communityJID)
+	if err != nil {
+		return response, err
+	}
+
+	response.CommunityID = communityJID.String()
+	response.Groups = make([]domainGroup.CommunityGroup, 0, len(subGroups))
+	for _, subGroup := range subGroups {
+		response.Groups = append(response.Groups, domainGroup.CommunityGroup{
+			GroupID:        subGroup.JID.String(),
+			Name:           subGroup.Name,
+			IsAnnouncement: subGroup.IsDefaultSubGroup,
+		})
+	}
+
+	return response, nil
+}
+
+func (service serviceGroup) GetAnnouncementGroup(ctx context.Context, request domainGroup.GetAnnouncementGroupRequest) (response domainGroup.CommunityGroup, err error) {
+	if err = validations.ValidateGetAnnouncementGroup(ctx, request); err != nil {
+		return response, err
+	}
+
+	groups, err := service.ListCommunityGroups(ctx, domainGroup.ListCommunityGroupsRequest{CommunityID: request.CommunityID})
+	if err != nil {
+		return response, err
+	}
+
+	for _, group := range groups.Groups {
+		if group.IsAnnouncement {
+			return group, nil
+		}
+	}
+
+	return response, pkgError.ValidationError(fmt.Sprintf("community %s has no announcement group", request.CommunityID))
+}
@@ -0,0 +1,102 @@
+package usecase
+
+import (
+	"context"
+	"time"
+
+	domainChatStorage "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/chatstorage"
+	domainGroup "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/group"
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/validations"
+	"github.com/sirupsen/logrus"
+)
+
+func (service serviceGroup) GetGroupHistory(ctx context.Context, request domainGroup.GroupHistoryRequest) (response domainGroup.GroupHistoryResponse, err error) {
+	if err = validations.ValidateGroupHistory(ctx, &request); err != nil {
+		return response, err
+	}
+
+	filter := &domainChatStorage.GroupMembershipFilter{
+		GroupJID:       request.GroupID,
+		ParticipantJID: request.Participant,
+		Action:         request.Action,
+		Limit:          request.Limit,
+		Offset:         request.Offset,
+	}
+	// The formats were checked by the validation above
+	if request.Since != "" {
+		since, _ := time.Parse(time.RFC3339, request.Since)
+		filter.Since = &since
+	}
+	if request.Until != "" {
+		until, _ := time.Parse(time.RFC3339, request.Until)
+		filter.Until = &until
+	}
+
+	events, err := service.chatStorageRepo.GetGroupMembershipEvents(filter)
+	if err != nil {
+		return response, err
+	}
+
+	totalCount, err := service.chatStorageRepo.GetGroupMembershipEventCount(filter)
+	if err != nil {
+		logrus.WithError(err).Error("Failed to get group membership event count")
+		// Continue with partial data
+		totalCount = 0
+	}
+
+	response.Data = make([]domainGroup.GroupMembershipEvent, 0, len(events))
+	for _, event := range events {
+		response.Data = append(response.Data, domainGroup.GroupMembershipEvent{
+			ID:             event.ID,
+			GroupID:        event.GroupJID,
+			ParticipantJID: event.ParticipantJID,
+			Action:         event.Action,
+			Role:           event.Role,
+			ActorJID:       event.ActorJID,
+			Reason:         event.Reason,
+			Source:         event.Source,
+			Timestamp:      event.Timestamp.Format(time.RFC3339),
+		})
+	}
+	response.Pagination = domainGroup.PaginationResponse{
+		Limit:  request.Limit,
+		Offset: request.Offset,
+		Total:  int(totalCount),
+	}
+
+	return response, nil
+}
+
+func (service serviceGroup) GetGroupMembers(ctx context.Context, request domainGroup.GroupMembersRequest) (response domainGroup.GroupMembersResponse, err error) {
+	if err = validations.ValidateGroupMembers(ctx, request); err != nil {
+		return response, err
+	}
+
+	var participants []*domainChatStorage.GroupParticipant
+	if request.AsOf != "" {
+		asOf, _ := time.Parse(time.RFC3339, request.AsOf)
+		participants, err = service.chatStorageRepo.GetGroupParticipantsAt(request.GroupID, asOf)
+		response.AsOf = asOf.Format(time.RFC3339)
+	} else {
+		participants, err = service.chatStorageRepo.GetGroupParticipants(request.GroupID)
+	}
+	if err != nil {
+		return response, err
+	}
+
+	response.GroupID = request.GroupID
+	if group, err := service.chatStorageRepo.GetGroup(request.GroupID); err == nil && group != nil {
+		response.Name = group.Name
+	}
+
+	response.Members = make([]domainGroup.GroupMember, 0, len(participants))
+	for _, participant := range participants {
+		response.Members = append(response.Members, domainGroup.GroupMember{
+			JID:      participant.ParticipantJID,
+			Role:     participant.Role,
+			JoinedAt: participant.JoinedAt.Format(time.RFC3339),
+		})
+	}
+
+	return response, nil
+}
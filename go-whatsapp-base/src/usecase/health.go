@@ -0,0 +1,188 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/config"
+	domainChatStorage "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/chatstorage"
+	domainHealth "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/health"
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/infrastructure/whatsapp"
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/utils"
+)
+
+// healthCheckTimeout bounds a single readiness check, so a stuck database
+// fails the probe instead of hanging it
+const healthCheckTimeout = 5 * time.Second
+
+// healthCheck returns its details and an error when the dependency is not usable
+type healthCheck struct {
+	name string
+	run  func(ctx context.Context) (map[string]any, error)
+}
+
+type serviceHealth struct {
+	chatStorageRepo domainChatStorage.IChatStorageRepository
+	startedAt       time.Time
+}
+
+func NewHealthService(chatStorageRepo domainChatStorage.IChatStorageRepository) domainHealth.IHealthUsecase {
+	return &serviceHealth{
+		chatStorageRepo: chatStorageRepo,
+		startedAt:       time.Now(),
+	}
+}
+
+func (service serviceHealth) Liveness(_ context.Context) domainHealth.LivenessResponse {
+	return domainHealth.LivenessResponse{
+		Status:        domainHealth.StatusOK,
+		Version:       config.AppVersion,
+		UptimeSeconds: int64(time.Since(service.startedAt).Seconds()),
+	}
+}
+
+func (service serviceHealth) Readiness(ctx context.Context) domainHealth.ReadinessResponse {
+	return runHealthChecks(ctx, []healthCheck{
+		{name: "whatsapp", run: checkWhatsApp},
+		{name: "chatstorage", run: service.checkChatStorage},
+		{name: "keys_db", run: checkKeysDB},
+		{name: "webhook_backlog", run: checkWebhookBacklog},
+		{name: "disk", run: checkDiskSpace},
+	})
+}
+
+// runHealthChecks runs the checks concurrently and reports them in the given order
+func runHealthChecks(ctx context.Context, checks []healthCheck) domainHealth.ReadinessResponse {
+	response := domainHealth.ReadinessResponse{
+		Status: domainHealth.StatusOK,
+		Checks: make([]domainHealth.CheckResult, len(checks)),
+	}
+
+	var wg sync.WaitGroup
+	for i, check := range checks {
+		wg.Add(1)
+		go func(i int, check healthCheck) {
+			defer wg.Done()
+			checkCtx, cancel := context.WithTimeout(ctx, healthCheckTimeout)
+			defer cancel()
+
+			start := time.Now()
+			details, err := check.run(checkCtx)
+			result := domainHealth.CheckResult{
+				Name:       check.name,
+				Status:     domainHealth.StatusOK,
+				Details:    details,
+				DurationMs: time.Since(start).Milliseconds(),
+			}
+			if err != nil {
+				result.Status = domainHealth.StatusFail
+				result.Message = err.Error()
+			}
+			response.Checks[i] = result
+		}(i, check)
+	}
+	wg.Wait()
+
+	for _, result := range response.Checks {
+		if result.Status != domainHealth.StatusOK {
+			response.Status = domainHealth.StatusFail
+			break
+		}
+	}
+	return response
+}
+
+func checkWhatsApp(_ context.Context) (map[string]any, error) {
+	isConnected, isLoggedIn, deviceID := whatsapp.GetConnectionStatus()
+	details := map[string]any{
+		"is_connected": isConnected,
+		"is_logged_in": isLoggedIn,
+		"device_id":    deviceID,
+	}
+	if attempt := whatsapp.LastReconnectAttempt(); !attempt.At.IsZero() {
+		details["last_reconnect_at"] = attempt.At.Format(time.RFC3339)
+		if attempt.Err != nil {
+			details["last_reconnect_error"] = attempt.Err.Error()
+		}
+	}
+
+	if !isLoggedIn {
+		return details, fmt.Errorf("whatsapp is not logged in")
+	}
+	if !isConnected {
+		return details, fmt.Errorf("whatsapp is not connected")
+	}
+	return details, nil
+}
+
+func (service serviceHealth) checkChatStorage(ctx context.Context) (map[string]any, error) {
+	return nil, service.chatStorageRepo.CheckWritable(ctx)
+}
+
+// checkKeysDB checks the device store, and the separate keys store when one
+// is configured. URIs are left out of the details as they may hold credentials.
+func checkKeysDB(ctx context.Context) (map[string]any, error) {
+	stores := map[string]string{"device_store": config.DBURI}
+	if config.DBKeysURI != "" {
+		stores["keys_store"] = config.DBKeysURI
+	}
+
+	details := map[string]any{}
+	var failed error
+	for _, name := range []string{"device_store", "keys_store"} {
+		uri, ok := stores[name]
+		if !ok {
+			continue
+		}
+		if err := whatsapp.CheckStoreWritable(ctx, uri); err != nil {
+			details[name] = err.Error()
+			if failed == nil {
+				failed = fmt.Errorf("%s is not writable", name)
+			}
+			continue
+		}
+		details[name] = domainHealth.StatusOK
+	}
+	return details, failed
+}
+
+func checkWebhookBacklog(_ context.Context) (map[string]any, error) {
+	backlog := whatsapp.WebhookBacklog()
+	details := map[string]any{
+		"in_flight": backlog,
+		"max":       config.AppReadyMaxWebhookBacklog,
+	}
+	if config.AppReadyMaxWebhookBacklog > 0 && backlog > int64(config.AppReadyMaxWebhookBacklog) {
+		return details, fmt.Errorf("%d webhook deliveries in flight, above the limit of %d", backlog, config.AppReadyMaxWebhookBacklog)
+	}
+	return details, nil
+}
+
+func checkDiskSpace(_ context.Context) (map[string]any, error) {
+	details := map[string]any{
+		"path":           config.PathMedia,
+		"min_free_bytes": config.AppReadyMinFreeDisk,
+	}
+	// Media folders are created lazily, measure the nearest existing parent
+	path := config.PathMedia
+	for {
+		if _, err := os.Stat(path); err == nil || filepath.Dir(path) == path {
+			break
+		}
+		path = filepath.Dir(path)
+	}
+
+	free, err := utils.DiskFreeBytes(path)
+	if err != nil {
+		return details, fmt.Errorf("failed to read free space: %w", err)
+	}
+	details["free_bytes"] = free
+	if config.AppReadyMinFreeDisk > 0 && free < uint64(config.AppReadyMinFreeDisk) {
+		return details, fmt.Errorf("%d bytes free under %s, below the minimum of %d", free, config.PathMedia, config.AppReadyMinFreeDisk)
+	}
+	return details, nil
+}
@@ -0,0 +1,118 @@
+package usecase
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"testing"
+
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/config"
+	domainHealth "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/health"
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/infrastructure/chatstorage"
+	_ "github.com/mattn/go-sqlite3"
+)
+
+func TestRunHealthChecksReportsEveryCheckInOrder(t *testing.T) {
+	response := runHealthChecks(context.Background(), []healthCheck{
+		{name: "first", run: func(context.Context) (map[string]any, error) {
+			return map[string]any{"value": 1}, nil
+		}},
+		{name: "second", run: func(context.Context) (map[string]any, error) {
+			return nil, fmt.Errorf("unreachable")
+		}},
+		{name: "third", run: func(context.Context) (map[string]any, error) {
+			return nil, nil
+		}},
+	})
+
+	if response.Status != domainHealth.StatusFail {
+		t.Fatalf("status = %q, want fail when a check fails", response.Status)
+	}
+	if len(response.Checks) != 3 {
+		t.Fatalf("got %d checks, want 3", len(response.Checks))
+	}
+	for i, name := range []string{"first", "second", "third"} {
+		if response.Checks[i].Name != name {
+			t.Fatalf("check %d = %q, want %q", i, response.Checks[i].Name, name)
+		}
+	}
+	if response.Checks[0].Status != domainHealth.StatusOK || response.Checks[0].Details["value"] != 1 {
+		t.Fatalf("unexpected first check: %+v", response.Checks[0])
+	}
+	if response.Checks[1].Status != domainHealth.StatusFail || response.Checks[1].Message != "unreachable" {
+		t.Fatalf("unexpected second check: %+v", response.Checks[1])
+	}
+}
+
+func TestRunHealthChecksAllPassing(t *testing.T) {
+	response := runHealthChecks(context.Background(), []healthCheck{
+		{name: "only", run: func(context.Context) (map[string]any, error) { return nil, nil }},
+	})
+	if response.Status != domainHealth.StatusOK {
+		t.Fatalf("status = %q, want ok", response.Status)
+	}
+}
+
+func TestCheckWebhookBacklogLimit(t *testing.T) {
+	original := config.AppReadyMaxWebhookBacklog
+	t.Cleanup(func() { config.AppReadyMaxWebhookBacklog = original })
+
+	config.AppReadyMaxWebhookBacklog = 10
+	details, err := checkWebhookBacklog(context.Background())
+	if err != nil {
+		t.Fatalf("idle backlog should pass: %v", err)
+	}
+	if details["in_flight"] != int64(0) || details["max"] != 10 {
+		t.Fatalf("unexpected details: %v", details)
+	}
+
+	config.AppReadyMaxWebhookBacklog = -1
+	if _, err := checkWebhookBacklog(context.Background()); err != nil {
+		t.Fatalf("a non positive limit disables the check: %v", err)
+	}
+}
+
+func TestCheckDiskSpace(t *testing.T) {
+	originalPath, originalMin := config.PathMedia, config.AppReadyMinFreeDisk
+	t.Cleanup(func() { config.PathMedia, config.AppReadyMinFreeDisk = originalPath, originalMin })
+
+	// The media folder does not exist yet, its parent is measured instead
+	config.PathMedia = t.TempDir() + "/media"
+	config.AppReadyMinFreeDisk = 1
+	details, err := checkDiskSpace(context.Background())
+	if err != nil {
+		t.Fatalf("checkDiskSpace returned error: %v", err)
+	}
+	if free, ok := details["free_bytes"].(uint64); !ok || free == 0 {
+		t.Fatalf("unexpected free bytes: %v", details["free_bytes"])
+	}
+
+	config.AppReadyMinFreeDisk = 1 << 62
+	if _, err := checkDiskSpace(context.Background()); err == nil {
+		t.Fatal("expected failure below the minimum free space")
+	}
+}
+
+func TestCheckChatStorageWritable(t *testing.T) {
+	db, err := sql.Open("sqlite3", ":memory:")
+	if err != nil {
+		t.Fatal(err)
+	}
+	db.SetMaxOpenConns(1)
+	t.Cleanup(func() { db.Close() })
+
+	repo := chatstorage.NewStorageRepository(db)
+	if err := repo.InitializeSchema(); err != nil {
+		t.Fatal(err)
+	}
+	service := serviceHealth{chatStorageRepo: repo}
+
+	if _, err := service.checkChatStorage(context.Background()); err != nil {
+		t.Fatalf("fresh storage should be writable: %v", err)
+	}
+
+	db.Close()
+	if _, err := service.checkChatStorage(context.Background()); err == nil {
+		t.Fatal("expected failure on a closed database")
+	}
+}
@@ -0,0 +1,289 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	domainChatStorage "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/chatstorage"
+	domainLabel "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/label"
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/infrastructure/whatsapp"
+	pkgError "github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/error"
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/utils"
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/validations"
+	"github.com/sirupsen/logrus"
+	"go.mau.fi/whatsmeow/appstate"
+)
+
+type serviceLabel struct {
+	chatStorageRepo domainChatStorage.IChatStorageRepository
+}
+
+func NewLabelService(chatStorageRepo domainChatStorage.IChatStorageRepository) domainLabel.ILabelUsecase {
+	return &serviceLabel{
+		chatStorageRepo: chatStorageRepo,
+	}
+}
+
+func (service serviceLabel) ListLabels(_ context.Context) (response domainLabel.ListLabelsResponse, err error) {
+	labels, err := service.chatStorageRepo.GetLabels(false)
+	if err != nil {
+		logrus.WithError(err).Error("Failed to get labels from storage")
+		return response, err
+	}
+
+	response.Data = make([]domainLabel.LabelInfo, 0, len(labels))
+	for _, label := range labels {
+		response.Data = append(response.Data, toLabelInfo(label))
+	}
+
+	return response, nil
+}
+
+func (service serviceLabel) CreateLabel(ctx context.Context, request domainLabel.LabelRequest) (response domainLabel.LabelInfo, err error) {
+	if err = validations.ValidateCreateLabel(ctx, &request); err != nil {
+		return response, err
+	}
+
+	labelID, err := service.nextLabelID(ctx)
+	if err != nil {
+		return response, err
+	}
+
+	label := &domainChatStorage.Label{
+		ID:    labelID,
+		Name:  request.Name,
+		Color: *request.Color,
+	}
+	if err = service.sendLabelEdit(ctx, label); err != nil {
+		return response, err
+	}
+
+	// Answer with the stored label, which carries its timestamps
+	stored, err := getLabel(service.chatStorageRepo, labelID)
+	if err != nil {
+		return response, err
+	}
+
+	return toLabelInfo(stored), nil
+}
+
+func (service serviceLabel) UpdateLabel(ctx context.Context, request domainLabel.LabelRequest) (response domainLabel.LabelInfo, err error) {
+	if err = validations.ValidateUpdateLabel(ctx, &request); err != nil {
+		return response, err
+	}
+
+	label, err := getLabel(service.chatStorageRepo, request.LabelID)
+	if err != nil {
+		return response, err
+	}
+
+	if request.Name != "" {
+		label.Name = request.Name
+	}
+	if request.Color != nil {
+		label.Color = *request.Color
+	}
+	if err = service.sendLabelEdit(ctx, label); err != nil {
+		return response, err
+	}
+
+	return toLabelInfo(label), nil
+}
+
+func (service serviceLabel) DeleteLabel(ctx context.Context, request domainLabel.DeleteLabelRequest) (err error) {
+	if err = validations.ValidateDeleteLabel(ctx, &request); err != nil {
+		return err
+	}
+
+	label, err := getLabel(service.chatStorageRepo, request.LabelID)
+	if err != nil {
+		return err
+	}
+
+	label.Deleted = true
+	return service.sendLabelEdit(ctx, label)
+}
+
+func (service serviceLabel) LabelChat(ctx context.Context, request domainLabel.ChatLabelRequest) (response domainLabel.ChatLabelResponse, err error) {
+	if err = validations.ValidateLabelChat(ctx, &request); err != nil {
+		return response, err
+	}
+
+	chatJID, err := utils.ValidateJidWithLogin(whatsapp.GetClient(), request.ChatJID)
+	if err != nil {
+		return response, err
+	}
+
+	// A label_name creates the label, or renames it, before labeling the chat
+	label, err := service.chatStorageRepo.GetLabel(request.LabelID)
+	if err != nil {
+		return response, err
+	}
+	switch {
+	case request.LabelName != "" && (label == nil || label.Deleted || label.Name != request.LabelName):
+		if label == nil || label.Deleted {
+			label = &domainChatStorage.Label{ID: request.LabelID}
+		}
+		label.Name = request.LabelName
+		if err = service.sendLabelEdit(ctx, label); err != nil {
+			return response, err
+		}
+	case label == nil || label.Deleted:
+		return response, pkgError.ValidationError(fmt.Sprintf("label %s not found", request.LabelID))
+	}
+
+	if err = whatsapp.GetClient().SendAppState(ctx, appstate.BuildLabelChat(chatJID, request.LabelID, request.Labeled)); err != nil {
+		logrus.WithError(err).WithFields(logrus.Fields{
+			"chat_jid": chatJID.String(),
+			"label_id": request.LabelID,
+			"labeled":  request.Labeled,
+		}).Error("Failed to send chat label app state")
+		return response, err
+	}
+
+	// Our own app state patches don't come back as events
+	if err = service.chatStorageRepo.SetChatLabel(request.LabelID, chatJID.String(), request.Labeled); err != nil {
+		return response, err
+	}
+
+	labels, err := service.chatStorageRepo.GetChatLabels([]string{chatJID.String()})
+	if err != nil {
+		return response, err
+	}
+
+	response.Status = "success"
+	response.ChatJID = chatJID.String()
+	response.LabelID = request.LabelID
+	response.Labeled = request.Labeled
+	response.Labels = labelIDs(labels[chatJID.String()])
+	if request.Labeled {
+		response.Message = fmt.Sprintf("Chat labeled successfully with label '%s'", label.Name)
+	} else {
+		response.Message = fmt.Sprintf("Label '%s' removed from chat successfully", label.Name)
+	}
+	return response, nil
+}
+
+func (service serviceLabel) LabelMessage(ctx context.Context, request domainLabel.MessageLabelRequest) (response domainLabel.MessageLabelResponse, err error) {
+	if err = validations.ValidateLabelMessage(ctx, &request); err != nil {
+		return response, err
+	}
+
+	chatJID, err := utils.ValidateJidWithLogin(whatsapp.GetClient(), request.Phone)
+	if err != nil {
+		return response, err
+	}
+	if _, err = getLabel(service.chatStorageRepo, request.LabelID); err != nil {
+		return response, err
+	}
+
+	patch := appstate.BuildLabelMessage(chatJID, request.LabelID, request.MessageID, request.Labeled)
+	if err = whatsapp.GetClient().SendAppState(ctx, patch); err != nil {
+		logrus.WithError(err).WithFields(logrus.Fields{
+			"chat_jid":   chatJID.String(),
+			"message_id": request.MessageID,
+			"label_id":   request.LabelID,
+			"labeled":    request.Labeled,
+		}).Error("Failed to send message label app state")
+		return response, err
+	}
+
+	if err = service.chatStorageRepo.SetMessageLabel(request.LabelID, chatJID.String(), request.MessageID, request.Labeled); err != nil {
+		return response, err
+	}
+
+	labels, err := service.chatStorageRepo.GetMessageLabels(chatJID.String(), request.MessageID)
+	if err != nil {
+		return response, err
+	}
+
+	response.ChatJID = chatJID.String()
+	response.MessageID = request.MessageID
+	response.Labels = labelIDs(labels)
+	return response, nil
+}
+
+// sendLabelEdit pushes a label to the other devices and stores it
+func (service serviceLabel) sendLabelEdit(ctx context.Context, label *domainChatStorage.Label) error {
+	client := whatsapp.GetClient()
+	if client == nil {
+		return pkgError.ErrWaCLI
+	}
+	if !client.IsLoggedIn() {
+		return pkgError.ErrNotLoggedIn
+	}
+
+	if err := client.SendAppState(ctx, appstate.BuildLabelEdit(label.ID, label.Name, label.Color, label.Deleted)); err != nil {
+		logrus.WithError(err).WithField("label_id", label.ID).Error("Failed to send label edit app state")
+		return err
+	}
+
+	return service.chatStorageRepo.StoreLabel(label)
+}
+
+// nextLabelID picks the ID for a new label. WhatsApp label IDs are sequential
+// numbers shared by all devices, and deleted IDs are not reused, so labels
+// made on the other devices are fetched first.
+func (service serviceLabel) nextLabelID(ctx context.Context) (string, error) {
+	client := whatsapp.GetClient()
+	if client == nil {
+		return "", pkgError.ErrWaCLI
+	}
+	if !client.IsLoggedIn() {
+		return "", pkgError.ErrNotLoggedIn
+	}
+
+	// The label edits this fetches are stored by the event handler before it returns
+	if err := client.FetchAppState(ctx, appstate.WAPatchRegular, false, false); err != nil {
+		logrus.WithError(err).Error("Failed to sync label app state")
+		return "", err
+	}
+
+	labels, err := service.chatStorageRepo.GetLabels(true)
+	if err != nil {
+		return "", err
+	}
+
+	highest := 0
+	for _, label := range labels {
+		if id, err := strconv.Atoi(label.ID); err == nil && id > highest {
+			highest = id
+		}
+	}
+
+	return strconv.Itoa(highest + 1), nil
+}
+
+// getLabel returns a stored label that is not deleted, or a validation error
+func getLabel(chatStorageRepo domainChatStorage.IChatStorageRepository, labelID string) (*domainChatStorage.Label, error) {
+	label, err := chatStorageRepo.GetLabel(labelID)
+	if err != nil {
+		return nil, err
+	}
+	if label == nil || label.Deleted {
+		return nil, pkgError.ValidationError(fmt.Sprintf("label %s not found", labelID))
+	}
+
+	return label, nil
+}
+
+func toLabelInfo(label *domainChatStorage.Label) domainLabel.LabelInfo {
+	return domainLabel.LabelInfo{
+		ID:        label.ID,
+		Name:      label.Name,
+		Color:     label.Color,
+		ChatCount: label.ChatCount,
+		CreatedAt: label.CreatedAt.Format(time.RFC3339),
+		UpdatedAt: label.UpdatedAt.Format(time.RFC3339),
+	}
+}
+
+// labelIDs returns an empty list instead of nil for consistent JSON
+func labelIDs(ids []string) []string {
+	if ids == nil {
+		return []string{}
+	}
+	return ids
+}
@@ -3,14 +3,17 @@ package usecase
 import (
 	"context"
 	"fmt"
+	"net/url"
 	"os"
 	"path/filepath"
+	"strings"
 	"time"
 
 	"github.com/aldinokemal/go-whatsapp-web-multidevice/config"
 	domainChatStorage "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/chatstorage"
 	domainMessage "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/message"
 	"github.com/aldinokemal/go-whatsapp-web-multidevice/infrastructure/whatsapp"
+	pkgError "github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/error"
 	"github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/utils"
 	"github.com/aldinokemal/go-whatsapp-web-multidevice/validations"
 	"github.com/sirupsen/logrus"
@@ -218,6 +221,11 @@ func (service serviceMessage) DownloadMedia(ctx context.Context, request domainM
 		return response, fmt.Errorf("message %s does not belong to chat %s", request.MessageID, dataWaRecipient.String())
 	}
 
+	// View-once media is not kept, as the sender intended
+	if message.ViewOnce {
+		return response, pkgError.ValidationError(fmt.Sprintf("message %s is view-once media and cannot be downloaded", request.MessageID))
+	}
+
 	// Create directory structure for organized storage
 	chatDir := filepath.Join(config.PathMedia, utils.ExtractPhoneNumber(message.ChatJID))
 	dateDir := filepath.Join(chatDir, message.Timestamp.Format("2006-01-02"))
@@ -227,85 +235,366 @@ func (service serviceMessage) DownloadMedia(ctx context.Context, request domainM
 		return response, fmt.Errorf("failed to create directory: %v", err)
 	}
 
-	// Create a downloadable message interface based on media type
-	var downloadableMsg interface{}
+	downloadableMsg, err := buildStoredMediaMessage(message)
+	if err != nil {
+		return response, err
+	}
+
+	// Download the media using existing utils.ExtractMedia function
+	extractedMedia, err := utils.ExtractMedia(ctx, whatsapp.GetClient(), dateDir, downloadableMsg)
+	if err != nil {
+		return response, fmt.Errorf("failed to download media: %v", err)
+	}
+
+	// Get file size
+	fileInfo, err := os.Stat(extractedMedia.MediaPath)
+	if err != nil {
+		logrus.Warnf("Could not get file size for %s: %v", extractedMedia.MediaPath, err)
+	}
+
+	// Build response
+	response.MessageID = request.MessageID
+	response.Status = fmt.Sprintf("Media downloaded successfully to %s", extractedMedia.MediaPath)
+	response.MediaType = message.MediaType
+	response.Filename = filepath.Base(extractedMedia.MediaPath)
+	response.FilePath = extractedMedia.MediaPath
+	if fileInfo != nil {
+		response.FileSize = fileInfo.Size()
+	}
+
+	logrus.Info(map[string]any{
+		"message_id": request.MessageID,
+		"phone":      request.Phone,
+		"chat":       dataWaRecipient.String(),
+		"media_type": response.MediaType,
+		"file_path":  response.FilePath,
+		"file_size":  response.FileSize,
+	})
+
+	return response, nil
+}
+
+// ForwardMessage implements message.IMessageService.
+func (service serviceMessage) ForwardMessage(ctx context.Context, request domainMessage.ForwardMessageRequest) (response domainMessage.ForwardMessageResponse, err error) {
+	if err = validations.ValidateForwardMessage(ctx, request); err != nil {
+		return response, err
+	}
+
+	dataWaRecipient, err := utils.ValidateJidWithLogin(whatsapp.GetClient(), request.Phone)
+	if err != nil {
+		return response, err
+	}
+
+	message, err := service.chatStorageRepo.GetMessageByID(request.MessageID)
+	if err != nil {
+		return response, fmt.Errorf("message not found: %v", err)
+	}
+
+	if message == nil {
+		return response, fmt.Errorf("message with ID %s not found", request.MessageID)
+	}
+
+	if message.ChatJID != dataWaRecipient.String() {
+		return response, fmt.Errorf("message %s does not belong to chat %s", request.MessageID, dataWaRecipient.String())
+	}
+
+	if message.MediaType == "" && message.Content == "" {
+		return response, fmt.Errorf("message %s has no forwardable content", request.MessageID)
+	}
+
+	forwarded := 0
+	for _, target := range request.Targets {
+		result := domainMessage.ForwardMessageResult{Target: target}
+
+		ts, errForward := service.forwardStoredMessage(ctx, message, target)
+		if errForward != nil {
+			logrus.Warnf("Failed to forward message %s to %s: %v", request.MessageID, target, errForward)
+			result.Status = "failed"
+			result.Message = errForward.Error()
+		} else {
+			forwarded++
+			result.MessageID = ts.ID
+			result.Status = "success"
+		}
+
+		response.Results = append(response.Results, result)
+	}
+
+	if forwarded == 0 {
+		return response, pkgError.InternalServerError(fmt.Sprintf("failed to forward message %s to any target", request.MessageID))
+	}
+
+	response.MessageID = request.MessageID
+	response.Status = fmt.Sprintf("Message forwarded to %d of %d targets", forwarded, len(request.Targets))
+	return response, nil
+}
+
+// forwardStoredMessage sends a stored message to a single target. Media keeps
+// referencing the original upload; newsletters need their own unencrypted copy,
+// so the media is downloaded and uploaded again for them.
+func (service serviceMessage) forwardStoredMessage(ctx context.Context, message *domainChatStorage.Message, target string) (whatsmeow.SendResponse, error) {
+	recipient, err := utils.ValidateJidWithLogin(whatsapp.GetClient(), target)
+	if err != nil {
+		return whatsmeow.SendResponse{}, err
+	}
+
+	media := storedMediaReference(message)
+	if message.MediaType != "" && recipient.Server == types.NewsletterServer {
+		if media, err = service.reuploadStoredMedia(ctx, message); err != nil {
+			return whatsmeow.SendResponse{}, err
+		}
+	}
+
+	ctxInfo := forwardContextInfo(message)
+	if expiration := getChatEphemeralExpiration(service.chatStorageRepo, recipient.String()); expiration > 0 {
+		ctxInfo.Expiration = proto.Uint32(expiration)
+	}
 
+	msg, err := buildForwardMessage(message, media, ctxInfo)
+	if err != nil {
+		return whatsmeow.SendResponse{}, err
+	}
+
+	ts, err := whatsapp.GetClient().SendMessage(ctx, recipient, msg)
+	if err != nil {
+		return whatsmeow.SendResponse{}, err
+	}
+
+	storeSentMessage(service.chatStorageRepo, ts, recipient, message.Content)
+	return ts, nil
+}
+
+// forwardContextInfo marks a forward, counting it on top of the times the
+// stored message had already been forwarded so WhatsApp shows "Forwarded many
+// times" once the score gets high enough.
+func forwardContextInfo(message *domainChatStorage.Message) *waE2E.ContextInfo {
+	return &waE2E.ContextInfo{
+		IsForwarded:     proto.Bool(true),
+		ForwardingScore: proto.Uint32(message.ForwardingScore + 1),
+	}
+}
+
+// reuploadStoredMedia downloads the media of a stored message and uploads it
+// again as newsletter media.
+func (service serviceMessage) reuploadStoredMedia(ctx context.Context, message *domainChatStorage.Message) (mediaReference, error) {
+	downloadable, err := buildStoredMediaMessage(message)
+	if err != nil {
+		return mediaReference{}, err
+	}
+
+	data, err := whatsapp.GetClient().Download(ctx, downloadable)
+	if err != nil {
+		return mediaReference{}, fmt.Errorf("failed to download media: %v", err)
+	}
+
+	uploaded, err := whatsapp.GetClient().UploadNewsletter(ctx, data, forwardMediaTypes[message.MediaType])
+	if err != nil {
+		return mediaReference{}, pkgError.WaUploadMediaError(fmt.Sprintf("failed to upload media: %v", err))
+	}
+
+	return mediaReference{
+		URL:           uploaded.URL,
+		DirectPath:    uploaded.DirectPath,
+		MediaKey:      uploaded.MediaKey,
+		FileSHA256:    uploaded.FileSHA256,
+		FileEncSHA256: uploaded.FileEncSHA256,
+		FileLength:    uploaded.FileLength,
+	}, nil
+}
+
+// mediaReference points at an already uploaded media blob.
+type mediaReference struct {
+	URL           string
+	DirectPath    string
+	MediaKey      []byte
+	FileSHA256    []byte
+	FileEncSHA256 []byte
+	FileLength    uint64
+}
+
+var forwardMediaTypes = map[string]whatsmeow.MediaType{
+	"image":    whatsmeow.MediaImage,
+	"video":    whatsmeow.MediaVideo,
+	"audio":    whatsmeow.MediaAudio,
+	"document": whatsmeow.MediaDocument,
+	"sticker":  whatsmeow.MediaImage,
+}
+
+func storedMediaReference(message *domainChatStorage.Message) mediaReference {
+	return mediaReference{
+		URL:           message.URL,
+		DirectPath:    directPathFromURL(message.URL),
+		MediaKey:      message.MediaKey,
+		FileSHA256:    message.FileSHA256,
+		FileEncSHA256: message.FileEncSHA256,
+		FileLength:    message.FileLength,
+	}
+}
+
+// directPathFromURL derives the media direct path from a stored CDN URL, which
+// is the URL path and query without the mms3 flag added by the CDN.
+func directPathFromURL(rawURL string) string {
+	parsed, err := url.Parse(rawURL)
+	if err != nil || parsed.Path == "" {
+		return ""
+	}
+
+	var params []string
+	for _, param := range strings.Split(parsed.RawQuery, "&") {
+		if param != "" && !strings.HasPrefix(param, "mms3=") {
+			params = append(params, param)
+		}
+	}
+
+	if len(params) == 0 {
+		return parsed.EscapedPath()
+	}
+	return parsed.EscapedPath() + "?" + strings.Join(params, "&")
+}
+
+// buildStoredMediaMessage rebuilds a downloadable media message from the media
+// info kept in chat storage.
+func buildStoredMediaMessage(message *domainChatStorage.Message) (whatsmeow.DownloadableMessage, error) {
 	switch message.MediaType {
 	case "image":
-		downloadableMsg = &waE2E.ImageMessage{
+		return &waE2E.ImageMessage{
 			URL:           proto.String(message.URL),
 			MediaKey:      message.MediaKey,
 			FileSHA256:    message.FileSHA256,
 			FileEncSHA256: message.FileEncSHA256,
 			FileLength:    proto.Uint64(message.FileLength),
-		}
+		}, nil
 	case "video":
-		downloadableMsg = &waE2E.VideoMessage{
+		return &waE2E.VideoMessage{
 			URL:           proto.String(message.URL),
 			MediaKey:      message.MediaKey,
 			FileSHA256:    message.FileSHA256,
 			FileEncSHA256: message.FileEncSHA256,
 			FileLength:    proto.Uint64(message.FileLength),
-		}
+		}, nil
 	case "audio":
-		downloadableMsg = &waE2E.AudioMessage{
+		return &waE2E.AudioMessage{
 			URL:           proto.String(message.URL),
 			MediaKey:      message.MediaKey,
 			FileSHA256:    message.FileSHA256,
 			FileEncSHA256: message.FileEncSHA256,
 			FileLength:    proto.Uint64(message.FileLength),
-		}
+		}, nil
 	case "document":
-		downloadableMsg = &waE2E.DocumentMessage{
+		return &waE2E.DocumentMessage{
 			URL:           proto.String(message.URL),
 			MediaKey:      message.MediaKey,
 			FileSHA256:    message.FileSHA256,
 			FileEncSHA256: message.FileEncSHA256,
 			FileLength:    proto.Uint64(message.FileLength),
 			FileName:      proto.String(message.Filename),
-		}
+		}, nil
 	case "sticker":
-		downloadableMsg = &waE2E.StickerMessage{
+		return &waE2E.StickerMessage{
 			URL:           proto.String(message.URL),
 			MediaKey:      message.MediaKey,
 			FileSHA256:    message.FileSHA256,
 			FileEncSHA256: message.FileEncSHA256,
 			FileLength:    proto.Uint64(message.FileLength),
-		}
+		}, nil
 	default:
-		return response, fmt.Errorf("unsupported media type: %s", message.MediaType)
+		return nil, fmt.Errorf("unsupported media type: %s", message.MediaType)
 	}
+}
 
-	// Download the media using existing utils.ExtractMedia function
-	extractedMedia, err := utils.ExtractMedia(ctx, whatsapp.GetClient(), dateDir, downloadableMsg.(whatsmeow.DownloadableMessage))
-	if err != nil {
-		return response, fmt.Errorf("failed to download media: %v", err)
+// storedMimetype returns the mimetype stored with the message, or the given
+// default for messages stored before mimetypes were kept.
+func storedMimetype(message *domainChatStorage.Message, fallback string) string {
+	if message.Mimetype != "" {
+		return message.Mimetype
 	}
+	return fallback
+}
 
-	// Get file size
-	fileInfo, err := os.Stat(extractedMedia.MediaPath)
-	if err != nil {
-		logrus.Warnf("Could not get file size for %s: %v", extractedMedia.MediaPath, err)
+// buildForwardMessage rebuilds the outgoing message for a forward. Text is
+// sent as an extended text message, media reuses the given media reference
+// with the stored content as caption where WhatsApp supports one.
+func buildForwardMessage(message *domainChatStorage.Message, media mediaReference, ctxInfo *waE2E.ContextInfo) (*waE2E.Message, error) {
+	if message.MediaType == "" {
+		return &waE2E.Message{
+			ExtendedTextMessage: &waE2E.ExtendedTextMessage{
+				Text:        proto.String(message.Content),
+				ContextInfo: ctxInfo,
+			},
+		}, nil
 	}
 
-	// Build response
-	response.MessageID = request.MessageID
-	response.Status = fmt.Sprintf("Media downloaded successfully to %s", extractedMedia.MediaPath)
-	response.MediaType = message.MediaType
-	response.Filename = filepath.Base(extractedMedia.MediaPath)
-	response.FilePath = extractedMedia.MediaPath
-	if fileInfo != nil {
-		response.FileSize = fileInfo.Size()
+	if media.URL == "" || len(media.MediaKey) == 0 {
+		return nil, fmt.Errorf("message %s does not contain forwardable media", message.ID)
 	}
 
-	logrus.Info(map[string]any{
-		"message_id": request.MessageID,
-		"phone":      request.Phone,
-		"chat":       dataWaRecipient.String(),
-		"media_type": response.MediaType,
-		"file_path":  response.FilePath,
-		"file_size":  response.FileSize,
-	})
+	var caption *string
+	if message.Content != "" {
+		caption = proto.String(message.Content)
+	}
 
-	return response, nil
+	switch message.MediaType {
+	case "image":
+		return &waE2E.Message{ImageMessage: &waE2E.ImageMessage{
+			URL:           proto.String(media.URL),
+			DirectPath:    proto.String(media.DirectPath),
+			MediaKey:      media.MediaKey,
+			FileSHA256:    media.FileSHA256,
+			FileEncSHA256: media.FileEncSHA256,
+			FileLength:    proto.Uint64(media.FileLength),
+			Mimetype:      proto.String(storedMimetype(message, "image/jpeg")),
+			Caption:       caption,
+			ContextInfo:   ctxInfo,
+		}}, nil
+	case "video":
+		return &waE2E.Message{VideoMessage: &waE2E.VideoMessage{
+			URL:           proto.String(media.URL),
+			DirectPath:    proto.String(media.DirectPath),
+			MediaKey:      media.MediaKey,
+			FileSHA256:    media.FileSHA256,
+			FileEncSHA256: media.FileEncSHA256,
+			FileLength:    proto.Uint64(media.FileLength),
+			Mimetype:      proto.String(storedMimetype(message, "video/mp4")),
+			Caption:       caption,
+			ContextInfo:   ctxInfo,
+		}}, nil
+	case "audio":
+		return &waE2E.Message{AudioMessage: &waE2E.AudioMessage{
+			URL:           proto.String(media.URL),
+			DirectPath:    proto.String(media.DirectPath),
+			MediaKey:      media.MediaKey,
+			FileSHA256:    media.FileSHA256,
+			FileEncSHA256: media.FileEncSHA256,
+			FileLength:    proto.Uint64(media.FileLength),
+			Mimetype:      proto.String(storedMimetype(message, "audio/ogg; codecs=opus")),
+			ContextInfo:   ctxInfo,
+		}}, nil
+	case "document":
+		return &waE2E.Message{DocumentMessage: &waE2E.DocumentMessage{
+			URL:           proto.String(media.URL),
+			DirectPath:    proto.String(media.DirectPath),
+			MediaKey:      media.MediaKey,
+			FileSHA256:    media.FileSHA256,
+			FileEncSHA256: media.FileEncSHA256,
+			FileLength:    proto.Uint64(media.FileLength),
+			Mimetype:      proto.String(storedMimetype(message, resolveDocumentMIME(message.Filename, nil))),
+			FileName:      proto.String(message.Filename),
+			Title:         proto.String(message.Filename),
+			Caption:       caption,
+			ContextInfo:   ctxInfo,
+		}}, nil
+	case "sticker":
+		return &waE2E.Message{StickerMessage: &waE2E.StickerMessage{
+			URL:           proto.String(media.URL),
+			DirectPath:    proto.String(media.DirectPath),
+			MediaKey:      media.MediaKey,
+			FileSHA256:    media.FileSHA256,
+			FileEncSHA256: media.FileEncSHA256,
+			FileLength:    proto.Uint64(media.FileLength),
+			Mimetype:      proto.String(storedMimetype(message, "image/webp")),
+			ContextInfo:   ctxInfo,
+		}}, nil
+	default:
+		return nil, fmt.Errorf("unsupported media type: %s", message.MediaType)
+	}
 }
@@ -0,0 +1,126 @@
+package usecase
+
+import (
+	"testing"
+
+	domainChatStorage "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/chatstorage"
+	"go.mau.fi/whatsmeow/proto/waE2E"
+	"google.golang.org/protobuf/proto"
+)
+
+func TestDirectPathFromURL(t *testing.T) {
+	tests := []struct {
+		name string
+		url  string
+		want string
+	}{
+		{
+			name: "Strips host and mms3 flag",
+			url:  "https://mmg.whatsapp.net/v/t62.7118-24/123_n.enc?ccb=11-4&oh=01_abc&oe=65A1B2C3&_nc_sid=5e03e0&mms3=true",
+			want: "/v/t62.7118-24/123_n.enc?ccb=11-4&oh=01_abc&oe=65A1B2C3&_nc_sid=5e03e0",
+		},
+		{
+			name: "Without query",
+			url:  "https://mmg.whatsapp.net/d/f/abc.enc",
+			want: "/d/f/abc.enc",
+		},
+		{
+			name: "Empty",
+			url:  "",
+			want: "",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := directPathFromURL(tt.url); got != tt.want {
+				t.Fatalf("directPathFromURL() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestBuildForwardMessage(t *testing.T) {
+	ctxInfo := &waE2E.ContextInfo{IsForwarded: proto.Bool(true), ForwardingScore: proto.Uint32(1)}
+
+	t.Run("Text", func(t *testing.T) {
+		msg, err := buildForwardMessage(&domainChatStorage.Message{ID: "A", Content: "hello"}, mediaReference{}, ctxInfo)
+		if err != nil {
+			t.Fatalf("buildForwardMessage() error = %v", err)
+		}
+		if msg.GetExtendedTextMessage().GetText() != "hello" {
+			t.Fatalf("unexpected text %q", msg.GetExtendedTextMessage().GetText())
+		}
+		if !msg.GetExtendedTextMessage().GetContextInfo().GetIsForwarded() {
+			t.Fatal("expected forwarded context info")
+		}
+	})
+
+	t.Run("Image", func(t *testing.T) {
+		stored := &domainChatStorage.Message{
+			ID:        "B",
+			Content:   "caption",
+			MediaType: "image",
+			URL:       "https://mmg.whatsapp.net/v/t62/abc.enc?oh=1&mms3=true",
+			MediaKey:  []byte("key"),
+		}
+		msg, err := buildForwardMessage(stored, storedMediaReference(stored), ctxInfo)
+		if err != nil {
+			t.Fatalf("buildForwardMessage() error = %v", err)
+		}
+		image := msg.GetImageMessage()
+		if image.GetCaption() != "caption" || image.GetDirectPath() != "/v/t62/abc.enc?oh=1" {
+			t.Fatalf("unexpected image message %+v", image)
+		}
+		if image.GetContextInfo().GetForwardingScore() != 1 {
+			t.Fatal("expected forwarding score 1")
+		}
+	})
+
+	t.Run("Stored mimetype", func(t *testing.T) {
+		stored := &domainChatStorage.Message{
+			ID:        "D",
+			MediaType: "video",
+			Mimetype:  "video/quicktime",
+			URL:       "https://mmg.whatsapp.net/v/abc.enc",
+			MediaKey:  []byte("key"),
+		}
+		msg, err := buildForwardMessage(stored, storedMediaReference(stored), ctxInfo)
+		if err != nil {
+			t.Fatalf("buildForwardMessage() error = %v", err)
+		}
+		if got := msg.GetVideoMessage().GetMimetype(); got != "video/quicktime" {
+			t.Fatalf("mimetype = %q, want video/quicktime", got)
+		}
+	})
+
+	t.Run("Media without key", func(t *testing.T) {
+		stored := &domainChatStorage.Message{ID: "C", MediaType: "video", URL: "https://mmg.whatsapp.net/v/abc.enc"}
+		if _, err := buildForwardMessage(stored, storedMediaReference(stored), ctxInfo); err == nil {
+			t.Fatal("expected error for media without key")
+		}
+	})
+}
+
+func TestForwardContextInfo(t *testing.T) {
+	tests := []struct {
+		name  string
+		score uint32
+		want  uint32
+	}{
+		{name: "Original message", score: 0, want: 1},
+		{name: "Already forwarded", score: 4, want: 5},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctxInfo := forwardContextInfo(&domainChatStorage.Message{ForwardingScore: tt.score})
+			if !ctxInfo.GetIsForwarded() {
+				t.Fatal("expected forwarded context info")
+			}
+			if got := ctxInfo.GetForwardingScore(); got != tt.want {
+				t.Fatalf("forwarding score = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
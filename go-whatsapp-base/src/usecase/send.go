@@ -16,6 +16,7 @@ import (
 	"github.com/aldinokemal/go-whatsapp-web-multidevice/config"
 	"github.com/aldinokemal/go-whatsapp-web-multidevice/domains/app"
 	domainChatStorage "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/chatstorage"
+	domainGroup "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/group"
 	domainSend "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/send"
 	"github.com/aldinokemal/go-whatsapp-web-multidevice/infrastructure/whatsapp"
 	pkgError "github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/error"
@@ -34,24 +35,20 @@ import (
 
 type serviceSend struct {
 	appService      app.IAppUsecase
+	groupService    domainGroup.IGroupUsecase
 	chatStorageRepo domainChatStorage.IChatStorageRepository
 }
 
-func NewSendService(appService app.IAppUsecase, chatStorageRepo domainChatStorage.IChatStorageRepository) domainSend.ISendUsecase {
-	return &serviceSend{
+func NewSendService(appService app.IAppUsecase, groupService domainGroup.IGroupUsecase, chatStorageRepo domainChatStorage.IChatStorageRepository) domainSend.ISendUsecase {
+	return newIdempotentSend(&serviceSend{
 		appService:      appService,
+		groupService:    groupService,
 		chatStorageRepo: chatStorageRepo,
-	}
+	}, chatStorageRepo)
 }
 
-// wrapSendMessage wraps the message sending process with message ID saving
-func (service serviceSend) wrapSendMessage(ctx context.Context, recipient types.JID, msg *waE2E.Message, content string) (whatsmeow.SendResponse, error) {
-	ts, err := whatsapp.GetClient().SendMessage(ctx, recipient, msg)
-	if err != nil {
-		return whatsmeow.SendResponse{}, err
-	}
-
-	// Store the sent message using chatstorage
+// storeSentMessage saves a sent message to chat storage without blocking the caller
+func storeSentMessage(chatStorageRepo domainChatStorage.IChatStorageRepository, ts whatsmeow.SendResponse, recipient types.JID, content string) {
 	senderJID := ""
 	if whatsapp.GetClient().Store.ID != nil {
 		senderJID = whatsapp.GetClient().Store.ID.String()
@@ -63,7 +60,7 @@ func (service serviceSend) wrapSendMessage(ctx context.Context, recipient types.
 		storeCtx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
 		defer cancel()
 
-		if err := service.chatStorageRepo.StoreSentMessageWithContext(storeCtx, ts.ID, senderJID, recipient.String(), content, ts.Timestamp); err != nil {
+		if err := chatStorageRepo.StoreSentMessageWithContext(storeCtx, ts.ID, senderJID, recipient.String(), content, ts.Timestamp); err != nil {
 			if errors.Is(err, context.DeadlineExceeded) {
 				logrus.Warn("Timeout storing sent message")
 			} else {
@@ -71,7 +68,21 @@ func (service serviceSend) wrapSendMessage(ctx context.Context, recipient types.
 			}
 		}
 	}()
+}
+
+// wrapSendMessage wraps the message sending process with message ID saving.
+// With the send queue enabled, a send made while disconnected is queued instead.
+func (service serviceSend) wrapSendMessage(ctx context.Context, request domainSend.BaseRequest, recipient types.JID, msg *waE2E.Message, content string) (whatsmeow.SendResponse, error) {
+	if queueingSends() {
+		return service.enqueueMessage(ctx, request, recipient, msg, content)
+	}
+
+	ts, err := whatsapp.GetClient().SendMessage(ctx, recipient, msg)
+	if err != nil {
+		return whatsmeow.SendResponse{}, err
+	}
 
+	storeSentMessage(service.chatStorageRepo, ts, recipient, content)
 	return ts, nil
 }
 
@@ -80,7 +91,24 @@ func (service serviceSend) SendText(ctx context.Context, request domainSend.Mess
 	if err != nil {
 		return response, err
 	}
-	dataWaRecipient, err := utils.ValidateJidWithLogin(whatsapp.GetClient(), request.BaseRequest.Phone)
+
+	// Render the stored template so missing variables are rejected before sending
+	if request.TemplateID != "" {
+		template, err := getMessageTemplate(service.chatStorageRepo, request.TemplateID)
+		if err != nil {
+			return response, err
+		}
+		if request.Message, _, err = renderMessageTemplate(template, request.Language, request.Variables); err != nil {
+			return response, err
+		}
+	}
+
+	dataWaRecipient, err := service.validateRecipient(request.BaseRequest.Phone)
+	if err != nil {
+		return response, err
+	}
+
+	mentions, err := service.resolveMentions(ctx, request.BaseRequest, dataWaRecipient, request.Message)
 	if err != nil {
 		return response, err
 	}
@@ -106,9 +134,8 @@ func (service serviceSend) SendText(ctx context.Context, request domainSend.Mess
 		msg.ExtendedTextMessage.ContextInfo.Expiration = proto.Uint32(service.getDefaultEphemeralExpiration(request.BaseRequest.Phone))
 	}
 
-	parsedMentions := service.getMentionFromText(ctx, request.Message)
-	if len(parsedMentions) > 0 {
-		msg.ExtendedTextMessage.ContextInfo.MentionedJID = parsedMentions
+	if len(mentions) > 0 {
+		msg.ExtendedTextMessage.ContextInfo.MentionedJID = mentions
 	}
 
 	// Reply message
@@ -146,8 +173,8 @@ func (service serviceSend) SendText(ctx context.Context, request domainSend.Mess
 			}
 
 			// Preserve mentions
-			if len(parsedMentions) > 0 {
-				ctxInfo.MentionedJID = parsedMentions
+			if len(mentions) > 0 {
+				ctxInfo.MentionedJID = mentions
 			}
 
 			msg.ExtendedTextMessage = &waE2E.ExtendedTextMessage{
@@ -159,13 +186,13 @@ func (service serviceSend) SendText(ctx context.Context, request domainSend.Mess
 		}
 	}
 
-	ts, err := service.wrapSendMessage(ctx, dataWaRecipient, msg, request.Message)
+	ts, err := service.wrapSendMessage(ctx, request.BaseRequest, dataWaRecipient, msg, request.Message)
 	if err != nil {
 		return response, err
 	}
 
 	response.MessageID = ts.ID
-	response.Status = fmt.Sprintf("Message sent to %s (server timestamp: %s)", request.Phone, ts.Timestamp.String())
+	response.Status = sendStatus(ts, "Message sent to %s (server timestamp: %s)", request.Phone, ts.Timestamp.String())
 	return response, nil
 }
 
@@ -174,7 +201,12 @@ func (service serviceSend) SendImage(ctx context.Context, request domainSend.Ima
 	if err != nil {
 		return response, err
 	}
-	dataWaRecipient, err := utils.ValidateJidWithLogin(whatsapp.GetClient(), request.Phone)
+	dataWaRecipient, err := service.validateRecipient(request.Phone)
+	if err != nil {
+		return response, err
+	}
+
+	mentions, err := service.resolveMentions(ctx, request.BaseRequest, dataWaRecipient, request.Caption)
 	if err != nil {
 		return response, err
 	}
@@ -315,7 +347,8 @@ func (service serviceSend) SendImage(ctx context.Context, request domainSend.Ima
 	if request.Caption != "" {
 		caption = "🖼️ " + request.Caption
 	}
-	ts, err := service.wrapSendMessage(ctx, dataWaRecipient, msg, caption)
+	setMessageMentions(msg, mentions)
+	ts, err := service.wrapSendMessage(ctx, request.BaseRequest, dataWaRecipient, msg, caption)
 	go func() {
 		errDelete := utils.RemoveFile(0, deletedItems...)
 		if errDelete != nil {
@@ -327,7 +360,7 @@ func (service serviceSend) SendImage(ctx context.Context, request domainSend.Ima
 	}
 
 	response.MessageID = ts.ID
-	response.Status = fmt.Sprintf("Message sent to %s (server timestamp: %s)", request.BaseRequest.Phone, ts.Timestamp.String())
+	response.Status = sendStatus(ts, "Message sent to %s (server timestamp: %s)", request.BaseRequest.Phone, ts.Timestamp.String())
 	return response, nil
 }
 
@@ -336,7 +369,12 @@ func (service serviceSend) SendFile(ctx context.Context, request domainSend.File
 	if err != nil {
 		return response, err
 	}
-	dataWaRecipient, err := utils.ValidateJidWithLogin(whatsapp.GetClient(), request.BaseRequest.Phone)
+	dataWaRecipient, err := service.validateRecipient(request.BaseRequest.Phone)
+	if err != nil {
+		return response, err
+	}
+
+	mentions, err := service.resolveMentions(ctx, request.BaseRequest, dataWaRecipient, request.Caption)
 	if err != nil {
 		return response, err
 	}
@@ -382,13 +420,14 @@ func (service serviceSend) SendFile(ctx context.Context, request domainSend.File
 	if request.Caption != "" {
 		caption = "📄 " + request.Caption
 	}
-	ts, err := service.wrapSendMessage(ctx, dataWaRecipient, msg, caption)
+	setMessageMentions(msg, mentions)
+	ts, err := service.wrapSendMessage(ctx, request.BaseRequest, dataWaRecipient, msg, caption)
 	if err != nil {
 		return response, err
 	}
 
 	response.MessageID = ts.ID
-	response.Status = fmt.Sprintf("Document sent to %s (server timestamp: %s)", request.BaseRequest.Phone, ts.Timestamp.String())
+	response.Status = sendStatus(ts, "Document sent to %s (server timestamp: %s)", request.BaseRequest.Phone, ts.Timestamp.String())
 	return response, nil
 }
 
@@ -412,7 +451,12 @@ func (service serviceSend) SendVideo(ctx context.Context, request domainSend.Vid
 	if err != nil {
 		return response, err
 	}
-	dataWaRecipient, err := utils.ValidateJidWithLogin(whatsapp.GetClient(), request.BaseRequest.Phone)
+	dataWaRecipient, err := service.validateRecipient(request.BaseRequest.Phone)
+	if err != nil {
+		return response, err
+	}
+
+	mentions, err := service.resolveMentions(ctx, request.BaseRequest, dataWaRecipient, request.Caption)
 	if err != nil {
 		return response, err
 	}
@@ -572,13 +616,14 @@ func (service serviceSend) SendVideo(ctx context.Context, request domainSend.Vid
 	if request.Caption != "" {
 		caption = "🎥 " + request.Caption
 	}
-	ts, err := service.wrapSendMessage(ctx, dataWaRecipient, msg, caption)
+	setMessageMentions(msg, mentions)
+	ts, err := service.wrapSendMessage(ctx, request.BaseRequest, dataWaRecipient, msg, caption)
 	if err != nil {
 		return response, err
 	}
 
 	response.MessageID = ts.ID
-	response.Status = fmt.Sprintf("Video sent to %s (server timestamp: %s)", request.BaseRequest.Phone, ts.Timestamp.String())
+	response.Status = sendStatus(ts, "Video sent to %s (server timestamp: %s)", request.BaseRequest.Phone, ts.Timestamp.String())
 	return response, nil
 }
 
@@ -587,7 +632,12 @@ func (service serviceSend) SendContact(ctx context.Context, request domainSend.C
 	if err != nil {
 		return response, err
 	}
-	dataWaRecipient, err := utils.ValidateJidWithLogin(whatsapp.GetClient(), request.BaseRequest.Phone)
+	dataWaRecipient, err := service.validateRecipient(request.BaseRequest.Phone)
+	if err != nil {
+		return response, err
+	}
+
+	mentions, err := service.resolveMentions(ctx, request.BaseRequest, dataWaRecipient, "")
 	if err != nil {
 		return response, err
 	}
@@ -615,13 +665,14 @@ func (service serviceSend) SendContact(ctx context.Context, request domainSend.C
 
 	content := "👤 " + request.ContactName
 
-	ts, err := service.wrapSendMessage(ctx, dataWaRecipient, msg, content)
+	setMessageMentions(msg, mentions)
+	ts, err := service.wrapSendMessage(ctx, request.BaseRequest, dataWaRecipient, msg, content)
 	if err != nil {
 		return response, err
 	}
 
 	response.MessageID = ts.ID
-	response.Status = fmt.Sprintf("Contact sent to %s (server timestamp: %s)", request.BaseRequest.Phone, ts.Timestamp.String())
+	response.Status = sendStatus(ts, "Contact sent to %s (server timestamp: %s)", request.BaseRequest.Phone, ts.Timestamp.String())
 	return response, nil
 }
 
@@ -630,7 +681,12 @@ func (service serviceSend) SendLink(ctx context.Context, request domainSend.Link
 	if err != nil {
 		return response, err
 	}
-	dataWaRecipient, err := utils.ValidateJidWithLogin(whatsapp.GetClient(), request.BaseRequest.Phone)
+	dataWaRecipient, err := service.validateRecipient(request.BaseRequest.Phone)
+	if err != nil {
+		return response, err
+	}
+
+	mentions, err := service.resolveMentions(ctx, request.BaseRequest, dataWaRecipient, request.Caption)
 	if err != nil {
 		return response, err
 	}
@@ -690,13 +746,14 @@ func (service serviceSend) SendLink(ctx context.Context, request domainSend.Link
 	if request.Caption != "" {
 		content = "🔗 " + request.Caption
 	}
-	ts, err := service.wrapSendMessage(ctx, dataWaRecipient, msg, content)
+	setMessageMentions(msg, mentions)
+	ts, err := service.wrapSendMessage(ctx, request.BaseRequest, dataWaRecipient, msg, content)
 	if err != nil {
 		return response, err
 	}
 
 	response.MessageID = ts.ID
-	response.Status = fmt.Sprintf("Link sent to %s (server timestamp: %s)", request.BaseRequest.Phone, ts.Timestamp.String())
+	response.Status = sendStatus(ts, "Link sent to %s (server timestamp: %s)", request.BaseRequest.Phone, ts.Timestamp.String())
 	return response, nil
 }
 
@@ -705,7 +762,12 @@ func (service serviceSend) SendLocation(ctx context.Context, request domainSend.
 	if err != nil {
 		return response, err
 	}
-	dataWaRecipient, err := utils.ValidateJidWithLogin(whatsapp.GetClient(), request.BaseRequest.Phone)
+	dataWaRecipient, err := service.validateRecipient(request.BaseRequest.Phone)
+	if err != nil {
+		return response, err
+	}
+
+	mentions, err := service.resolveMentions(ctx, request.BaseRequest, dataWaRecipient, "")
 	if err != nil {
 		return response, err
 	}
@@ -735,13 +797,14 @@ func (service serviceSend) SendLocation(ctx context.Context, request domainSend.
 	content := "📍 " + request.Latitude + ", " + request.Longitude
 
 	// Send WhatsApp Message Proto
-	ts, err := service.wrapSendMessage(ctx, dataWaRecipient, msg, content)
+	setMessageMentions(msg, mentions)
+	ts, err := service.wrapSendMessage(ctx, request.BaseRequest, dataWaRecipient, msg, content)
 	if err != nil {
 		return response, err
 	}
 
 	response.MessageID = ts.ID
-	response.Status = fmt.Sprintf("Send location success %s (server timestamp: %s)", request.BaseRequest.Phone, ts.Timestamp.String())
+	response.Status = sendStatus(ts, "Send location success %s (server timestamp: %s)", request.BaseRequest.Phone, ts.Timestamp.String())
 	return response, nil
 }
 
@@ -752,7 +815,12 @@ func (service serviceSend) SendAudio(ctx context.Context, request domainSend.Aud
 		return response, err
 	}
 
-	dataWaRecipient, err := utils.ValidateJidWithLogin(whatsapp.GetClient(), request.BaseRequest.Phone)
+	dataWaRecipient, err := service.validateRecipient(request.BaseRequest.Phone)
+	if err != nil {
+		return response, err
+	}
+
+	mentions, err := service.resolveMentions(ctx, request.BaseRequest, dataWaRecipient, "")
 	if err != nil {
 		return response, err
 	}
@@ -809,13 +877,14 @@ func (service serviceSend) SendAudio(ctx context.Context, request domainSend.Aud
 
 	content := "🎵 Audio"
 
-	ts, err := service.wrapSendMessage(ctx, dataWaRecipient, msg, content)
+	setMessageMentions(msg, mentions)
+	ts, err := service.wrapSendMessage(ctx, request.BaseRequest, dataWaRecipient, msg, content)
 	if err != nil {
 		return response, err
 	}
 
 	response.MessageID = ts.ID
-	response.Status = fmt.Sprintf("Send audio success %s (server timestamp: %s)", request.BaseRequest.Phone, ts.Timestamp.String())
+	response.Status = sendStatus(ts, "Send audio success %s (server timestamp: %s)", request.BaseRequest.Phone, ts.Timestamp.String())
 	return response, nil
 }
 
@@ -824,7 +893,12 @@ func (service serviceSend) SendPoll(ctx context.Context, request domainSend.Poll
 	if err != nil {
 		return response, err
 	}
-	dataWaRecipient, err := utils.ValidateJidWithLogin(whatsapp.GetClient(), request.BaseRequest.Phone)
+	dataWaRecipient, err := service.validateRecipient(request.BaseRequest.Phone)
+	if err != nil {
+		return response, err
+	}
+
+	mentions, err := service.resolveMentions(ctx, request.BaseRequest, dataWaRecipient, "")
 	if err != nil {
 		return response, err
 	}
@@ -840,13 +914,14 @@ func (service serviceSend) SendPoll(ctx context.Context, request domainSend.Poll
 		msg.PollCreationMessage.ContextInfo.Expiration = proto.Uint32(uint32(*request.BaseRequest.Duration))
 	}
 
-	ts, err := service.wrapSendMessage(ctx, dataWaRecipient, msg, content)
+	setMessageMentions(msg, mentions)
+	ts, err := service.wrapSendMessage(ctx, request.BaseRequest, dataWaRecipient, msg, content)
 	if err != nil {
 		return response, err
 	}
 
 	response.MessageID = ts.ID
-	response.Status = fmt.Sprintf("Send poll success %s (server timestamp: %s)", request.BaseRequest.Phone, ts.Timestamp.String())
+	response.Status = sendStatus(ts, "Send poll success %s (server timestamp: %s)", request.BaseRequest.Phone, ts.Timestamp.String())
 	return response, nil
 }
 
@@ -908,13 +983,109 @@ func (service serviceSend) getMentionFromText(_ context.Context, messages string
 	mentions := utils.ContainsMention(messages)
 	for _, mention := range mentions {
 		// Get JID from phone number
-		if dataWaRecipient, err := utils.ValidateJidWithLogin(whatsapp.GetClient(), mention); err == nil {
+		if dataWaRecipient, err := service.validateRecipient(mention); err == nil {
 			result = append(result, dataWaRecipient.String())
 		}
 	}
 	return result
 }
 
+// resolveMentions collects the JIDs to tag: @number patterns in text, the explicit mentions
+// and, with mention_all, every participant of the recipient group
+func (service serviceSend) resolveMentions(ctx context.Context, request domainSend.BaseRequest, recipient types.JID, text string) ([]string, error) {
+	var result []string
+	seen := make(map[string]bool)
+	add := func(jid string) {
+		if !seen[jid] {
+			seen[jid] = true
+			result = append(result, jid)
+		}
+	}
+
+	for _, jid := range service.getMentionFromText(ctx, text) {
+		add(jid)
+	}
+
+	for _, mention := range request.Mentions {
+		jid, err := service.validateRecipient(strings.TrimSpace(mention))
+		if err != nil {
+			return nil, pkgError.ValidationError(fmt.Sprintf("mentions: %v", err))
+		}
+		add(jid.String())
+	}
+
+	if request.MentionAll {
+		if recipient.Server != types.GroupServer {
+			return nil, pkgError.ValidationError("mention_all: only supported when sending to a group")
+		}
+
+		group, err := service.groupService.GetGroupParticipants(ctx, domainGroup.GetGroupParticipantsRequest{GroupID: recipient.String()})
+		if err != nil {
+			return nil, err
+		}
+
+		if config.WhatsappMentionAllMax > 0 && len(group.Participants) > config.WhatsappMentionAllMax {
+			return nil, pkgError.ValidationError(fmt.Sprintf(
+				"mention_all: group has %d participants, more than the allowed %d", len(group.Participants), config.WhatsappMentionAllMax,
+			))
+		}
+
+		for _, participant := range group.Participants {
+			add(participant.JID)
+		}
+	}
+
+	return result, nil
+}
+
+// setMessageMentions sets the mentioned JIDs on the context info of whichever message type msg carries
+func setMessageMentions(msg *waE2E.Message, mentions []string) {
+	if len(mentions) == 0 {
+		return
+	}
+	if ctxInfo := messageContextInfo(msg); ctxInfo != nil {
+		ctxInfo.MentionedJID = mentions
+	}
+}
+
+// messageContextInfo returns the context info of the message, creating it when missing
+func messageContextInfo(msg *waE2E.Message) *waE2E.ContextInfo {
+	ensure := func(ctxInfo **waE2E.ContextInfo) *waE2E.ContextInfo {
+		if *ctxInfo == nil {
+			*ctxInfo = &waE2E.ContextInfo{}
+		}
+		return *ctxInfo
+	}
+
+	switch {
+	case msg.GetExtendedTextMessage() != nil:
+		return ensure(&msg.ExtendedTextMessage.ContextInfo)
+	case msg.GetImageMessage() != nil:
+		return ensure(&msg.ImageMessage.ContextInfo)
+	case msg.GetVideoMessage() != nil:
+		return ensure(&msg.VideoMessage.ContextInfo)
+	case msg.GetDocumentMessage() != nil:
+		return ensure(&msg.DocumentMessage.ContextInfo)
+	case msg.GetAudioMessage() != nil:
+		return ensure(&msg.AudioMessage.ContextInfo)
+	case msg.GetStickerMessage() != nil:
+		return ensure(&msg.StickerMessage.ContextInfo)
+	case msg.GetContactMessage() != nil:
+		return ensure(&msg.ContactMessage.ContextInfo)
+	case msg.GetLocationMessage() != nil:
+		return ensure(&msg.LocationMessage.ContextInfo)
+	case msg.GetPollCreationMessage() != nil:
+		return ensure(&msg.PollCreationMessage.ContextInfo)
+	case msg.GetPollCreationMessageV3() != nil:
+		return ensure(&msg.PollCreationMessageV3.ContextInfo)
+	case msg.GetProductMessage() != nil:
+		return ensure(&msg.ProductMessage.ContextInfo)
+	case msg.GetListMessage() != nil:
+		return ensure(&msg.ListMessage.ContextInfo)
+	}
+	return nil
+}
+
 func (service serviceSend) SendSticker(ctx context.Context, request domainSend.StickerRequest) (response domainSend.GenericResponse, err error) {
 	// Validate request
 	err = validations.ValidateSendSticker(ctx, request)
@@ -922,7 +1093,12 @@ func (service serviceSend) SendSticker(ctx context.Context, request domainSend.S
 		return response, err
 	}
 
-	dataWaRecipient, err := utils.ValidateJidWithLogin(whatsapp.GetClient(), request.Phone)
+	dataWaRecipient, err := service.validateRecipient(request.Phone)
+	if err != nil {
+		return response, err
+	}
+
+	mentions, err := service.resolveMentions(ctx, request.BaseRequest, dataWaRecipient, "")
 	if err != nil {
 		return response, err
 	}
@@ -1088,17 +1264,22 @@ func (service serviceSend) SendSticker(ctx context.Context, request domainSend.S
 	content := "🎨 Sticker"
 
 	// Send the sticker message
-	ts, err := service.wrapSendMessage(ctx, dataWaRecipient, msg, content)
+	setMessageMentions(msg, mentions)
+	ts, err := service.wrapSendMessage(ctx, request.BaseRequest, dataWaRecipient, msg, content)
 	if err != nil {
 		return response, err
 	}
 
 	response.MessageID = ts.ID
-	response.Status = fmt.Sprintf("Sticker sent to %s (server timestamp: %s)", request.Phone, ts.Timestamp.String())
+	response.Status = sendStatus(ts, "Sticker sent to %s (server timestamp: %s)", request.Phone, ts.Timestamp.String())
 	return response, nil
 }
 
 func (service serviceSend) uploadMedia(ctx context.Context, mediaType whatsmeow.MediaType, media []byte, recipient types.JID) (uploaded whatsmeow.UploadResponse, err error) {
+	// The upload needs a connection, so a queued send uploads during the flush
+	if queueingSends() {
+		return whatsapp.QueueMedia(media)
+	}
 	if recipient.Server == types.NewsletterServer {
 		uploaded, err = whatsapp.GetClient().UploadNewsletter(ctx, media, mediaType)
 	} else {
@@ -1108,12 +1289,17 @@ func (service serviceSend) uploadMedia(ctx context.Context, mediaType whatsmeow.
 }
 
 func (service serviceSend) getDefaultEphemeralExpiration(jid string) (expiration uint32) {
+	return getChatEphemeralExpiration(service.chatStorageRepo, jid)
+}
+
+// getChatEphemeralExpiration returns the disappearing message timer stored for a chat
+func getChatEphemeralExpiration(chatStorageRepo domainChatStorage.IChatStorageRepository, jid string) (expiration uint32) {
 	expiration = 0
 	if jid == "" {
 		return expiration
 	}
 
-	chat, err := service.chatStorageRepo.GetChat(jid)
+	chat, err := chatStorageRepo.GetChat(jid)
 	if err != nil {
 		return expiration
 	}
@@ -0,0 +1,157 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/config"
+	domainChatStorage "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/chatstorage"
+	domainSend "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/send"
+	pkgError "github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/error"
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/utils"
+	"github.com/sirupsen/logrus"
+)
+
+// maxIdempotencyKeyLength bounds the keys clients may send
+const maxIdempotencyKeyLength = 255
+
+// idempotentSend answers a repeated send carrying the same idempotency key with
+// the response of the first one instead of sending the message again
+type idempotentSend struct {
+	domainSend.ISendUsecase
+	chatStorageRepo domainChatStorage.IChatStorageRepository
+	locks           *utils.KeyLocks
+}
+
+func newIdempotentSend(service domainSend.ISendUsecase, chatStorageRepo domainChatStorage.IChatStorageRepository) domainSend.ISendUsecase {
+	return &idempotentSend{
+		ISendUsecase:    service,
+		chatStorageRepo: chatStorageRepo,
+		locks:           utils.NewKeyLocks(),
+	}
+}
+
+func (service idempotentSend) SendText(ctx context.Context, request domainSend.MessageRequest) (domainSend.GenericResponse, error) {
+	return service.once(request.BaseRequest, "message", func() (domainSend.GenericResponse, error) {
+		return service.ISendUsecase.SendText(ctx, request)
+	})
+}
+
+func (service idempotentSend) SendImage(ctx context.Context, request domainSend.ImageRequest) (domainSend.GenericResponse, error) {
+	return service.once(request.BaseRequest, "image", func() (domainSend.GenericResponse, error) {
+		return service.ISendUsecase.SendImage(ctx, request)
+	})
+}
+
+func (service idempotentSend) SendFile(ctx context.Context, request domainSend.FileRequest) (domainSend.GenericResponse, error) {
+	return service.once(request.BaseRequest, "file", func() (domainSend.GenericResponse, error) {
+		return service.ISendUsecase.SendFile(ctx, request)
+	})
+}
+
+func (service idempotentSend) SendVideo(ctx context.Context, request domainSend.VideoRequest) (domainSend.GenericResponse, error) {
+	return service.once(request.BaseRequest, "video", func() (domainSend.GenericResponse, error) {
+		return service.ISendUsecase.SendVideo(ctx, request)
+	})
+}
+
+func (service idempotentSend) SendAudio(ctx context.Context, request domainSend.AudioRequest) (domainSend.GenericResponse, error) {
+	return service.once(request.BaseRequest, "audio", func() (domainSend.GenericResponse, error) {
+		return service.ISendUsecase.SendAudio(ctx, request)
+	})
+}
+
+func (service idempotentSend) SendSticker(ctx context.Context, request domainSend.StickerRequest) (domainSend.GenericResponse, error) {
+	return service.once(request.BaseRequest, "sticker", func() (domainSend.GenericResponse, error) {
+		return service.ISendUsecase.SendSticker(ctx, request)
+	})
+}
+
+func (service idempotentSend) SendContact(ctx context.Context, request domainSend.ContactRequest) (domainSend.GenericResponse, error) {
+	return service.once(request.BaseRequest, "contact", func() (domainSend.GenericResponse, error) {
+		return service.ISendUsecase.SendContact(ctx, request)
+	})
+}
+
+func (service idempotentSend) SendLink(ctx context.Context, request domainSend.LinkRequest) (domainSend.GenericResponse, error) {
+	return service.once(request.BaseRequest, "link", func() (domainSend.GenericResponse, error) {
+		return service.ISendUsecase.SendLink(ctx, request)
+	})
+}
+
+func (service idempotentSend) SendLocation(ctx context.Context, request domainSend.LocationRequest) (domainSend.GenericResponse, error) {
+	return service.once(request.BaseRequest, "location", func() (domainSend.GenericResponse, error) {
+		return service.ISendUsecase.SendLocation(ctx, request)
+	})
+}
+
+func (service idempotentSend) SendPoll(ctx context.Context, request domainSend.PollRequest) (domainSend.GenericResponse, error) {
+	return service.once(request.BaseRequest, "poll", func() (domainSend.GenericResponse, error) {
+		return service.ISendUsecase.SendPoll(ctx, request)
+	})
+}
+
+func (service idempotentSend) SendProduct(ctx context.Context, request domainSend.ProductRequest) (domainSend.GenericResponse, error) {
+	return service.once(request.BaseRequest, "product", func() (domainSend.GenericResponse, error) {
+		return service.ISendUsecase.SendProduct(ctx, request)
+	})
+}
+
+func (service idempotentSend) SendProductList(ctx context.Context, request domainSend.ProductListRequest) (domainSend.GenericResponse, error) {
+	return service.once(request.BaseRequest, "product_list", func() (domainSend.GenericResponse, error) {
+		return service.ISendUsecase.SendProductList(ctx, request)
+	})
+}
+
+// once runs send unless a send with the same key succeeded within the window,
+// in which case the stored response is returned. Failed sends aren't recorded,
+// so they can be retried with the same key.
+func (service idempotentSend) once(base domainSend.BaseRequest, operation string, send func() (domainSend.GenericResponse, error)) (response domainSend.GenericResponse, err error) {
+	key := strings.TrimSpace(base.IdempotencyKey)
+	if key == "" || config.WhatsappIdempotencyWindow <= 0 || service.chatStorageRepo == nil {
+		return send()
+	}
+	if len(key) > maxIdempotencyKeyLength {
+		return response, pkgError.ValidationError(fmt.Sprintf("idempotency key must be at most %d characters", maxIdempotencyKeyLength))
+	}
+
+	// Requests with the same key run one at a time, so a retry that arrives
+	// while the first attempt is still sending waits for its result
+	service.locks.Lock(key)
+	defer service.locks.Unlock(key)
+
+	since := time.Now().Add(-config.WhatsappIdempotencyWindow)
+	record, err := service.chatStorageRepo.GetIdempotencyRecord(key, since)
+	if err != nil {
+		return response, fmt.Errorf("failed to check idempotency key: %w", err)
+	}
+	if record != nil {
+		if record.Operation != operation || record.Recipient != base.Phone {
+			return response, pkgError.ValidationError(fmt.Sprintf("idempotency key %s was already used for a different request", key))
+		}
+		logrus.Infof("Idempotency key %s matched message %s, not sending again", key, record.MessageID)
+		return domainSend.GenericResponse{MessageID: record.MessageID, Status: record.Status}, nil
+	}
+
+	response, err = send()
+	if err != nil {
+		return response, err
+	}
+
+	if err := service.chatStorageRepo.StoreIdempotencyRecord(&domainChatStorage.IdempotencyRecord{
+		Key:       key,
+		Operation: operation,
+		Recipient: base.Phone,
+		MessageID: response.MessageID,
+		Status:    response.Status,
+	}); err != nil {
+		logrus.Warnf("Failed to store idempotency key %s: %v", key, err)
+	}
+	if _, err := service.chatStorageRepo.DeleteIdempotencyRecordsBefore(since); err != nil {
+		logrus.Warnf("Failed to delete expired idempotency keys: %v", err)
+	}
+
+	return response, nil
+}
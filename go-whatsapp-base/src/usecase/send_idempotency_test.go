@@ -0,0 +1,176 @@
+package usecase
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"sync"
+	"testing"
+
+	domainSend "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/send"
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/infrastructure/chatstorage"
+	_ "github.com/mattn/go-sqlite3"
+)
+
+// countingSend is a send usecase that only counts text and product sends
+type countingSend struct {
+	domainSend.ISendUsecase
+	mu    sync.Mutex
+	count int
+	fail  bool
+}
+
+func (s *countingSend) SendText(_ context.Context, _ domainSend.MessageRequest) (domainSend.GenericResponse, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.fail {
+		return domainSend.GenericResponse{}, fmt.Errorf("not connected")
+	}
+	s.count++
+	return domainSend.GenericResponse{MessageID: fmt.Sprintf("MSG%d", s.count), Status: "sent"}, nil
+}
+
+func (s *countingSend) SendProduct(_ context.Context, _ domainSend.ProductRequest) (domainSend.GenericResponse, error) {
+	return s.SendText(context.Background(), domainSend.MessageRequest{})
+}
+
+func (s *countingSend) SendProductList(_ context.Context, _ domainSend.ProductListRequest) (domainSend.GenericResponse, error) {
+	return s.SendText(context.Background(), domainSend.MessageRequest{})
+}
+
+func newIdempotencyTestService(t *testing.T) (*countingSend, domainSend.ISendUsecase) {
+	t.Helper()
+
+	db, err := sql.Open("sqlite3", ":memory:")
+	if err != nil {
+		t.Fatal(err)
+	}
+	db.SetMaxOpenConns(1)
+	t.Cleanup(func() { db.Close() })
+
+	repo := chatstorage.NewStorageRepository(db)
+	if err := repo.InitializeSchema(); err != nil {
+		t.Fatal(err)
+	}
+
+	inner := &countingSend{}
+	return inner, newIdempotentSend(inner, repo)
+}
+
+func TestIdempotentSendReplaysFirstResponse(t *testing.T) {
+	inner, service := newIdempotencyTestService(t)
+	request := domainSend.MessageRequest{
+		BaseRequest: domainSend.BaseRequest{Phone: "628123@s.whatsapp.net", IdempotencyKey: "order-1"},
+		Message:     "hello",
+	}
+
+	first, err := service.SendText(context.Background(), request)
+	if err != nil {
+		t.Fatal(err)
+	}
+	second, err := service.SendText(context.Background(), request)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if inner.count != 1 {
+		t.Fatalf("expected one send, got %d", inner.count)
+	}
+	if first != second {
+		t.Fatalf("expected the first response again, got %+v and %+v", first, second)
+	}
+
+	// Without a key every request is sent
+	request.IdempotencyKey = ""
+	if _, err := service.SendText(context.Background(), request); err != nil {
+		t.Fatal(err)
+	}
+	if inner.count != 2 {
+		t.Fatalf("expected a second send without key, got %d", inner.count)
+	}
+}
+
+func TestIdempotentSendConcurrentRetries(t *testing.T) {
+	inner, service := newIdempotencyTestService(t)
+	request := domainSend.MessageRequest{
+		BaseRequest: domainSend.BaseRequest{Phone: "628123@s.whatsapp.net", IdempotencyKey: "retry"},
+		Message:     "hello",
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 5; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, err := service.SendText(context.Background(), request); err != nil {
+				t.Error(err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if inner.count != 1 {
+		t.Fatalf("expected one send, got %d", inner.count)
+	}
+	// The key's lock is dropped only once the last waiter is done with it
+	if locks := service.(*idempotentSend).locks.Len(); locks != 0 {
+		t.Fatalf("expected no locks left, got %d", locks)
+	}
+}
+
+func TestIdempotentSendRejectsReuseAndRetriesFailures(t *testing.T) {
+	inner, service := newIdempotencyTestService(t)
+	request := domainSend.MessageRequest{
+		BaseRequest: domainSend.BaseRequest{Phone: "628123@s.whatsapp.net", IdempotencyKey: "key"},
+		Message:     "hello",
+	}
+
+	inner.fail = true
+	if _, err := service.SendText(context.Background(), request); err == nil {
+		t.Fatal("expected the send to fail")
+	}
+
+	// A failed send isn't recorded, so the retry sends
+	inner.fail = false
+	if _, err := service.SendText(context.Background(), request); err != nil {
+		t.Fatal(err)
+	}
+	if inner.count != 1 {
+		t.Fatalf("expected the retry to send, got %d", inner.count)
+	}
+
+	request.Phone = "628999@s.whatsapp.net"
+	if _, err := service.SendText(context.Background(), request); err == nil {
+		t.Fatal("expected an error when the key is reused for another recipient")
+	}
+}
+
+func TestIdempotentSendProducts(t *testing.T) {
+	inner, service := newIdempotencyTestService(t)
+	base := domainSend.BaseRequest{Phone: "628123@s.whatsapp.net", IdempotencyKey: "catalog-1"}
+
+	for i := 0; i < 2; i++ {
+		if _, err := service.SendProduct(context.Background(), domainSend.ProductRequest{BaseRequest: base, ProductID: "P1"}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if inner.count != 1 {
+		t.Fatalf("expected one product send, got %d", inner.count)
+	}
+
+	base.IdempotencyKey = "catalog-2"
+	for i := 0; i < 2; i++ {
+		if _, err := service.SendProductList(context.Background(), domainSend.ProductListRequest{BaseRequest: base}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if inner.count != 2 {
+		t.Fatalf("expected one product list send, got %d", inner.count-1)
+	}
+
+	// The key of a product send can't be reused for a product list
+	base.IdempotencyKey = "catalog-1"
+	if _, err := service.SendProductList(context.Background(), domainSend.ProductListRequest{BaseRequest: base}); err == nil {
+		t.Fatal("expected an error when the key is reused for another operation")
+	}
+}
@@ -0,0 +1,245 @@
+package usecase
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+
+	domainSend "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/send"
+	domainUser "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/user"
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/infrastructure/whatsapp"
+	pkgError "github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/error"
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/utils"
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/validations"
+	"github.com/disintegration/imaging"
+	"github.com/sirupsen/logrus"
+	"go.mau.fi/whatsmeow"
+	"go.mau.fi/whatsmeow/proto/waE2E"
+	"go.mau.fi/whatsmeow/types"
+	"google.golang.org/protobuf/proto"
+)
+
+// catalogPageSize and catalogMaxPages bound the catalog scan for a product id
+const (
+	catalogPageSize = 100
+	catalogMaxPages = 10
+)
+
+func (service serviceSend) SendProduct(ctx context.Context, request domainSend.ProductRequest) (response domainSend.GenericResponse, err error) {
+	err = validations.ValidateSendProduct(ctx, request)
+	if err != nil {
+		return response, err
+	}
+	dataWaRecipient, err := service.validateRecipient(request.BaseRequest.Phone)
+	if err != nil {
+		return response, err
+	}
+
+	mentions, err := service.resolveMentions(ctx, request.BaseRequest, dataWaRecipient, request.Body)
+	if err != nil {
+		return response, err
+	}
+
+	// The product snapshot is read from our catalog, which needs a connection
+	if queueingSends() {
+		return response, pkgError.ErrNotConnected
+	}
+	owner := whatsapp.GetClient().Store.GetJID().ToNonAD()
+	product, err := findCatalogProduct(ctx, owner, request.ProductID)
+	if err != nil {
+		return response, err
+	}
+
+	snapshot := &waE2E.ProductMessage_ProductSnapshot{
+		ProductID:         proto.String(product.ID),
+		Title:             proto.String(product.Name),
+		Description:       proto.String(product.Description),
+		CurrencyCode:      proto.String(product.Currency),
+		PriceAmount1000:   proto.Int64(product.PriceAmount1000),
+		RetailerID:        proto.String(product.RetailerID),
+		URL:               proto.String(product.URL),
+		ProductImageCount: proto.Uint32(uint32(len(product.ImageURLs))),
+	}
+	if len(product.ImageURLs) > 0 {
+		image, err := service.uploadProductImage(ctx, product.ImageURLs[0], dataWaRecipient)
+		if err != nil {
+			logrus.Warnf("Failed to attach image of product %s: %v, continue without image", product.ID, err)
+		} else {
+			snapshot.ProductImage = image
+		}
+	}
+
+	msg := &waE2E.Message{ProductMessage: &waE2E.ProductMessage{
+		Product:          snapshot,
+		BusinessOwnerJID: proto.String(owner.String()),
+	}}
+	if request.Body != "" {
+		msg.ProductMessage.Body = proto.String(request.Body)
+	}
+	if request.Footer != "" {
+		msg.ProductMessage.Footer = proto.String(request.Footer)
+	}
+	setProductContextInfo(msg, request.BaseRequest)
+
+	setMessageMentions(msg, mentions)
+	ts, err := service.wrapSendMessage(ctx, request.BaseRequest, dataWaRecipient, msg, "🛍️ "+product.Name)
+	if err != nil {
+		return response, err
+	}
+
+	response.MessageID = ts.ID
+	response.Status = sendStatus(ts, "Send product success %s (server timestamp: %s)", request.BaseRequest.Phone, ts.Timestamp.String())
+	return response, nil
+}
+
+func (service serviceSend) SendProductList(ctx context.Context, request domainSend.ProductListRequest) (response domainSend.GenericResponse, err error) {
+	err = validations.ValidateSendProductList(ctx, request)
+	if err != nil {
+		return response, err
+	}
+	dataWaRecipient, err := service.validateRecipient(request.BaseRequest.Phone)
+	if err != nil {
+		return response, err
+	}
+
+	mentions, err := service.resolveMentions(ctx, request.BaseRequest, dataWaRecipient, request.Description)
+	if err != nil {
+		return response, err
+	}
+
+	if queueingSends() {
+		return response, pkgError.ErrNotConnected
+	}
+	owner := whatsapp.GetClient().Store.GetJID().ToNonAD()
+
+	headerProductID := request.HeaderProductID
+	if headerProductID == "" {
+		headerProductID = request.Sections[0].ProductIDs[0]
+	}
+	header := &waE2E.ListMessage_ProductListHeaderImage{ProductID: proto.String(headerProductID)}
+	if product, err := findCatalogProduct(ctx, owner, headerProductID); err != nil {
+		return response, err
+	} else if len(product.ImageURLs) > 0 {
+		if thumbnail, err := productThumbnail(product.ImageURLs[0]); err != nil {
+			logrus.Warnf("Failed to build header thumbnail of product %s: %v", product.ID, err)
+		} else {
+			header.JPEGThumbnail = thumbnail
+		}
+	}
+
+	sections := make([]*waE2E.ListMessage_ProductSection, 0, len(request.Sections))
+	for _, section := range request.Sections {
+		products := make([]*waE2E.ListMessage_Product, 0, len(section.ProductIDs))
+		for _, productID := range section.ProductIDs {
+			products = append(products, &waE2E.ListMessage_Product{ProductID: proto.String(productID)})
+		}
+		sections = append(sections, &waE2E.ListMessage_ProductSection{
+			Title:    proto.String(section.Title),
+			Products: products,
+		})
+	}
+
+	msg := &waE2E.Message{ListMessage: &waE2E.ListMessage{
+		Title:       proto.String(request.Title),
+		Description: proto.String(request.Description),
+		ButtonText:  proto.String(request.ButtonText),
+		ListType:    waE2E.ListMessage_PRODUCT_LIST.Enum(),
+		ProductListInfo: &waE2E.ListMessage_ProductListInfo{
+			ProductSections:  sections,
+			HeaderImage:      header,
+			BusinessOwnerJID: proto.String(owner.String()),
+		},
+	}}
+	if request.Footer != "" {
+		msg.ListMessage.FooterText = proto.String(request.Footer)
+	}
+	setProductContextInfo(msg, request.BaseRequest)
+
+	setMessageMentions(msg, mentions)
+	ts, err := service.wrapSendMessage(ctx, request.BaseRequest, dataWaRecipient, msg, "🛍️ "+request.Title)
+	if err != nil {
+		return response, err
+	}
+
+	response.MessageID = ts.ID
+	response.Status = sendStatus(ts, "Send product list success %s (server timestamp: %s)", request.BaseRequest.Phone, ts.Timestamp.String())
+	return response, nil
+}
+
+// findCatalogProduct pages through a catalog until the product is found
+func findCatalogProduct(ctx context.Context, owner types.JID, productID string) (product domainUser.BusinessProduct, err error) {
+	var cursor string
+	for page := 0; page < catalogMaxPages; page++ {
+		products, next, err := whatsapp.GetBusinessCatalog(ctx, whatsapp.GetClient(), owner, catalogPageSize, cursor)
+		if err != nil {
+			return product, err
+		}
+		for _, product := range products {
+			if product.ID == productID {
+				return product, nil
+			}
+		}
+		if next == "" {
+			break
+		}
+		cursor = next
+	}
+	return product, pkgError.ValidationError("product_id: product " + productID + " not found in catalog")
+}
+
+// uploadProductImage downloads a catalog image and uploads it for the product snapshot
+func (service serviceSend) uploadProductImage(ctx context.Context, imageURL string, recipient types.JID) (*waE2E.ImageMessage, error) {
+	imageData, _, err := utils.DownloadImageFromURL(imageURL)
+	if err != nil {
+		return nil, err
+	}
+	thumbnail, err := jpegThumbnail(imageData)
+	if err != nil {
+		return nil, err
+	}
+	uploaded, err := service.uploadMedia(ctx, whatsmeow.MediaImage, imageData, recipient)
+	if err != nil {
+		return nil, err
+	}
+	return &waE2E.ImageMessage{
+		JPEGThumbnail: thumbnail,
+		URL:           proto.String(uploaded.URL),
+		DirectPath:    proto.String(uploaded.DirectPath),
+		MediaKey:      uploaded.MediaKey,
+		Mimetype:      proto.String(http.DetectContentType(imageData)),
+		FileEncSHA256: uploaded.FileEncSHA256,
+		FileSHA256:    uploaded.FileSHA256,
+		FileLength:    proto.Uint64(uint64(len(imageData))),
+	}, nil
+}
+
+func productThumbnail(imageURL string) ([]byte, error) {
+	imageData, _, err := utils.DownloadImageFromURL(imageURL)
+	if err != nil {
+		return nil, err
+	}
+	return jpegThumbnail(imageData)
+}
+
+func jpegThumbnail(imageData []byte) ([]byte, error) {
+	srcImage, err := imaging.Decode(bytes.NewReader(imageData))
+	if err != nil {
+		return nil, err
+	}
+	var thumbnail bytes.Buffer
+	if err := imaging.Encode(&thumbnail, imaging.Resize(srcImage, 100, 0, imaging.Lanczos), imaging.JPEG); err != nil {
+		return nil, err
+	}
+	return thumbnail.Bytes(), nil
+}
+
+func setProductContextInfo(msg *waE2E.Message, request domainSend.BaseRequest) {
+	if request.IsForwarded {
+		ctxInfo := messageContextInfo(msg)
+		ctxInfo.IsForwarded = proto.Bool(true)
+		ctxInfo.ForwardingScore = proto.Uint32(100)
+	}
+	if request.Duration != nil && *request.Duration > 0 {
+		messageContextInfo(msg).Expiration = proto.Uint32(uint32(*request.Duration))
+	}
+}
@@ -0,0 +1,171 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/config"
+	domainChatStorage "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/chatstorage"
+	domainSend "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/send"
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/infrastructure/whatsapp"
+	pkgError "github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/error"
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/utils"
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/validations"
+	"github.com/sirupsen/logrus"
+	"go.mau.fi/whatsmeow"
+	"go.mau.fi/whatsmeow/proto/waE2E"
+	"go.mau.fi/whatsmeow/types"
+	"google.golang.org/protobuf/proto"
+)
+
+// queueingSends reports whether sends should be queued instead of failing:
+// the send queue is enabled and the device is paired but not connected
+func queueingSends() bool {
+	client := whatsapp.GetClient()
+	return config.WhatsappSendQueue && client != nil && client.Store.ID != nil && !client.IsConnected()
+}
+
+// validateRecipient parses the recipient of a send. While sends are queued the
+// on-WhatsApp check is skipped since it needs a connection.
+func (service serviceSend) validateRecipient(phone string) (types.JID, error) {
+	if queueingSends() {
+		return utils.ParseJID(phone)
+	}
+	return utils.ValidateJidWithLogin(whatsapp.GetClient(), phone)
+}
+
+// enqueueMessage stores a send made while disconnected. The message ID is
+// reserved now so the caller gets the ID the message will be delivered with.
+func (service serviceSend) enqueueMessage(ctx context.Context, request domainSend.BaseRequest, recipient types.JID, msg *waE2E.Message, content string) (whatsmeow.SendResponse, error) {
+	payload, err := proto.Marshal(msg)
+	if err != nil {
+		return whatsmeow.SendResponse{}, fmt.Errorf("failed to encode queued message: %w", err)
+	}
+
+	ttl := config.WhatsappSendQueueTTL
+	if request.QueueTTL != nil {
+		ttl = time.Duration(*request.QueueTTL) * time.Second
+	}
+
+	queued := &domainChatStorage.QueuedMessage{
+		MessageID: whatsapp.GetClient().GenerateMessageID(),
+		Recipient: recipient.String(),
+		Content:   content,
+		Payload:   payload,
+		ExpiresAt: time.Now().Add(ttl),
+	}
+	if err := service.chatStorageRepo.EnqueueMessage(queued); err != nil {
+		return whatsmeow.SendResponse{}, fmt.Errorf("failed to queue message: %w", err)
+	}
+	logrus.Infof("Client disconnected, queued message %s to %s until %s", queued.MessageID, queued.Recipient, queued.ExpiresAt.Format(time.RFC3339))
+
+	// The client may have reconnected after the check, and the flush on connect already ran
+	if whatsapp.GetClient().IsConnected() {
+		go func() {
+			if _, err := whatsapp.FlushSendQueue(context.Background(), service.chatStorageRepo); err != nil {
+				logrus.Warnf("Failed to flush the send queue: %v", err)
+			}
+		}()
+	}
+
+	return whatsmeow.SendResponse{ID: queued.MessageID}, nil
+}
+
+// sendStatus describes a finished send, or that it was queued while disconnected
+func sendStatus(ts whatsmeow.SendResponse, format string, args ...any) string {
+	if ts.Timestamp.IsZero() {
+		return fmt.Sprintf("Queued until WhatsApp reconnects, it will be sent as message %s", ts.ID)
+	}
+	return fmt.Sprintf(format, args...)
+}
+
+func (service serviceSend) GetSendQueue(ctx context.Context, request domainSend.QueueListRequest) (response domainSend.QueueListResponse, err error) {
+	if err = validations.ValidateSendQueueList(ctx, &request); err != nil {
+		return response, err
+	}
+
+	filter := &domainChatStorage.QueuedMessageFilter{
+		Status: request.Status,
+		Limit:  request.Limit,
+		Offset: request.Offset,
+	}
+
+	items, err := service.chatStorageRepo.GetQueuedMessages(filter)
+	if err != nil {
+		return response, err
+	}
+	total, err := service.chatStorageRepo.GetQueuedMessageCount(filter)
+	if err != nil {
+		return response, err
+	}
+
+	response.Data = make([]domainSend.QueueItem, 0, len(items))
+	for _, item := range items {
+		response.Data = append(response.Data, toQueueItem(item))
+	}
+	response.Pagination = domainSend.PaginationResponse{
+		Limit:  request.Limit,
+		Offset: request.Offset,
+		Total:  int(total),
+	}
+
+	return response, nil
+}
+
+func (service serviceSend) CancelQueuedSend(ctx context.Context, id int64) (response domainSend.QueueItem, err error) {
+	item, err := service.chatStorageRepo.GetQueuedMessage(id)
+	if err != nil {
+		return response, err
+	}
+	if item == nil {
+		return response, pkgError.ValidationError(fmt.Sprintf("queued send %d not found", id))
+	}
+
+	updated, err := service.chatStorageRepo.UpdateQueuedMessageStatus(id, domainChatStorage.QueueStatusQueued, domainChatStorage.QueueStatusCancelled, "")
+	if err != nil {
+		return response, err
+	}
+	if !updated {
+		return response, pkgError.ValidationError(fmt.Sprintf("queued send %d is already %s", id, item.Status))
+	}
+
+	item.Status = domainChatStorage.QueueStatusCancelled
+	whatsapp.NotifyQueuedSendCancelled(ctx, item)
+	return toQueueItem(item), nil
+}
+
+func (service serviceSend) FlushSendQueue(ctx context.Context) (response domainSend.QueueFlushResponse, err error) {
+	result, err := whatsapp.FlushSendQueue(ctx, service.chatStorageRepo)
+	if errors.Is(err, whatsapp.ErrSendQueueDisconnected) {
+		return response, pkgError.ErrNotConnected
+	}
+	if err != nil {
+		return response, err
+	}
+
+	return domainSend.QueueFlushResponse{
+		Sent:      result.Sent,
+		Failed:    result.Failed,
+		Expired:   result.Expired,
+		Remaining: result.Remaining,
+	}, nil
+}
+
+func toQueueItem(item *domainChatStorage.QueuedMessage) domainSend.QueueItem {
+	queueItem := domainSend.QueueItem{
+		ID:        item.ID,
+		MessageID: item.MessageID,
+		Recipient: item.Recipient,
+		Content:   item.Content,
+		Status:    item.Status,
+		Error:     item.Error,
+		ExpiresAt: item.ExpiresAt.Format(time.RFC3339),
+		CreatedAt: item.CreatedAt.Format(time.RFC3339),
+	}
+	if item.SentAt != nil {
+		queueItem.SentAt = item.SentAt.Format(time.RFC3339)
+	}
+	return queueItem
+}
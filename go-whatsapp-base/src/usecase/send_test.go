@@ -1,6 +1,11 @@
 package usecase
 
-import "testing"
+import (
+	"testing"
+
+	"go.mau.fi/whatsmeow/proto/waE2E"
+	"google.golang.org/protobuf/proto"
+)
 
 func TestResolveDocumentMIME(t *testing.T) {
 	tests := []struct {
@@ -39,3 +44,55 @@ func TestResolveDocumentMIME(t *testing.T) {
 		})
 	}
 }
+
+func TestSetMessageMentions(t *testing.T) {
+	mentions := []string{"6281234567890@s.whatsapp.net", "6289876543210@s.whatsapp.net"}
+
+	tests := []struct {
+		name string
+		msg  *waE2E.Message
+		get  func(*waE2E.Message) *waE2E.ContextInfo
+	}{
+		{
+			name: "Image caption",
+			msg:  &waE2E.Message{ImageMessage: &waE2E.ImageMessage{Caption: proto.String("hi")}},
+			get:  func(m *waE2E.Message) *waE2E.ContextInfo { return m.GetImageMessage().GetContextInfo() },
+		},
+		{
+			name: "Poll keeps existing context info",
+			msg: &waE2E.Message{PollCreationMessage: &waE2E.PollCreationMessage{
+				ContextInfo: &waE2E.ContextInfo{Expiration: proto.Uint32(3600)},
+			}},
+			get: func(m *waE2E.Message) *waE2E.ContextInfo { return m.GetPollCreationMessage().GetContextInfo() },
+		},
+		{
+			name: "Location",
+			msg:  &waE2E.Message{LocationMessage: &waE2E.LocationMessage{}},
+			get:  func(m *waE2E.Message) *waE2E.ContextInfo { return m.GetLocationMessage().GetContextInfo() },
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			setMessageMentions(tt.msg, mentions)
+			ctxInfo := tt.get(tt.msg)
+			if len(ctxInfo.GetMentionedJID()) != len(mentions) {
+				t.Fatalf("expected %d mentions, got %v", len(mentions), ctxInfo.GetMentionedJID())
+			}
+		})
+	}
+
+	t.Run("Poll keeps expiration", func(t *testing.T) {
+		if tests[1].msg.GetPollCreationMessage().GetContextInfo().GetExpiration() != 3600 {
+			t.Fatal("expected expiration to be preserved")
+		}
+	})
+
+	t.Run("No mentions leaves message untouched", func(t *testing.T) {
+		msg := &waE2E.Message{StickerMessage: &waE2E.StickerMessage{}}
+		setMessageMentions(msg, nil)
+		if msg.GetStickerMessage().GetContextInfo() != nil {
+			t.Fatal("expected no context info")
+		}
+	})
+}
@@ -0,0 +1,256 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	domainChatStorage "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/chatstorage"
+	domainTemplate "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/template"
+	pkgError "github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/error"
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/utils"
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/validations"
+	"github.com/sirupsen/logrus"
+)
+
+type serviceTemplate struct {
+	chatStorageRepo domainChatStorage.IChatStorageRepository
+}
+
+func NewTemplateService(chatStorageRepo domainChatStorage.IChatStorageRepository) domainTemplate.ITemplateUsecase {
+	return &serviceTemplate{
+		chatStorageRepo: chatStorageRepo,
+	}
+}
+
+func (service serviceTemplate) ListTemplates(_ context.Context) (response domainTemplate.ListTemplatesResponse, err error) {
+	templates, err := service.chatStorageRepo.GetMessageTemplates()
+	if err != nil {
+		logrus.WithError(err).Error("Failed to get message templates from storage")
+		return response, err
+	}
+
+	response.Data = make([]domainTemplate.TemplateInfo, 0, len(templates))
+	for _, template := range templates {
+		response.Data = append(response.Data, toTemplateInfo(template))
+	}
+
+	return response, nil
+}
+
+func (service serviceTemplate) GetTemplate(ctx context.Context, request domainTemplate.GetTemplateRequest) (response domainTemplate.TemplateInfo, err error) {
+	if err = validations.ValidateGetTemplate(ctx, request); err != nil {
+		return response, err
+	}
+
+	template, err := getMessageTemplate(service.chatStorageRepo, request.TemplateID)
+	if err != nil {
+		return response, err
+	}
+
+	return toTemplateInfo(template), nil
+}
+
+func (service serviceTemplate) CreateTemplate(ctx context.Context, request domainTemplate.TemplateRequest) (response domainTemplate.TemplateInfo, err error) {
+	if err = validations.ValidateTemplate(ctx, &request); err != nil {
+		return response, err
+	}
+
+	existing, err := service.chatStorageRepo.GetMessageTemplate(request.TemplateID)
+	if err != nil {
+		return response, err
+	}
+	if existing != nil {
+		return response, pkgError.ValidationError(fmt.Sprintf("template %s already exists", request.TemplateID))
+	}
+
+	template := &domainChatStorage.MessageTemplate{ID: request.TemplateID}
+	applyTemplateRequest(template, request)
+
+	if err = service.chatStorageRepo.StoreMessageTemplate(template); err != nil {
+		return response, pkgError.InternalServerError(fmt.Sprintf("failed to create template: %v", err))
+	}
+
+	logrus.WithField("template_id", template.ID).Info("Created message template")
+	return toTemplateInfo(template), nil
+}
+
+func (service serviceTemplate) UpdateTemplate(ctx context.Context, request domainTemplate.TemplateRequest) (response domainTemplate.TemplateInfo, err error) {
+	if err = validations.ValidateUpdateTemplate(ctx, &request); err != nil {
+		return response, err
+	}
+
+	template, err := getMessageTemplate(service.chatStorageRepo, request.TemplateID)
+	if err != nil {
+		return response, err
+	}
+
+	applyTemplateRequest(template, request)
+
+	if err = service.chatStorageRepo.StoreMessageTemplate(template); err != nil {
+		return response, pkgError.InternalServerError(fmt.Sprintf("failed to update template: %v", err))
+	}
+
+	logrus.WithField("template_id", template.ID).Info("Updated message template")
+	return toTemplateInfo(template), nil
+}
+
+func (service serviceTemplate) DeleteTemplate(ctx context.Context, request domainTemplate.DeleteTemplateRequest) (err error) {
+	if err = validations.ValidateDeleteTemplate(ctx, request); err != nil {
+		return err
+	}
+
+	if _, err = getMessageTemplate(service.chatStorageRepo, request.TemplateID); err != nil {
+		return err
+	}
+
+	if err = service.chatStorageRepo.DeleteMessageTemplate(request.TemplateID); err != nil {
+		return pkgError.InternalServerError(fmt.Sprintf("failed to delete template: %v", err))
+	}
+
+	logrus.WithField("template_id", request.TemplateID).Info("Deleted message template")
+	return nil
+}
+
+func (service serviceTemplate) RenderTemplate(ctx context.Context, request domainTemplate.RenderTemplateRequest) (response domainTemplate.RenderTemplateResponse, err error) {
+	if err = validations.ValidateRenderTemplate(ctx, request); err != nil {
+		return response, err
+	}
+
+	template, err := getMessageTemplate(service.chatStorageRepo, request.TemplateID)
+	if err != nil {
+		return response, err
+	}
+
+	message, language, err := renderMessageTemplate(template, request.Language, request.Variables)
+	if err != nil {
+		return response, err
+	}
+
+	return domainTemplate.RenderTemplateResponse{
+		TemplateID: template.ID,
+		Language:   language,
+		Message:    message,
+	}, nil
+}
+
+// getMessageTemplate loads a template, reporting a missing one as a validation error
+func getMessageTemplate(chatStorageRepo domainChatStorage.IChatStorageRepository, id string) (*domainChatStorage.MessageTemplate, error) {
+	template, err := chatStorageRepo.GetMessageTemplate(id)
+	if err != nil {
+		return nil, err
+	}
+	if template == nil {
+		return nil, pkgError.ValidationError(fmt.Sprintf("template %s not found", id))
+	}
+	return template, nil
+}
+
+// renderMessageTemplate validates the supplied variables and renders the variant best
+// matching the requested language. It returns the rendered text and the language used.
+func renderMessageTemplate(template *domainChatStorage.MessageTemplate, language string, values map[string]string) (string, string, error) {
+	if err := validations.ValidateTemplateVariables(template, values); err != nil {
+		return "", "", err
+	}
+
+	resolved := make(map[string]string, len(template.Variables))
+	for _, variable := range template.Variables {
+		value := values[variable.Name]
+		if value == "" {
+			value = variable.Default
+		}
+		resolved[variable.Name] = value
+	}
+
+	variant := selectTemplateVariant(template, language)
+	return utils.RenderTemplatePlaceholders(variant.Body, resolved), variant.Language, nil
+}
+
+// selectTemplateVariant picks the variant for a language. It tries an exact match, then the
+// base language ("pt" for "pt-BR"), then the template default language.
+func selectTemplateVariant(template *domainChatStorage.MessageTemplate, language string) domainChatStorage.TemplateVariant {
+	baseLanguage := func(lang string) string {
+		lang = strings.ToLower(lang)
+		if i := strings.IndexAny(lang, "-_"); i > 0 {
+			return lang[:i]
+		}
+		return lang
+	}
+
+	if language != "" {
+		for _, variant := range template.Variants {
+			if strings.EqualFold(variant.Language, language) {
+				return variant
+			}
+		}
+		for _, variant := range template.Variants {
+			if baseLanguage(variant.Language) == baseLanguage(language) {
+				return variant
+			}
+		}
+	}
+
+	for _, variant := range template.Variants {
+		if strings.EqualFold(variant.Language, template.DefaultLanguage) {
+			return variant
+		}
+	}
+
+	if len(template.Variants) > 0 {
+		return template.Variants[0]
+	}
+	return domainChatStorage.TemplateVariant{}
+}
+
+func applyTemplateRequest(template *domainChatStorage.MessageTemplate, request domainTemplate.TemplateRequest) {
+	template.Description = request.Description
+	template.DefaultLanguage = request.DefaultLanguage
+
+	template.Variables = make([]domainChatStorage.TemplateVariable, 0, len(request.Variables))
+	for _, variable := range request.Variables {
+		template.Variables = append(template.Variables, domainChatStorage.TemplateVariable{
+			Name:     variable.Name,
+			Type:     variable.Type,
+			Required: variable.Required,
+			Default:  variable.Default,
+		})
+	}
+
+	template.Variants = make([]domainChatStorage.TemplateVariant, 0, len(request.Variants))
+	for _, variant := range request.Variants {
+		template.Variants = append(template.Variants, domainChatStorage.TemplateVariant{
+			Language: variant.Language,
+			Body:     variant.Body,
+		})
+	}
+}
+
+func toTemplateInfo(template *domainChatStorage.MessageTemplate) domainTemplate.TemplateInfo {
+	info := domainTemplate.TemplateInfo{
+		ID:              template.ID,
+		Description:     template.Description,
+		DefaultLanguage: template.DefaultLanguage,
+		Variables:       make([]domainTemplate.Variable, 0, len(template.Variables)),
+		Variants:        make([]domainTemplate.Variant, 0, len(template.Variants)),
+		CreatedAt:       template.CreatedAt.Format(time.RFC3339),
+		UpdatedAt:       template.UpdatedAt.Format(time.RFC3339),
+	}
+
+	for _, variable := range template.Variables {
+		info.Variables = append(info.Variables, domainTemplate.Variable{
+			Name:     variable.Name,
+			Type:     variable.Type,
+			Required: variable.Required,
+			Default:  variable.Default,
+		})
+	}
+	for _, variant := range template.Variants {
+		info.Variants = append(info.Variants, domainTemplate.Variant{
+			Language: variant.Language,
+			Body:     variant.Body,
+		})
+	}
+
+	return info
+}
@@ -0,0 +1,76 @@
+package usecase
+
+import (
+	"strings"
+	"testing"
+
+	domainChatStorage "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/chatstorage"
+)
+
+func newTestTemplate() *domainChatStorage.MessageTemplate {
+	return &domainChatStorage.MessageTemplate{
+		ID:              "order_shipped",
+		DefaultLanguage: "en",
+		Variables: []domainChatStorage.TemplateVariable{
+			{Name: "name", Type: domainChatStorage.TemplateVarText, Required: true},
+			{Name: "order_id", Type: domainChatStorage.TemplateVarNumber, Required: true},
+			{Name: "carrier", Type: domainChatStorage.TemplateVarText, Default: "our courier"},
+		},
+		Variants: []domainChatStorage.TemplateVariant{
+			{Language: "en", Body: "Hi {{name}}, order #{{order_id}} was handed to {{carrier}}."},
+			{Language: "pt-BR", Body: "Olá {{name}}, o pedido #{{order_id}} foi entregue a {{carrier}}."},
+		},
+	}
+}
+
+func TestSelectTemplateVariant(t *testing.T) {
+	tests := []struct {
+		name     string
+		language string
+		want     string
+	}{
+		{name: "Exact match", language: "pt-BR", want: "pt-BR"},
+		{name: "Case insensitive", language: "PT-br", want: "pt-BR"},
+		{name: "Base language", language: "pt", want: "pt-BR"},
+		{name: "Regional variant of base language", language: "en-GB", want: "en"},
+		{name: "Unknown falls back to default", language: "de", want: "en"},
+		{name: "Empty uses default", language: "", want: "en"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := selectTemplateVariant(newTestTemplate(), tt.language); got.Language != tt.want {
+				t.Fatalf("selectTemplateVariant() = %q, want %q", got.Language, tt.want)
+			}
+		})
+	}
+}
+
+func TestRenderMessageTemplate(t *testing.T) {
+	t.Run("Uses defaults for omitted variables", func(t *testing.T) {
+		message, language, err := renderMessageTemplate(newTestTemplate(), "pt", map[string]string{"name": "Ana", "order_id": "42"})
+		if err != nil {
+			t.Fatalf("renderMessageTemplate() error = %v", err)
+		}
+		if language != "pt-BR" {
+			t.Fatalf("unexpected language %q", language)
+		}
+		if message != "Olá Ana, o pedido #42 foi entregue a our courier." {
+			t.Fatalf("unexpected message %q", message)
+		}
+	})
+
+	t.Run("Rejects missing required variables", func(t *testing.T) {
+		_, _, err := renderMessageTemplate(newTestTemplate(), "en", map[string]string{"carrier": "DHL"})
+		if err == nil || !strings.Contains(err.Error(), "missing required variables: name, order_id") {
+			t.Fatalf("expected missing variables error, got %v", err)
+		}
+	})
+
+	t.Run("Rejects values of the wrong type", func(t *testing.T) {
+		_, _, err := renderMessageTemplate(newTestTemplate(), "en", map[string]string{"name": "Ana", "order_id": "abc"})
+		if err == nil || !strings.Contains(err.Error(), "order_id: must be a number") {
+			t.Fatalf("expected type error, got %v", err)
+		}
+	})
+}
@@ -8,6 +8,7 @@ import (
 	"image"
 	"time"
 
+	domainChatStorage "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/chatstorage"
 	domainUser "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/user"
 	"github.com/aldinokemal/go-whatsapp-web-multidevice/infrastructure/whatsapp"
 	pkgError "github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/error"
@@ -20,11 +21,13 @@ import (
 )
 
 type serviceUser struct {
-	// Remove the WaCli field - we'll use the global client instead
+	chatStorageRepo domainChatStorage.IChatStorageRepository
 }
 
-func NewUserService() domainUser.IUserUsecase {
-	return &serviceUser{}
+func NewUserService(chatStorageRepo domainChatStorage.IChatStorageRepository) domainUser.IUserUsecase {
+	return &serviceUser{
+		chatStorageRepo: chatStorageRepo,
+	}
 }
 
 func (service serviceUser) Info(ctx context.Context, request domainUser.InfoRequest) (response domainUser.InfoResponse, err error) {
@@ -126,6 +129,8 @@ func (service serviceUser) MyListGroups(ctx context.Context) (response domainUse
 	}
 
 	for _, group := range groups {
+		// Keep the stored group members current with the fresh list
+		whatsapp.SyncGroupInfo(ctx, service.chatStorageRepo, group)
 		response.Data = append(response.Data, *group)
 	}
 	return response, nil
@@ -303,3 +308,54 @@ func (service serviceUser) BusinessProfile(ctx context.Context, request domainUs
 
 	return response, nil
 }
+
+func (service serviceUser) BusinessCatalog(ctx context.Context, request domainUser.BusinessCatalogRequest) (response domainUser.BusinessCatalogResponse, err error) {
+	err = validations.ValidateBusinessCatalog(ctx, request)
+	if err != nil {
+		return response, err
+	}
+
+	dataWaRecipient, err := utils.ValidateJidWithLogin(whatsapp.GetClient(), request.Phone)
+	if err != nil {
+		return response, err
+	}
+
+	if request.Limit == 0 {
+		request.Limit = 10
+	}
+
+	products, cursor, err := whatsapp.GetBusinessCatalog(ctx, whatsapp.GetClient(), dataWaRecipient, request.Limit, request.Cursor)
+	if err != nil {
+		return response, err
+	}
+
+	response.JID = dataWaRecipient.String()
+	response.Products = products
+	response.Cursor = cursor
+	return response, nil
+}
+
+func (service serviceUser) BusinessCollections(ctx context.Context, request domainUser.BusinessCollectionsRequest) (response domainUser.BusinessCollectionsResponse, err error) {
+	err = validations.ValidateBusinessCollections(ctx, request)
+	if err != nil {
+		return response, err
+	}
+
+	dataWaRecipient, err := utils.ValidateJidWithLogin(whatsapp.GetClient(), request.Phone)
+	if err != nil {
+		return response, err
+	}
+
+	if request.Limit == 0 {
+		request.Limit = 10
+	}
+
+	collections, err := whatsapp.GetBusinessCollections(ctx, whatsapp.GetClient(), dataWaRecipient, request.Limit)
+	if err != nil {
+		return response, err
+	}
+
+	response.JID = dataWaRecipient.String()
+	response.Collections = collections
+	return response, nil
+}
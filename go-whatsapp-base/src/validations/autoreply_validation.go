@@ -0,0 +1,116 @@
+package validations
+
+import (
+	"context"
+	"errors"
+	"regexp"
+
+	domainAutoReply "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/autoreply"
+	domainChatStorage "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/chatstorage"
+	pkgError "github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/error"
+	validation "github.com/go-ozzo/ozzo-validation/v4"
+	"github.com/go-ozzo/ozzo-validation/v4/is"
+)
+
+func ValidateAutoReplyRule(ctx context.Context, request *domainAutoReply.RuleRequest) error {
+	// Set defaults if not provided
+	if request.ChatType == "" {
+		request.ChatType = domainChatStorage.AutoReplyChatPrivate
+	}
+	if request.ResponseType == "" {
+		request.ResponseType = domainChatStorage.AutoReplyResponseText
+	}
+
+	isMedia := request.ResponseType == domainChatStorage.AutoReplyResponseImage ||
+		request.ResponseType == domainChatStorage.AutoReplyResponseVideo
+
+	err := validation.ValidateStructWithContext(ctx, request,
+		validation.Field(&request.Name, validation.Required),
+		validation.Field(&request.ChatType, validation.In(
+			domainChatStorage.AutoReplyChatPrivate,
+			domainChatStorage.AutoReplyChatGroup,
+			domainChatStorage.AutoReplyChatAll,
+		)),
+		validation.Field(&request.ResponseType, validation.In(
+			domainChatStorage.AutoReplyResponseText,
+			domainChatStorage.AutoReplyResponseTemplate,
+			domainChatStorage.AutoReplyResponseImage,
+			domainChatStorage.AutoReplyResponseVideo,
+		)),
+		validation.Field(&request.ResponseText, validation.When(!isMedia, validation.Required)),
+		validation.Field(&request.MediaURL, validation.When(isMedia, validation.Required, is.URL)),
+		validation.Field(&request.Pattern, validation.By(validateRegexPattern)),
+		validation.Field(&request.Keywords, validation.Each(validation.Required)),
+		validation.Field(&request.Senders, validation.Each(validation.Required)),
+		validation.Field(&request.Priority, validation.Min(0)),
+		validation.Field(&request.CooldownSeconds, validation.Min(0)),
+	)
+
+	if err != nil {
+		return pkgError.ValidationError(err.Error())
+	}
+
+	return nil
+}
+
+func ValidateUpdateAutoReplyRule(ctx context.Context, request *domainAutoReply.RuleRequest) error {
+	if request.RuleID <= 0 {
+		return pkgError.ValidationError("rule_id: cannot be blank.")
+	}
+
+	return ValidateAutoReplyRule(ctx, request)
+}
+
+func ValidateGetAutoReplyRule(ctx context.Context, request domainAutoReply.GetRuleRequest) error {
+	err := validation.ValidateStructWithContext(ctx, &request,
+		validation.Field(&request.RuleID, validation.Required, validation.Min(int64(1))),
+	)
+
+	if err != nil {
+		return pkgError.ValidationError(err.Error())
+	}
+
+	return nil
+}
+
+func ValidateDeleteAutoReplyRule(ctx context.Context, request domainAutoReply.DeleteRuleRequest) error {
+	err := validation.ValidateStructWithContext(ctx, &request,
+		validation.Field(&request.RuleID, validation.Required, validation.Min(int64(1))),
+	)
+
+	if err != nil {
+		return pkgError.ValidationError(err.Error())
+	}
+
+	return nil
+}
+
+func ValidateListAutoReplyHits(ctx context.Context, request *domainAutoReply.ListHitsRequest) error {
+	// Set default limit if not provided
+	if request.Limit == 0 {
+		request.Limit = 50
+	}
+
+	err := validation.ValidateStructWithContext(ctx, request,
+		validation.Field(&request.RuleID, validation.Min(int64(0))),
+		validation.Field(&request.Limit, validation.Min(1), validation.Max(100)),
+		validation.Field(&request.Offset, validation.Min(0)),
+	)
+
+	if err != nil {
+		return pkgError.ValidationError(err.Error())
+	}
+
+	return nil
+}
+
+func validateRegexPattern(value any) error {
+	pattern, _ := value.(string)
+	if pattern == "" {
+		return nil
+	}
+	if _, err := regexp.Compile(pattern); err != nil {
+		return errors.New("must be a valid regular expression")
+	}
+	return nil
+}
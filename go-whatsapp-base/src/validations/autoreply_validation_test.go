@@ -0,0 +1,102 @@
+package validations
+
+import (
+	"context"
+	"testing"
+
+	domainAutoReply "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/autoreply"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidateAutoReplyRule(t *testing.T) {
+	type args struct {
+		request domainAutoReply.RuleRequest
+	}
+	tests := []struct {
+		name        string
+		args        args
+		errContains []string
+	}{
+		{
+			name: "should success with keyword text rule",
+			args: args{request: domainAutoReply.RuleRequest{
+				Name:         "pricing",
+				Keywords:     []string{"price", "cost"},
+				ResponseText: "Our price list: https://example.com/prices",
+			}},
+		},
+		{
+			name: "should success with image rule without text",
+			args: args{request: domainAutoReply.RuleRequest{
+				Name:         "menu",
+				Pattern:      "(?i)^menu$",
+				ResponseType: "image",
+				MediaURL:     "https://example.com/menu.jpg",
+			}},
+		},
+		{
+			name: "should error with empty name and response text",
+			args: args{request: domainAutoReply.RuleRequest{
+				Keywords: []string{"hello"},
+			}},
+			errContains: []string{"name: cannot be blank", "response_text: cannot be blank"},
+		},
+		{
+			name: "should error with invalid pattern",
+			args: args{request: domainAutoReply.RuleRequest{
+				Name:         "broken",
+				Pattern:      "([a-z",
+				ResponseText: "hi",
+			}},
+			errContains: []string{"pattern: must be a valid regular expression"},
+		},
+		{
+			name: "should error with unknown chat type",
+			args: args{request: domainAutoReply.RuleRequest{
+				Name:         "broken",
+				ChatType:     "channel",
+				ResponseText: "hi",
+			}},
+			errContains: []string{"chat_type: must be a valid value"},
+		},
+		{
+			name: "should error with media rule without url",
+			args: args{request: domainAutoReply.RuleRequest{
+				Name:         "video",
+				ResponseType: "video",
+			}},
+			errContains: []string{"media_url: cannot be blank"},
+		},
+		{
+			name: "should error with negative cooldown",
+			args: args{request: domainAutoReply.RuleRequest{
+				Name:            "cooldown",
+				ResponseText:    "hi",
+				CooldownSeconds: -1,
+			}},
+			errContains: []string{"cooldown_seconds: must be no less than 0"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateAutoReplyRule(context.Background(), &tt.args.request)
+			if len(tt.errContains) == 0 {
+				assert.NoError(t, err)
+			} else {
+				assert.Error(t, err)
+				for _, errContain := range tt.errContains {
+					assert.Contains(t, err.Error(), errContain)
+				}
+			}
+		})
+	}
+}
+
+func TestValidateAutoReplyRuleDefaults(t *testing.T) {
+	request := domainAutoReply.RuleRequest{Name: "default", ResponseText: "hi"}
+	err := ValidateAutoReplyRule(context.Background(), &request)
+	assert.NoError(t, err)
+	assert.Equal(t, "private", request.ChatType)
+	assert.Equal(t, "text", request.ResponseType)
+}
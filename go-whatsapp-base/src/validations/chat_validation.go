@@ -56,3 +56,33 @@ func ValidatePinChat(ctx context.Context, request *domainChat.PinChatRequest) er
 
 	return nil
 }
+
+func ValidateBackfillChat(ctx context.Context, request *domainChat.BackfillChatRequest) error {
+	// Set default count if not provided
+	if request.Count == 0 {
+		request.Count = 50
+	}
+
+	err := validation.ValidateStructWithContext(ctx, request,
+		validation.Field(&request.ChatJID, validation.Required),
+		validation.Field(&request.Count, validation.Min(1), validation.Max(500)),
+	)
+
+	if err != nil {
+		return pkgError.ValidationError(err.Error())
+	}
+
+	return nil
+}
+
+func ValidateGetChatBackfill(ctx context.Context, request *domainChat.GetChatBackfillRequest) error {
+	err := validation.ValidateStructWithContext(ctx, request,
+		validation.Field(&request.ChatJID, validation.Required),
+	)
+
+	if err != nil {
+		return pkgError.ValidationError(err.Error())
+	}
+
+	return nil
+}
@@ -183,3 +183,49 @@ func TestValidatePinChat(t *testing.T) {
 		})
 	}
 }
+
+func TestValidateBackfillChat(t *testing.T) {
+	type args struct {
+		request domainChat.BackfillChatRequest
+	}
+	tests := []struct {
+		name  string
+		args  args
+		err   any
+		count int
+	}{
+		{
+			name: "should success and default count",
+			args: args{request: domainChat.BackfillChatRequest{
+				ChatJID: "6289685028129@s.whatsapp.net",
+			}},
+			err:   nil,
+			count: 50,
+		},
+		{
+			name: "should error with empty chat_jid",
+			args: args{request: domainChat.BackfillChatRequest{
+				Count: 10,
+			}},
+			err:   pkgError.ValidationError("chat_jid: cannot be blank."),
+			count: 10,
+		},
+		{
+			name: "should error with count above maximum",
+			args: args{request: domainChat.BackfillChatRequest{
+				ChatJID: "6289685028129@s.whatsapp.net",
+				Count:   501,
+			}},
+			err:   pkgError.ValidationError("count: must be no greater than 500."),
+			count: 501,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateBackfillChat(context.Background(), &tt.args.request)
+			assert.Equal(t, tt.err, err)
+			assert.Equal(t, tt.count, tt.args.request.Count)
+		})
+	}
+}
@@ -0,0 +1,73 @@
+package validations
+
+import (
+	"context"
+	"path/filepath"
+	"strings"
+
+	domainContact "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/contact"
+	pkgError "github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/error"
+	validation "github.com/go-ozzo/ozzo-validation/v4"
+)
+
+func ValidateListContacts(ctx context.Context, request *domainContact.ListContactsRequest) error {
+	// Set default limit if not provided
+	if request.Limit == 0 {
+		request.Limit = 25
+	}
+
+	err := validation.ValidateStructWithContext(ctx, request,
+		validation.Field(&request.Limit, validation.Min(1), validation.Max(100)),
+		validation.Field(&request.Offset, validation.Min(0)),
+	)
+
+	if err != nil {
+		return pkgError.ValidationError(err.Error())
+	}
+
+	return nil
+}
+
+func ValidateImportContacts(ctx context.Context, request *domainContact.ImportContactsRequest) error {
+	if request.File == nil {
+		return pkgError.ValidationError("file: cannot be blank.")
+	}
+
+	// Detect the format from the file name when it isn't given
+	if request.Format == "" {
+		switch strings.ToLower(filepath.Ext(request.File.Filename)) {
+		case ".vcf", ".vcard":
+			request.Format = domainContact.FormatVCard
+		case ".csv":
+			request.Format = domainContact.FormatCSV
+		}
+	}
+	request.Format = strings.ToLower(request.Format)
+
+	err := validation.ValidateStructWithContext(ctx, request,
+		validation.Field(&request.Format, validation.Required, validation.In(domainContact.FormatVCard, domainContact.FormatCSV)),
+	)
+
+	if err != nil {
+		return pkgError.ValidationError(err.Error())
+	}
+
+	return nil
+}
+
+func ValidateExportContacts(ctx context.Context, request *domainContact.ExportContactsRequest) error {
+	if request.Format == "" {
+		request.Format = domainContact.FormatVCard
+	}
+	request.Format = strings.ToLower(request.Format)
+
+	err := validation.ValidateStructWithContext(ctx, request,
+		validation.Field(&request.Format, validation.In(domainContact.FormatVCard, domainContact.FormatCSV)),
+	)
+
+	if err != nil {
+		return pkgError.ValidationError(err.Error())
+	}
+
+	return nil
+}
@@ -0,0 +1,110 @@
+package validations
+
+import (
+	"context"
+	"mime/multipart"
+	"testing"
+
+	domainContact "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/contact"
+	pkgError "github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/error"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidateListContacts(t *testing.T) {
+	tests := []struct {
+		name      string
+		request   domainContact.ListContactsRequest
+		wantLimit int
+		err       any
+	}{
+		{
+			name:      "should default limit",
+			request:   domainContact.ListContactsRequest{},
+			wantLimit: 25,
+			err:       nil,
+		},
+		{
+			name:      "should success with max limit",
+			request:   domainContact.ListContactsRequest{Limit: 100, Search: "john"},
+			wantLimit: 100,
+			err:       nil,
+		},
+		{
+			name:      "should error with limit over max",
+			request:   domainContact.ListContactsRequest{Limit: 101},
+			wantLimit: 101,
+			err:       pkgError.ValidationError("limit: must be no greater than 100."),
+		},
+		{
+			name:      "should error with negative offset",
+			request:   domainContact.ListContactsRequest{Limit: 10, Offset: -1},
+			wantLimit: 10,
+			err:       pkgError.ValidationError("offset: must be no less than 0."),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateListContacts(context.Background(), &tt.request)
+			assert.Equal(t, tt.err, err)
+			assert.Equal(t, tt.wantLimit, tt.request.Limit)
+		})
+	}
+}
+
+func TestValidateImportContacts(t *testing.T) {
+	tests := []struct {
+		name       string
+		request    domainContact.ImportContactsRequest
+		wantFormat string
+		err        any
+	}{
+		{
+			name:       "should detect vcard from file name",
+			request:    domainContact.ImportContactsRequest{File: &multipart.FileHeader{Filename: "contacts.VCF"}},
+			wantFormat: domainContact.FormatVCard,
+			err:        nil,
+		},
+		{
+			name:       "should accept explicit csv format",
+			request:    domainContact.ImportContactsRequest{File: &multipart.FileHeader{Filename: "export.txt"}, Format: "CSV"},
+			wantFormat: domainContact.FormatCSV,
+			err:        nil,
+		},
+		{
+			name:    "should error without file",
+			request: domainContact.ImportContactsRequest{Format: domainContact.FormatCSV},
+			err:     pkgError.ValidationError("file: cannot be blank."),
+		},
+		{
+			name:    "should error when format cannot be detected",
+			request: domainContact.ImportContactsRequest{File: &multipart.FileHeader{Filename: "contacts.txt"}},
+			err:     pkgError.ValidationError("format: cannot be blank."),
+		},
+		{
+			name:       "should error with unknown format",
+			request:    domainContact.ImportContactsRequest{File: &multipart.FileHeader{Filename: "contacts"}, Format: "xml"},
+			wantFormat: "xml",
+			err:        pkgError.ValidationError("format: must be a valid value."),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateImportContacts(context.Background(), &tt.request)
+			assert.Equal(t, tt.err, err)
+			if tt.wantFormat != "" {
+				assert.Equal(t, tt.wantFormat, tt.request.Format)
+			}
+		})
+	}
+}
+
+func TestValidateExportContacts(t *testing.T) {
+	request := domainContact.ExportContactsRequest{}
+	assert.Nil(t, ValidateExportContacts(context.Background(), &request))
+	assert.Equal(t, domainContact.FormatVCard, request.Format)
+
+	request = domainContact.ExportContactsRequest{Format: "pdf"}
+	assert.Equal(t, pkgError.ValidationError("format: must be a valid value."), ValidateExportContacts(context.Background(), &request))
+}
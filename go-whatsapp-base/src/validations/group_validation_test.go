@@ -706,3 +706,175 @@ func TestValidateGroupInfo(t *testing.T) {
 		})
 	}
 }
+
+func TestValidateCreateCommunity(t *testing.T) {
+	type args struct {
+		request domainGroup.CreateCommunityRequest
+	}
+	tests := []struct {
+		name string
+		args args
+		err  any
+	}{
+		{
+			name: "should success without participants",
+			args: args{request: domainGroup.CreateCommunityRequest{
+				Title: "Neighbourhood",
+			}},
+			err: nil,
+		},
+		{
+			name: "should error with empty title",
+			args: args{request: domainGroup.CreateCommunityRequest{
+				Participants: []string{"6281234567890"},
+			}},
+			err: pkgError.ValidationError("title: cannot be blank."),
+		},
+		{
+			name: "should error with blank participant",
+			args: args{request: domainGroup.CreateCommunityRequest{
+				Title:        "Neighbourhood",
+				Participants: []string{""},
+			}},
+			err: pkgError.ValidationError("participants: (0: cannot be blank.)."),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateCreateCommunity(context.Background(), tt.args.request)
+			assert.Equal(t, tt.err, err)
+		})
+	}
+}
+
+func TestValidateCommunityGroups(t *testing.T) {
+	type args struct {
+		request domainGroup.CommunityGroupsRequest
+	}
+	tests := []struct {
+		name string
+		args args
+		err  any
+	}{
+		{
+			name: "should success with community and groups",
+			args: args{request: domainGroup.CommunityGroupsRequest{
+				CommunityID: "120363025246125244@g.us",
+				GroupIDs:    []string{"120363025246125245@g.us"},
+			}},
+			err: nil,
+		},
+		{
+			name: "should error with empty community id",
+			args: args{request: domainGroup.CommunityGroupsRequest{
+				GroupIDs: []string{"120363025246125245@g.us"},
+			}},
+			err: pkgError.ValidationError("community_id: cannot be blank."),
+		},
+		{
+			name: "should error with empty group ids",
+			args: args{request: domainGroup.CommunityGroupsRequest{
+				CommunityID: "120363025246125244@g.us",
+			}},
+			err: pkgError.ValidationError("group_ids: cannot be blank."),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateCommunityGroups(context.Background(), tt.args.request)
+			assert.Equal(t, tt.err, err)
+		})
+	}
+}
+
+func TestValidateGroupHistory(t *testing.T) {
+	tests := []struct {
+		name      string
+		request   domainGroup.GroupHistoryRequest
+		wantLimit int
+		err       any
+	}{
+		{
+			name:      "should success and default limit",
+			request:   domainGroup.GroupHistoryRequest{GroupID: "120363025246125244@g.us"},
+			wantLimit: 50,
+			err:       nil,
+		},
+		{
+			name: "should success with filters",
+			request: domainGroup.GroupHistoryRequest{
+				GroupID: "120363025246125244@g.us",
+				Action:  "leave",
+				Since:   "2025-01-01T00:00:00Z",
+				Until:   "2025-02-01T00:00:00+07:00",
+				Limit:   100,
+			},
+			wantLimit: 100,
+			err:       nil,
+		},
+		{
+			name:      "should error with empty group id",
+			request:   domainGroup.GroupHistoryRequest{},
+			wantLimit: 50,
+			err:       pkgError.ValidationError("group_id: cannot be blank."),
+		},
+		{
+			name:      "should error with unknown action",
+			request:   domainGroup.GroupHistoryRequest{GroupID: "120363025246125244@g.us", Action: "kick"},
+			wantLimit: 50,
+			err:       pkgError.ValidationError("action: must be a valid value."),
+		},
+		{
+			name:      "should error with invalid since",
+			request:   domainGroup.GroupHistoryRequest{GroupID: "120363025246125244@g.us", Since: "2025-01-01"},
+			wantLimit: 50,
+			err:       pkgError.ValidationError("since: must be a valid date."),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateGroupHistory(context.Background(), &tt.request)
+			assert.Equal(t, tt.err, err)
+			assert.Equal(t, tt.wantLimit, tt.request.Limit)
+		})
+	}
+}
+
+func TestValidateGroupMembers(t *testing.T) {
+	tests := []struct {
+		name    string
+		request domainGroup.GroupMembersRequest
+		err     any
+	}{
+		{
+			name:    "should success without as_of",
+			request: domainGroup.GroupMembersRequest{GroupID: "120363025246125244@g.us"},
+			err:     nil,
+		},
+		{
+			name:    "should success with as_of",
+			request: domainGroup.GroupMembersRequest{GroupID: "120363025246125244@g.us", AsOf: "2025-01-01T00:00:00Z"},
+			err:     nil,
+		},
+		{
+			name:    "should error with invalid as_of",
+			request: domainGroup.GroupMembersRequest{GroupID: "120363025246125244@g.us", AsOf: "yesterday"},
+			err:     pkgError.ValidationError("as_of: must be a valid date."),
+		},
+		{
+			name:    "should error with empty group id",
+			request: domainGroup.GroupMembersRequest{},
+			err:     pkgError.ValidationError("group_id: cannot be blank."),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateGroupMembers(context.Background(), tt.request)
+			assert.Equal(t, tt.err, err)
+		})
+	}
+}
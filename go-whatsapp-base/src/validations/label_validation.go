@@ -0,0 +1,80 @@
+package validations
+
+import (
+	"context"
+
+	domainLabel "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/label"
+	pkgError "github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/error"
+	validation "github.com/go-ozzo/ozzo-validation/v4"
+)
+
+func ValidateCreateLabel(ctx context.Context, request *domainLabel.LabelRequest) error {
+	// Set default color if not provided
+	if request.Color == nil {
+		color := int32(0)
+		request.Color = &color
+	}
+
+	err := validation.ValidateStructWithContext(ctx, request,
+		validation.Field(&request.Name, validation.Required, validation.Length(1, 100)),
+		validation.Field(&request.Color, validation.Min(int32(0)), validation.Max(int32(domainLabel.MaxColor))),
+	)
+	if err != nil {
+		return pkgError.ValidationError(err.Error())
+	}
+
+	return nil
+}
+
+func ValidateUpdateLabel(ctx context.Context, request *domainLabel.LabelRequest) error {
+	err := validation.ValidateStructWithContext(ctx, request,
+		validation.Field(&request.LabelID, validation.Required),
+		validation.Field(&request.Name, validation.Length(1, 100)),
+		validation.Field(&request.Color, validation.Min(int32(0)), validation.Max(int32(domainLabel.MaxColor))),
+	)
+	if err != nil {
+		return pkgError.ValidationError(err.Error())
+	}
+
+	if request.Name == "" && request.Color == nil {
+		return pkgError.ValidationError("name: name or color must be provided.")
+	}
+
+	return nil
+}
+
+func ValidateDeleteLabel(ctx context.Context, request *domainLabel.DeleteLabelRequest) error {
+	err := validation.ValidateStructWithContext(ctx, request,
+		validation.Field(&request.LabelID, validation.Required),
+	)
+	if err != nil {
+		return pkgError.ValidationError(err.Error())
+	}
+
+	return nil
+}
+
+func ValidateLabelChat(ctx context.Context, request *domainLabel.ChatLabelRequest) error {
+	err := validation.ValidateStructWithContext(ctx, request,
+		validation.Field(&request.ChatJID, validation.Required),
+		validation.Field(&request.LabelID, validation.Required),
+	)
+	if err != nil {
+		return pkgError.ValidationError(err.Error())
+	}
+
+	return nil
+}
+
+func ValidateLabelMessage(ctx context.Context, request *domainLabel.MessageLabelRequest) error {
+	err := validation.ValidateStructWithContext(ctx, request,
+		validation.Field(&request.MessageID, validation.Required),
+		validation.Field(&request.Phone, validation.Required),
+		validation.Field(&request.LabelID, validation.Required),
+	)
+	if err != nil {
+		return pkgError.ValidationError(err.Error())
+	}
+
+	return nil
+}
@@ -0,0 +1,140 @@
+package validations
+
+import (
+	"context"
+	"testing"
+
+	domainLabel "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/label"
+	pkgError "github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/error"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidateCreateLabel(t *testing.T) {
+	tests := []struct {
+		name      string
+		request   domainLabel.LabelRequest
+		wantColor int32
+		err       any
+	}{
+		{
+			name:      "should default color",
+			request:   domainLabel.LabelRequest{Name: "New lead"},
+			wantColor: 0,
+			err:       nil,
+		},
+		{
+			name:      "should success with max color",
+			request:   domainLabel.LabelRequest{Name: "Paid", Color: func() *int32 { v := int32(19); return &v }()},
+			wantColor: 19,
+			err:       nil,
+		},
+		{
+			name:      "should error without name",
+			request:   domainLabel.LabelRequest{},
+			wantColor: 0,
+			err:       pkgError.ValidationError("name: cannot be blank."),
+		},
+		{
+			name:      "should error with color over max",
+			request:   domainLabel.LabelRequest{Name: "Paid", Color: func() *int32 { v := int32(20); return &v }()},
+			wantColor: 20,
+			err:       pkgError.ValidationError("color: must be no greater than 19."),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateCreateLabel(context.Background(), &tt.request)
+			assert.Equal(t, tt.err, err)
+			assert.Equal(t, tt.wantColor, *tt.request.Color)
+		})
+	}
+}
+
+func TestValidateUpdateLabel(t *testing.T) {
+	tests := []struct {
+		name    string
+		request domainLabel.LabelRequest
+		err     any
+	}{
+		{
+			name:    "should success with name only",
+			request: domainLabel.LabelRequest{LabelID: "3", Name: "Follow up"},
+			err:     nil,
+		},
+		{
+			name:    "should success with color only",
+			request: domainLabel.LabelRequest{LabelID: "3", Color: func() *int32 { v := int32(0); return &v }()},
+			err:     nil,
+		},
+		{
+			name:    "should error without changes",
+			request: domainLabel.LabelRequest{LabelID: "3"},
+			err:     pkgError.ValidationError("name: name or color must be provided."),
+		},
+		{
+			name:    "should error without label id",
+			request: domainLabel.LabelRequest{Name: "Follow up"},
+			err:     pkgError.ValidationError("label_id: cannot be blank."),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateUpdateLabel(context.Background(), &tt.request)
+			assert.Equal(t, tt.err, err)
+		})
+	}
+}
+
+func TestValidateLabelChat(t *testing.T) {
+	tests := []struct {
+		name    string
+		request domainLabel.ChatLabelRequest
+		err     any
+	}{
+		{
+			name:    "should success",
+			request: domainLabel.ChatLabelRequest{ChatJID: "6281234567890@s.whatsapp.net", LabelID: "1", Labeled: true},
+			err:     nil,
+		},
+		{
+			name:    "should error without label id",
+			request: domainLabel.ChatLabelRequest{ChatJID: "6281234567890@s.whatsapp.net"},
+			err:     pkgError.ValidationError("label_id: cannot be blank."),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateLabelChat(context.Background(), &tt.request)
+			assert.Equal(t, tt.err, err)
+		})
+	}
+}
+
+func TestValidateLabelMessage(t *testing.T) {
+	tests := []struct {
+		name    string
+		request domainLabel.MessageLabelRequest
+		err     any
+	}{
+		{
+			name:    "should success",
+			request: domainLabel.MessageLabelRequest{MessageID: "3EB0ABC", Phone: "6281234567890", LabelID: "1"},
+			err:     nil,
+		},
+		{
+			name:    "should error without phone",
+			request: domainLabel.MessageLabelRequest{MessageID: "3EB0ABC", LabelID: "1"},
+			err:     pkgError.ValidationError("phone: cannot be blank."),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateLabelMessage(context.Background(), &tt.request)
+			assert.Equal(t, tt.err, err)
+		})
+	}
+}
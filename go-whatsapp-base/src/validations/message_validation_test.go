@@ -291,3 +291,68 @@ func TestValidateStarMessage(t *testing.T) {
 		})
 	}
 }
+
+func TestValidateForwardMessage(t *testing.T) {
+	type args struct {
+		request domainMessage.ForwardMessageRequest
+	}
+	tests := []struct {
+		name string
+		args args
+		err  any
+	}{
+		{
+			name: "should success with valid phone, message id and targets",
+			args: args{request: domainMessage.ForwardMessageRequest{
+				Phone:     "6281234567890@s.whatsapp.net",
+				MessageID: "3EB0789ABC123456",
+				Targets:   []string{"6289876543210@s.whatsapp.net", "120363025246125888@g.us"},
+			}},
+			err: nil,
+		},
+		{
+			name: "should error with empty targets",
+			args: args{request: domainMessage.ForwardMessageRequest{
+				Phone:     "6281234567890@s.whatsapp.net",
+				MessageID: "3EB0789ABC123456",
+			}},
+			err: pkgError.ValidationError("targets: cannot be blank."),
+		},
+		{
+			name: "should error with blank target entry",
+			args: args{request: domainMessage.ForwardMessageRequest{
+				Phone:     "6281234567890@s.whatsapp.net",
+				MessageID: "3EB0789ABC123456",
+				Targets:   []string{"6289876543210@s.whatsapp.net", ""},
+			}},
+			err: pkgError.ValidationError("targets: (1: cannot be blank.)."),
+		},
+		{
+			name: "should error with empty message id",
+			args: args{request: domainMessage.ForwardMessageRequest{
+				Phone:   "6281234567890@s.whatsapp.net",
+				Targets: []string{"6289876543210@s.whatsapp.net"},
+			}},
+			err: pkgError.ValidationError("message_id: cannot be blank."),
+		},
+		{
+			name: "should error with empty phone",
+			args: args{request: domainMessage.ForwardMessageRequest{
+				MessageID: "3EB0789ABC123456",
+				Targets:   []string{"6289876543210@s.whatsapp.net"},
+			}},
+			err: pkgError.ValidationError("phone: cannot be blank."),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateForwardMessage(context.Background(), tt.args.request)
+			if tt.err == nil {
+				assert.NoError(t, err)
+			} else {
+				assert.Equal(t, tt.err, err)
+			}
+		})
+	}
+}
@@ -4,8 +4,10 @@ import (
 	"context"
 	"fmt"
 	"sort"
+	"strings"
 
 	"github.com/aldinokemal/go-whatsapp-web-multidevice/config"
+	domainChatStorage "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/chatstorage"
 	domainSend "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/send"
 	pkgError "github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/error"
 	"github.com/dustin/go-humanize"
@@ -29,6 +31,24 @@ func validateDuration(dur *int) error {
 	return nil
 }
 
+// validateMentions rejects blank entries in the optional mentions list
+func validateMentions(mentions []string) error {
+	for _, mention := range mentions {
+		if strings.TrimSpace(mention) == "" {
+			return pkgError.ValidationError("mentions: cannot contain blank entries.")
+		}
+	}
+	return nil
+}
+
+// validateQueueTTL accepts a missing or positive queue expiry in seconds
+func validateQueueTTL(ttl *int) error {
+	if ttl != nil && *ttl <= 0 {
+		return pkgError.ValidationError("queue_ttl: must be a positive number of seconds.")
+	}
+	return nil
+}
+
 // validatePhoneNumber validates that the phone number is in international format (not starting with 0)
 func validatePhoneNumber(phone string) error {
 	if phone == "" {
@@ -52,7 +72,7 @@ func validatePhoneNumber(phone string) error {
 func ValidateSendMessage(ctx context.Context, request domainSend.MessageRequest) error {
 	err := validation.ValidateStructWithContext(ctx, &request,
 		validation.Field(&request.Phone, validation.Required),
-		validation.Field(&request.Message, validation.Required),
+		validation.Field(&request.Message, validation.When(request.TemplateID == "", validation.Required)),
 	)
 
 	if err != nil {
@@ -68,6 +88,14 @@ func ValidateSendMessage(ctx context.Context, request domainSend.MessageRequest)
 	if err := validateDuration(request.Duration); err != nil {
 		return err
 	}
+
+	if err := validateMentions(request.Mentions); err != nil {
+		return err
+	}
+
+	if err := validateQueueTTL(request.QueueTTL); err != nil {
+		return err
+	}
 	return nil
 }
 
@@ -117,6 +145,14 @@ func ValidateSendImage(ctx context.Context, request domainSend.ImageRequest) err
 		return err
 	}
 
+	if err := validateMentions(request.Mentions); err != nil {
+		return err
+	}
+
+	if err := validateQueueTTL(request.QueueTTL); err != nil {
+		return err
+	}
+
 	return nil
 }
 
@@ -172,6 +208,14 @@ func ValidateSendSticker(ctx context.Context, request domainSend.StickerRequest)
 		return err
 	}
 
+	if err := validateMentions(request.Mentions); err != nil {
+		return err
+	}
+
+	if err := validateQueueTTL(request.QueueTTL); err != nil {
+		return err
+	}
+
 	return nil
 }
 
@@ -199,6 +243,14 @@ func ValidateSendFile(ctx context.Context, request domainSend.FileRequest) error
 		return err
 	}
 
+	if err := validateMentions(request.Mentions); err != nil {
+		return err
+	}
+
+	if err := validateQueueTTL(request.QueueTTL); err != nil {
+		return err
+	}
+
 	return nil
 }
 
@@ -256,6 +308,14 @@ func ValidateSendVideo(ctx context.Context, request domainSend.VideoRequest) err
 		return err
 	}
 
+	if err := validateMentions(request.Mentions); err != nil {
+		return err
+	}
+
+	if err := validateQueueTTL(request.QueueTTL); err != nil {
+		return err
+	}
+
 	return nil
 }
 
@@ -284,6 +344,14 @@ func ValidateSendContact(ctx context.Context, request domainSend.ContactRequest)
 		return err
 	}
 
+	if err := validateMentions(request.Mentions); err != nil {
+		return err
+	}
+
+	if err := validateQueueTTL(request.QueueTTL); err != nil {
+		return err
+	}
+
 	return nil
 }
 
@@ -307,6 +375,14 @@ func ValidateSendLink(ctx context.Context, request domainSend.LinkRequest) error
 		return err
 	}
 
+	if err := validateMentions(request.Mentions); err != nil {
+		return err
+	}
+
+	if err := validateQueueTTL(request.QueueTTL); err != nil {
+		return err
+	}
+
 	return nil
 }
 
@@ -330,6 +406,14 @@ func ValidateSendLocation(ctx context.Context, request domainSend.LocationReques
 		return err
 	}
 
+	if err := validateMentions(request.Mentions); err != nil {
+		return err
+	}
+
+	if err := validateQueueTTL(request.QueueTTL); err != nil {
+		return err
+	}
+
 	return nil
 }
 
@@ -405,6 +489,14 @@ func ValidateSendAudio(ctx context.Context, request domainSend.AudioRequest) err
 		return err
 	}
 
+	if err := validateMentions(request.Mentions); err != nil {
+		return err
+	}
+
+	if err := validateQueueTTL(request.QueueTTL); err != nil {
+		return err
+	}
+
 	return nil
 }
 
@@ -438,6 +530,14 @@ func ValidateSendPoll(ctx context.Context, request domainSend.PollRequest) error
 		return err
 	}
 
+	if err := validateMentions(request.Mentions); err != nil {
+		return err
+	}
+
+	if err := validateQueueTTL(request.QueueTTL); err != nil {
+		return err
+	}
+
 	// validate options should be unique each other
 	uniqueOptions := make(map[string]bool)
 	for _, option := range request.Options {
@@ -450,6 +550,86 @@ func ValidateSendPoll(ctx context.Context, request domainSend.PollRequest) error
 	return nil
 }
 
+func ValidateSendProduct(ctx context.Context, request domainSend.ProductRequest) error {
+	err := validation.ValidateStructWithContext(ctx, &request,
+		validation.Field(&request.Phone, validation.Required),
+		validation.Field(&request.ProductID, validation.Required),
+	)
+
+	if err != nil {
+		return pkgError.ValidationError(err.Error())
+	}
+
+	// Custom validation for phone number format
+	if err := validatePhoneNumber(request.Phone); err != nil {
+		return err
+	}
+
+	if err := validateDuration(request.Duration); err != nil {
+		return err
+	}
+
+	if err := validateMentions(request.Mentions); err != nil {
+		return err
+	}
+
+	if err := validateQueueTTL(request.QueueTTL); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func ValidateSendProductList(ctx context.Context, request domainSend.ProductListRequest) error {
+	err := validation.ValidateStructWithContext(ctx, &request,
+		validation.Field(&request.Phone, validation.Required),
+		validation.Field(&request.Title, validation.Required),
+		validation.Field(&request.ButtonText, validation.Required, validation.Length(1, 20)),
+		validation.Field(&request.Sections, validation.Required, validation.Length(1, 10)),
+	)
+
+	if err != nil {
+		return pkgError.ValidationError(err.Error())
+	}
+
+	// Custom validation for phone number format
+	if err := validatePhoneNumber(request.Phone); err != nil {
+		return err
+	}
+
+	if err := validateDuration(request.Duration); err != nil {
+		return err
+	}
+
+	if err := validateMentions(request.Mentions); err != nil {
+		return err
+	}
+
+	if err := validateQueueTTL(request.QueueTTL); err != nil {
+		return err
+	}
+
+	listed := make(map[string]bool)
+	for i, section := range request.Sections {
+		err := validation.ValidateStruct(&section,
+			validation.Field(&section.Title, validation.Required),
+			validation.Field(&section.ProductIDs, validation.Required, validation.Length(1, 30), validation.Each(validation.Required)),
+		)
+		if err != nil {
+			return pkgError.ValidationError(fmt.Sprintf("sections[%d]: %s", i, err.Error()))
+		}
+		for _, productID := range section.ProductIDs {
+			listed[productID] = true
+		}
+	}
+
+	if request.HeaderProductID != "" && !listed[request.HeaderProductID] {
+		return pkgError.ValidationError("header_product_id must be one of the listed products")
+	}
+
+	return nil
+}
+
 func ValidateSendPresence(ctx context.Context, request domainSend.PresenceRequest) error {
 	err := validation.ValidateStructWithContext(ctx, &request,
 		validation.Field(&request.Type, validation.In("available", "unavailable")),
@@ -479,3 +659,28 @@ func ValidateSendChatPresence(ctx context.Context, request domainSend.ChatPresen
 
 	return nil
 }
+
+func ValidateSendQueueList(ctx context.Context, request *domainSend.QueueListRequest) error {
+	// Set default limit if not provided
+	if request.Limit == 0 {
+		request.Limit = 50
+	}
+
+	err := validation.ValidateStructWithContext(ctx, request,
+		validation.Field(&request.Status, validation.In(
+			domainChatStorage.QueueStatusQueued,
+			domainChatStorage.QueueStatusSent,
+			domainChatStorage.QueueStatusFailed,
+			domainChatStorage.QueueStatusExpired,
+			domainChatStorage.QueueStatusCancelled,
+		)),
+		validation.Field(&request.Limit, validation.Min(1), validation.Max(100)),
+		validation.Field(&request.Offset, validation.Min(0)),
+	)
+
+	if err != nil {
+		return pkgError.ValidationError(err.Error())
+	}
+
+	return nil
+}
@@ -50,6 +50,39 @@ func TestValidateSendMessage(t *testing.T) {
 			}},
 			err: pkgError.ValidationError("message: cannot be blank."),
 		},
+		{
+			name: "should error with blank mention",
+			args: args{request: domainSend.MessageRequest{
+				BaseRequest: domainSend.BaseRequest{
+					Phone:    "1728937129312@s.whatsapp.net",
+					Mentions: []string{"6281234567890", " "},
+				},
+				Message: "Hello team",
+			}},
+			err: pkgError.ValidationError("mentions: cannot contain blank entries."),
+		},
+		{
+			name: "should error with non positive queue ttl",
+			args: args{request: domainSend.MessageRequest{
+				BaseRequest: domainSend.BaseRequest{
+					Phone:    "1728937129312@s.whatsapp.net",
+					QueueTTL: func() *int { v := 0; return &v }(),
+				},
+				Message: "Hello team",
+			}},
+			err: pkgError.ValidationError("queue_ttl: must be a positive number of seconds."),
+		},
+		{
+			name: "should success with template instead of message",
+			args: args{request: domainSend.MessageRequest{
+				BaseRequest: domainSend.BaseRequest{
+					Phone: "1728937129312@s.whatsapp.net",
+				},
+				TemplateID: "order_shipped",
+				Variables:  map[string]string{"name": "Budi"},
+			}},
+			err: nil,
+		},
 	}
 
 	for _, tt := range tests {
@@ -1106,3 +1139,155 @@ func TestValidateSendAudio_WithDuration(t *testing.T) {
 		})
 	}
 }
+
+func TestValidateSendQueueList(t *testing.T) {
+	tests := []struct {
+		name      string
+		request   domainSend.QueueListRequest
+		wantLimit int
+		err       any
+	}{
+		{
+			name:      "should success and default limit",
+			request:   domainSend.QueueListRequest{},
+			wantLimit: 50,
+			err:       nil,
+		},
+		{
+			name:      "should success with status",
+			request:   domainSend.QueueListRequest{Status: "queued", Limit: 10},
+			wantLimit: 10,
+			err:       nil,
+		},
+		{
+			name:      "should error with unknown status",
+			request:   domainSend.QueueListRequest{Status: "pending"},
+			wantLimit: 50,
+			err:       pkgError.ValidationError("status: must be a valid value."),
+		},
+		{
+			name:      "should error with limit over maximum",
+			request:   domainSend.QueueListRequest{Limit: 101},
+			wantLimit: 101,
+			err:       pkgError.ValidationError("limit: must be no greater than 100."),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateSendQueueList(context.Background(), &tt.request)
+			assert.Equal(t, tt.err, err)
+			assert.Equal(t, tt.wantLimit, tt.request.Limit)
+		})
+	}
+}
+
+func TestValidateSendProduct(t *testing.T) {
+	type args struct {
+		request domainSend.ProductRequest
+	}
+	tests := []struct {
+		name string
+		args args
+		err  any
+	}{
+		{
+			name: "should success normal condition",
+			args: args{request: domainSend.ProductRequest{
+				BaseRequest: domainSend.BaseRequest{
+					Phone: "1728937129312@s.whatsapp.net",
+				},
+				ProductID: "7282471485125876",
+				Body:      "Back in stock",
+			}},
+			err: nil,
+		},
+		{
+			name: "should error with empty product id",
+			args: args{request: domainSend.ProductRequest{
+				BaseRequest: domainSend.BaseRequest{
+					Phone: "1728937129312@s.whatsapp.net",
+				},
+			}},
+			err: pkgError.ValidationError("product_id: cannot be blank."),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateSendProduct(context.Background(), tt.args.request)
+			assert.Equal(t, tt.err, err)
+		})
+	}
+}
+
+func TestValidateSendProductList(t *testing.T) {
+	valid := func() domainSend.ProductListRequest {
+		return domainSend.ProductListRequest{
+			BaseRequest: domainSend.BaseRequest{
+				Phone: "1728937129312@s.whatsapp.net",
+			},
+			Title:      "New arrivals",
+			ButtonText: "View products",
+			Sections: []domainSend.ProductListSection{
+				{Title: "Shirts", ProductIDs: []string{"7282471485125876", "6142739482374611"}},
+			},
+		}
+	}
+
+	tests := []struct {
+		name   string
+		modify func(request *domainSend.ProductListRequest)
+		err    any
+	}{
+		{
+			name:   "should success normal condition",
+			modify: func(request *domainSend.ProductListRequest) {},
+			err:    nil,
+		},
+		{
+			name: "should success with listed header product",
+			modify: func(request *domainSend.ProductListRequest) {
+				request.HeaderProductID = "6142739482374611"
+			},
+			err: nil,
+		},
+		{
+			name: "should error with empty sections",
+			modify: func(request *domainSend.ProductListRequest) {
+				request.Sections = nil
+			},
+			err: pkgError.ValidationError("sections: cannot be blank."),
+		},
+		{
+			name: "should error with empty button text",
+			modify: func(request *domainSend.ProductListRequest) {
+				request.ButtonText = ""
+			},
+			err: pkgError.ValidationError("button_text: cannot be blank."),
+		},
+		{
+			name: "should error with section without products",
+			modify: func(request *domainSend.ProductListRequest) {
+				request.Sections[0].ProductIDs = nil
+			},
+			err: pkgError.ValidationError("sections[0]: product_ids: cannot be blank."),
+		},
+		{
+			name: "should error with unlisted header product",
+			modify: func(request *domainSend.ProductListRequest) {
+				request.HeaderProductID = "1111111111111111"
+			},
+			err: pkgError.ValidationError("header_product_id must be one of the listed products"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			request := valid()
+			tt.modify(&request)
+			err := ValidateSendProductList(context.Background(), request)
+			assert.Equal(t, tt.err, err)
+		})
+	}
+}
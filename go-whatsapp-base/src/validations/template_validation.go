@@ -0,0 +1,203 @@
+package validations
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	domainChatStorage "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/chatstorage"
+	domainTemplate "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/template"
+	pkgError "github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/error"
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/utils"
+	validation "github.com/go-ozzo/ozzo-validation/v4"
+	"github.com/go-ozzo/ozzo-validation/v4/is"
+)
+
+// TemplateDateLayout is the format accepted for date template variables
+const TemplateDateLayout = "2006-01-02"
+
+var (
+	templateIDRegex       = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9_.-]*$`)
+	templateVariableRegex = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+)
+
+func ValidateTemplate(ctx context.Context, request *domainTemplate.TemplateRequest) error {
+	// Set defaults if not provided
+	for i := range request.Variables {
+		if request.Variables[i].Type == "" {
+			request.Variables[i].Type = domainChatStorage.TemplateVarText
+		}
+	}
+	if request.DefaultLanguage == "" && len(request.Variants) > 0 {
+		request.DefaultLanguage = request.Variants[0].Language
+	}
+
+	err := validation.ValidateStructWithContext(ctx, request,
+		validation.Field(&request.TemplateID, validation.Required, validation.Length(1, 64), validation.Match(templateIDRegex)),
+		validation.Field(&request.Variants, validation.Required),
+		validation.Field(&request.DefaultLanguage, validation.Required),
+	)
+	if err != nil {
+		return pkgError.ValidationError(err.Error())
+	}
+
+	declared := make(map[string]bool, len(request.Variables))
+	for i, variable := range request.Variables {
+		err := validation.ValidateStructWithContext(ctx, &variable,
+			validation.Field(&variable.Name, validation.Required, validation.Match(templateVariableRegex)),
+			validation.Field(&variable.Type, validation.In(
+				domainChatStorage.TemplateVarText,
+				domainChatStorage.TemplateVarNumber,
+				domainChatStorage.TemplateVarDate,
+				domainChatStorage.TemplateVarURL,
+			)),
+		)
+		if err != nil {
+			return pkgError.ValidationError(fmt.Sprintf("variables[%d]: %s", i, err.Error()))
+		}
+		if declared[variable.Name] {
+			return pkgError.ValidationError(fmt.Sprintf("variables[%d]: duplicate variable %s.", i, variable.Name))
+		}
+		if variable.Default != "" {
+			if err := validateTemplateValue(variable.Type, variable.Default); err != nil {
+				return pkgError.ValidationError(fmt.Sprintf("variables[%d]: default %s.", i, err.Error()))
+			}
+		}
+		declared[variable.Name] = true
+	}
+
+	languages := make(map[string]bool, len(request.Variants))
+	for i, variant := range request.Variants {
+		err := validation.ValidateStructWithContext(ctx, &variant,
+			validation.Field(&variant.Language, validation.Required),
+			validation.Field(&variant.Body, validation.Required),
+		)
+		if err != nil {
+			return pkgError.ValidationError(fmt.Sprintf("variants[%d]: %s", i, err.Error()))
+		}
+
+		language := strings.ToLower(variant.Language)
+		if languages[language] {
+			return pkgError.ValidationError(fmt.Sprintf("variants[%d]: duplicate language %s.", i, variant.Language))
+		}
+		languages[language] = true
+
+		for _, name := range utils.TemplatePlaceholders(variant.Body) {
+			if !declared[name] {
+				return pkgError.ValidationError(fmt.Sprintf("variants[%d]: placeholder {{%s}} is not a declared variable.", i, name))
+			}
+		}
+	}
+
+	if !languages[strings.ToLower(request.DefaultLanguage)] {
+		return pkgError.ValidationError("default_language: must match one of the variant languages.")
+	}
+
+	return nil
+}
+
+func ValidateUpdateTemplate(ctx context.Context, request *domainTemplate.TemplateRequest) error {
+	if request.TemplateID == "" {
+		return pkgError.ValidationError("template_id: cannot be blank.")
+	}
+
+	return ValidateTemplate(ctx, request)
+}
+
+func ValidateGetTemplate(ctx context.Context, request domainTemplate.GetTemplateRequest) error {
+	err := validation.ValidateStructWithContext(ctx, &request,
+		validation.Field(&request.TemplateID, validation.Required),
+	)
+
+	if err != nil {
+		return pkgError.ValidationError(err.Error())
+	}
+
+	return nil
+}
+
+func ValidateDeleteTemplate(ctx context.Context, request domainTemplate.DeleteTemplateRequest) error {
+	err := validation.ValidateStructWithContext(ctx, &request,
+		validation.Field(&request.TemplateID, validation.Required),
+	)
+
+	if err != nil {
+		return pkgError.ValidationError(err.Error())
+	}
+
+	return nil
+}
+
+func ValidateRenderTemplate(ctx context.Context, request domainTemplate.RenderTemplateRequest) error {
+	err := validation.ValidateStructWithContext(ctx, &request,
+		validation.Field(&request.TemplateID, validation.Required),
+	)
+
+	if err != nil {
+		return pkgError.ValidationError(err.Error())
+	}
+
+	return nil
+}
+
+// ValidateTemplateVariables checks the values supplied for a template against its declared
+// variables. Missing values fall back to the variable default; required variables without
+// either are rejected, as are values that do not match the variable type.
+func ValidateTemplateVariables(template *domainChatStorage.MessageTemplate, values map[string]string) error {
+	var missing, invalid []string
+	for _, variable := range template.Variables {
+		value := values[variable.Name]
+		if value == "" {
+			value = variable.Default
+		}
+
+		if value == "" {
+			if variable.Required {
+				missing = append(missing, variable.Name)
+			}
+			continue
+		}
+
+		if err := validateTemplateValue(variable.Type, value); err != nil {
+			invalid = append(invalid, fmt.Sprintf("%s: %s", variable.Name, err.Error()))
+		}
+	}
+
+	if len(missing) == 0 && len(invalid) == 0 {
+		return nil
+	}
+
+	var problems []string
+	if len(missing) > 0 {
+		problems = append(problems, "missing required variables: "+strings.Join(missing, ", "))
+	}
+	if len(invalid) > 0 {
+		sort.Strings(invalid)
+		problems = append(problems, strings.Join(invalid, "; "))
+	}
+
+	return pkgError.ValidationError(fmt.Sprintf("variables: %s.", strings.Join(problems, "; ")))
+}
+
+func validateTemplateValue(varType, value string) error {
+	switch varType {
+	case domainChatStorage.TemplateVarNumber:
+		if _, err := strconv.ParseFloat(value, 64); err != nil {
+			return errors.New("must be a number")
+		}
+	case domainChatStorage.TemplateVarDate:
+		if _, err := time.Parse(TemplateDateLayout, value); err != nil {
+			return errors.New("must be a date in YYYY-MM-DD format")
+		}
+	case domainChatStorage.TemplateVarURL:
+		if err := is.URL.Validate(value); err != nil {
+			return errors.New("must be a valid URL")
+		}
+	}
+	return nil
+}
@@ -0,0 +1,124 @@
+package validations
+
+import (
+	"context"
+	"testing"
+
+	domainTemplate "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/template"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidateTemplate(t *testing.T) {
+	type args struct {
+		request domainTemplate.TemplateRequest
+	}
+	tests := []struct {
+		name        string
+		args        args
+		errContains []string
+	}{
+		{
+			name: "should success with typed variables and two languages",
+			args: args{request: domainTemplate.TemplateRequest{
+				TemplateID: "order_shipped",
+				Variables: []domainTemplate.Variable{
+					{Name: "name", Required: true},
+					{Name: "order_id", Type: "number", Required: true},
+					{Name: "eta", Type: "date", Default: "2025-01-31"},
+				},
+				Variants: []domainTemplate.Variant{
+					{Language: "en", Body: "Hi {{name}}, order {{order_id}} arrives {{eta}}"},
+					{Language: "id", Body: "Halo {{name}}, pesanan {{order_id}} tiba {{eta}}"},
+				},
+			}},
+		},
+		{
+			name: "should error without variants",
+			args: args{request: domainTemplate.TemplateRequest{
+				TemplateID: "empty",
+			}},
+			errContains: []string{"variants: cannot be blank"},
+		},
+		{
+			name: "should error with invalid template id",
+			args: args{request: domainTemplate.TemplateRequest{
+				TemplateID: "has space",
+				Variants:   []domainTemplate.Variant{{Language: "en", Body: "hi"}},
+			}},
+			errContains: []string{"template_id: must be in a valid format"},
+		},
+		{
+			name: "should error with undeclared placeholder",
+			args: args{request: domainTemplate.TemplateRequest{
+				TemplateID: "greeting",
+				Variants:   []domainTemplate.Variant{{Language: "en", Body: "Hi {{name}}"}},
+			}},
+			errContains: []string{"placeholder {{name}} is not a declared variable"},
+		},
+		{
+			name: "should error with unknown variable type",
+			args: args{request: domainTemplate.TemplateRequest{
+				TemplateID: "greeting",
+				Variables:  []domainTemplate.Variable{{Name: "name", Type: "email"}},
+				Variants:   []domainTemplate.Variant{{Language: "en", Body: "Hi {{name}}"}},
+			}},
+			errContains: []string{"variables[0]: type: must be a valid value"},
+		},
+		{
+			name: "should error with default that does not match type",
+			args: args{request: domainTemplate.TemplateRequest{
+				TemplateID: "invoice",
+				Variables:  []domainTemplate.Variable{{Name: "amount", Type: "number", Default: "ten"}},
+				Variants:   []domainTemplate.Variant{{Language: "en", Body: "Total {{amount}}"}},
+			}},
+			errContains: []string{"variables[0]: default must be a number"},
+		},
+		{
+			name: "should error with duplicate language",
+			args: args{request: domainTemplate.TemplateRequest{
+				TemplateID: "greeting",
+				Variants: []domainTemplate.Variant{
+					{Language: "en", Body: "Hi"},
+					{Language: "EN", Body: "Hello"},
+				},
+			}},
+			errContains: []string{"variants[1]: duplicate language EN"},
+		},
+		{
+			name: "should error when default language has no variant",
+			args: args{request: domainTemplate.TemplateRequest{
+				TemplateID:      "greeting",
+				DefaultLanguage: "fr",
+				Variants:        []domainTemplate.Variant{{Language: "en", Body: "Hi"}},
+			}},
+			errContains: []string{"default_language: must match one of the variant languages"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateTemplate(context.Background(), &tt.args.request)
+			if len(tt.errContains) == 0 {
+				assert.NoError(t, err)
+				return
+			}
+
+			assert.Error(t, err)
+			for _, contains := range tt.errContains {
+				assert.Contains(t, err.Error(), contains)
+			}
+		})
+	}
+}
+
+func TestValidateTemplateDefaults(t *testing.T) {
+	request := domainTemplate.TemplateRequest{
+		TemplateID: "greeting",
+		Variables:  []domainTemplate.Variable{{Name: "name"}},
+		Variants:   []domainTemplate.Variant{{Language: "id", Body: "Halo {{name}}"}},
+	}
+
+	assert.NoError(t, ValidateTemplate(context.Background(), &request))
+	assert.Equal(t, "text", request.Variables[0].Type)
+	assert.Equal(t, "id", request.DefaultLanguage)
+}
@@ -110,3 +110,88 @@ func TestValidateBusinessProfile(t *testing.T) {
 		})
 	}
 }
+
+func TestValidateBusinessCatalog(t *testing.T) {
+	type args struct {
+		request domainUser.BusinessCatalogRequest
+	}
+	tests := []struct {
+		name string
+		args args
+		err  any
+	}{
+		{
+			name: "should success with valid phone",
+			args: args{request: domainUser.BusinessCatalogRequest{
+				Phone: "1728937129312@s.whatsapp.net",
+			}},
+			err: nil,
+		},
+		{
+			name: "should success with limit and cursor",
+			args: args{request: domainUser.BusinessCatalogRequest{
+				Phone:  "1728937129312@s.whatsapp.net",
+				Limit:  50,
+				Cursor: "QVFIUnR4",
+			}},
+			err: nil,
+		},
+		{
+			name: "should error with empty phone",
+			args: args{request: domainUser.BusinessCatalogRequest{
+				Phone: "",
+			}},
+			err: pkgError.ValidationError("phone: cannot be blank."),
+		},
+		{
+			name: "should error with limit over 100",
+			args: args{request: domainUser.BusinessCatalogRequest{
+				Phone: "1728937129312@s.whatsapp.net",
+				Limit: 101,
+			}},
+			err: pkgError.ValidationError("limit: must be no greater than 100."),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateBusinessCatalog(context.Background(), tt.args.request)
+			assert.Equal(t, tt.err, err)
+		})
+	}
+}
+
+func TestValidateBusinessCollections(t *testing.T) {
+	type args struct {
+		request domainUser.BusinessCollectionsRequest
+	}
+	tests := []struct {
+		name string
+		args args
+		err  any
+	}{
+		{
+			name: "should success with valid phone",
+			args: args{request: domainUser.BusinessCollectionsRequest{
+				Phone: "1728937129312@s.whatsapp.net",
+				Limit: 20,
+			}},
+			err: nil,
+		},
+		{
+			name: "should error with negative limit",
+			args: args{request: domainUser.BusinessCollectionsRequest{
+				Phone: "1728937129312@s.whatsapp.net",
+				Limit: -1,
+			}},
+			err: pkgError.ValidationError("limit: must be no less than 0."),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateBusinessCollections(context.Background(), tt.args.request)
+			assert.Equal(t, tt.err, err)
+		})
+	}
+}
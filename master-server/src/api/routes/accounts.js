@@ -1,5 +1,5 @@
 const { Router } = require('express');
-const axios = require('axios');
+const workerClient = require('../../utils/workerClient');
 const { query } = require('../../config/database');
 const workerManager = require('../../services/WorkerManager');
 
@@ -52,7 +52,7 @@ router.get('/', async (req, res, next) => {
 
         for (const worker of WORKERS) {
             try {
-                const response = await axios.get(`${worker.url}/accounts`, { timeout: 5000 });
+                const response = await workerClient.get(`${worker.url}/accounts`, { timeout: 5000 });
                 const accounts = response.data.accounts || [];
 
                 accounts.forEach(acc => {
@@ -98,7 +98,7 @@ router.get('/:phone', async (req, res, next) => {
 
         for (const worker of WORKERS) {
             try {
-                const response = await axios.get(`${worker.url}/accounts/${phone}`, { timeout: 5000 });
+                const response = await workerClient.get(`${worker.url}/accounts/${phone}`, { timeout: 5000 });
                 if (response.data) {
                     const acc = response.data;
                     return res.json({
@@ -130,7 +130,7 @@ router.get('/:phone/disconnect-reason', async (req, res, next) => {
 
         for (const worker of WORKERS) {
             try {
-                const response = await axios.get(`${worker.url}/accounts/${phone}/disconnect-reason`, { timeout: 5000 });
+                const response = await workerClient.get(`${worker.url}/accounts/${phone}/disconnect-reason`, { timeout: 5000 });
                 if (response.data) {
                     return res.json({
                         ...response.data,
@@ -177,7 +177,7 @@ router.post('/pair', async (req, res, next) => {
         let existingAccount = null;
         for (const w of WORKERS) {
             try {
-                const accountsResponse = await axios.get(`${w.url}/accounts`, { timeout: 5000 });
+                const accountsResponse = await workerClient.get(`${w.url}/accounts`, { timeout: 5000 });
                 const accounts = accountsResponse.data?.accounts || [];
                 const acc = accounts.find(a => a.phone === phone);
                 if (acc) {
@@ -252,7 +252,7 @@ router.post('/pair', async (req, res, next) => {
         }
 
         // Request pairing from worker with session_number
-        const response = await axios.post(`${worker.url}/accounts/pair`, {
+        const response = await workerClient.post(`${worker.url}/accounts/pair`, {
             phone: phone,
             session_number: sessionNum || 1
         }, { timeout: 30000 });
@@ -277,7 +277,7 @@ router.post('/:phone/disconnect', async (req, res, next) => {
         // Try to disconnect from all workers
         for (const worker of WORKERS) {
             try {
-                await axios.post(`${worker.url}/accounts/${phone}/disconnect`, {}, { timeout: 5000 });
+                await workerClient.post(`${worker.url}/accounts/${phone}/disconnect`, {}, { timeout: 5000 });
             } catch (err) {
                 // Ignore errors, try all workers
             }
@@ -297,7 +297,7 @@ router.post('/:phone/reconnect', async (req, res, next) => {
         // Try to reconnect from all workers
         for (const worker of WORKERS) {
             try {
-                await axios.post(`${worker.url}/accounts/${phone}/reconnect`, {}, { timeout: 5000 });
+                await workerClient.post(`${worker.url}/accounts/${phone}/reconnect`, {}, { timeout: 5000 });
             } catch (err) {
                 // Ignore errors, try all workers
             }
@@ -317,7 +317,7 @@ router.delete('/:phone', async (req, res, next) => {
         // Try to delete from all workers
         for (const worker of WORKERS) {
             try {
-                await axios.delete(`${worker.url}/accounts/${phone}`, { timeout: 5000 });
+                await workerClient.delete(`${worker.url}/accounts/${phone}`, { timeout: 5000 });
             } catch (err) {
                 // Ignore errors, try all workers
             }
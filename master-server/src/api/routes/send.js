@@ -100,7 +100,24 @@ router.post('/', async (req, res, next) => {
         let existingChatsCount = 0;
         let newContactsCount = 0;
 
+        // Recipients that opted out on any worker are not queued again
+        let optedOut = new Set();
+        try {
+            const optOutResult = await query(`
+                SELECT recipient
+                FROM opt_outs
+                WHERE recipient = ANY($1)
+            `, [normalizedContacts.map(c => String(c.phone).replace(/\D/g, ''))]);
+            optedOut = new Set(optOutResult.rows.map(row => row.recipient));
+        } catch (err) {
+            console.error('[Send] DB error reading opt-outs:', err.message);
+        }
+
         for (const contact of normalizedContacts) {
+            if (optedOut.has(String(contact.phone).replace(/\D/g, ''))) {
+                continue;
+            }
+
             // Check if there's existing chat
             const existingChat = await query(`
                 SELECT sender_phone
@@ -154,6 +171,9 @@ router.post('/', async (req, res, next) => {
         console.log(`[Send] 📥 Received request: ${normalizedContacts.length} contacts`);
         console.log(`[Send] ✅ Added ${queueInserts.length} messages to queue | Campaign: ${campaignId}`);
         console.log(`[Send] 📊 Contacts: ${uniqueContacts} unique | ${existingChatsCount} existing chats, ${newContactsCount} new`);
+        if (optedOut.size > 0) {
+            console.log(`[Send] 🚫 Skipped ${optedOut.size} recipient(s) that opted out`);
+        }
 
         if (alreadyPending > 0) {
             console.log(`[Send] ⏳ ${alreadyPending} message(s) already waiting in queue`);
@@ -168,6 +188,7 @@ router.post('/', async (req, res, next) => {
             campaign_id: campaignId,
             total: normalizedContacts.length,
             queued: queueInserts.length,
+            opted_out: optedOut.size,
             message: 'Messages added to queue. Processing will start when 2+ messages are waiting.'
         });
 
@@ -0,0 +1,263 @@
+// Worker reports
+// Routes the workers call back on to tell the master what happened on their
+// side. They authenticate with WORKER_API_SECRET instead of the API key.
+
+const { Router } = require('express');
+const { query, pool } = require('../../config/database');
+const { workerAuth } = require('../../middleware/auth');
+const logger = require('../../utils/logger');
+
+const router = Router();
+
+// POST /api/accounts/:phone/health/message - Outcome of a send or send job
+router.post('/:phone/health/message', workerAuth, async (req, res, next) => {
+    try {
+        const phone = req.params.phone;
+        const { success, attempted, worker_id, to_phone, error, message_id, job_id, status } = req.body || {};
+
+        if (!to_phone) {
+            return res.status(400).json({ error: 'to_phone required' });
+        }
+
+        // Jobs report their own status (sent, failed, cancelled, interrupted)
+        let logStatus = success ? 'SENT' : (attempted ? 'FAILED' : 'NOT_SENT');
+        if (job_id && status) {
+            logStatus = String(status).toUpperCase();
+        }
+
+        await query(`
+            INSERT INTO send_log (phone, recipient, status, error, worker_id, message_id, job_id)
+            VALUES ($1, $2, $3, $4, $5, $6, $7)
+            ON CONFLICT (job_id) WHERE job_id IS NOT NULL DO NOTHING
+        `, [phone, to_phone, logStatus, error || null, worker_id || null, message_id || null, job_id || null]);
+
+        if (!success) {
+            logger.warn(`[WorkerReports] ❌ ${worker_id || 'worker'} failed to send from ${phone} to ${to_phone}${job_id ? ` (job ${job_id})` : ''}: ${error}`);
+        }
+
+        res.json({ success: true });
+    } catch (err) {
+        next(err);
+    }
+});
+
+// POST /api/accounts/:phone/policy-rejections - A worker policy refused a recipient
+router.post('/:phone/policy-rejections', workerAuth, async (req, res, next) => {
+    try {
+        const phone = req.params.phone;
+        const { worker_id, to_phone, reason, policy, code } = req.body || {};
+
+        if (!to_phone) {
+            return res.status(400).json({ error: 'to_phone required' });
+        }
+
+        await query(`
+            INSERT INTO send_log (phone, recipient, status, error, worker_id)
+            VALUES ($1, $2, 'REJECTED', $3, $4)
+        `, [phone, to_phone, code ? `${code}: ${reason}` : reason, worker_id || null]);
+
+        logger.info(`[WorkerReports] 🚫 ${policy || 'Policy'} refused ${to_phone} for ${phone}: ${code || reason}`);
+        res.json({ success: true });
+    } catch (err) {
+        next(err);
+    }
+});
+
+// POST /api/accounts/:phone/messages/:id/status - Delivery or read receipt of a sent message
+router.post('/:phone/messages/:id/status', workerAuth, async (req, res, next) => {
+    try {
+        const { phone, id } = req.params;
+        const { worker_id, message, status } = req.body || {};
+
+        if (!message || !status) {
+            return res.status(400).json({ error: 'message and status required' });
+        }
+
+        // Reports can overtake each other, so never move a message backwards:
+        // keep the first time of each step and derive the status from them
+        await query(`
+            INSERT INTO message_status (phone, message_id, recipient, status, sent_at, delivered_at, read_at, failed_at, error, worker_id, updated_at)
+            VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, NOW())
+            ON CONFLICT (phone, message_id) DO UPDATE SET
+                sent_at = COALESCE(message_status.sent_at, EXCLUDED.sent_at),
+                delivered_at = COALESCE(message_status.delivered_at, EXCLUDED.delivered_at),
+                read_at = COALESCE(message_status.read_at, EXCLUDED.read_at),
+                failed_at = COALESCE(message_status.failed_at, EXCLUDED.failed_at),
+                error = COALESCE(EXCLUDED.error, message_status.error),
+                status = CASE
+                    WHEN COALESCE(message_status.read_at, EXCLUDED.read_at) IS NOT NULL THEN 'read'
+                    WHEN COALESCE(message_status.delivered_at, EXCLUDED.delivered_at) IS NOT NULL THEN 'delivered'
+                    WHEN COALESCE(message_status.failed_at, EXCLUDED.failed_at) IS NOT NULL THEN 'failed'
+                    ELSE EXCLUDED.status
+                END,
+                worker_id = EXCLUDED.worker_id,
+                updated_at = NOW()
+        `, [
+            phone,
+            id,
+            message.to_phone,
+            status,
+            message.sent_at || null,
+            message.delivered_at || null,
+            message.read_at || null,
+            message.failed_at || null,
+            message.error || null,
+            worker_id || null
+        ]);
+
+        res.json({ success: true });
+    } catch (err) {
+        next(err);
+    }
+});
+
+// POST /api/accounts/:phone/inbound - A message an account received
+router.post('/:phone/inbound', workerAuth, async (req, res, next) => {
+    try {
+        const phone = req.params.phone;
+        const { worker_id, message, media_path } = req.body || {};
+
+        if (!message || !message.id || !message.from) {
+            return res.status(400).json({ error: 'message with id and from required' });
+        }
+
+        // Workers retry until acknowledged, so a message may arrive twice
+        await query(`
+            INSERT INTO inbound_messages (phone, message_id, from_phone, from_name, type, body, worker_id, payload, received_at)
+            VALUES ($1, $2, $3, $4, $5, $6, $7, $8, COALESCE($9, NOW()))
+            ON CONFLICT (phone, message_id) DO NOTHING
+        `, [
+            phone,
+            message.id,
+            message.from,
+            message.from_name || null,
+            message.type || 'text',
+            message.message || null,
+            worker_id || null,
+            JSON.stringify({ ...message, media_path: media_path || undefined }),
+            message.timestamp || null
+        ]);
+
+        logger.info(`[WorkerReports] 📨 ${message.type || 'text'} from ${message.from} to ${phone}`);
+        res.json({ success: true });
+    } catch (err) {
+        next(err);
+    }
+});
+
+// POST /api/accounts/:phone/opt-outs - A recipient replied with an opt-out keyword
+router.post('/:phone/opt-outs', workerAuth, async (req, res, next) => {
+    try {
+        const phone = req.params.phone;
+        const { worker_id, to_phone, source, keyword, created_at } = req.body || {};
+
+        if (!to_phone) {
+            return res.status(400).json({ error: 'to_phone required' });
+        }
+        const recipient = String(to_phone).replace(/\D/g, '');
+
+        await query(`
+            INSERT INTO opt_outs (recipient, phone, source, keyword, worker_id, created_at)
+            VALUES ($1, $2, $3, $4, $5, COALESCE($6, NOW()))
+            ON CONFLICT (recipient) DO NOTHING
+        `, [recipient, phone, source || 'keyword', keyword || null, worker_id || null, created_at || null]);
+
+        // Other workers don't know the recipient opted out, so drop what is queued
+        const dropped = await query(`
+            UPDATE message_queue
+            SET status = 'failed',
+                error_code = 'recipient_suppressed',
+                processed_at = NOW()
+            WHERE status = 'pending'
+              AND regexp_replace(recipient_phone, '\\D', '', 'g') = $1
+        `, [recipient]);
+
+        logger.info(`[WorkerReports] 🚫 ${recipient} opted out via ${phone}${keyword ? ` with "${keyword}"` : ''}, dropped ${dropped.rowCount} queued message(s)`);
+        res.json({ success: true, dropped: dropped.rowCount });
+    } catch (err) {
+        next(err);
+    }
+});
+
+// POST /api/accounts/:phone/frequency-cap/reservations - Count a send against the recipient's cap
+router.post('/:phone/frequency-cap/reservations', workerAuth, async (req, res, next) => {
+    try {
+        const phone = req.params.phone;
+        const { worker_id, to_phone } = req.body || {};
+        const max = parseInt(req.body?.max, 10);
+        const windowSeconds = parseInt(req.body?.window_seconds, 10);
+
+        if (!to_phone || !(max > 0) || !(windowSeconds > 0)) {
+            return res.status(400).json({ error: 'to_phone, max and window_seconds required' });
+        }
+        const recipient = String(to_phone).replace(/\D/g, '');
+
+        const client = await pool.connect();
+        try {
+            await client.query('BEGIN');
+            // Reservations of one recipient from all workers take turns
+            await client.query('SELECT pg_advisory_xact_lock(hashtext($1))', [recipient]);
+            await client.query(`
+                DELETE FROM frequency_reservations
+                WHERE recipient = $1 AND reserved_at <= NOW() - make_interval(secs => $2)
+            `, [recipient, windowSeconds]);
+
+            const recent = await client.query(`
+                SELECT reserved_at FROM frequency_reservations
+                WHERE recipient = $1
+                ORDER BY reserved_at
+            `, [recipient]);
+
+            if (recent.rows.length >= max) {
+                await client.query('COMMIT');
+                // The oldest reservation in the window frees a slot first
+                const oldest = recent.rows[recent.rows.length - max].reserved_at;
+                const nextAllowed = new Date(new Date(oldest).getTime() + windowSeconds * 1000);
+                logger.info(`[WorkerReports] 🚫 Frequency cap refused ${recipient} for ${phone} until ${nextAllowed.toISOString()}`);
+                return res.status(409).json({
+                    error: 'frequency_capped',
+                    sent: recent.rows.length,
+                    max,
+                    next_allowed: nextAllowed.toISOString()
+                });
+            }
+
+            const inserted = await client.query(`
+                INSERT INTO frequency_reservations (recipient, phone, worker_id)
+                VALUES ($1, $2, $3)
+                RETURNING id
+            `, [recipient, phone, worker_id || null]);
+            await client.query('COMMIT');
+
+            res.status(201).json({ success: true, id: inserted.rows[0].id, sent: recent.rows.length + 1 });
+        } catch (err) {
+            await client.query('ROLLBACK').catch(() => {});
+            throw err;
+        } finally {
+            client.release();
+        }
+    } catch (err) {
+        next(err);
+    }
+});
+
+// DELETE /api/accounts/:phone/frequency-cap/reservations/:id - A reserved send did not go out
+router.delete('/:phone/frequency-cap/reservations/:id', workerAuth, async (req, res, next) => {
+    try {
+        const { phone, id } = req.params;
+
+        if (!/^\d+$/.test(id)) {
+            return res.status(400).json({ error: 'invalid reservation id' });
+        }
+
+        await query(`
+            DELETE FROM frequency_reservations WHERE id = $1 AND phone = $2
+        `, [id, phone]);
+
+        res.json({ success: true });
+    } catch (err) {
+        next(err);
+    }
+});
+
+module.exports = router;
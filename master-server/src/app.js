@@ -7,6 +7,7 @@ const logger = require('./utils/logger');
 const accountsRouter = require('./api/routes/accounts');
 const sendRouter = require('./api/routes/send');
 const campaignsRouter = require('./api/routes/campaigns');
+const workerReportsRouter = require('./api/routes/workerReports');
 const { query } = require('./config/database');
 const { apiKeyAuth } = require('./middleware/auth');
 const { requestLogger } = require('./middleware/requestLogger');
@@ -14,6 +15,13 @@ const queueProcessor = require('./services/QueueProcessor');
 
 dotenv.config();
 
+// Worker calls and reports are authenticated with WORKER_API_SECRET, so
+// refuse to start without it unless auth is switched off on purpose
+if (!process.env.WORKER_API_SECRET && process.env.WORKER_AUTH_DISABLED !== 'true') {
+    logger.error('WORKER_API_SECRET is not set; set it, or WORKER_AUTH_DISABLED=true to leave worker routes open');
+    process.exit(1);
+}
+
 const app = express();
 
 // v8.0: Simple and clean master server
@@ -41,6 +49,10 @@ app.get('/health', async (req, res) => {
     }
 });
 
+// Worker reports (protected with the worker secret, checked per route so
+// the other /api/accounts routes still fall through to the API key)
+app.use('/api/accounts', workerReportsRouter);
+
 // API Routes (protected with API key)
 app.use('/api/accounts', apiKeyAuth, accountsRouter);
 app.use('/api/send', apiKeyAuth, sendRouter);
@@ -1,6 +1,8 @@
 // API Key Authentication Middleware
 // Validates X-API-Key header against API_KEY environment variable
 
+const crypto = require('crypto');
+
 function apiKeyAuth(req, res, next) {
     const apiKey = req.headers['x-api-key'] || req.headers['authorization']?.replace('Bearer ', '');
     const expectedKey = process.env.API_KEY;
@@ -37,5 +39,39 @@ function apiKeyAuth(req, res, next) {
     next();
 }
 
-module.exports = { apiKeyAuth };
+// Worker Authentication Middleware
+// Validates the bearer token workers send when reporting back against
+// WORKER_API_SECRET, the secret the master signs its worker calls with
+function workerAuth(req, res, next) {
+    const expected = process.env.WORKER_API_SECRET;
+    const clientIP = req.ip || req.connection.remoteAddress || req.headers['x-forwarded-for'] || 'unknown';
+
+    // Same as the workers: without a secret the worker routes only stay open
+    // when WORKER_AUTH_DISABLED says so
+    if (!expected) {
+        if (process.env.WORKER_AUTH_DISABLED === 'true') {
+            return next();
+        }
+        console.error(`[AUTH] ❌ REJECTED: WORKER_API_SECRET not configured | IP: ${clientIP} | Path: ${req.path}`);
+        return res.status(503).json({
+            error: 'Worker auth not configured',
+            message: 'Set WORKER_API_SECRET, or WORKER_AUTH_DISABLED=true to leave worker routes open'
+        });
+    }
+
+    const token = (req.headers['authorization'] || '').replace('Bearer ', '');
+    const given = crypto.createHash('sha256').update(token).digest();
+    const want = crypto.createHash('sha256').update(expected).digest();
+    if (!token || !crypto.timingSafeEqual(given, want)) {
+        console.error(`[AUTH] ❌ REJECTED: Invalid worker token | IP: ${clientIP} | Path: ${req.path}`);
+        return res.status(401).json({
+            error: 'Worker token required',
+            message: 'Workers must send Authorization: Bearer <WORKER_API_SECRET>'
+        });
+    }
+
+    next();
+}
+
+module.exports = { apiKeyAuth, workerAuth };
 
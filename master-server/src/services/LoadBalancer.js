@@ -1,4 +1,4 @@
-const axios = require('axios');
+const workerClient = require('../utils/workerClient');
 const { query } = require('../config/database');
 
 const workers = [
@@ -95,7 +95,7 @@ class LoadBalancer {
         
         for (const worker of workers) {
             try {
-                const response = await axios.get(`${worker.url}/accounts`, { timeout: 5000 });
+                const response = await workerClient.get(`${worker.url}/accounts`, { timeout: 5000 });
                 if (response.data && response.data.accounts) {
                     for (const acc of response.data.accounts) {
                         if (acc.logged_in && acc.connected) {
@@ -222,7 +222,7 @@ class LoadBalancer {
             message: message.message
         };
 
-        const response = await axios.post(`${worker.url}/send`, payload, { timeout: 30000 });
+        const response = await workerClient.post(`${worker.url}/send`, payload, { timeout: 30000 });
 
         return {
             workerId: worker.id,
@@ -244,7 +244,7 @@ class LoadBalancer {
 
             for (const msg of dist.messages) {
                 try {
-                    const result = await axios.post(`${dist.account.worker.url}/send`, {
+                    const result = await workerClient.post(`${dist.account.worker.url}/send`, {
                         from_phone: phone,
                         to_phone: msg.toPhone,
                         message: msg.message
@@ -4,6 +4,7 @@
 
 const { query } = require('../config/database');
 const axios = require('axios');
+const workerClient = require('../utils/workerClient');
 const logger = require('../utils/logger');
 
 class QueueProcessor {
@@ -224,7 +225,7 @@ class QueueProcessor {
 
                         // Check message status after failure
                         const checkStatus = await query(`
-                            SELECT status, retry_count FROM message_queue WHERE id = $1
+                            SELECT status, retry_count, retry_at FROM message_queue WHERE id = $1
                         `, [contact.id]);
 
                         if (!checkStatus.rows || checkStatus.rows.length === 0) {
@@ -234,8 +235,12 @@ class QueueProcessor {
 
                         const status = checkStatus.rows[0]?.status;
                         const retryCount = checkStatus.rows[0]?.retry_count || 0;
+                        const retryAt = checkStatus.rows[0]?.retry_at;
 
-                        if (status === 'failed') {
+                        if (status === 'pending' && retryAt && new Date(retryAt) > new Date()) {
+                            // Deferred by a worker policy - picked up again once retry_at passes
+                            break;
+                        } else if (status === 'failed') {
                             // Message failed permanently (blocked or max retries)
                             failedCount++;
                             logger.error(`[QueueProcessor] ❌ Permanent failure for ${contact.recipient_phone} (retry count: ${retryCount})`);
@@ -249,6 +254,7 @@ class QueueProcessor {
                             const refreshedContact = await query(`
                                 SELECT id, recipient_phone, recipient_name, message_template, priority, campaign_id
                                 FROM message_queue WHERE id = $1 AND status = 'pending'
+                                  AND (retry_at IS NULL OR retry_at <= NOW())
                             `, [contact.id]);
                             if (refreshedContact.rows.length > 0) {
                                 Object.assign(contact, refreshedContact.rows[0]);
@@ -313,6 +319,7 @@ class QueueProcessor {
                 SELECT COUNT(*) as count
                 FROM message_queue
                 WHERE status = 'pending'
+                  AND (retry_at IS NULL OR retry_at <= NOW())
             `);
             return parseInt(result.rows[0].count);
         } catch (err) {
@@ -374,6 +381,7 @@ class QueueProcessor {
                 FROM message_queue q
                 LEFT JOIN chat_history ch ON ch.recipient_phone = q.recipient_phone
                 WHERE q.status = 'pending'
+                  AND (q.retry_at IS NULL OR q.retry_at <= NOW())  -- Deferred by a worker policy
                 ORDER BY 
                     has_existing_chat DESC,  -- Existing chats first
                     q.priority DESC,
@@ -412,7 +420,7 @@ class QueueProcessor {
 
         for (const worker of this.workers) {
             try {
-                const response = await axios.get(`${worker.url}/accounts`, { timeout: 5000 });
+                const response = await workerClient.get(`${worker.url}/accounts`, { timeout: 5000 });
                 if (response.data && response.data.accounts) {
                     const workerAccounts = response.data.accounts.filter(acc => acc.logged_in && acc.connected);
                     workerStatus[worker.id] = { healthy: true, accountCount: workerAccounts.length };
@@ -443,12 +451,12 @@ class QueueProcessor {
             for (const worker of failedWorkers) {
                 try {
                     // Try to ping worker health endpoint first
-                    await axios.get(`${worker.url}/health`, { timeout: 3000 });
+                    await workerClient.get(`${worker.url}/health`, { timeout: 3000 });
                     logger.info(`[QueueProcessor] ✅ Worker ${worker.id} is back online`);
                 } catch (err) {
                     // Worker still down - try to trigger reconnect for all accounts
                     try {
-                        const response = await axios.get(`${worker.url}/accounts`, { timeout: 3000 });
+                        const response = await workerClient.get(`${worker.url}/accounts`, { timeout: 3000 });
                         if (response.data && response.data.accounts) {
                             // Try to reconnect disconnected accounts
                             const disconnectedAccounts = response.data.accounts.filter(
@@ -456,7 +464,7 @@ class QueueProcessor {
                             );
                             for (const acc of disconnectedAccounts) {
                                 try {
-                                    await axios.post(
+                                    await workerClient.post(
                                         `${worker.url}/accounts/${acc.phone}/reconnect`,
                                         {},
                                         { timeout: 5000 }
@@ -726,7 +734,7 @@ class QueueProcessor {
             `, [sender.phone, contact.id]);
 
             // Send to worker
-            const response = await axios.post(`${sender.worker_url}/send`, {
+            const response = await workerClient.post(`${sender.worker_url}/send`, {
                 from_phone: sender.phone,
                 to_phone: contact.recipient_phone,
                 message: contact.message_template,
@@ -766,6 +774,13 @@ class QueueProcessor {
             }
 
         } catch (err) {
+            // A worker policy refused the recipient (403 with a policy code)
+            const rejection = err.response && err.response.data;
+            if (err.response && err.response.status === 403 && rejection && rejection.code && rejection.code !== 'forbidden') {
+                await this.handlePolicyRejection(sender, contact, rejection);
+                return false;
+            }
+
             const errorMsg = err.message || err.toString();
             const isConnectionError = errorMsg.includes('ECONNREFUSED') ||
                 errorMsg.includes('timeout') ||
@@ -778,7 +793,7 @@ class QueueProcessor {
                 logger.warn(`[QueueProcessor] 🔄 Worker ${sender.worker_id} connection failed, attempting reconnect...`);
                 try {
                     // Try to reconnect the account
-                    await axios.post(
+                    await workerClient.post(
                         `${sender.worker_url}/accounts/${sender.phone}/reconnect`,
                         {},
                         { timeout: 5000 }
@@ -864,6 +879,36 @@ class QueueProcessor {
         }
     }
 
+    // Handle a send a worker policy refused. With retry_at (quiet hours) or
+    // next_allowed (frequency_capped) the message waits until then; without
+    // them the refusal is final, as retrying would only be refused again
+    // (consent_missing, recipient_suppressed)
+    async handlePolicyRejection(sender, contact, rejection) {
+        const retryAt = rejection.retry_at || rejection.next_allowed;
+        if (retryAt) {
+            await query(`
+                UPDATE message_queue
+                SET status = 'pending',
+                    assigned_sender = NULL,
+                    processed_at = NULL,
+                    retry_at = $1,
+                    error_code = $2
+                WHERE id = $3
+            `, [retryAt, rejection.code, contact.id]);
+            logger.info(`[QueueProcessor] ⏰ ${rejection.code} for ${contact.recipient_phone} from ${sender.phone} - deferred until ${retryAt}`);
+            return;
+        }
+
+        await query(`
+            UPDATE message_queue
+            SET status = 'failed',
+                error_code = $1,
+                processed_at = NOW()
+            WHERE id = $2
+        `, [rejection.code, contact.id]);
+        logger.warn(`[QueueProcessor] 🚫 ${rejection.code} for ${contact.recipient_phone} from ${sender.phone} - not retrying: ${rejection.message}`);
+    }
+
     // Update sender after sending
     async updateSenderAfterSend(senderPhone) {
         // Update counters: messages_last_minute is reset every minute (max 15 per minute per device)
@@ -49,12 +49,12 @@ class WorkerManager {
     // Find worker with no accounts (empty worker)
     async findEmptyWorker() {
         try {
-            const axios = require('axios');
+            const workerClient = require('../utils/workerClient');
             const workers = this.loadWorkers();
             
             for (const worker of workers) {
                 try {
-                    const response = await axios.get(`${worker.url}/accounts`, { timeout: 5000 });
+                    const response = await workerClient.get(`${worker.url}/accounts`, { timeout: 5000 });
                     const accounts = response.data?.accounts || [];
                     const connectedAccounts = accounts.filter(acc => acc.logged_in && acc.connected);
                     
@@ -301,12 +301,12 @@ class WorkerManager {
 
     // Wait for worker to be ready
     async waitForWorkerReady(workerId, port, maxAttempts = 30) {
-        const axios = require('axios');
+        const workerClient = require('../utils/workerClient');
         const workerUrl = `http://${workerId}:3001`;
         
         for (let i = 0; i < maxAttempts; i++) {
             try {
-                const response = await axios.get(`${workerUrl}/health`, { timeout: 3000 });
+                const response = await workerClient.get(`${workerUrl}/health`, { timeout: 3000 });
                 if (response.data && response.data.healthy) {
                     logger.info(`[WorkerManager] ${workerId} is ready!`);
                     return true;
@@ -0,0 +1,44 @@
+// Worker API client
+// Adds the WORKER_API_SECRET bearer token to every call and signs each one
+// the way the workers check /send: X-Timestamp is the Unix time, X-Request-Id
+// is unique to the call and X-Signature-256 is the HMAC-SHA256 of
+// "<timestamp>.<request id>.<METHOD>.<path>.<body>"
+
+const crypto = require('crypto');
+const axios = require('axios');
+
+const workerClient = axios.create();
+
+workerClient.interceptors.request.use((config) => {
+    const secret = process.env.WORKER_API_SECRET;
+    if (!secret) {
+        return config;
+    }
+
+    // Sign the exact bytes that go out
+    let body = '';
+    if (config.data !== undefined && config.data !== null) {
+        body = typeof config.data === 'string' ? config.data : JSON.stringify(config.data);
+        config.data = body;
+        config.headers['Content-Type'] = 'application/json';
+    }
+
+    const method = (config.method || 'get').toUpperCase();
+    const path = new URL(config.url, config.baseURL).pathname;
+    const timestamp = Math.floor(Date.now() / 1000).toString();
+    // Workers accept each request ID once, so identical calls in the same
+    // second are not refused as replays
+    const requestId = crypto.randomUUID();
+    const signature = crypto
+        .createHmac('sha256', secret)
+        .update(`${timestamp}.${requestId}.${method}.${path}.${body}`)
+        .digest('hex');
+
+    config.headers['Authorization'] = `Bearer ${secret}`;
+    config.headers['X-Timestamp'] = timestamp;
+    config.headers['X-Request-Id'] = requestId;
+    config.headers['X-Signature-256'] = `sha256=${signature}`;
+    return config;
+});
+
+module.exports = workerClient;
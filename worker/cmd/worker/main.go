@@ -5,6 +5,8 @@ import (
 	"log"
 	"net/http"
 	"os"
+	"os/signal"
+	"syscall"
 	"time"
 
 	"github.com/gorilla/mux"
@@ -67,8 +69,11 @@ func main() {
 		log.Fatalf("Failed to initialize worker server: %v", err)
 	}
 
+	// SIGTERM (docker stop) and Ctrl+C trigger a graceful shutdown
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
 	// Start background services (load sessions, start monitor)
-	ctx := context.Background()
 	server.StartBackgroundServices(ctx)
 
 	router := mux.NewRouter()
@@ -85,7 +90,33 @@ func main() {
 
 	log.Printf("Worker %s listening on port %s", workerID, port)
 
-	if err := srv.ListenAndServe(); err != nil && err != http.ErrServerClosed {
-		log.Fatalf("Server error: %v", err)
+	go func() {
+		if err := srv.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+			log.Fatalf("Server error: %v", err)
+		}
+	}()
+
+	<-ctx.Done()
+	stop()
+
+	shutdownTimeout := 30 * time.Second
+	if v := os.Getenv("SHUTDOWN_TIMEOUT"); v != "" {
+		if d, err := time.ParseDuration(v); err == nil && d > 0 {
+			shutdownTimeout = d
+		} else {
+			log.Printf("[SHUTDOWN] ⚠️ Invalid SHUTDOWN_TIMEOUT %q, using %s", v, shutdownTimeout)
+		}
+	}
+	log.Printf("[SHUTDOWN] Signal received, shutting down within %s...", shutdownTimeout)
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+	defer cancel()
+
+	// Refuse new sends and jobs before draining, so requests that arrive while
+	// the in-flight ones finish can't start sends the deadline would cut short
+	server.BeginShutdown()
+	if err := srv.Shutdown(shutdownCtx); err != nil {
+		log.Printf("[SHUTDOWN] ⚠️ HTTP server did not stop cleanly: %v", err)
 	}
+	server.Shutdown(shutdownCtx)
 }
@@ -0,0 +1,272 @@
+package api
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"log"
+	"net"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/whatsapp-automation/worker/internal/whatsapp"
+)
+
+// Scopes a worker API token can hold
+const (
+	ScopeRead  = "read"  // status, accounts, messages, inbox and jobs
+	ScopeSend  = "send"  // sends and send jobs
+	ScopeAdmin = "admin" // pairing, sessions and policy settings
+)
+
+// DefaultSignatureMaxAge is how far a signed request's timestamp may be from
+// the worker's clock
+const DefaultSignatureMaxAge = 5 * time.Minute
+
+// maxSignedBody bounds the body read to check a signature
+const maxSignedBody = 1 << 20
+
+// maxRequestIDLength bounds the X-Request-Id kept to refuse replays
+const maxRequestIDLength = 128
+
+// apiToken is a bearer token and what it may do
+type apiToken struct {
+	name   string
+	token  []byte
+	scopes map[string]bool
+}
+
+// Authenticator guards the worker API with bearer tokens. WORKER_API_SECRET
+// is the Master's token and holds every scope; WORKER_API_TOKENS adds more
+// tokens with chosen scopes, as name:token:scope+scope separated by commas.
+// Sends must also be signed: X-Timestamp carries the Unix time, X-Request-Id
+// a value unique to the request and X-Signature-256 is "sha256=" and the hex
+// HMAC-SHA256, keyed by the token, of "<timestamp>.<request id>.<METHOD>.<path>.<body>".
+// A request ID is accepted once per token, so identical calls made within
+// the same second are still told apart.
+type Authenticator struct {
+	tokens []apiToken
+	maxAge time.Duration
+	audit  *whatsapp.AuthAuditLog
+	seen   map[string]time.Time // token name and request ID -> when it can be forgotten
+	mu     sync.Mutex
+}
+
+// NewAuthenticator loads the tokens from the environment. Without any token
+// it fails unless WORKER_AUTH_DISABLED=true leaves the API open on purpose.
+func NewAuthenticator() (*Authenticator, error) {
+	a := &Authenticator{
+		maxAge: DefaultSignatureMaxAge,
+		audit:  whatsapp.NewAuthAuditLog(),
+		seen:   make(map[string]time.Time),
+	}
+
+	if value := os.Getenv("WORKER_SIGNATURE_MAX_AGE"); value != "" {
+		if d, err := time.ParseDuration(value); err == nil && d > 0 {
+			a.maxAge = d
+		} else {
+			log.Printf("[AUTH] ⚠️ Invalid WORKER_SIGNATURE_MAX_AGE %q, using %s", value, a.maxAge)
+		}
+	}
+
+	if secret := os.Getenv("WORKER_API_SECRET"); secret != "" {
+		a.tokens = append(a.tokens, apiToken{
+			name:   "master",
+			token:  []byte(secret),
+			scopes: map[string]bool{ScopeRead: true, ScopeSend: true, ScopeAdmin: true},
+		})
+	}
+	for _, spec := range strings.Split(os.Getenv("WORKER_API_TOKENS"), ",") {
+		spec = strings.TrimSpace(spec)
+		if spec == "" {
+			continue
+		}
+		token, err := parseAPIToken(spec)
+		if err != nil {
+			log.Printf("[AUTH] ⚠️ Ignoring WORKER_API_TOKENS entry: %v", err)
+			continue
+		}
+		a.tokens = append(a.tokens, token)
+	}
+
+	if !a.Enabled() {
+		if os.Getenv("WORKER_AUTH_DISABLED") != "true" {
+			return nil, fmt.Errorf("WORKER_API_SECRET is not set; set it, or WORKER_AUTH_DISABLED=true to leave the API open")
+		}
+		log.Printf("[AUTH] ⚠️ WORKER_AUTH_DISABLED=true - the API is open to anyone who can reach the port")
+	} else {
+		log.Printf("[AUTH] 🔒 API requires one of %d tokens, sends signed within %s", len(a.tokens), a.maxAge)
+	}
+	return a, nil
+}
+
+// parseAPIToken reads a name:token:scope+scope entry
+func parseAPIToken(spec string) (apiToken, error) {
+	parts := strings.Split(spec, ":")
+	if len(parts) != 3 || parts[0] == "" || parts[1] == "" {
+		return apiToken{}, fmt.Errorf("%q is not name:token:scopes", parts[0])
+	}
+	token := apiToken{name: parts[0], token: []byte(parts[1]), scopes: make(map[string]bool)}
+	for _, scope := range strings.Split(parts[2], "+") {
+		switch scope {
+		case ScopeRead, ScopeSend, ScopeAdmin:
+			token.scopes[scope] = true
+		default:
+			return apiToken{}, fmt.Errorf("token %s has unknown scope %q", token.name, scope)
+		}
+	}
+	return token, nil
+}
+
+// Enabled reports whether any token is configured
+func (a *Authenticator) Enabled() bool {
+	return len(a.tokens) > 0
+}
+
+// Audit returns the refused calls, newest first
+func (a *Authenticator) Audit(limit int) ([]whatsapp.AuthAuditEntry, error) {
+	return a.audit.List(limit)
+}
+
+// Require lets a request through when it carries a token with the scope
+func (a *Authenticator) Require(scope string, next http.HandlerFunc) http.HandlerFunc {
+	return a.guard(scope, false, next)
+}
+
+// RequireSigned is Require for routes whose requests must also be signed
+func (a *Authenticator) RequireSigned(scope string, next http.HandlerFunc) http.HandlerFunc {
+	return a.guard(scope, true, next)
+}
+
+func (a *Authenticator) guard(scope string, signed bool, next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if !a.Enabled() {
+			next(w, r)
+			return
+		}
+
+		token, ok := a.lookup(r)
+		if !ok {
+			w.Header().Set("WWW-Authenticate", `Bearer realm="worker"`)
+			a.reject(w, r, nil, scope, http.StatusUnauthorized, "unauthorized", "missing or invalid API token")
+			return
+		}
+		if !token.scopes[scope] {
+			a.reject(w, r, token, scope, http.StatusForbidden, "forbidden", fmt.Sprintf("token %s lacks the %s scope", token.name, scope))
+			return
+		}
+		if signed {
+			if code, reason := a.verifySignature(r, token); code != "" {
+				a.reject(w, r, token, scope, http.StatusUnauthorized, code, reason)
+				return
+			}
+		}
+		next(w, r)
+	}
+}
+
+// lookup finds the token of the Authorization header. Every token is
+// compared so the time taken does not reveal which one nearly matched.
+func (a *Authenticator) lookup(r *http.Request) (*apiToken, bool) {
+	header := r.Header.Get("Authorization")
+	if !strings.HasPrefix(header, "Bearer ") {
+		return nil, false
+	}
+	given := sha256.Sum256([]byte(strings.TrimSpace(strings.TrimPrefix(header, "Bearer "))))
+
+	var found *apiToken
+	for i := range a.tokens {
+		want := sha256.Sum256(a.tokens[i].token)
+		if subtle.ConstantTimeCompare(given[:], want[:]) == 1 {
+			found = &a.tokens[i]
+		}
+	}
+	return found, found != nil
+}
+
+// verifySignature checks the timestamp and HMAC of a request and that it was
+// not seen before. It returns an error code and reason when it fails, and
+// leaves the body readable for the handler.
+func (a *Authenticator) verifySignature(r *http.Request, token *apiToken) (string, string) {
+	timestamp := r.Header.Get("X-Timestamp")
+	requestID := r.Header.Get("X-Request-Id")
+	signature := strings.TrimPrefix(r.Header.Get("X-Signature-256"), "sha256=")
+	if timestamp == "" || requestID == "" || signature == "" {
+		return "invalid_signature", "X-Timestamp, X-Request-Id and X-Signature-256 headers required"
+	}
+	if len(requestID) > maxRequestIDLength {
+		return "invalid_signature", fmt.Sprintf("X-Request-Id must be at most %d characters", maxRequestIDLength)
+	}
+
+	seconds, err := strconv.ParseInt(timestamp, 10, 64)
+	if err != nil {
+		return "invalid_signature", "X-Timestamp must be Unix seconds"
+	}
+	now := time.Now()
+	signedAt := time.Unix(seconds, 0)
+	if signedAt.Before(now.Add(-a.maxAge)) || signedAt.After(now.Add(a.maxAge)) {
+		return "signature_expired", fmt.Sprintf("request signed at %s, outside the %s allowed", signedAt.UTC().Format(time.RFC3339), a.maxAge)
+	}
+
+	body, err := io.ReadAll(io.LimitReader(r.Body, maxSignedBody+1))
+	if err != nil {
+		return "invalid_signature", "failed to read request body"
+	}
+	if len(body) > maxSignedBody {
+		return "invalid_signature", "request body too large"
+	}
+	r.Body = io.NopCloser(bytes.NewReader(body))
+
+	mac := hmac.New(sha256.New, token.token)
+	fmt.Fprintf(mac, "%s.%s.%s.%s.", timestamp, requestID, r.Method, r.URL.Path)
+	mac.Write(body)
+	given, err := hex.DecodeString(signature)
+	if err != nil || !hmac.Equal(given, mac.Sum(nil)) {
+		return "invalid_signature", "signature does not match the request"
+	}
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	for key, expires := range a.seen {
+		if now.After(expires) {
+			delete(a.seen, key)
+		}
+	}
+	key := token.name + "." + requestID
+	if _, replayed := a.seen[key]; replayed {
+		return "replayed_request", "request ID already used"
+	}
+	a.seen[key] = signedAt.Add(a.maxAge)
+	return "", ""
+}
+
+// reject answers a refused call and records it in the audit log
+func (a *Authenticator) reject(w http.ResponseWriter, r *http.Request, token *apiToken, scope string, status int, code, reason string) {
+	remoteIP := r.RemoteAddr
+	if host, _, err := net.SplitHostPort(r.RemoteAddr); err == nil {
+		remoteIP = host
+	}
+	entry := whatsapp.AuthAuditEntry{
+		Method:   r.Method,
+		Path:     r.URL.Path,
+		RemoteIP: remoteIP,
+		Scope:    scope,
+		Status:   status,
+		Reason:   reason,
+	}
+	if token != nil {
+		entry.Token = token.name
+	}
+	a.audit.Record(entry)
+
+	log.Printf("[AUTH] ❌ %s %s from %s refused: %s", r.Method, r.URL.Path, remoteIP, reason)
+	writeErrorCode(w, status, code, reason)
+}
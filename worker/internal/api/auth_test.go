@@ -0,0 +1,187 @@
+package api
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+// signedRequest builds a POST /send signed the way the Master signs it
+func signedRequest(secret string, signedAt time.Time, requestID, body string) *http.Request {
+	timestamp := strconv.FormatInt(signedAt.Unix(), 10)
+	mac := hmac.New(sha256.New, []byte(secret))
+	fmt.Fprintf(mac, "%s.%s.%s.%s.%s", timestamp, requestID, http.MethodPost, "/send", body)
+
+	req := httptest.NewRequest(http.MethodPost, "/send", strings.NewReader(body))
+	req.Header.Set("Authorization", "Bearer "+secret)
+	req.Header.Set("X-Timestamp", timestamp)
+	req.Header.Set("X-Request-Id", requestID)
+	req.Header.Set("X-Signature-256", "sha256="+hex.EncodeToString(mac.Sum(nil)))
+	return req
+}
+
+func newTestAuthenticator(t *testing.T) *Authenticator {
+	t.Helper()
+	// Refused calls are audited under the sessions directory
+	t.Chdir(t.TempDir())
+	t.Setenv("WORKER_API_SECRET", "master-secret")
+	t.Setenv("WORKER_API_TOKENS", "dashboard:dashboard-token:read")
+	t.Setenv("WORKER_AUTH_DISABLED", "")
+
+	a, err := NewAuthenticator()
+	if err != nil {
+		t.Fatalf("NewAuthenticator returned error: %v", err)
+	}
+	return a
+}
+
+func TestAuthenticatorRequireSigned(t *testing.T) {
+	const body = `{"from_phone":"4915112345678","to_phone":"4915187654321","message":"hi"}`
+	now := time.Now()
+
+	tests := []struct {
+		name     string
+		request  func() *http.Request
+		wantCode int
+		wantErr  string
+	}{
+		{
+			name:     "valid signature",
+			request:  func() *http.Request { return signedRequest("master-secret", now, "req-valid", body) },
+			wantCode: http.StatusOK,
+		},
+		{
+			name:     "missing token",
+			request:  func() *http.Request { return httptest.NewRequest(http.MethodPost, "/send", strings.NewReader(body)) },
+			wantCode: http.StatusUnauthorized,
+			wantErr:  "unauthorized",
+		},
+		{
+			name:     "token without the send scope",
+			request:  func() *http.Request { return signedRequest("dashboard-token", now, "req-scope", body) },
+			wantCode: http.StatusForbidden,
+			wantErr:  "forbidden",
+		},
+		{
+			name: "missing signature headers",
+			request: func() *http.Request {
+				req := signedRequest("master-secret", now, "req-headers", body)
+				req.Header.Del("X-Request-Id")
+				return req
+			},
+			wantCode: http.StatusUnauthorized,
+			wantErr:  "invalid_signature",
+		},
+		{
+			name: "tampered body",
+			request: func() *http.Request {
+				req := signedRequest("master-secret", now, "req-tampered", body)
+				tampered := signedRequest("master-secret", now, "req-tampered", strings.Replace(body, "hi", "bye", 1))
+				req.Body = tampered.Body
+				return req
+			},
+			wantCode: http.StatusUnauthorized,
+			wantErr:  "invalid_signature",
+		},
+		{
+			name:     "signed too long ago",
+			request:  func() *http.Request { return signedRequest("master-secret", now.Add(-10*time.Minute), "req-old", body) },
+			wantCode: http.StatusUnauthorized,
+			wantErr:  "signature_expired",
+		},
+		{
+			name: "signed in the future",
+			request: func() *http.Request {
+				return signedRequest("master-secret", now.Add(10*time.Minute), "req-future", body)
+			},
+			wantCode: http.StatusUnauthorized,
+			wantErr:  "signature_expired",
+		},
+	}
+
+	a := newTestAuthenticator(t)
+	handler := a.RequireSigned(ScopeSend, func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, http.StatusOK, map[string]interface{}{"success": true})
+	})
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			handler(rec, tt.request())
+
+			if rec.Code != tt.wantCode {
+				t.Fatalf("status = %d, want %d: %s", rec.Code, tt.wantCode, rec.Body.String())
+			}
+			if tt.wantErr == "" {
+				return
+			}
+			var response struct {
+				Code string `json:"code"`
+			}
+			if err := json.Unmarshal(rec.Body.Bytes(), &response); err != nil {
+				t.Fatalf("failed to decode response: %v", err)
+			}
+			if response.Code != tt.wantErr {
+				t.Errorf("code = %q, want %q", response.Code, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestAuthenticatorRefusesReplays(t *testing.T) {
+	const body = `{"message":"hi"}`
+	a := newTestAuthenticator(t)
+	handler := a.RequireSigned(ScopeSend, func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, http.StatusOK, map[string]interface{}{"success": true})
+	})
+	now := time.Now()
+
+	steps := []struct {
+		requestID string
+		wantCode  int
+	}{
+		{"req-1", http.StatusOK},
+		// An identical call in the same second with its own ID goes through
+		{"req-2", http.StatusOK},
+		{"req-1", http.StatusUnauthorized},
+	}
+	for _, step := range steps {
+		rec := httptest.NewRecorder()
+		handler(rec, signedRequest("master-secret", now, step.requestID, body))
+		if rec.Code != step.wantCode {
+			t.Fatalf("request %s: status = %d, want %d: %s", step.requestID, rec.Code, step.wantCode, rec.Body.String())
+		}
+	}
+}
+
+func TestNewAuthenticatorWithoutSecret(t *testing.T) {
+	t.Chdir(t.TempDir())
+	t.Setenv("WORKER_API_SECRET", "")
+	t.Setenv("WORKER_API_TOKENS", "")
+
+	t.Setenv("WORKER_AUTH_DISABLED", "")
+	if _, err := NewAuthenticator(); err == nil {
+		t.Fatal("NewAuthenticator without a secret succeeded, want an error")
+	}
+
+	t.Setenv("WORKER_AUTH_DISABLED", "true")
+	a, err := NewAuthenticator()
+	if err != nil {
+		t.Fatalf("NewAuthenticator with WORKER_AUTH_DISABLED=true returned error: %v", err)
+	}
+	rec := httptest.NewRecorder()
+	a.Require(ScopeAdmin, func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	})(rec, httptest.NewRequest(http.MethodGet, "/quiet-hours", nil))
+	if rec.Code != http.StatusNoContent {
+		t.Fatalf("status = %d with auth disabled, want %d", rec.Code, http.StatusNoContent)
+	}
+}
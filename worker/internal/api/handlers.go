@@ -3,8 +3,12 @@ package api
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"log"
+	"mime"
 	"net/http"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/gorilla/mux"
@@ -25,10 +29,19 @@ type Server struct {
 	ProxyConfig  *config.ProxyConfig
 	client       *whatsapp.ClientManager
 	monitor      *whatsapp.ConnectionMonitor
+	templates    *whatsapp.TemplateStore
+	idempotency  *whatsapp.IdempotencyStore
+	jobs         *whatsapp.JobQueue
+	auth         *Authenticator
 }
 
 // NewServer creates a new API server
 func NewServer(workerID, deviceSeed, proxyCountry string, fp fingerprint.DeviceFingerprint, proxyConfig *config.ProxyConfig) (*Server, error) {
+	auth, err := NewAuthenticator()
+	if err != nil {
+		return nil, err
+	}
+
 	client := whatsapp.NewClientManager(fp, proxyCountry, workerID, proxyConfig)
 	monitor := whatsapp.NewConnectionMonitor(client)
 
@@ -40,6 +53,10 @@ func NewServer(workerID, deviceSeed, proxyCountry string, fp fingerprint.DeviceF
 		ProxyConfig:  proxyConfig,
 		client:       client,
 		monitor:      monitor,
+		templates:    whatsapp.NewTemplateStore(),
+		idempotency:  whatsapp.NewIdempotencyStore(),
+		jobs:         whatsapp.NewJobQueue(client),
+		auth:         auth,
 	}, nil
 }
 
@@ -68,11 +85,43 @@ func (s *Server) StartBackgroundServices(ctx context.Context) {
 	s.client.StartHeartbeat()
 	log.Printf("[STARTUP] Heartbeat started")
 
-	// Setup message handlers
-	s.client.SetupAllMessageHandlers()
+	// Purge quarantined sessions past their retention
+	s.client.Quarantine().StartPurger()
+	log.Printf("[STARTUP] Quarantine purger started")
+
+	// Retry inbound forwards left over from the last run
+	s.client.Inbox().StartForwarder()
+	log.Printf("[STARTUP] Inbox forwarder started")
+
+	// Resume queued send jobs
+	s.jobs.Start()
+	log.Printf("[STARTUP] Job queue started")
 	log.Printf("[STARTUP] Ready")
 }
 
+// BeginShutdown makes the API refuse new sends and jobs with 503 while the
+// HTTP server drains the requests already in progress. Running jobs whose
+// send has not started yet go back in the queue for the next start.
+func (s *Server) BeginShutdown() {
+	s.jobs.StopAccepting()
+	s.client.StopAcceptingSends()
+}
+
+// Shutdown stops the background services in reverse order of startup: the
+// job queue first, so no job starts against a disconnecting client, then the
+// inbox forwarder, the purger and the monitor, then the client manager
+func (s *Server) Shutdown(ctx context.Context) {
+	log.Printf("[SHUTDOWN] Stopping job queue...")
+	s.jobs.Shutdown(ctx)
+
+	s.client.Inbox().StopForwarder(ctx)
+	s.client.Quarantine().StopPurger()
+	s.monitor.Stop()
+
+	s.client.Shutdown(ctx)
+	log.Printf("[SHUTDOWN] Done")
+}
+
 // GetClientManager returns client manager
 func (s *Server) GetClientManager() *whatsapp.ClientManager {
 	return s.client
@@ -85,22 +134,74 @@ func (s *Server) GetMonitor() *whatsapp.ConnectionMonitor {
 
 // RegisterRoutes registers HTTP routes
 func (s *Server) RegisterRoutes(r *mux.Router) {
-	// Health
+	// Health stays open for container health checks, every other route needs
+	// a token with its scope when WORKER_API_SECRET is set
 	r.HandleFunc("/health", s.handleHealth).Methods(http.MethodGet)
-	r.HandleFunc("/status", s.handleStatus).Methods(http.MethodGet)
+	r.HandleFunc("/status", s.auth.Require(ScopeRead, s.handleStatus)).Methods(http.MethodGet)
 
 	// Send
-	r.HandleFunc("/send", s.handleSend).Methods(http.MethodPost)
+	r.HandleFunc("/send", s.auth.RequireSigned(ScopeSend, s.handleSend)).Methods(http.MethodPost)
+
+	// Send jobs
+	r.HandleFunc("/jobs", s.auth.Require(ScopeRead, s.handleJobsList)).Methods(http.MethodGet)
+	r.HandleFunc("/jobs", s.auth.RequireSigned(ScopeSend, s.handleJobSubmit)).Methods(http.MethodPost)
+	r.HandleFunc("/jobs/{id}", s.auth.Require(ScopeRead, s.handleJobGet)).Methods(http.MethodGet)
+	r.HandleFunc("/jobs/{id}", s.auth.Require(ScopeSend, s.handleJobCancel)).Methods(http.MethodDelete)
+
+	// Templates
+	r.HandleFunc("/templates", s.auth.Require(ScopeRead, s.handleTemplatesList)).Methods(http.MethodGet)
+	r.HandleFunc("/templates", s.auth.Require(ScopeAdmin, s.handleTemplateSave)).Methods(http.MethodPost)
+	r.HandleFunc("/templates/{id}", s.auth.Require(ScopeRead, s.handleTemplateGet)).Methods(http.MethodGet)
+	r.HandleFunc("/templates/{id}", s.auth.Require(ScopeAdmin, s.handleTemplateDelete)).Methods(http.MethodDelete)
+	r.HandleFunc("/templates/{id}/render", s.auth.Require(ScopeRead, s.handleTemplateRender)).Methods(http.MethodPost)
+
+	// Suppression list (opt-outs)
+	r.HandleFunc("/suppressions", s.auth.Require(ScopeRead, s.handleSuppressionsList)).Methods(http.MethodGet)
+	r.HandleFunc("/suppressions", s.auth.Require(ScopeAdmin, s.handleSuppressionAdd)).Methods(http.MethodPost)
+	r.HandleFunc("/suppressions/audit", s.auth.Require(ScopeRead, s.handleSuppressionAudit)).Methods(http.MethodGet)
+	r.HandleFunc("/suppressions/{phone}", s.auth.Require(ScopeAdmin, s.handleSuppressionRemove)).Methods(http.MethodDelete)
+
+	// Consent registry
+	r.HandleFunc("/consent", s.auth.Require(ScopeRead, s.handleConsentList)).Methods(http.MethodGet)
+	r.HandleFunc("/consent", s.auth.Require(ScopeAdmin, s.handleConsentImport)).Methods(http.MethodPost)
+	r.HandleFunc("/consent/import", s.auth.Require(ScopeAdmin, s.handleConsentImportCSV)).Methods(http.MethodPost)
+	r.HandleFunc("/consent/check", s.auth.Require(ScopeRead, s.handleConsentCheck)).Methods(http.MethodPost)
+	r.HandleFunc("/consent/{phone}", s.auth.Require(ScopeAdmin, s.handleConsentRevoke)).Methods(http.MethodDelete)
+
+	// Quiet hours
+	r.HandleFunc("/quiet-hours", s.auth.Require(ScopeRead, s.handleQuietHoursGet)).Methods(http.MethodGet)
+	r.HandleFunc("/quiet-hours", s.auth.Require(ScopeAdmin, s.handleQuietHoursSet)).Methods(http.MethodPut)
+	r.HandleFunc("/quiet-hours/check", s.auth.Require(ScopeRead, s.handleQuietHoursCheck)).Methods(http.MethodGet)
+
+	// Delivery tracking
+	r.HandleFunc("/messages", s.auth.Require(ScopeRead, s.handleMessagesList)).Methods(http.MethodGet)
+	r.HandleFunc("/messages/{id}", s.auth.Require(ScopeRead, s.handleMessageGet)).Methods(http.MethodGet)
+	r.HandleFunc("/accounts/{phone}/delivery", s.auth.Require(ScopeRead, s.handleAccountDelivery)).Methods(http.MethodGet)
+
+	// Inbound messages
+	r.HandleFunc("/inbox", s.auth.Require(ScopeRead, s.handleInboxList)).Methods(http.MethodGet)
+	r.HandleFunc("/inbox/{phone}/{id}", s.auth.Require(ScopeRead, s.handleInboxGet)).Methods(http.MethodGet)
+	r.HandleFunc("/inbox/{phone}/{id}/media", s.auth.Require(ScopeRead, s.handleInboxMedia)).Methods(http.MethodGet)
+
+	// Frequency cap
+	r.HandleFunc("/frequency-cap/{phone}", s.auth.Require(ScopeRead, s.handleFrequencyCapGet)).Methods(http.MethodGet)
 
 	// Accounts
-	r.HandleFunc("/accounts", s.handleAccountsList).Methods(http.MethodGet)
-	r.HandleFunc("/accounts/{phone}/disconnect-reason", s.handleDisconnectReason).Methods(http.MethodGet)
-	r.HandleFunc("/accounts/pair", s.handlePair).Methods(http.MethodPost)
-	r.HandleFunc("/accounts/connect", s.handleConnect).Methods(http.MethodPost)
-	r.HandleFunc("/accounts/{phone}/reconnect", s.handleReconnect).Methods(http.MethodPost)
+	r.HandleFunc("/accounts", s.auth.Require(ScopeRead, s.handleAccountsList)).Methods(http.MethodGet)
+	r.HandleFunc("/accounts/{phone}/disconnect-reason", s.auth.Require(ScopeRead, s.handleDisconnectReason)).Methods(http.MethodGet)
+	r.HandleFunc("/accounts/pair", s.auth.Require(ScopeAdmin, s.handlePair)).Methods(http.MethodPost)
+	r.HandleFunc("/accounts/connect", s.auth.Require(ScopeAdmin, s.handleConnect)).Methods(http.MethodPost)
+	r.HandleFunc("/accounts/{phone}/reconnect", s.auth.Require(ScopeAdmin, s.handleReconnect)).Methods(http.MethodPost)
 
 	// Sessions
-	r.HandleFunc("/sessions", s.handleSessions).Methods(http.MethodGet)
+	r.HandleFunc("/sessions", s.auth.Require(ScopeAdmin, s.handleSessions)).Methods(http.MethodGet)
+	r.HandleFunc("/sessions/quarantine", s.auth.Require(ScopeRead, s.handleQuarantineList)).Methods(http.MethodGet)
+	r.HandleFunc("/sessions/quarantine/{id}", s.auth.Require(ScopeRead, s.handleQuarantineGet)).Methods(http.MethodGet)
+	r.HandleFunc("/sessions/quarantine/{id}/restore", s.auth.Require(ScopeAdmin, s.handleQuarantineRestore)).Methods(http.MethodPost)
+	r.HandleFunc("/sessions/quarantine/{id}", s.auth.Require(ScopeAdmin, s.handleQuarantinePurge)).Methods(http.MethodDelete)
+
+	// Auth
+	r.HandleFunc("/auth/audit", s.auth.Require(ScopeAdmin, s.handleAuthAudit)).Methods(http.MethodGet)
 }
 
 func writeJSON(w http.ResponseWriter, status int, data interface{}) {
@@ -113,6 +214,11 @@ func writeError(w http.ResponseWriter, status int, message string) {
 	writeJSON(w, status, map[string]interface{}{"error": true, "message": message})
 }
 
+// writeErrorCode adds a machine readable code the master can act on
+func writeErrorCode(w http.ResponseWriter, status int, code, message string) {
+	writeJSON(w, status, map[string]interface{}{"error": true, "code": code, "message": message})
+}
+
 // GET /health
 func (s *Server) handleHealth(w http.ResponseWriter, r *http.Request) {
 	health := s.client.HealthSummary()
@@ -130,6 +236,7 @@ func (s *Server) handleStatus(w http.ResponseWriter, r *http.Request) {
 		"worker_id": s.WorkerID,
 		"country":   s.ProxyCountry,
 		"accounts":  s.client.GetAllAccountsStatus(),
+		"inbox":     s.client.Inbox().Stats(),
 	})
 }
 
@@ -139,6 +246,20 @@ type SendRequest struct {
 	ToPhone   string `json:"to_phone"`
 	Message   string `json:"message"`
 	Name      string `json:"name"` // For {name} replacement
+
+	// TemplateID renders a stored template instead of Message
+	TemplateID string            `json:"template_id,omitempty"`
+	Variables  map[string]string `json:"variables,omitempty"`
+	Language   string            `json:"language,omitempty"`
+
+	// IdempotencyKey makes retries safe; the Idempotency-Key header takes precedence
+	IdempotencyKey string `json:"idempotency_key,omitempty"`
+
+	// ConsentScope is the consent the recipient must have given, CONSENT_DEFAULT_SCOPE when empty
+	ConsentScope string `json:"consent_scope,omitempty"`
+
+	// Campaign selects the campaign's quiet hours window, if one is configured
+	Campaign string `json:"campaign,omitempty"`
 }
 
 // POST /send - Send a message with anti-ban
@@ -149,25 +270,75 @@ func (s *Server) handleSend(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	if req.FromPhone == "" || req.ToPhone == "" || req.Message == "" {
-		writeError(w, http.StatusBadRequest, "from_phone, to_phone, message required")
+	if req.FromPhone == "" || req.ToPhone == "" || (req.Message == "" && req.TemplateID == "") {
+		writeError(w, http.StatusBadRequest, "from_phone, to_phone, message or template_id required")
 		return
 	}
 
+	if key := strings.TrimSpace(r.Header.Get("Idempotency-Key")); key != "" {
+		req.IdempotencyKey = key
+	}
+	req.IdempotencyKey = strings.TrimSpace(req.IdempotencyKey)
+	if len(req.IdempotencyKey) > whatsapp.MaxIdempotencyKeyLength {
+		writeError(w, http.StatusBadRequest, "idempotency key must be at most 255 characters")
+		return
+	}
+
+	// Render the template first so missing variables fail before anything is sent
+	if req.TemplateID != "" {
+		message, status, err := s.renderTemplate(req.TemplateID, req.Language, req.Variables, req.Name)
+		if err != nil {
+			log.Printf("[SEND] ❌ Template %s rejected: %v", req.TemplateID, err)
+			writeError(w, status, err.Error())
+			return
+		}
+		req.Message = message
+	}
+
 	ctx, cancel := context.WithTimeout(r.Context(), 30*time.Second)
 	defer cancel()
+	ctx = whatsapp.WithSendPolicy(ctx, whatsapp.SendPolicyOptions{ConsentScope: req.ConsentScope, Campaign: req.Campaign})
 
 	// Log detailed request info
-	log.Printf("[SEND] 📤 Request: from=%s to=%s name=%q message_len=%d",
-		req.FromPhone, req.ToPhone, req.Name, len(req.Message))
+	log.Printf("[SEND] 📤 Request: from=%s to=%s name=%q template=%q message_len=%d",
+		req.FromPhone, req.ToPhone, req.Name, req.TemplateID, len(req.Message))
 
-	result, err := s.client.SendMessage(ctx, req.FromPhone, req.ToPhone, req.Message, req.Name)
+	result, replayed, err := s.idempotency.Do(req.IdempotencyKey, req.FromPhone, req.ToPhone, func() (*whatsapp.SendResult, error) {
+		return s.client.SendMessage(ctx, req.FromPhone, req.ToPhone, req.Message, req.Name)
+	})
+	if errors.Is(err, whatsapp.ErrIdempotencyConflict) {
+		writeError(w, http.StatusConflict, err.Error())
+		return
+	}
+	if errors.Is(err, whatsapp.ErrPolicyRejected) {
+		log.Printf("[SEND] 🚫 %s → %s refused: %v", req.FromPhone, req.ToPhone, err)
+		writePolicyError(w, err)
+		return
+	}
+	if errors.Is(err, whatsapp.ErrShuttingDown) {
+		writeError(w, http.StatusServiceUnavailable, err.Error())
+		return
+	}
+	if !replayed && whatsapp.SendAttempted(err) {
+		// Report the attempt to the Master for health tracking
+		go func() {
+			report := whatsapp.SendReport{FromPhone: req.FromPhone, ToPhone: req.ToPhone, Result: result, Err: err}
+			if reportErr := s.client.ReportSendToMaster(report); reportErr != nil {
+				log.Printf("[SEND] ⚠️ Failed to report send from %s to master: %v", req.FromPhone, reportErr)
+			}
+		}()
+	}
 	if err != nil {
 		log.Printf("[SEND] ❌ Error from %s to %s: %v", req.FromPhone, req.ToPhone, err)
 		writeError(w, http.StatusInternalServerError, err.Error())
 		return
 	}
 
+	if replayed {
+		log.Printf("[SEND] 🔁 %s → %s | Idempotency key %q already sent as %s",
+			req.FromPhone, req.ToPhone, req.IdempotencyKey, result.MessageID)
+	}
+
 	log.Printf("[SEND] ✅ %s → %s | MessageID: %s | Timestamp: %d",
 		req.FromPhone, req.ToPhone, result.MessageID, result.Timestamp)
 
@@ -178,6 +349,616 @@ func (s *Server) handleSend(w http.ResponseWriter, r *http.Request) {
 	})
 }
 
+// POST /jobs - Queue a send and return its job ID without waiting for it
+func (s *Server) handleJobSubmit(w http.ResponseWriter, r *http.Request) {
+	var req SendRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "Invalid JSON")
+		return
+	}
+
+	if req.FromPhone == "" || req.ToPhone == "" || (req.Message == "" && req.TemplateID == "") {
+		writeError(w, http.StatusBadRequest, "from_phone, to_phone, message or template_id required")
+		return
+	}
+
+	if key := strings.TrimSpace(r.Header.Get("Idempotency-Key")); key != "" {
+		req.IdempotencyKey = key
+	}
+	req.IdempotencyKey = strings.TrimSpace(req.IdempotencyKey)
+	if len(req.IdempotencyKey) > whatsapp.MaxIdempotencyKeyLength {
+		writeError(w, http.StatusBadRequest, "idempotency key must be at most 255 characters")
+		return
+	}
+
+	// Render now so a bad template is refused instead of failing in the queue
+	if req.TemplateID != "" {
+		message, status, err := s.renderTemplate(req.TemplateID, req.Language, req.Variables, req.Name)
+		if err != nil {
+			log.Printf("[JOBS] ❌ Template %s rejected: %v", req.TemplateID, err)
+			writeError(w, status, err.Error())
+			return
+		}
+		req.Message = message
+	}
+
+	job, existing, err := s.jobs.Submit(whatsapp.JobRequest{
+		FromPhone:      req.FromPhone,
+		ToPhone:        req.ToPhone,
+		Message:        req.Message,
+		Name:           req.Name,
+		ConsentScope:   req.ConsentScope,
+		Campaign:       req.Campaign,
+		IdempotencyKey: req.IdempotencyKey,
+	})
+	if errors.Is(err, whatsapp.ErrIdempotencyConflict) {
+		writeError(w, http.StatusConflict, err.Error())
+		return
+	}
+	if errors.Is(err, whatsapp.ErrShuttingDown) {
+		writeError(w, http.StatusServiceUnavailable, err.Error())
+		return
+	}
+	if err != nil {
+		log.Printf("[JOBS] ❌ Failed to queue %s → %s: %v", req.FromPhone, req.ToPhone, err)
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	if existing {
+		log.Printf("[JOBS] 🔁 %s → %s | Idempotency key %q already queued as job %s",
+			req.FromPhone, req.ToPhone, req.IdempotencyKey, job.ID)
+	} else {
+		log.Printf("[JOBS] 📥 %s → %s | Queued job %s", req.FromPhone, req.ToPhone, job.ID)
+	}
+
+	writeJSON(w, http.StatusAccepted, map[string]interface{}{
+		"success": true,
+		"job_id":  job.ID,
+		"status":  job.Status,
+	})
+}
+
+// GET /jobs?from_phone=&status= - List jobs, newest first
+func (s *Server) handleJobsList(w http.ResponseWriter, r *http.Request) {
+	jobs := s.jobs.List(r.URL.Query().Get("from_phone"), r.URL.Query().Get("status"))
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"jobs":  jobs,
+		"total": len(jobs),
+	})
+}
+
+// GET /jobs/{id} - Status of a job, with the message ID once sent
+func (s *Server) handleJobGet(w http.ResponseWriter, r *http.Request) {
+	job, err := s.jobs.Get(mux.Vars(r)["id"])
+	if err != nil {
+		writeError(w, http.StatusNotFound, err.Error())
+		return
+	}
+	writeJSON(w, http.StatusOK, job)
+}
+
+// DELETE /jobs/{id} - Cancel a queued or running job
+func (s *Server) handleJobCancel(w http.ResponseWriter, r *http.Request) {
+	job, err := s.jobs.Cancel(mux.Vars(r)["id"])
+	switch {
+	case errors.Is(err, whatsapp.ErrJobNotFound):
+		writeError(w, http.StatusNotFound, err.Error())
+		return
+	case errors.Is(err, whatsapp.ErrJobFinished):
+		writeError(w, http.StatusConflict, err.Error())
+		return
+	case err != nil:
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	log.Printf("[JOBS] 🛑 Cancel requested for job %s (%s)", job.ID, job.Status)
+	writeJSON(w, http.StatusAccepted, job)
+}
+
+// writePolicyError answers a refused send with 403. Refusals that can be
+// retried later, such as quiet hours, say when in Retry-After and retry_at.
+func writePolicyError(w http.ResponseWriter, err error) {
+	response := map[string]interface{}{"error": true, "code": policyErrorCode(err), "message": err.Error()}
+	var policyErr *whatsapp.PolicyError
+	if errors.As(err, &policyErr) && policyErr.Deferrable() {
+		seconds := int(time.Until(policyErr.RetryAt).Seconds()) + 1
+		w.Header().Set("Retry-After", strconv.Itoa(seconds))
+		response["retry_at"] = policyErr.RetryAt
+	}
+	var capErr *whatsapp.FrequencyCapError
+	if errors.As(err, &capErr) {
+		response["next_allowed"] = capErr.NextAllowed
+	}
+	writeJSON(w, http.StatusForbidden, response)
+}
+
+// policyErrorCode returns the machine readable code of a policy rejection
+func policyErrorCode(err error) string {
+	_, code := whatsapp.PolicyCode(err)
+	return code
+}
+
+// renderTemplate renders a stored template for a send. The name given for the
+// {name} shortcut also fills a "name" variable when none was supplied.
+func (s *Server) renderTemplate(id, language string, variables map[string]string, name string) (string, int, error) {
+	if name != "" {
+		if _, exists := variables["name"]; !exists {
+			merged := map[string]string{"name": name}
+			for k, v := range variables {
+				merged[k] = v
+			}
+			variables = merged
+		}
+	}
+
+	message, _, err := s.templates.Render(id, language, variables)
+	return message, templateErrorStatus(err), err
+}
+
+// templateErrorStatus maps template store errors to HTTP status codes
+func templateErrorStatus(err error) int {
+	var templateErr *whatsapp.TemplateError
+	switch {
+	case err == nil:
+		return http.StatusOK
+	case errors.Is(err, whatsapp.ErrTemplateNotFound):
+		return http.StatusNotFound
+	case errors.As(err, &templateErr):
+		return http.StatusBadRequest
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+// GET /templates
+func (s *Server) handleTemplatesList(w http.ResponseWriter, r *http.Request) {
+	templates := s.templates.List()
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"total":     len(templates),
+		"templates": templates,
+	})
+}
+
+// POST /templates - Create or replace a template
+func (s *Server) handleTemplateSave(w http.ResponseWriter, r *http.Request) {
+	var req whatsapp.MessageTemplate
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "Invalid JSON")
+		return
+	}
+
+	saved, err := s.templates.Save(req)
+	if err != nil {
+		writeError(w, templateErrorStatus(err), err.Error())
+		return
+	}
+
+	writeJSON(w, http.StatusOK, saved)
+}
+
+// GET /templates/{id}
+func (s *Server) handleTemplateGet(w http.ResponseWriter, r *http.Request) {
+	template, err := s.templates.Get(mux.Vars(r)["id"])
+	if err != nil {
+		writeError(w, templateErrorStatus(err), err.Error())
+		return
+	}
+
+	writeJSON(w, http.StatusOK, template)
+}
+
+// DELETE /templates/{id}
+func (s *Server) handleTemplateDelete(w http.ResponseWriter, r *http.Request) {
+	id := mux.Vars(r)["id"]
+	if err := s.templates.Delete(id); err != nil {
+		writeError(w, templateErrorStatus(err), err.Error())
+		return
+	}
+
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"success": true,
+		"id":      id,
+	})
+}
+
+// POST /templates/{id}/render - Preview a template without sending it
+func (s *Server) handleTemplateRender(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		Language  string            `json:"language"`
+		Variables map[string]string `json:"variables"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "Invalid JSON")
+		return
+	}
+
+	id := mux.Vars(r)["id"]
+	message, language, err := s.templates.Render(id, req.Language, req.Variables)
+	if err != nil {
+		writeError(w, templateErrorStatus(err), err.Error())
+		return
+	}
+
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"id":       id,
+		"language": language,
+		"message":  message,
+	})
+}
+
+// GET /suppressions - List recipients that opted out
+func (s *Server) handleSuppressionsList(w http.ResponseWriter, r *http.Request) {
+	entries := s.client.Suppressions().List()
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"total":        len(entries),
+		"suppressions": entries,
+	})
+}
+
+// SuppressionRequest for POST /suppressions
+type SuppressionRequest struct {
+	Phone  string `json:"phone"`
+	Reason string `json:"reason"`
+	Actor  string `json:"actor"` // who made the change, kept in the audit trail
+}
+
+// POST /suppressions - Suppress a recipient by hand
+func (s *Server) handleSuppressionAdd(w http.ResponseWriter, r *http.Request) {
+	var req SuppressionRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "Invalid JSON")
+		return
+	}
+
+	entry, added, err := s.client.Suppressions().Add(whatsapp.SuppressionEntry{
+		Phone:  req.Phone,
+		Source: whatsapp.SuppressionSourceAPI,
+		Reason: req.Reason,
+		Actor:  req.Actor,
+	})
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if added {
+		log.Printf("[SUPPRESSION] 🚫 +%s suppressed by %q: %s", entry.Phone, req.Actor, req.Reason)
+	}
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"success":     true,
+		"added":       added,
+		"suppression": entry,
+	})
+}
+
+// DELETE /suppressions/{phone}?actor=&reason= - Allow messaging a recipient again
+func (s *Server) handleSuppressionRemove(w http.ResponseWriter, r *http.Request) {
+	phone := mux.Vars(r)["phone"]
+	actor := r.URL.Query().Get("actor")
+	reason := r.URL.Query().Get("reason")
+
+	err := s.client.Suppressions().Remove(phone, actor, reason)
+	if errors.Is(err, whatsapp.ErrSuppressionNotFound) {
+		writeError(w, http.StatusNotFound, err.Error())
+		return
+	}
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	log.Printf("[SUPPRESSION] ✅ %s removed from the list by %q: %s", phone, actor, reason)
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"success": true,
+		"phone":   phone,
+	})
+}
+
+// GET /suppressions/audit?phone=&limit= - Changes to the list, newest first
+func (s *Server) handleSuppressionAudit(w http.ResponseWriter, r *http.Request) {
+	limit := 100
+	if value := r.URL.Query().Get("limit"); value != "" {
+		parsed, err := strconv.Atoi(value)
+		if err != nil || parsed < 0 {
+			writeError(w, http.StatusBadRequest, "limit must be a non-negative number")
+			return
+		}
+		limit = parsed
+	}
+
+	audit := s.client.Suppressions().Audit(r.URL.Query().Get("phone"), limit)
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"total": len(audit),
+		"audit": audit,
+	})
+}
+
+// GET /consent?phone= - List recorded consent
+func (s *Server) handleConsentList(w http.ResponseWriter, r *http.Request) {
+	consents := s.client.Consents().List(r.URL.Query().Get("phone"))
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"required": s.client.Consents().Required(),
+		"total":    len(consents),
+		"consents": consents,
+	})
+}
+
+// ConsentImportRequest for POST /consent
+type ConsentImportRequest struct {
+	Records []whatsapp.ConsentRecord `json:"records"`
+}
+
+// POST /consent - Record consent given by recipients
+func (s *Server) handleConsentImport(w http.ResponseWriter, r *http.Request) {
+	var req ConsentImportRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "Invalid JSON")
+		return
+	}
+	if len(req.Records) == 0 {
+		writeError(w, http.StatusBadRequest, "records required")
+		return
+	}
+
+	imported, importErrs, err := s.client.Consents().Import(req.Records)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	log.Printf("[CONSENT] 📋 Imported %d records, skipped %d", imported, len(importErrs))
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"success":  len(importErrs) == 0,
+		"imported": imported,
+		"errors":   importErrs,
+	})
+}
+
+// POST /consent/import - Import consent from CSV, sent as the body or as a
+// multipart "file" field
+func (s *Server) handleConsentImportCSV(w http.ResponseWriter, r *http.Request) {
+	body := r.Body
+	if strings.HasPrefix(r.Header.Get("Content-Type"), "multipart/form-data") {
+		file, _, err := r.FormFile("file")
+		if err != nil {
+			writeError(w, http.StatusBadRequest, "file required")
+			return
+		}
+		defer file.Close()
+		body = file
+	}
+
+	imported, importErrs, err := s.client.Consents().ImportCSV(body)
+	if errors.Is(err, whatsapp.ErrConsentNotSaved) {
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	log.Printf("[CONSENT] 📋 Imported %d records from CSV, skipped %d", imported, len(importErrs))
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"success":  len(importErrs) == 0,
+		"imported": imported,
+		"errors":   importErrs,
+	})
+}
+
+// ConsentCheckRequest for POST /consent/check
+type ConsentCheckRequest struct {
+	Recipients []string `json:"recipients"`
+	Scope      string   `json:"scope"`
+	Campaign   string   `json:"campaign,omitempty"`
+}
+
+// POST /consent/check - Screen recipients against the pre-send policies
+// before queueing a campaign
+func (s *Server) handleConsentCheck(w http.ResponseWriter, r *http.Request) {
+	var req ConsentCheckRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "Invalid JSON")
+		return
+	}
+	if len(req.Recipients) == 0 {
+		writeError(w, http.StatusBadRequest, "recipients required")
+		return
+	}
+
+	ctx := whatsapp.WithSendPolicy(r.Context(), whatsapp.SendPolicyOptions{ConsentScope: req.Scope, Campaign: req.Campaign})
+	results := make([]map[string]interface{}, 0, len(req.Recipients))
+	rejected := 0
+	for _, phone := range req.Recipients {
+		result := map[string]interface{}{"phone": phone, "allowed": true}
+		if err := s.client.CheckSendPolicy(ctx, phone); err != nil {
+			result["allowed"] = false
+			result["code"] = policyErrorCode(err)
+			result["reason"] = err.Error()
+			var policyErr *whatsapp.PolicyError
+			if errors.As(err, &policyErr) && policyErr.Deferrable() {
+				result["retry_at"] = policyErr.RetryAt
+			}
+			rejected++
+		}
+		results = append(results, result)
+	}
+
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"total":    len(results),
+		"rejected": rejected,
+		"results":  results,
+	})
+}
+
+// DELETE /consent/{phone}?scope= - Revoke consent, for every scope when none is given
+func (s *Server) handleConsentRevoke(w http.ResponseWriter, r *http.Request) {
+	phone := mux.Vars(r)["phone"]
+	scope := r.URL.Query().Get("scope")
+
+	err := s.client.Consents().Revoke(phone, scope)
+	if errors.Is(err, whatsapp.ErrConsentNotFound) {
+		writeError(w, http.StatusNotFound, err.Error())
+		return
+	}
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"success": true,
+		"phone":   phone,
+		"scope":   scope,
+	})
+}
+
+// GET /quiet-hours - Current quiet hours policy
+func (s *Server) handleQuietHoursGet(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, s.client.QuietHours().Config())
+}
+
+// PUT /quiet-hours - Replace the quiet hours policy
+func (s *Server) handleQuietHoursSet(w http.ResponseWriter, r *http.Request) {
+	var req whatsapp.QuietHoursConfig
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "Invalid JSON")
+		return
+	}
+
+	config, err := s.client.QuietHours().SetConfig(req)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	log.Printf("[QUIET] 🌙 Policy updated: enabled=%v default=%s-%s mode=%s countries=%d campaigns=%d",
+		config.Enabled, config.Default.Start, config.Default.End, config.Mode, len(config.Countries), len(config.Campaigns))
+	writeJSON(w, http.StatusOK, config)
+}
+
+// GET /quiet-hours/check?phone=&campaign= - Recipient's local time and window
+func (s *Server) handleQuietHoursCheck(w http.ResponseWriter, r *http.Request) {
+	phone := r.URL.Query().Get("phone")
+	if phone == "" {
+		writeError(w, http.StatusBadRequest, "phone required")
+		return
+	}
+	writeJSON(w, http.StatusOK, s.client.QuietHours().Decide(phone, r.URL.Query().Get("campaign"), time.Now()))
+}
+
+// GET /messages?account=&status=&limit= - Tracked sent messages, newest first
+func (s *Server) handleMessagesList(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+
+	limit := 100
+	if value := query.Get("limit"); value != "" {
+		parsed, err := strconv.Atoi(value)
+		if err != nil || parsed < 1 {
+			writeError(w, http.StatusBadRequest, "limit must be a positive number")
+			return
+		}
+		limit = parsed
+	}
+
+	messages := s.client.Delivery().List(query.Get("account"), query.Get("status"), limit)
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"messages": messages,
+		"total":    len(messages),
+	})
+}
+
+// GET /messages/{id} - Sent, delivered, read and failed times of a message
+func (s *Server) handleMessageGet(w http.ResponseWriter, r *http.Request) {
+	msg, err := s.client.Delivery().Get(mux.Vars(r)["id"])
+	if err != nil {
+		writeError(w, http.StatusNotFound, err.Error())
+		return
+	}
+	writeJSON(w, http.StatusOK, msg)
+}
+
+// GET /accounts/{phone}/delivery - Delivery and read rates of an account
+func (s *Server) handleAccountDelivery(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, s.client.Delivery().Stats(mux.Vars(r)["phone"]))
+}
+
+// GET /inbox?account=&since=&limit= - Stored inbound messages, newest first
+func (s *Server) handleInboxList(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+
+	var since time.Time
+	if value := query.Get("since"); value != "" {
+		parsed, err := time.Parse(time.RFC3339, value)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, "since must be an RFC 3339 time")
+			return
+		}
+		since = parsed
+	}
+	limit := 100
+	if value := query.Get("limit"); value != "" {
+		parsed, err := strconv.Atoi(value)
+		if err != nil || parsed < 1 {
+			writeError(w, http.StatusBadRequest, "limit must be a positive number")
+			return
+		}
+		limit = parsed
+	}
+
+	messages := s.client.Inbox().List(query.Get("account"), since, limit)
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"messages": messages,
+		"total":    len(messages),
+	})
+}
+
+// GET /inbox/{phone}/{id} - One inbound message
+func (s *Server) handleInboxGet(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	msg, err := s.client.Inbox().Get(vars["phone"], vars["id"])
+	if err != nil {
+		writeError(w, http.StatusNotFound, err.Error())
+		return
+	}
+	writeJSON(w, http.StatusOK, msg)
+}
+
+// GET /inbox/{phone}/{id}/media - Downloaded media of an inbound message
+func (s *Server) handleInboxMedia(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	path, media, err := s.client.Inbox().MediaPath(vars["phone"], vars["id"])
+	if err != nil {
+		writeError(w, http.StatusNotFound, err.Error())
+		return
+	}
+
+	w.Header().Set("Content-Type", media.MimeType)
+	if media.FileName != "" {
+		w.Header().Set("Content-Disposition", mime.FormatMediaType("attachment", map[string]string{"filename": media.FileName}))
+	}
+	http.ServeFile(w, r, path)
+}
+
+// GET /frequency-cap/{phone} - Messages a recipient got from this worker within the cap window
+func (s *Server) handleFrequencyCapGet(w http.ResponseWriter, r *http.Request) {
+	phone := mux.Vars(r)["phone"]
+	capStore := s.client.FrequencyCap()
+
+	response := map[string]interface{}{
+		"phone":   phone,
+		"enabled": capStore.Enabled(),
+		"sent":    capStore.Count(phone),
+		"allowed": true,
+	}
+	if err := capStore.Check(phone); err != nil {
+		response["allowed"] = false
+		response["reason"] = err.Error()
+	}
+	writeJSON(w, http.StatusOK, response)
+}
+
 // GET /accounts
 func (s *Server) handleAccountsList(w http.ResponseWriter, r *http.Request) {
 	accounts := s.client.GetAllAccountsStatus()
@@ -411,3 +1192,106 @@ func (s *Server) handleSessions(w http.ResponseWriter, r *http.Request) {
 		"sessions": accounts,
 	})
 }
+
+// GET /sessions/quarantine?phone= - Sessions of expired accounts, newest first
+func (s *Server) handleQuarantineList(w http.ResponseWriter, r *http.Request) {
+	sessions, err := s.client.Quarantine().List(r.URL.Query().Get("phone"))
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"total":    len(sessions),
+		"sessions": sessions,
+	})
+}
+
+// GET /sessions/quarantine/{id} - One quarantined session and why it was quarantined
+func (s *Server) handleQuarantineGet(w http.ResponseWriter, r *http.Request) {
+	session, err := s.client.Quarantine().Get(mux.Vars(r)["id"])
+	if errors.Is(err, whatsapp.ErrQuarantineNotFound) {
+		writeError(w, http.StatusNotFound, err.Error())
+		return
+	}
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	writeJSON(w, http.StatusOK, session)
+}
+
+// POST /sessions/quarantine/{id}/restore - Move a session back and load it
+func (s *Server) handleQuarantineRestore(w http.ResponseWriter, r *http.Request) {
+	ctx, cancel := context.WithTimeout(r.Context(), 60*time.Second)
+	defer cancel()
+
+	session, loaded, err := s.client.RestoreQuarantinedSession(ctx, mux.Vars(r)["id"])
+	if errors.Is(err, whatsapp.ErrQuarantineNotFound) {
+		writeError(w, http.StatusNotFound, err.Error())
+		return
+	}
+	if errors.Is(err, whatsapp.ErrSessionExists) {
+		writeError(w, http.StatusConflict, err.Error())
+		return
+	}
+	if err != nil && session == nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	response := map[string]interface{}{
+		"success": true,
+		"session": session,
+		"loaded":  loaded,
+	}
+	if err != nil {
+		// The files are back, the account can still be reconnected
+		response["warning"] = err.Error()
+	} else if !loaded {
+		response["warning"] = "session restored but not logged in, the account needs pairing"
+	}
+	writeJSON(w, http.StatusOK, response)
+}
+
+// DELETE /sessions/quarantine/{id} - Delete a quarantined session for good
+func (s *Server) handleQuarantinePurge(w http.ResponseWriter, r *http.Request) {
+	session, err := s.client.Quarantine().Purge(mux.Vars(r)["id"])
+	if errors.Is(err, whatsapp.ErrQuarantineNotFound) {
+		writeError(w, http.StatusNotFound, err.Error())
+		return
+	}
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	log.Printf("[QUARANTINE] 🗑️ Purged session of %s (%s)", session.Phone, session.ID)
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"success": true,
+		"id":      session.ID,
+		"phone":   session.Phone,
+	})
+}
+
+// GET /auth/audit?limit= - API calls refused for a missing token, scope or signature, newest first
+func (s *Server) handleAuthAudit(w http.ResponseWriter, r *http.Request) {
+	limit := 100
+	if value := r.URL.Query().Get("limit"); value != "" {
+		parsed, err := strconv.Atoi(value)
+		if err != nil || parsed < 0 {
+			writeError(w, http.StatusBadRequest, "limit must be a non-negative number")
+			return
+		}
+		limit = parsed
+	}
+
+	audit, err := s.auth.Audit(limit)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"enabled": s.auth.Enabled(),
+		"total":   len(audit),
+		"audit":   audit,
+	})
+}
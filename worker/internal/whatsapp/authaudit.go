@@ -0,0 +1,101 @@
+package whatsapp
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// AuthAuditEntry records one API call the worker refused
+type AuthAuditEntry struct {
+	At       time.Time `json:"at"`
+	Method   string    `json:"method"`
+	Path     string    `json:"path"`
+	RemoteIP string    `json:"remote_ip"`
+	Token    string    `json:"token,omitempty"` // name of the token used, when it was valid
+	Scope    string    `json:"scope,omitempty"` // scope the route requires
+	Status   int       `json:"status"`
+	Reason   string    `json:"reason"`
+}
+
+// AuthAuditLog appends refused API calls to a JSON lines file next to the
+// sessions. Appending keeps a flood of bad requests cheap to record.
+type AuthAuditLog struct {
+	path string
+	mu   sync.Mutex
+}
+
+// NewAuthAuditLog writes to <sessions dir>/auth_audit.log
+func NewAuthAuditLog() *AuthAuditLog {
+	return &AuthAuditLog{path: filepath.Join(getSessionsDir(), "auth_audit.log")}
+}
+
+// Record appends an entry. Failures are logged, a refused call is refused
+// whether or not it could be recorded.
+func (a *AuthAuditLog) Record(entry AuthAuditEntry) {
+	if entry.At.IsZero() {
+		entry.At = time.Now().UTC()
+	}
+	line, err := json.Marshal(entry)
+	if err != nil {
+		return
+	}
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	if err := os.MkdirAll(filepath.Dir(a.path), 0755); err != nil {
+		log.Printf("[AUTH] ⚠️ Failed to create audit directory: %v", err)
+		return
+	}
+	f, err := os.OpenFile(a.path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600)
+	if err != nil {
+		log.Printf("[AUTH] ⚠️ Failed to open %s: %v", a.path, err)
+		return
+	}
+	defer f.Close()
+	if _, err := f.Write(append(line, '\n')); err != nil {
+		log.Printf("[AUTH] ⚠️ Failed to write %s: %v", a.path, err)
+	}
+}
+
+// List returns the most recent entries, newest first
+func (a *AuthAuditLog) List(limit int) ([]AuthAuditEntry, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	entries := make([]AuthAuditEntry, 0)
+	f, err := os.Open(a.path)
+	if os.IsNotExist(err) {
+		return entries, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to open auth audit log: %w", err)
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		var entry AuthAuditEntry
+		if err := json.Unmarshal(scanner.Bytes(), &entry); err != nil {
+			continue // a line cut short by a crash
+		}
+		entries = append(entries, entry)
+		if limit > 0 && len(entries) > limit {
+			entries = entries[1:]
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read auth audit log: %w", err)
+	}
+
+	for i, j := 0, len(entries)-1; i < j; i, j = i+1, j-1 {
+		entries[i], entries[j] = entries[j], entries[i]
+	}
+	return entries, nil
+}
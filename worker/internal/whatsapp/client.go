@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"log"
 	"math/rand"
@@ -55,6 +56,27 @@ type ClientManager struct {
 	proxyPool   *config.ProxyPool
 
 	heartbeat *HeartbeatManager
+
+	// suppressions, consents, quiet hours and the frequency cap are checked
+	// before every send
+	suppressions *SuppressionStore
+	consents     *ConsentStore
+	quietHours   *QuietHoursStore
+	frequency    *FrequencyCapStore
+
+	// inbox stores inbound messages and forwards them
+	inbox *InboxStore
+
+	// delivery links receipts back to sent messages
+	delivery *DeliveryTracker
+
+	// quarantine holds the sessions of accounts that could not be revived
+	quarantine *QuarantineStore
+
+	// Shutdown refuses new sends and waits for inFlight ones
+	shutdownMu   sync.Mutex
+	shuttingDown bool
+	inFlight     sync.WaitGroup
 }
 
 // AccountClient represents a connected WhatsApp account
@@ -108,9 +130,51 @@ func NewClientManager(fp fingerprint.DeviceFingerprint, proxyCountry, workerID s
 		accounts:     make(map[string]*AccountClient),
 		proxyConfig:  proxyConfig,
 		proxyPool:    proxyPool,
+		suppressions: NewSuppressionStore(),
+		consents:     NewConsentStore(),
+		quietHours:   NewQuietHoursStore(),
+		frequency:    NewFrequencyCapStore(workerID),
+		inbox:        NewInboxStore(workerID),
+		delivery:     NewDeliveryTracker(workerID),
+		quarantine:   NewQuarantineStore(),
 	}
 }
 
+// Suppressions returns the opt-out list shared by all accounts
+func (m *ClientManager) Suppressions() *SuppressionStore {
+	return m.suppressions
+}
+
+// Consents returns the consent registry shared by all accounts
+func (m *ClientManager) Consents() *ConsentStore {
+	return m.consents
+}
+
+// QuietHours returns the recipient-local quiet hours policy
+func (m *ClientManager) QuietHours() *QuietHoursStore {
+	return m.quietHours
+}
+
+// FrequencyCap returns the per-recipient cap shared by all accounts
+func (m *ClientManager) FrequencyCap() *FrequencyCapStore {
+	return m.frequency
+}
+
+// Inbox returns the inbound messages of all accounts
+func (m *ClientManager) Inbox() *InboxStore {
+	return m.inbox
+}
+
+// Delivery returns the delivery and read tracking of sent messages
+func (m *ClientManager) Delivery() *DeliveryTracker {
+	return m.delivery
+}
+
+// Quarantine returns the sessions of expired accounts
+func (m *ClientManager) Quarantine() *QuarantineStore {
+	return m.quarantine
+}
+
 // GetProxyPool returns the proxy pool (for handlers to access stats)
 func (m *ClientManager) GetProxyPool() *config.ProxyPool {
 	return m.proxyPool
@@ -794,12 +858,15 @@ func (m *ClientManager) handleEvent(phone string, evt interface{}) {
 		}
 
 	case *events.Receipt:
-		// Track message delivery for Delivery Rate calculation
-		if v.Type == events.ReceiptTypeDelivered || v.Type == events.ReceiptTypeRead {
+		// Track message delivery for Delivery Rate calculation, counting each
+		// message once however many receipts it gets
+		delivered := m.delivery.ApplyReceipt(phone, v.MessageIDs, v.Type, v.Timestamp)
+		if delivered > 0 {
 			acc.mu.Lock()
-			acc.MessagesDelivered++
+			acc.MessagesDelivered += delivered
 			acc.mu.Unlock()
-
+		}
+		if v.Type == events.ReceiptTypeDelivered || v.Type == events.ReceiptTypeRead {
 			// Update health - message was delivered
 			if health := m.GetAccountHealth(phone); health != nil {
 				health.LastAlive = time.Now()
@@ -936,6 +1003,11 @@ func (m *ClientManager) attemptSmartReconnect(phone string) {
 // SendMessage sends a message with anti-ban measures
 // v8.0: Simplified - no warmup checks, just anti-ban
 func (m *ClientManager) SendMessage(ctx context.Context, fromPhone, toPhone, message string, name ...string) (*SendResult, error) {
+	if err := m.beginSend(); err != nil {
+		return nil, err
+	}
+	defer m.inFlight.Done()
+
 	m.mu.RLock()
 	acc, exists := m.accounts[fromPhone]
 	m.mu.RUnlock()
@@ -961,6 +1033,30 @@ func (m *ClientManager) SendMessage(ctx context.Context, fromPhone, toPhone, mes
 		return nil, fmt.Errorf("invalid recipient phone: %w", err)
 	}
 
+	// === POLICY: opt-outs, consent, quiet hours and frequency cap ===
+	// The cap slot is reserved up front so parallel sends from other accounts
+	// see it, and given back unless the message goes out.
+	releaseCap := func() {}
+	err = m.CheckSendPolicy(ctx, toPhone)
+	if err == nil {
+		releaseCap, err = m.frequency.Reserve(fromPhone, toPhone)
+	}
+	if err != nil {
+		log.Printf("[%s] 🚫 Refusing send to %s: %v", fromPhone, toPhone, err)
+		// Deferrable refusals are retried later, so campaigns must not drop them
+		var policyErr *PolicyError
+		if !errors.As(err, &policyErr) || !policyErr.Deferrable() {
+			go m.reportPolicyRejectionToMaster(fromPhone, toPhone, err)
+		}
+		return nil, err
+	}
+	sent := false
+	defer func() {
+		if !sent {
+			releaseCap()
+		}
+	}()
+
 	// Get name for {name} replacement
 	contactName := ""
 	if len(name) > 0 {
@@ -1024,11 +1120,9 @@ func (m *ClientManager) SendMessage(ctx context.Context, fromPhone, toPhone, mes
 	log.Printf("[%s] 📤 Sending message to JID: %s (phone: %s) | Message length: %d chars | Name: %q",
 		fromPhone, recipientJID.String(), toPhone, len(variedMessage), contactName)
 
-	// Send message
-	resp, err := acc.Client.SendMessage(ctx, recipientJID, msg)
-
-	// === Report to Master for health tracking ===
-	go m.reportMessageToMaster(fromPhone, err == nil, err)
+	// Send message, with an ID chosen up front so failures can be tracked too
+	messageID := acc.Client.GenerateMessageID()
+	resp, err := acc.Client.SendMessage(ctx, recipientJID, msg, whatsmeow.SendRequestExtra{ID: messageID})
 
 	if err != nil {
 		// Track failed message for delivery rate
@@ -1044,9 +1138,13 @@ func (m *ClientManager) SendMessage(ctx context.Context, fromPhone, toPhone, mes
 		if isProxyError(err) {
 			log.Printf("[%s] Proxy error detected, will rotate on next message", fromPhone)
 		}
-		return nil, fmt.Errorf("failed to send message: %w", err)
+		m.delivery.TrackFailed(messageID, fromPhone, toPhone, err)
+		return nil, fmt.Errorf("%w: %w", ErrSendFailed, err)
 	}
 
+	sent = true
+	m.delivery.TrackSent(resp.ID, fromPhone, toPhone, resp.Timestamp)
+
 	// Increment message counters
 	acc.mu.Lock()
 	acc.SessionMsgCount++
@@ -1352,40 +1450,95 @@ func calculateTypingDelay(message string) time.Duration {
 	return time.Duration(totalMs) * time.Millisecond
 }
 
-// reportMessageToMaster reports message success/failure to Master for health tracking
-func (m *ClientManager) reportMessageToMaster(phone string, success bool, sendErr error) {
+// ErrSendFailed wraps errors returned by WhatsApp for the send itself, as
+// opposed to sends refused or abandoned before reaching WhatsApp
+var ErrSendFailed = errors.New("failed to send message")
+
+// SendAttempted reports whether a SendMessage outcome reached WhatsApp
+func SendAttempted(err error) bool {
+	return err == nil || errors.Is(err, ErrSendFailed)
+}
+
+// SendReport is the outcome of a send pushed to the Master for health
+// tracking. JobID and Status are set for sends made through the job queue.
+type SendReport struct {
+	FromPhone string
+	ToPhone   string
+	JobID     string
+	Status    string
+	Result    *SendResult
+	Err       error
+}
+
+// ReportSendToMaster pushes the outcome of a send to the Master, retrying up
+// to 3 times. The error tells the caller whether the Master has it.
+func (m *ClientManager) ReportSendToMaster(report SendReport) error {
 	masterURL := os.Getenv("MASTER_URL")
 	if masterURL == "" {
 		masterURL = "http://master:5000"
 	}
 
-	url := fmt.Sprintf("%s/api/accounts/%s/health/message", masterURL, phone)
+	url := fmt.Sprintf("%s/api/accounts/%s/health/message", masterURL, report.FromPhone)
 
 	payload := map[string]interface{}{
-		"success": success,
+		"success":   report.Err == nil,
+		"attempted": SendAttempted(report.Err),
+		"worker_id": m.WorkerID,
+		"to_phone":  report.ToPhone,
+	}
+	if report.Err != nil {
+		payload["error"] = report.Err.Error()
 	}
-	if sendErr != nil {
-		payload["error"] = sendErr.Error()
+	if report.Result != nil {
+		payload["message_id"] = report.Result.MessageID
+		payload["timestamp"] = report.Result.Timestamp
+	}
+	if report.JobID != "" {
+		payload["job_id"] = report.JobID
+		payload["status"] = report.Status
 	}
 
 	jsonData, err := json.Marshal(payload)
 	if err != nil {
-		return
+		return fmt.Errorf("failed to marshal send report: %w", err)
 	}
 
-	req, err := http.NewRequest("POST", url, bytes.NewBuffer(jsonData))
-	if err != nil {
-		return
+	var lastErr error
+	for attempt := 1; attempt <= 3; attempt++ {
+		req, err := http.NewRequest("POST", url, bytes.NewBuffer(jsonData))
+		if err != nil {
+			return fmt.Errorf("failed to create send report request: %w", err)
+		}
+
+		req.Header.Set("Content-Type", "application/json")
+		setMasterAuth(req)
+
+		client := &http.Client{Timeout: 5 * time.Second}
+		resp, err := client.Do(req)
+		if err != nil {
+			lastErr = err
+		} else {
+			resp.Body.Close()
+			if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+				return nil
+			}
+			lastErr = fmt.Errorf("master returned status %d", resp.StatusCode)
+		}
+
+		if attempt < 3 {
+			time.Sleep(time.Duration(attempt) * 2 * time.Second)
+		}
 	}
 
-	req.Header.Set("Content-Type", "application/json")
+	return lastErr
+}
 
-	client := &http.Client{Timeout: 5 * time.Second}
-	resp, err := client.Do(req)
-	if err != nil {
-		return
+// setMasterAuth identifies the worker to the Master's worker routes with the
+// shared WORKER_API_SECRET
+func setMasterAuth(req *http.Request) {
+	if secret := os.Getenv("WORKER_API_SECRET"); secret != "" {
+		req.Header.Set("Authorization", "Bearer "+secret)
 	}
-	defer resp.Body.Close()
 }
 
 // SendResult represents the result of sending a message
@@ -0,0 +1,408 @@
+package whatsapp
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Consent policy codes reported to the caller and the master
+const (
+	ConsentCodeMissing = "consent_missing"
+	ConsentCodeExpired = "consent_expired"
+)
+
+// ConsentScopeAll is a scope that covers every kind of contact
+const ConsentScopeAll = "all"
+
+// DefaultConsentScope is checked for sends that don't name a scope
+const DefaultConsentScope = "marketing"
+
+// ErrConsentNotFound is returned when revoking consent that was never recorded
+var ErrConsentNotFound = errors.New("no consent recorded for this phone and scope")
+
+// ErrConsentNotSaved is returned when imported records could not be written;
+// none of them are kept
+var ErrConsentNotSaved = errors.New("failed to save consent")
+
+// ConsentRecord is a recipient's agreement to be contacted for one scope
+type ConsentRecord struct {
+	Phone     string     `json:"phone"`
+	Scope     string     `json:"scope"`
+	Source    string     `json:"source"` // where it was given, e.g. "signup-form"
+	GrantedAt time.Time  `json:"granted_at"`
+	ExpiresAt *time.Time `json:"expires_at,omitempty"`
+	UpdatedAt time.Time  `json:"updated_at"`
+}
+
+// ConsentImportError is a record the import skipped. Line is the CSV line,
+// or the 1-based position of the record in a JSON import.
+type ConsentImportError struct {
+	Line  int    `json:"line"`
+	Phone string `json:"phone,omitempty"`
+	Error string `json:"error"`
+}
+
+// ConsentStore keeps recipient consent in a JSON file next to the sessions.
+// Sends are only checked against it when CONSENT_REQUIRED is true. Each
+// worker has its own registry, so consent must be imported into every worker
+// whose accounts may send to the recipient.
+type ConsentStore struct {
+	path         string
+	required     bool
+	defaultScope string
+	records      map[string]map[string]*ConsentRecord // phone -> scope -> record
+	mu           sync.RWMutex
+}
+
+// NewConsentStore loads records from <sessions dir>/consent.json.
+// CONSENT_DEFAULT_SCOPE sets the scope checked when a send names none.
+func NewConsentStore() *ConsentStore {
+	s := &ConsentStore{
+		path:         filepath.Join(getSessionsDir(), "consent.json"),
+		required:     os.Getenv("CONSENT_REQUIRED") == "true",
+		defaultScope: DefaultConsentScope,
+		records:      make(map[string]map[string]*ConsentRecord),
+	}
+	if value := strings.TrimSpace(os.Getenv("CONSENT_DEFAULT_SCOPE")); value != "" {
+		s.defaultScope = strings.ToLower(value)
+	}
+
+	data, err := os.ReadFile(s.path)
+	if err != nil {
+		if !os.IsNotExist(err) {
+			log.Printf("[CONSENT] ⚠️ Failed to read %s: %v", s.path, err)
+		}
+		return s
+	}
+
+	var records []*ConsentRecord
+	if err := json.Unmarshal(data, &records); err != nil {
+		log.Printf("[CONSENT] ⚠️ Failed to parse %s: %v", s.path, err)
+		return s
+	}
+	for _, r := range records {
+		s.putLocked(r)
+	}
+
+	log.Printf("[CONSENT] 📋 Loaded consent for %d recipients (required: %v)", len(s.records), s.required)
+	return s
+}
+
+// Required reports whether sends are checked against the registry
+func (s *ConsentStore) Required() bool {
+	return s.required
+}
+
+// Check returns a *PolicyError when consent is required and the recipient has
+// no unexpired consent for the scope or for all scopes
+func (s *ConsentStore) Check(phone, scope string) error {
+	if !s.required {
+		return nil
+	}
+	phone = sanitizePhone(phone)
+	scope = s.normalizeScope(scope)
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var expired *ConsentRecord
+	for _, candidate := range []string{scope, ConsentScopeAll} {
+		record, exists := s.records[phone][candidate]
+		if !exists {
+			continue
+		}
+		if record.ExpiresAt != nil && !record.ExpiresAt.After(time.Now()) {
+			expired = record
+			continue
+		}
+		return nil
+	}
+
+	if expired != nil {
+		return &PolicyError{
+			Policy: "consent",
+			Code:   ConsentCodeExpired,
+			Phone:  phone,
+			Reason: fmt.Sprintf("%s consent expired on %s", expired.Scope, expired.ExpiresAt.Format(time.RFC3339)),
+		}
+	}
+	return &PolicyError{
+		Policy: "consent",
+		Code:   ConsentCodeMissing,
+		Phone:  phone,
+		Reason: fmt.Sprintf("no %s consent recorded", scope),
+	}
+}
+
+// List returns the records of one phone, or of every phone when empty
+func (s *ConsentStore) List(phone string) []ConsentRecord {
+	phone = sanitizePhone(phone)
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	result := make([]ConsentRecord, 0)
+	for p, scopes := range s.records {
+		if phone != "" && p != phone {
+			continue
+		}
+		for _, r := range scopes {
+			result = append(result, *r)
+		}
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].Phone != result[j].Phone {
+			return result[i].Phone < result[j].Phone
+		}
+		return result[i].Scope < result[j].Scope
+	})
+	return result
+}
+
+// Import validates and stores records, replacing earlier consent for the
+// same phone and scope. Invalid records are skipped and reported. When the
+// registry can't be saved nothing is imported and ErrConsentNotSaved is
+// returned.
+func (s *ConsentStore) Import(records []ConsentRecord) (int, []ConsentImportError, error) {
+	errs := make([]ConsentImportError, 0)
+	valid := make([]*ConsentRecord, 0, len(records))
+	now := time.Now().UTC()
+	for i := range records {
+		record := records[i]
+		if err := s.validate(&record); err != nil {
+			errs = append(errs, ConsentImportError{Line: i + 1, Phone: records[i].Phone, Error: err.Error()})
+			continue
+		}
+		record.UpdatedAt = now
+		valid = append(valid, &record)
+	}
+	if len(valid) == 0 {
+		return 0, errs, nil
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	// Keep what the import replaces, to put it back if saving fails
+	replaced := make([]*ConsentRecord, len(valid))
+	for i, record := range valid {
+		replaced[i] = s.records[record.Phone][record.Scope]
+		s.putLocked(record)
+	}
+	if err := s.persistLocked(); err != nil {
+		for i := len(valid) - 1; i >= 0; i-- {
+			if replaced[i] != nil {
+				s.putLocked(replaced[i])
+				continue
+			}
+			delete(s.records[valid[i].Phone], valid[i].Scope)
+			if len(s.records[valid[i].Phone]) == 0 {
+				delete(s.records, valid[i].Phone)
+			}
+		}
+		return 0, errs, fmt.Errorf("%w: %v", ErrConsentNotSaved, err)
+	}
+	return len(valid), errs, nil
+}
+
+// ImportCSV reads records with a header row naming the columns phone, scope,
+// source, granted_at and expires_at. Times are RFC 3339 or YYYY-MM-DD.
+func (s *ConsentStore) ImportCSV(reader io.Reader) (int, []ConsentImportError, error) {
+	csvReader := csv.NewReader(reader)
+	csvReader.TrimLeadingSpace = true
+	csvReader.FieldsPerRecord = -1
+
+	header, err := csvReader.Read()
+	if err != nil {
+		return 0, nil, fmt.Errorf("failed to read CSV header: %w", err)
+	}
+	columns := make(map[string]int)
+	for i, name := range header {
+		columns[strings.ToLower(strings.TrimSpace(strings.TrimPrefix(name, "\ufeff")))] = i
+	}
+	if _, ok := columns["phone"]; !ok {
+		return 0, nil, fmt.Errorf("CSV header must include a phone column")
+	}
+
+	field := func(row []string, name string) string {
+		if i, ok := columns[name]; ok && i < len(row) {
+			return strings.TrimSpace(row[i])
+		}
+		return ""
+	}
+
+	records := make([]ConsentRecord, 0)
+	errs := make([]ConsentImportError, 0)
+	lines := make([]int, 0) // CSV line of each record, for error reports
+	for {
+		row, err := csvReader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			var parseErr *csv.ParseError
+			if !errors.As(err, &parseErr) {
+				return 0, nil, fmt.Errorf("failed to read CSV: %w", err)
+			}
+			errs = append(errs, ConsentImportError{Line: parseErr.StartLine, Error: parseErr.Err.Error()})
+			continue
+		}
+		line, _ := csvReader.FieldPos(0)
+
+		record := ConsentRecord{
+			Phone:  field(row, "phone"),
+			Scope:  field(row, "scope"),
+			Source: field(row, "source"),
+		}
+		if record.GrantedAt, err = parseConsentTime(field(row, "granted_at")); err != nil {
+			errs = append(errs, ConsentImportError{Line: line, Phone: record.Phone, Error: "granted_at: " + err.Error()})
+			continue
+		}
+		if value := field(row, "expires_at"); value != "" {
+			expiresAt, err := parseConsentTime(value)
+			if err != nil {
+				errs = append(errs, ConsentImportError{Line: line, Phone: record.Phone, Error: "expires_at: " + err.Error()})
+				continue
+			}
+			record.ExpiresAt = &expiresAt
+		}
+		records = append(records, record)
+		lines = append(lines, line)
+	}
+
+	imported, importErrs, err := s.Import(records)
+	if err != nil {
+		return 0, nil, err
+	}
+	for _, e := range importErrs {
+		e.Line = lines[e.Line-1]
+		errs = append(errs, e)
+	}
+	sort.Slice(errs, func(i, j int) bool { return errs[i].Line < errs[j].Line })
+	return imported, errs, nil
+}
+
+// Revoke removes consent for one scope, or for every scope when empty
+func (s *ConsentStore) Revoke(phone, scope string) error {
+	phone = sanitizePhone(phone)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	scopes, exists := s.records[phone]
+	if !exists {
+		return ErrConsentNotFound
+	}
+	if scope == "" {
+		delete(s.records, phone)
+	} else {
+		scope = strings.ToLower(strings.TrimSpace(scope))
+		if _, exists := scopes[scope]; !exists {
+			return ErrConsentNotFound
+		}
+		delete(scopes, scope)
+		if len(scopes) == 0 {
+			delete(s.records, phone)
+		}
+	}
+	return s.persistLocked()
+}
+
+func (s *ConsentStore) normalizeScope(scope string) string {
+	scope = strings.ToLower(strings.TrimSpace(scope))
+	if scope == "" {
+		return s.defaultScope
+	}
+	return scope
+}
+
+// validate normalizes a record before it is stored
+func (s *ConsentStore) validate(record *ConsentRecord) error {
+	record.Phone = sanitizePhone(record.Phone)
+	if record.Phone == "" {
+		return fmt.Errorf("phone required")
+	}
+	record.Scope = s.normalizeScope(record.Scope)
+	record.Source = strings.TrimSpace(record.Source)
+	if record.Source == "" {
+		return fmt.Errorf("source required")
+	}
+	if record.GrantedAt.IsZero() {
+		record.GrantedAt = time.Now().UTC()
+	}
+	if record.GrantedAt.After(time.Now().Add(time.Minute)) {
+		return fmt.Errorf("granted_at is in the future")
+	}
+	if record.ExpiresAt != nil && !record.ExpiresAt.After(record.GrantedAt) {
+		return fmt.Errorf("expires_at must be after granted_at")
+	}
+	return nil
+}
+
+// putLocked indexes a record. Caller must hold the write lock.
+func (s *ConsentStore) putLocked(record *ConsentRecord) {
+	if _, exists := s.records[record.Phone]; !exists {
+		s.records[record.Phone] = make(map[string]*ConsentRecord)
+	}
+	s.records[record.Phone][record.Scope] = record
+}
+
+// persistLocked writes all records to disk. Caller must hold the write lock.
+func (s *ConsentStore) persistLocked() error {
+	records := make([]*ConsentRecord, 0, len(s.records))
+	for _, scopes := range s.records {
+		for _, r := range scopes {
+			records = append(records, r)
+		}
+	}
+	sort.Slice(records, func(i, j int) bool {
+		if records[i].Phone != records[j].Phone {
+			return records[i].Phone < records[j].Phone
+		}
+		return records[i].Scope < records[j].Scope
+	})
+
+	jsonData, err := json.MarshalIndent(records, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal consent records: %w", err)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(s.path), 0755); err != nil {
+		return fmt.Errorf("failed to create consent directory: %w", err)
+	}
+
+	tmpFile := s.path + ".tmp"
+	if err := os.WriteFile(tmpFile, jsonData, 0644); err != nil {
+		return fmt.Errorf("failed to write consent file: %w", err)
+	}
+	if err := os.Rename(tmpFile, s.path); err != nil {
+		return fmt.Errorf("failed to replace consent file: %w", err)
+	}
+
+	return nil
+}
+
+// parseConsentTime accepts RFC 3339 timestamps and plain dates, empty is zero
+func parseConsentTime(value string) (time.Time, error) {
+	if value == "" {
+		return time.Time{}, nil
+	}
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t.UTC(), nil
+	}
+	t, err := time.Parse("2006-01-02", value)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("%q is not an RFC 3339 time or YYYY-MM-DD date", value)
+	}
+	return t, nil
+}
@@ -0,0 +1,382 @@
+package whatsapp
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+
+	"go.mau.fi/whatsmeow/types"
+)
+
+// Delivery statuses of a sent message, in the order they are reached
+const (
+	DeliverySent      = "sent"
+	DeliveryDelivered = "delivered"
+	DeliveryRead      = "read"
+	DeliveryFailed    = "failed"
+)
+
+// DefaultDeliveryRetention is how long sent messages are tracked
+const DefaultDeliveryRetention = 7 * 24 * time.Hour
+
+// deliverySaveDelay batches the changes of busy sends and receipt bursts into
+// one write of the delivery file
+const deliverySaveDelay = 2 * time.Second
+
+// ErrMessageNotTracked is returned for a message ID the tracker does not know
+var ErrMessageNotTracked = errors.New("message not tracked")
+
+// TrackedMessage is a message sent by one of the accounts and what happened
+// to it since
+type TrackedMessage struct {
+	ID          string     `json:"id"`
+	FromPhone   string     `json:"from_phone"`
+	ToPhone     string     `json:"to_phone"`
+	Status      string     `json:"status"`
+	SentAt      *time.Time `json:"sent_at,omitempty"`
+	DeliveredAt *time.Time `json:"delivered_at,omitempty"`
+	ReadAt      *time.Time `json:"read_at,omitempty"`
+	FailedAt    *time.Time `json:"failed_at,omitempty"`
+	Error       string     `json:"error,omitempty"`
+}
+
+// DeliveryStats aggregates the tracked messages of one account
+type DeliveryStats struct {
+	Phone        string  `json:"phone"`
+	Sent         int     `json:"sent"` // every message that left, whatever happened next
+	Delivered    int     `json:"delivered"`
+	Read         int     `json:"read"`
+	Failed       int     `json:"failed"`
+	DeliveryRate float64 `json:"delivery_rate"`
+	ReadRate     float64 `json:"read_rate"`
+}
+
+// DeliveryTracker links receipts back to sends and keeps each message's
+// timestamps in a JSON file next to the sessions. Every change is reported
+// to the Master. The file is written at most every deliverySaveDelay, off
+// the send and receipt paths, so a crash loses the last few changes at most.
+type DeliveryTracker struct {
+	path      string
+	workerID  string
+	retention time.Duration
+	messages  map[string]*TrackedMessage // message ID -> message
+	saveTimer *time.Timer                // pending write, nil when saved
+	mu        sync.Mutex
+	saveMu    sync.Mutex // serializes writes of the file
+}
+
+// NewDeliveryTracker loads tracked messages from <sessions dir>/delivery.json.
+// DELIVERY_RETENTION overrides how long they are kept.
+func NewDeliveryTracker(workerID string) *DeliveryTracker {
+	t := &DeliveryTracker{
+		path:      filepath.Join(getSessionsDir(), "delivery.json"),
+		workerID:  workerID,
+		retention: envDuration("DELIVERY_RETENTION", DefaultDeliveryRetention),
+		messages:  make(map[string]*TrackedMessage),
+	}
+
+	data, err := os.ReadFile(t.path)
+	if err != nil {
+		if !os.IsNotExist(err) {
+			log.Printf("[DELIVERY] ⚠️ Failed to read %s: %v", t.path, err)
+		}
+		return t
+	}
+
+	var messages []*TrackedMessage
+	if err := json.Unmarshal(data, &messages); err != nil {
+		log.Printf("[DELIVERY] ⚠️ Failed to parse %s: %v", t.path, err)
+		return t
+	}
+	for _, msg := range messages {
+		t.messages[msg.ID] = msg
+	}
+
+	log.Printf("[DELIVERY] 📬 Tracking %d sent messages", len(t.messages))
+	return t
+}
+
+// TrackSent records a message WhatsApp accepted
+func (t *DeliveryTracker) TrackSent(id, fromPhone, toPhone string, at time.Time) {
+	at = at.UTC()
+	msg := &TrackedMessage{ID: id, FromPhone: fromPhone, ToPhone: toPhone, Status: DeliverySent, SentAt: &at}
+	t.store(msg)
+}
+
+// TrackFailed records a message WhatsApp did not accept
+func (t *DeliveryTracker) TrackFailed(id, fromPhone, toPhone string, sendErr error) {
+	now := time.Now().UTC()
+	msg := &TrackedMessage{ID: id, FromPhone: fromPhone, ToPhone: toPhone, Status: DeliveryFailed, FailedAt: &now, Error: sendErr.Error()}
+	t.store(msg)
+}
+
+func (t *DeliveryTracker) store(msg *TrackedMessage) {
+	t.mu.Lock()
+	t.messages[msg.ID] = msg
+	t.pruneLocked()
+	t.scheduleSaveLocked()
+	copied := *msg
+	t.mu.Unlock()
+
+	go t.report(copied)
+}
+
+// ApplyReceipt moves the messages of a receipt forward and returns how many
+// were delivered for the first time. Receipts for messages that are not
+// tracked, or that would move a message backwards, are ignored.
+func (t *DeliveryTracker) ApplyReceipt(fromPhone string, ids []types.MessageID, receiptType types.ReceiptType, at time.Time) int {
+	at = at.UTC()
+
+	t.mu.Lock()
+	changed := make([]TrackedMessage, 0, len(ids))
+	newlyDelivered := 0
+	for _, id := range ids {
+		msg, exists := t.messages[id]
+		if !exists || msg.FromPhone != fromPhone {
+			continue
+		}
+
+		switch receiptType {
+		case types.ReceiptTypeDelivered:
+			if msg.DeliveredAt != nil {
+				continue
+			}
+			msg.DeliveredAt = &at
+			newlyDelivered++
+			if msg.Status == DeliverySent {
+				msg.Status = DeliveryDelivered
+			}
+		case types.ReceiptTypeRead, types.ReceiptTypePlayed:
+			if msg.ReadAt != nil {
+				continue
+			}
+			// A read receipt can overtake the delivery receipt
+			if msg.DeliveredAt == nil {
+				msg.DeliveredAt = &at
+				newlyDelivered++
+			}
+			msg.ReadAt = &at
+			msg.Status = DeliveryRead
+		case types.ReceiptTypeServerError:
+			if msg.FailedAt != nil || msg.DeliveredAt != nil {
+				continue
+			}
+			msg.FailedAt = &at
+			msg.Status = DeliveryFailed
+			msg.Error = "server rejected the message"
+		default:
+			continue
+		}
+		changed = append(changed, *msg)
+	}
+	if len(changed) > 0 {
+		t.scheduleSaveLocked()
+	}
+	t.mu.Unlock()
+
+	for _, msg := range changed {
+		go t.report(msg)
+	}
+	return newlyDelivered
+}
+
+// Get returns a tracked message
+func (t *DeliveryTracker) Get(id string) (*TrackedMessage, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	msg, exists := t.messages[id]
+	if !exists {
+		return nil, ErrMessageNotTracked
+	}
+	copied := *msg
+	return &copied, nil
+}
+
+// List returns tracked messages newest first, filtered by account and status
+// when given
+func (t *DeliveryTracker) List(fromPhone, status string, limit int) []TrackedMessage {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	result := make([]TrackedMessage, 0)
+	for _, msg := range t.messages {
+		if (fromPhone == "" || msg.FromPhone == fromPhone) && (status == "" || msg.Status == status) {
+			result = append(result, *msg)
+		}
+	}
+	sort.Slice(result, func(i, j int) bool { return trackedAt(result[i]).After(trackedAt(result[j])) })
+	if limit > 0 && len(result) > limit {
+		result = result[:limit]
+	}
+	return result
+}
+
+// Stats aggregates the tracked messages of an account
+func (t *DeliveryTracker) Stats(fromPhone string) DeliveryStats {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	stats := DeliveryStats{Phone: fromPhone}
+	for _, msg := range t.messages {
+		if msg.FromPhone != fromPhone {
+			continue
+		}
+		if msg.SentAt != nil {
+			stats.Sent++
+		}
+		if msg.DeliveredAt != nil {
+			stats.Delivered++
+		}
+		if msg.ReadAt != nil {
+			stats.Read++
+		}
+		if msg.Status == DeliveryFailed {
+			stats.Failed++
+		}
+	}
+	if stats.Sent > 0 {
+		stats.DeliveryRate = float64(stats.Delivered) / float64(stats.Sent)
+		stats.ReadRate = float64(stats.Read) / float64(stats.Sent)
+	}
+	return stats
+}
+
+// report tells the Master about a status change, retrying briefly
+func (t *DeliveryTracker) report(msg TrackedMessage) {
+	masterURL := os.Getenv("MASTER_URL")
+	if masterURL == "" {
+		masterURL = "http://master:5000"
+	}
+
+	url := fmt.Sprintf("%s/api/accounts/%s/messages/%s/status", masterURL, msg.FromPhone, msg.ID)
+
+	payload := map[string]interface{}{
+		"worker_id": t.workerID,
+		"message":   msg,
+		"status":    msg.Status,
+	}
+	jsonData, err := json.Marshal(payload)
+	if err != nil {
+		return
+	}
+
+	client := &http.Client{Timeout: 5 * time.Second}
+	for attempt := 1; attempt <= 3; attempt++ {
+		req, err := http.NewRequest("POST", url, bytes.NewBuffer(jsonData))
+		if err != nil {
+			return
+		}
+		req.Header.Set("Content-Type", "application/json")
+		setMasterAuth(req)
+
+		resp, err := client.Do(req)
+		if err == nil {
+			resp.Body.Close()
+			if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+				return
+			}
+			err = fmt.Errorf("master returned status %d", resp.StatusCode)
+		}
+		if attempt == 3 {
+			log.Printf("[DELIVERY] ⚠️ Failed to report %s of %s to master: %v", msg.Status, msg.ID, err)
+			return
+		}
+		time.Sleep(time.Duration(attempt) * 2 * time.Second)
+	}
+}
+
+// pruneLocked drops messages older than the retention. Caller must hold the lock.
+func (t *DeliveryTracker) pruneLocked() {
+	cutoff := time.Now().Add(-t.retention)
+	for id, msg := range t.messages {
+		if trackedAt(*msg).Before(cutoff) {
+			delete(t.messages, id)
+		}
+	}
+}
+
+// scheduleSaveLocked writes the file after deliverySaveDelay unless a write
+// is already pending. Caller must hold the lock.
+func (t *DeliveryTracker) scheduleSaveLocked() {
+	if t.saveTimer != nil {
+		return
+	}
+	t.saveTimer = time.AfterFunc(deliverySaveDelay, func() {
+		if err := t.Flush(); err != nil {
+			log.Printf("[DELIVERY] ⚠️ Failed to save tracked messages: %v", err)
+		}
+	})
+}
+
+// Flush writes pending changes now. Shutdown calls it so nothing is lost.
+func (t *DeliveryTracker) Flush() error {
+	t.saveMu.Lock()
+	defer t.saveMu.Unlock()
+
+	t.mu.Lock()
+	if t.saveTimer == nil {
+		t.mu.Unlock()
+		return nil
+	}
+	t.saveTimer.Stop()
+	t.saveTimer = nil
+	messages := make([]TrackedMessage, 0, len(t.messages))
+	for _, msg := range t.messages {
+		messages = append(messages, *msg)
+	}
+	t.mu.Unlock()
+
+	if err := t.persist(messages); err != nil {
+		// Try again after the next delay
+		t.mu.Lock()
+		t.scheduleSaveLocked()
+		t.mu.Unlock()
+		return err
+	}
+	return nil
+}
+
+// persist writes a snapshot of the tracked messages to disk. Caller must hold
+// saveMu.
+func (t *DeliveryTracker) persist(messages []TrackedMessage) error {
+	sort.Slice(messages, func(i, j int) bool { return trackedAt(messages[i]).Before(trackedAt(messages[j])) })
+
+	jsonData, err := json.Marshal(messages)
+	if err != nil {
+		return fmt.Errorf("failed to marshal tracked messages: %w", err)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(t.path), 0755); err != nil {
+		return fmt.Errorf("failed to create delivery directory: %w", err)
+	}
+
+	tmpFile := t.path + ".tmp"
+	if err := os.WriteFile(tmpFile, jsonData, 0644); err != nil {
+		return fmt.Errorf("failed to write delivery file: %w", err)
+	}
+	if err := os.Rename(tmpFile, t.path); err != nil {
+		return fmt.Errorf("failed to replace delivery file: %w", err)
+	}
+
+	return nil
+}
+
+// trackedAt is when a message was sent, or when the send failed
+func trackedAt(msg TrackedMessage) time.Time {
+	if msg.SentAt != nil {
+		return *msg.SentAt
+	}
+	if msg.FailedAt != nil {
+		return *msg.FailedAt
+	}
+	return time.Time{}
+}
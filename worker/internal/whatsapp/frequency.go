@@ -0,0 +1,392 @@
+package whatsapp
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// FrequencyCapCode is the policy code of sends refused by the frequency cap
+const FrequencyCapCode = "frequency_capped"
+
+// DefaultFrequencyCapWindow is the rolling window the cap counts sends in
+const DefaultFrequencyCapWindow = 24 * time.Hour
+
+// frequencySaveDelay batches the reservations of busy sends into one write of
+// the frequency file
+const frequencySaveDelay = 2 * time.Second
+
+// ErrFrequencyCapped is matched by errors.Is for sends refused because the
+// recipient already got the maximum number of messages in the window
+var ErrFrequencyCapped = errors.New("recipient frequency cap reached")
+
+// FrequencyCapError is returned by SendMessage for a capped recipient
+type FrequencyCapError struct {
+	Phone       string
+	Sent        int
+	Max         int
+	Window      time.Duration
+	NextAllowed time.Time
+}
+
+func (e *FrequencyCapError) Error() string {
+	return fmt.Sprintf("recipient +%s already got %d of %d messages in the last %s, next send allowed at %s",
+		e.Phone, e.Sent, e.Max, e.Window, e.NextAllowed.Format(time.RFC3339))
+}
+
+func (e *FrequencyCapError) Is(target error) bool {
+	return target == ErrFrequencyCapped || target == ErrPolicyRejected
+}
+
+// FrequencyCapStore refuses more than FREQUENCY_CAP_MAX messages to one
+// recipient within FREQUENCY_CAP_WINDOW. The Master holds the count shared by
+// all workers: every send reserves a slot there first. Send times are also
+// kept locally in a JSON file next to the sessions, written at most every
+// frequencySaveDelay, so the cap still holds for this worker's own sends
+// while the Master is unreachable and across restarts.
+type FrequencyCapStore struct {
+	path      string
+	workerID  string
+	max       int
+	window    time.Duration
+	sends     map[string][]time.Time // recipient -> send times within the window
+	saveTimer *time.Timer            // pending write, nil when saved
+	mu        sync.Mutex
+	saveMu    sync.Mutex // serializes writes of the file
+}
+
+// NewFrequencyCapStore loads send times from <sessions dir>/frequency.json.
+// The cap is off unless FREQUENCY_CAP_MAX is a positive number.
+func NewFrequencyCapStore(workerID string) *FrequencyCapStore {
+	s := &FrequencyCapStore{
+		path:     filepath.Join(getSessionsDir(), "frequency.json"),
+		workerID: workerID,
+		window:   envDuration("FREQUENCY_CAP_WINDOW", DefaultFrequencyCapWindow),
+		sends:    make(map[string][]time.Time),
+	}
+	if value := os.Getenv("FREQUENCY_CAP_MAX"); value != "" {
+		max, err := strconv.Atoi(value)
+		if err != nil || max < 0 {
+			log.Printf("[FREQUENCY] ⚠️ Invalid FREQUENCY_CAP_MAX %q, cap disabled", value)
+		} else {
+			s.max = max
+		}
+	}
+
+	data, err := os.ReadFile(s.path)
+	if err != nil {
+		if !os.IsNotExist(err) {
+			log.Printf("[FREQUENCY] ⚠️ Failed to read %s: %v", s.path, err)
+		}
+		return s
+	}
+	if err := json.Unmarshal(data, &s.sends); err != nil {
+		log.Printf("[FREQUENCY] ⚠️ Failed to parse %s: %v", s.path, err)
+		s.sends = make(map[string][]time.Time)
+		return s
+	}
+	s.pruneLocked(time.Now())
+
+	log.Printf("[FREQUENCY] 📊 Cap %d messages per recipient per %s, tracking %d recipients", s.max, s.window, len(s.sends))
+	return s
+}
+
+// Enabled reports whether sends are capped
+func (s *FrequencyCapStore) Enabled() bool {
+	return s.max > 0
+}
+
+// Check returns a *FrequencyCapError when the recipient reached the cap by
+// this worker's own count. Reserve asks the Master, which sees every worker.
+func (s *FrequencyCapStore) Check(phone string) error {
+	if !s.Enabled() {
+		return nil
+	}
+	phone = sanitizePhone(phone)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.checkLocked(phone, time.Now())
+}
+
+// Reserve counts a send from fromPhone to the recipient before it is made,
+// so concurrent sends from any account of any worker cannot both pass the
+// cap. The returned release func takes the send back out of the count when
+// it did not go through. When the Master cannot be reached the send is only
+// capped by this worker's count.
+func (s *FrequencyCapStore) Reserve(fromPhone, toPhone string) (func(), error) {
+	if !s.Enabled() {
+		return func() {}, nil
+	}
+	toPhone = sanitizePhone(toPhone)
+
+	reservationID, err := s.reserveOnMaster(fromPhone, toPhone)
+	if err != nil {
+		var capErr *FrequencyCapError
+		if errors.As(err, &capErr) {
+			return nil, err
+		}
+		log.Printf("[FREQUENCY] ⚠️ Master unreachable, capping %s by this worker's count only: %v", toPhone, err)
+	}
+
+	now := time.Now().UTC()
+	s.mu.Lock()
+	if err := s.checkLocked(toPhone, now); err != nil {
+		s.mu.Unlock()
+		if reservationID != "" {
+			go s.releaseOnMaster(fromPhone, reservationID)
+		}
+		return nil, err
+	}
+	s.sends[toPhone] = append(s.sends[toPhone], now)
+	s.scheduleSaveLocked()
+	s.mu.Unlock()
+
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			s.release(toPhone, now)
+			if reservationID != "" {
+				go s.releaseOnMaster(fromPhone, reservationID)
+			}
+		})
+	}, nil
+}
+
+// Count returns how many messages the recipient got within the window
+func (s *FrequencyCapStore) Count(phone string) int {
+	phone = sanitizePhone(phone)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	count := 0
+	cutoff := time.Now().Add(-s.window)
+	for _, t := range s.sends[phone] {
+		if t.After(cutoff) {
+			count++
+		}
+	}
+	return count
+}
+
+func (s *FrequencyCapStore) release(phone string, at time.Time) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	sends := s.sends[phone]
+	for i, t := range sends {
+		if t.Equal(at) {
+			s.sends[phone] = append(sends[:i], sends[i+1:]...)
+			break
+		}
+	}
+	if len(s.sends[phone]) == 0 {
+		delete(s.sends, phone)
+	}
+	s.scheduleSaveLocked()
+}
+
+// reserveOnMaster takes a slot in the Master's count of the recipient. It
+// returns the reservation ID, or a *FrequencyCapError when the recipient
+// reached the cap across all workers.
+func (s *FrequencyCapStore) reserveOnMaster(fromPhone, toPhone string) (string, error) {
+	masterURL := os.Getenv("MASTER_URL")
+	if masterURL == "" {
+		masterURL = "http://master:5000"
+	}
+
+	url := fmt.Sprintf("%s/api/accounts/%s/frequency-cap/reservations", masterURL, fromPhone)
+
+	jsonData, err := json.Marshal(map[string]interface{}{
+		"worker_id":      s.workerID,
+		"to_phone":       toPhone,
+		"max":            s.max,
+		"window_seconds": int(s.window.Seconds()),
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal reservation: %w", err)
+	}
+
+	req, err := http.NewRequest("POST", url, bytes.NewBuffer(jsonData))
+	if err != nil {
+		return "", fmt.Errorf("failed to create reservation request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	setMasterAuth(req)
+
+	client := &http.Client{Timeout: 5 * time.Second}
+	resp, err := client.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	var body struct {
+		ID          json.Number `json:"id"`
+		Sent        int         `json:"sent"`
+		NextAllowed time.Time   `json:"next_allowed"`
+	}
+	switch resp.StatusCode {
+	case http.StatusCreated, http.StatusOK:
+		if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+			return "", fmt.Errorf("failed to decode reservation: %w", err)
+		}
+		return body.ID.String(), nil
+	case http.StatusConflict:
+		if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+			return "", fmt.Errorf("failed to decode refused reservation: %w", err)
+		}
+		return "", &FrequencyCapError{
+			Phone:       toPhone,
+			Sent:        body.Sent,
+			Max:         s.max,
+			Window:      s.window,
+			NextAllowed: body.NextAllowed.UTC(),
+		}
+	default:
+		return "", fmt.Errorf("master returned status %d", resp.StatusCode)
+	}
+}
+
+// releaseOnMaster gives a reserved slot back to the Master. A reservation
+// that cannot be released only counts against the recipient until it leaves
+// the window.
+func (s *FrequencyCapStore) releaseOnMaster(fromPhone, reservationID string) {
+	masterURL := os.Getenv("MASTER_URL")
+	if masterURL == "" {
+		masterURL = "http://master:5000"
+	}
+
+	url := fmt.Sprintf("%s/api/accounts/%s/frequency-cap/reservations/%s", masterURL, fromPhone, reservationID)
+
+	req, err := http.NewRequest("DELETE", url, nil)
+	if err != nil {
+		log.Printf("[FREQUENCY] ⚠️ Failed to create release request: %v", err)
+		return
+	}
+	setMasterAuth(req)
+
+	client := &http.Client{Timeout: 5 * time.Second}
+	resp, err := client.Do(req)
+	if err != nil {
+		log.Printf("[FREQUENCY] ⚠️ Failed to release reservation %s: %v", reservationID, err)
+		return
+	}
+	resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		log.Printf("[FREQUENCY] ⚠️ Master refused to release reservation %s: status %d", reservationID, resp.StatusCode)
+	}
+}
+
+// checkLocked counts the sends within the window. Caller must hold the lock.
+func (s *FrequencyCapStore) checkLocked(phone string, now time.Time) error {
+	cutoff := now.Add(-s.window)
+	recent := make([]time.Time, 0, len(s.sends[phone]))
+	for _, t := range s.sends[phone] {
+		if t.After(cutoff) {
+			recent = append(recent, t)
+		}
+	}
+	if len(recent) < s.max {
+		return nil
+	}
+	// Sends are appended in order, so the oldest recent one frees a slot first
+	return &FrequencyCapError{
+		Phone:       phone,
+		Sent:        len(recent),
+		Max:         s.max,
+		Window:      s.window,
+		NextAllowed: recent[len(recent)-s.max].Add(s.window).UTC(),
+	}
+}
+
+// pruneLocked drops send times older than the window. Caller must hold the lock.
+func (s *FrequencyCapStore) pruneLocked(now time.Time) {
+	cutoff := now.Add(-s.window)
+	for phone, sends := range s.sends {
+		kept := sends[:0]
+		for _, t := range sends {
+			if t.After(cutoff) {
+				kept = append(kept, t)
+			}
+		}
+		if len(kept) == 0 {
+			delete(s.sends, phone)
+		} else {
+			s.sends[phone] = kept
+		}
+	}
+}
+
+// scheduleSaveLocked writes the file after frequencySaveDelay unless a write
+// is already pending. Caller must hold the lock.
+func (s *FrequencyCapStore) scheduleSaveLocked() {
+	if s.saveTimer != nil {
+		return
+	}
+	s.saveTimer = time.AfterFunc(frequencySaveDelay, func() {
+		if err := s.Flush(); err != nil {
+			log.Printf("[FREQUENCY] ⚠️ Failed to save send times: %v", err)
+		}
+	})
+}
+
+// Flush writes pending changes now. Shutdown calls it so nothing is lost.
+func (s *FrequencyCapStore) Flush() error {
+	s.saveMu.Lock()
+	defer s.saveMu.Unlock()
+
+	s.mu.Lock()
+	if s.saveTimer == nil {
+		s.mu.Unlock()
+		return nil
+	}
+	s.saveTimer.Stop()
+	s.saveTimer = nil
+	s.pruneLocked(time.Now())
+	sends := make(map[string][]time.Time, len(s.sends))
+	for phone, times := range s.sends {
+		sends[phone] = append([]time.Time(nil), times...)
+	}
+	s.mu.Unlock()
+
+	if err := s.persist(sends); err != nil {
+		// Try again after the next delay
+		s.mu.Lock()
+		s.scheduleSaveLocked()
+		s.mu.Unlock()
+		return err
+	}
+	return nil
+}
+
+// persist writes a snapshot of the send times to disk. Caller must hold saveMu.
+func (s *FrequencyCapStore) persist(sends map[string][]time.Time) error {
+	jsonData, err := json.Marshal(sends)
+	if err != nil {
+		return fmt.Errorf("failed to marshal send times: %w", err)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(s.path), 0755); err != nil {
+		return fmt.Errorf("failed to create frequency directory: %w", err)
+	}
+
+	tmpFile := s.path + ".tmp"
+	if err := os.WriteFile(tmpFile, jsonData, 0644); err != nil {
+		return fmt.Errorf("failed to write frequency file: %w", err)
+	}
+	if err := os.Rename(tmpFile, s.path); err != nil {
+		return fmt.Errorf("failed to replace frequency file: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,140 @@
+package whatsapp
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+const (
+	testFromPhone = "4915100000001"
+	testToPhone   = "4915187654321"
+)
+
+// newTestFrequencyCap creates a store capping at max sends per hour that
+// talks to masterURL
+func newTestFrequencyCap(t *testing.T, masterURL string, max string) *FrequencyCapStore {
+	t.Helper()
+	t.Chdir(t.TempDir())
+	t.Setenv("MASTER_URL", masterURL)
+	t.Setenv("WORKER_API_SECRET", "")
+	t.Setenv("FREQUENCY_CAP_MAX", max)
+	t.Setenv("FREQUENCY_CAP_WINDOW", "1h")
+
+	s := NewFrequencyCapStore("worker-1")
+	// Write pending send times before the temporary directory goes away
+	t.Cleanup(func() { s.Flush() })
+	return s
+}
+
+func TestFrequencyCapReserveOnMaster(t *testing.T) {
+	released := make(chan string, 1)
+	master := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method + " " + r.URL.Path {
+		case "POST /api/accounts/" + testFromPhone + "/frequency-cap/reservations":
+			var body struct {
+				ToPhone       string `json:"to_phone"`
+				Max           int    `json:"max"`
+				WindowSeconds int    `json:"window_seconds"`
+			}
+			if err := json.NewDecoder(r.Body).Decode(&body); err != nil || body.ToPhone != testToPhone || body.Max != 2 || body.WindowSeconds != 3600 {
+				t.Errorf("unexpected reservation %+v (%v)", body, err)
+			}
+			w.WriteHeader(http.StatusCreated)
+			w.Write([]byte(`{"success":true,"id":7,"sent":1}`))
+		case "DELETE /api/accounts/" + testFromPhone + "/frequency-cap/reservations/7":
+			released <- r.URL.Path
+		default:
+			t.Errorf("unexpected master call %s %s", r.Method, r.URL.Path)
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer master.Close()
+
+	s := newTestFrequencyCap(t, master.URL, "2")
+
+	release, err := s.Reserve(testFromPhone, "+"+testToPhone)
+	if err != nil {
+		t.Fatalf("Reserve() error = %v", err)
+	}
+	if count := s.Count(testToPhone); count != 1 {
+		t.Fatalf("Count() after reserve = %d, want 1", count)
+	}
+
+	// Releasing twice only gives the slot back once
+	release()
+	release()
+	if count := s.Count(testToPhone); count != 0 {
+		t.Fatalf("Count() after release = %d, want 0", count)
+	}
+	select {
+	case <-released:
+	case <-time.After(5 * time.Second):
+		t.Fatal("reservation was not released on the master")
+	}
+}
+
+func TestFrequencyCapRefusedByMaster(t *testing.T) {
+	nextAllowed := time.Now().Add(30 * time.Minute).UTC().Truncate(time.Second)
+	master := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusConflict)
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"error":        FrequencyCapCode,
+			"sent":         2,
+			"max":          2,
+			"next_allowed": nextAllowed,
+		})
+	}))
+	defer master.Close()
+
+	s := newTestFrequencyCap(t, master.URL, "2")
+
+	_, err := s.Reserve(testFromPhone, testToPhone)
+	var capErr *FrequencyCapError
+	if !errors.As(err, &capErr) {
+		t.Fatalf("Reserve() error = %v, want a *FrequencyCapError", err)
+	}
+	if capErr.Sent != 2 || capErr.Max != 2 || !capErr.NextAllowed.Equal(nextAllowed) {
+		t.Fatalf("unexpected cap error %+v", capErr)
+	}
+	if count := s.Count(testToPhone); count != 0 {
+		t.Fatalf("Count() after a refused reservation = %d, want 0", count)
+	}
+}
+
+func TestFrequencyCapWithoutMaster(t *testing.T) {
+	master := httptest.NewServer(http.NotFoundHandler())
+	masterURL := master.URL
+	master.Close()
+
+	s := newTestFrequencyCap(t, masterURL, "1")
+
+	release, err := s.Reserve(testFromPhone, testToPhone)
+	if err != nil {
+		t.Fatalf("first Reserve() error = %v, want the local count to allow it", err)
+	}
+	if _, err := s.Reserve(testFromPhone, testToPhone); !errors.Is(err, ErrFrequencyCapped) {
+		t.Fatalf("second Reserve() error = %v, want ErrFrequencyCapped by the local count", err)
+	}
+
+	release()
+	if _, err := s.Reserve(testFromPhone, testToPhone); err != nil {
+		t.Fatalf("Reserve() after release error = %v", err)
+	}
+}
+
+func TestFrequencyCapDisabled(t *testing.T) {
+	s := newTestFrequencyCap(t, "http://127.0.0.1:1", "0")
+
+	for i := 0; i < 3; i++ {
+		if _, err := s.Reserve(testFromPhone, testToPhone); err != nil {
+			t.Fatalf("Reserve() with the cap disabled error = %v", err)
+		}
+	}
+	if err := s.Check(testToPhone); err != nil {
+		t.Fatalf("Check() with the cap disabled error = %v", err)
+	}
+}
@@ -0,0 +1,189 @@
+package whatsapp
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+)
+
+// DefaultIdempotencyWindow is how long a send is remembered by its key
+const DefaultIdempotencyWindow = 24 * time.Hour
+
+// MaxIdempotencyKeyLength limits the size of client supplied keys
+const MaxIdempotencyKeyLength = 255
+
+// ErrIdempotencyConflict is returned when a key is reused for a different send
+var ErrIdempotencyConflict = errors.New("idempotency key was already used for a different send")
+
+// IdempotencyEntry is a completed send remembered by its idempotency key
+type IdempotencyEntry struct {
+	Key       string     `json:"key"`
+	FromPhone string     `json:"from_phone"`
+	ToPhone   string     `json:"to_phone"`
+	Result    SendResult `json:"result"`
+	CreatedAt time.Time  `json:"created_at"`
+}
+
+// IdempotencyStore remembers successful sends by key in a JSON file next to
+// the sessions, so a retried request returns the first result instead of
+// sending the message again.
+type IdempotencyStore struct {
+	path    string
+	window  time.Duration
+	entries map[string]*IdempotencyEntry
+	mu      sync.Mutex
+
+	// keyLocks serializes concurrent requests carrying the same key
+	keyLocks sync.Map
+}
+
+// NewIdempotencyStore loads entries from <sessions dir>/idempotency.json.
+// The window is read from IDEMPOTENCY_WINDOW (e.g. "24h"); 0 disables keys.
+func NewIdempotencyStore() *IdempotencyStore {
+	s := &IdempotencyStore{
+		path:    filepath.Join(getSessionsDir(), "idempotency.json"),
+		window:  DefaultIdempotencyWindow,
+		entries: make(map[string]*IdempotencyEntry),
+	}
+
+	if value := os.Getenv("IDEMPOTENCY_WINDOW"); value != "" {
+		window, err := time.ParseDuration(value)
+		if err != nil || window < 0 {
+			log.Printf("[IDEMPOTENCY] ⚠️ Invalid IDEMPOTENCY_WINDOW %q, using %s", value, DefaultIdempotencyWindow)
+		} else {
+			s.window = window
+		}
+	}
+
+	data, err := os.ReadFile(s.path)
+	if err != nil {
+		if !os.IsNotExist(err) {
+			log.Printf("[IDEMPOTENCY] ⚠️ Failed to read %s: %v", s.path, err)
+		}
+		return s
+	}
+
+	var entries []*IdempotencyEntry
+	if err := json.Unmarshal(data, &entries); err != nil {
+		log.Printf("[IDEMPOTENCY] ⚠️ Failed to parse %s: %v", s.path, err)
+		return s
+	}
+	for _, e := range entries {
+		s.entries[e.Key] = e
+	}
+	s.pruneLocked(time.Now())
+
+	log.Printf("[IDEMPOTENCY] 🔑 Loaded %d keys (window %s)", len(s.entries), s.window)
+	return s
+}
+
+// Do runs send once per key within the window. A repeated key with the same
+// sender and recipient returns the stored result with replayed set; a key
+// reused for another sender or recipient returns ErrIdempotencyConflict.
+// Failed sends are not remembered so the client can retry them.
+func (s *IdempotencyStore) Do(key, fromPhone, toPhone string, send func() (*SendResult, error)) (result *SendResult, replayed bool, err error) {
+	if key == "" || s.window <= 0 {
+		result, err = send()
+		return result, false, err
+	}
+	if len(key) > MaxIdempotencyKeyLength {
+		return nil, false, fmt.Errorf("idempotency key must be at most %d characters", MaxIdempotencyKeyLength)
+	}
+
+	lock, _ := s.keyLocks.LoadOrStore(key, &sync.Mutex{})
+	keyLock := lock.(*sync.Mutex)
+	keyLock.Lock()
+	defer keyLock.Unlock()
+
+	if entry := s.get(key); entry != nil {
+		if entry.FromPhone != fromPhone || entry.ToPhone != toPhone {
+			return nil, false, ErrIdempotencyConflict
+		}
+		stored := entry.Result
+		return &stored, true, nil
+	}
+
+	result, err = send()
+	if err != nil {
+		return nil, false, err
+	}
+
+	s.put(&IdempotencyEntry{
+		Key:       key,
+		FromPhone: fromPhone,
+		ToPhone:   toPhone,
+		Result:    *result,
+		CreatedAt: time.Now().UTC(),
+	})
+	return result, false, nil
+}
+
+// get returns the entry for a key if it is still inside the window
+func (s *IdempotencyStore) get(key string) *IdempotencyEntry {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	entry, exists := s.entries[key]
+	if !exists || time.Since(entry.CreatedAt) > s.window {
+		return nil
+	}
+	copied := *entry
+	return &copied
+}
+
+// put records a completed send, dropping expired entries on the way
+func (s *IdempotencyStore) put(entry *IdempotencyEntry) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.entries[entry.Key] = entry
+	s.pruneLocked(time.Now())
+	if err := s.persistLocked(); err != nil {
+		// The message is already sent; keep the key in memory at least
+		log.Printf("[IDEMPOTENCY] ⚠️ Failed to save key %s: %v", entry.Key, err)
+	}
+}
+
+// pruneLocked removes expired entries. Caller must hold the lock.
+func (s *IdempotencyStore) pruneLocked(now time.Time) {
+	for key, entry := range s.entries {
+		if now.Sub(entry.CreatedAt) > s.window {
+			delete(s.entries, key)
+			s.keyLocks.Delete(key)
+		}
+	}
+}
+
+// persistLocked writes all entries to disk. Caller must hold the lock.
+func (s *IdempotencyStore) persistLocked() error {
+	entries := make([]*IdempotencyEntry, 0, len(s.entries))
+	for _, e := range s.entries {
+		entries = append(entries, e)
+	}
+	sort.Slice(entries, func(i, j int) bool { return entries[i].CreatedAt.Before(entries[j].CreatedAt) })
+
+	jsonData, err := json.MarshalIndent(entries, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal idempotency keys: %w", err)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(s.path), 0755); err != nil {
+		return fmt.Errorf("failed to create idempotency directory: %w", err)
+	}
+
+	tmpFile := s.path + ".tmp"
+	if err := os.WriteFile(tmpFile, jsonData, 0644); err != nil {
+		return fmt.Errorf("failed to write idempotency file: %w", err)
+	}
+	if err := os.Rename(tmpFile, s.path); err != nil {
+		return fmt.Errorf("failed to replace idempotency file: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,596 @@
+package whatsapp
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Inbound message types
+const (
+	InboundTypeText     = "text"
+	InboundTypeImage    = "image"
+	InboundTypeVideo    = "video"
+	InboundTypeAudio    = "audio"
+	InboundTypeDocument = "document"
+	InboundTypeSticker  = "sticker"
+	InboundTypeReaction = "reaction"
+	InboundTypeLocation = "location"
+	InboundTypeContact  = "contact"
+)
+
+// Inbox defaults, overridden by INBOX_RETENTION, INBOX_MAX_PER_ACCOUNT,
+// INBOX_MEDIA_MAX_BYTES and INBOUND_MAX_ATTEMPTS
+const (
+	DefaultInboxRetention     = 30 * 24 * time.Hour
+	DefaultInboxMaxPerAccount = 5000
+	DefaultInboxMediaMaxBytes = 16 * 1024 * 1024
+	DefaultInboundMaxAttempts = 10
+)
+
+// inboxSaveDelay batches bursts of inbound messages and forward results into
+// one write of each changed account file
+const inboxSaveDelay = 2 * time.Second
+
+// ErrInboundNotFound is returned for an unknown inbound message
+var ErrInboundNotFound = errors.New("inbound message not found")
+
+// ReplyContext is the message an inbound message replies to
+type ReplyContext struct {
+	ID          string `json:"id"`
+	Participant string `json:"participant,omitempty"` // author of the quoted message
+	Text        string `json:"text,omitempty"`
+}
+
+// InboundReaction is an emoji reaction to an earlier message; an empty emoji
+// removes the reaction
+type InboundReaction struct {
+	TargetID string `json:"target_id"`
+	Emoji    string `json:"emoji"`
+}
+
+// InboundMedia describes downloaded media. File is relative to the inbox
+// media directory and empty when the download failed or was skipped.
+type InboundMedia struct {
+	MimeType string `json:"mime_type"`
+	FileName string `json:"file_name,omitempty"`
+	Size     uint64 `json:"size"`
+	SHA256   string `json:"sha256,omitempty"`
+	File     string `json:"file,omitempty"`
+	Error    string `json:"error,omitempty"`
+}
+
+// InboundLocation is a shared location
+type InboundLocation struct {
+	Latitude  float64 `json:"latitude"`
+	Longitude float64 `json:"longitude"`
+	Name      string  `json:"name,omitempty"`
+	Address   string  `json:"address,omitempty"`
+}
+
+// ReceivedMessage represents an incoming message
+type ReceivedMessage struct {
+	ID        string           `json:"id"`
+	From      string           `json:"from"`
+	FromName  string           `json:"from_name,omitempty"`
+	To        string           `json:"to"`
+	Type      string           `json:"type"`
+	Message   string           `json:"message"` // text, or the media caption
+	Timestamp time.Time        `json:"timestamp"`
+	IsGroup   bool             `json:"is_group"`
+	GroupID   string           `json:"group_id,omitempty"`
+	GroupName string           `json:"group_name,omitempty"`
+	ReplyTo   *ReplyContext    `json:"reply_to,omitempty"`
+	Reaction  *InboundReaction `json:"reaction,omitempty"`
+	Media     *InboundMedia    `json:"media,omitempty"`
+	Location  *InboundLocation `json:"location,omitempty"`
+
+	// Forwarding state
+	ForwardedAt     *time.Time `json:"forwarded_at,omitempty"`
+	ForwardAttempts int        `json:"forward_attempts,omitempty"`
+	ForwardError    string     `json:"forward_error,omitempty"`
+	NextAttemptAt   *time.Time `json:"next_attempt_at,omitempty"`
+}
+
+// InboxStore keeps inbound messages per account in JSON files under
+// <sessions dir>/inbox, with their media next to them, and forwards each one
+// to INBOUND_WEBHOOK_URL, or to the Master when no webhook is set, retrying
+// with backoff until it is accepted. Changed account files are written at
+// most every inboxSaveDelay, off the receive and forward paths.
+type InboxStore struct {
+	dir           string
+	workerID      string
+	retention     time.Duration
+	maxPerAccount int
+	mediaMaxBytes int64
+	maxAttempts   int
+	webhookURL    string
+	webhookSecret string
+
+	messages   map[string][]*ReceivedMessage // account -> messages, oldest first
+	groupNames map[string]string             // group JID -> subject
+	dirty      map[string]bool               // accounts changed since the last write
+	saveTimer  *time.Timer                   // pending write, nil when saved
+	stopChan   chan struct{}                 // closed to stop the forwarder
+	doneChan   chan struct{}                 // closed when the forwarder returned
+	mu         sync.Mutex
+	saveMu     sync.Mutex // serializes writes of the files
+}
+
+// NewInboxStore loads the stored messages of every account
+func NewInboxStore(workerID string) *InboxStore {
+	s := &InboxStore{
+		dir:           filepath.Join(getSessionsDir(), "inbox"),
+		workerID:      workerID,
+		retention:     envDuration("INBOX_RETENTION", DefaultInboxRetention),
+		maxPerAccount: envInt("INBOX_MAX_PER_ACCOUNT", DefaultInboxMaxPerAccount),
+		mediaMaxBytes: int64(envInt("INBOX_MEDIA_MAX_BYTES", DefaultInboxMediaMaxBytes)),
+		maxAttempts:   envInt("INBOUND_MAX_ATTEMPTS", DefaultInboundMaxAttempts),
+		webhookURL:    os.Getenv("INBOUND_WEBHOOK_URL"),
+		webhookSecret: os.Getenv("INBOUND_WEBHOOK_SECRET"),
+		messages:      make(map[string][]*ReceivedMessage),
+		groupNames:    make(map[string]string),
+		dirty:         make(map[string]bool),
+	}
+
+	files, err := filepath.Glob(filepath.Join(s.dir, "*.json"))
+	if err != nil {
+		return s
+	}
+	total := 0
+	for _, file := range files {
+		data, err := os.ReadFile(file)
+		if err != nil {
+			log.Printf("[INBOX] ⚠️ Failed to read %s: %v", file, err)
+			continue
+		}
+		var messages []*ReceivedMessage
+		if err := json.Unmarshal(data, &messages); err != nil {
+			log.Printf("[INBOX] ⚠️ Failed to parse %s: %v", file, err)
+			continue
+		}
+		// Forwards cut short by the last shutdown are retried
+		now := time.Now().UTC()
+		for _, msg := range messages {
+			if msg.ForwardedAt == nil && msg.NextAttemptAt == nil && msg.ForwardAttempts < s.maxAttempts {
+				msg.NextAttemptAt = &now
+			}
+		}
+		account := strings.TrimSuffix(filepath.Base(file), ".json")
+		s.messages[account] = messages
+		total += len(messages)
+	}
+
+	log.Printf("[INBOX] 📥 Loaded %d inbound messages for %d accounts", total, len(s.messages))
+	return s
+}
+
+// MediaDir returns where media of an account is stored
+func (s *InboxStore) MediaDir(account string) string {
+	return filepath.Join(s.dir, "media", sanitizePhone(account))
+}
+
+// MediaMaxBytes is the largest media file that is downloaded, 0 skips media
+func (s *InboxStore) MediaMaxBytes() int64 {
+	return s.mediaMaxBytes
+}
+
+// Add stores a message and forwards it. It reports false for a message that
+// was already stored, which happens when WhatsApp redelivers it.
+func (s *InboxStore) Add(msg ReceivedMessage) bool {
+	s.mu.Lock()
+	for _, existing := range s.messages[msg.To] {
+		if existing.ID == msg.ID {
+			s.mu.Unlock()
+			return false
+		}
+	}
+	stored := msg
+	s.messages[msg.To] = append(s.messages[msg.To], &stored)
+	s.pruneLocked(msg.To)
+	s.scheduleSaveLocked(msg.To)
+	s.mu.Unlock()
+
+	go s.forward(msg.To, msg.ID)
+	return true
+}
+
+// List returns the newest messages of an account, or of every account when
+// empty, newest first
+func (s *InboxStore) List(account string, since time.Time, limit int) []ReceivedMessage {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	result := make([]ReceivedMessage, 0)
+	for acc, messages := range s.messages {
+		if account != "" && acc != account {
+			continue
+		}
+		for _, msg := range messages {
+			if msg.Timestamp.After(since) {
+				result = append(result, *msg)
+			}
+		}
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].Timestamp.After(result[j].Timestamp) })
+	if limit > 0 && len(result) > limit {
+		result = result[:limit]
+	}
+	return result
+}
+
+// Get returns one message of an account
+func (s *InboxStore) Get(account, id string) (*ReceivedMessage, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, msg := range s.messages[account] {
+		if msg.ID == id {
+			copied := *msg
+			return &copied, nil
+		}
+	}
+	return nil, ErrInboundNotFound
+}
+
+// MediaPath returns the file of a message's media
+func (s *InboxStore) MediaPath(account, id string) (string, *InboundMedia, error) {
+	msg, err := s.Get(account, id)
+	if err != nil {
+		return "", nil, err
+	}
+	if msg.Media == nil || msg.Media.File == "" {
+		return "", nil, fmt.Errorf("message %s has no downloaded media", id)
+	}
+	return filepath.Join(s.dir, "media", msg.Media.File), msg.Media, nil
+}
+
+// GroupName returns a cached group subject
+func (s *InboxStore) GroupName(jid string) (string, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	name, ok := s.groupNames[jid]
+	return name, ok
+}
+
+// SetGroupName caches a group subject
+func (s *InboxStore) SetGroupName(jid, name string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.groupNames[jid] = name
+}
+
+// Stats returns counts for the status endpoint
+func (s *InboxStore) Stats() map[string]interface{} {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	total, pending, failed := 0, 0, 0
+	var last *time.Time
+	for _, messages := range s.messages {
+		for _, msg := range messages {
+			total++
+			if msg.ForwardedAt == nil {
+				if msg.NextAttemptAt != nil {
+					pending++
+				} else if msg.ForwardAttempts >= s.maxAttempts {
+					failed++
+				}
+			}
+			if last == nil || msg.Timestamp.After(*last) {
+				ts := msg.Timestamp
+				last = &ts
+			}
+		}
+	}
+	return map[string]interface{}{
+		"total":             total,
+		"forward_pending":   pending,
+		"forward_failed":    failed,
+		"last_message_time": last,
+	}
+}
+
+// StartForwarder retries forwards that failed, including those left over from
+// before a restart, until StopForwarder is called
+func (s *InboxStore) StartForwarder() {
+	s.mu.Lock()
+	if s.stopChan != nil {
+		s.mu.Unlock()
+		return
+	}
+	stop := make(chan struct{})
+	done := make(chan struct{})
+	s.stopChan, s.doneChan = stop, done
+	s.mu.Unlock()
+
+	go func() {
+		defer close(done)
+		ticker := time.NewTicker(15 * time.Second)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+				for _, key := range s.due() {
+					select {
+					case <-stop:
+						return
+					default:
+					}
+					s.forward(key[0], key[1])
+				}
+			case <-stop:
+				return
+			}
+		}
+	}()
+}
+
+// StopForwarder stops the retry loop and waits for the forward in progress
+// until ctx expires. Messages not forwarded yet are retried after a restart.
+func (s *InboxStore) StopForwarder(ctx context.Context) {
+	s.mu.Lock()
+	stop, done := s.stopChan, s.doneChan
+	s.stopChan, s.doneChan = nil, nil
+	s.mu.Unlock()
+	if stop == nil {
+		return
+	}
+
+	close(stop)
+	select {
+	case <-done:
+	case <-ctx.Done():
+		log.Printf("[INBOX] ⚠️ Gave up waiting for the forwarder: %v", ctx.Err())
+	}
+}
+
+// due returns account and ID of the messages whose retry time has come
+func (s *InboxStore) due() [][2]string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now()
+	keys := make([][2]string, 0)
+	for account, messages := range s.messages {
+		for _, msg := range messages {
+			if msg.ForwardedAt == nil && msg.NextAttemptAt != nil && !msg.NextAttemptAt.After(now) {
+				keys = append(keys, [2]string{account, msg.ID})
+			}
+		}
+	}
+	return keys
+}
+
+// forward pushes one message and records the outcome
+func (s *InboxStore) forward(account, id string) {
+	s.mu.Lock()
+	var msg *ReceivedMessage
+	for _, m := range s.messages[account] {
+		if m.ID == id {
+			msg = m
+			break
+		}
+	}
+	if msg == nil || msg.ForwardedAt != nil {
+		s.mu.Unlock()
+		return
+	}
+	// Claim it so the retry loop does not send it at the same time
+	msg.NextAttemptAt = nil
+	payload := *msg
+	s.mu.Unlock()
+
+	err := s.post(payload)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	now := time.Now().UTC()
+	msg.ForwardAttempts++
+	if err == nil {
+		msg.ForwardedAt = &now
+		msg.ForwardError = ""
+	} else {
+		msg.ForwardError = err.Error()
+		if msg.ForwardAttempts < s.maxAttempts {
+			// 30s, 1m, 2m, ... capped at 1h
+			backoff := 30 * time.Second << (msg.ForwardAttempts - 1)
+			if backoff > time.Hour || backoff <= 0 {
+				backoff = time.Hour
+			}
+			next := now.Add(backoff)
+			msg.NextAttemptAt = &next
+			log.Printf("[INBOX] ⚠️ Forward of %s for %s failed (attempt %d/%d), retrying at %s: %v",
+				id, account, msg.ForwardAttempts, s.maxAttempts, next.Format(time.RFC3339), err)
+		} else {
+			log.Printf("[INBOX] ❌ Giving up forwarding %s for %s after %d attempts: %v", id, account, msg.ForwardAttempts, err)
+		}
+	}
+	s.scheduleSaveLocked(account)
+}
+
+// post sends a message to the webhook or the Master
+func (s *InboxStore) post(msg ReceivedMessage) error {
+	url := s.webhookURL
+	toMaster := url == ""
+	if toMaster {
+		masterURL := os.Getenv("MASTER_URL")
+		if masterURL == "" {
+			masterURL = "http://master:5000"
+		}
+		url = fmt.Sprintf("%s/api/accounts/%s/inbound", masterURL, msg.To)
+	}
+
+	// The receiver does not need our delivery bookkeeping
+	msg.ForwardedAt, msg.ForwardAttempts, msg.ForwardError, msg.NextAttemptAt = nil, 0, "", nil
+	payload := map[string]interface{}{
+		"worker_id": s.workerID,
+		"message":   msg,
+	}
+	if msg.Media != nil && msg.Media.File != "" {
+		payload["media_path"] = fmt.Sprintf("/inbox/%s/%s/media", msg.To, msg.ID)
+	}
+	jsonData, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("failed to marshal inbound message: %w", err)
+	}
+
+	req, err := http.NewRequest("POST", url, bytes.NewBuffer(jsonData))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if toMaster {
+		setMasterAuth(req)
+	}
+	if s.webhookSecret != "" {
+		mac := hmac.New(sha256.New, []byte(s.webhookSecret))
+		mac.Write(jsonData)
+		req.Header.Set("X-Signature-256", "sha256="+hex.EncodeToString(mac.Sum(nil)))
+	}
+
+	client := &http.Client{Timeout: 10 * time.Second}
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("%s returned status %d", url, resp.StatusCode)
+	}
+	return nil
+}
+
+// pruneLocked drops the oldest messages past the retention or the per-account
+// limit (0 for no limit), with their media. Caller must hold the lock.
+func (s *InboxStore) pruneLocked(account string) {
+	messages := s.messages[account]
+	cutoff := time.Now().Add(-s.retention)
+	drop := 0
+	for drop < len(messages) && ((s.maxPerAccount > 0 && len(messages)-drop > s.maxPerAccount) || messages[drop].Timestamp.Before(cutoff)) {
+		if media := messages[drop].Media; media != nil && media.File != "" {
+			os.Remove(filepath.Join(s.dir, "media", media.File))
+		}
+		drop++
+	}
+	if drop > 0 {
+		s.messages[account] = append([]*ReceivedMessage(nil), messages[drop:]...)
+	}
+}
+
+// scheduleSaveLocked marks an account changed and writes the changed files
+// after inboxSaveDelay unless a write is already pending. Caller must hold
+// the lock.
+func (s *InboxStore) scheduleSaveLocked(account string) {
+	s.dirty[account] = true
+	if s.saveTimer != nil {
+		return
+	}
+	s.saveTimer = time.AfterFunc(inboxSaveDelay, func() {
+		if err := s.Flush(); err != nil {
+			log.Printf("[INBOX] ⚠️ Failed to save inbox: %v", err)
+		}
+	})
+}
+
+// Flush writes the changed accounts now. Shutdown calls it so nothing is lost.
+func (s *InboxStore) Flush() error {
+	s.saveMu.Lock()
+	defer s.saveMu.Unlock()
+
+	s.mu.Lock()
+	if s.saveTimer == nil {
+		s.mu.Unlock()
+		return nil
+	}
+	s.saveTimer.Stop()
+	s.saveTimer = nil
+	snapshots := make(map[string][]ReceivedMessage, len(s.dirty))
+	for account := range s.dirty {
+		messages := make([]ReceivedMessage, 0, len(s.messages[account]))
+		for _, msg := range s.messages[account] {
+			messages = append(messages, *msg)
+		}
+		snapshots[account] = messages
+	}
+	s.dirty = make(map[string]bool)
+	s.mu.Unlock()
+
+	var firstErr error
+	for account, messages := range snapshots {
+		if err := s.persist(account, messages); err != nil {
+			// Try again after the next delay
+			s.mu.Lock()
+			s.scheduleSaveLocked(account)
+			s.mu.Unlock()
+			if firstErr == nil {
+				firstErr = fmt.Errorf("account %s: %w", account, err)
+			}
+		}
+	}
+	return firstErr
+}
+
+// persist writes a snapshot of the messages of one account. Caller must hold
+// saveMu.
+func (s *InboxStore) persist(account string, messages []ReceivedMessage) error {
+	jsonData, err := json.Marshal(messages)
+	if err != nil {
+		return fmt.Errorf("failed to marshal inbox: %w", err)
+	}
+
+	if err := os.MkdirAll(s.dir, 0755); err != nil {
+		return fmt.Errorf("failed to create inbox directory: %w", err)
+	}
+
+	path := filepath.Join(s.dir, account+".json")
+	tmpFile := path + ".tmp"
+	if err := os.WriteFile(tmpFile, jsonData, 0644); err != nil {
+		return fmt.Errorf("failed to write inbox file: %w", err)
+	}
+	if err := os.Rename(tmpFile, path); err != nil {
+		return fmt.Errorf("failed to replace inbox file: %w", err)
+	}
+
+	return nil
+}
+
+// envInt reads a non-negative integer from the environment, falling back to def
+func envInt(key string, def int) int {
+	value := os.Getenv(key)
+	if value == "" {
+		return def
+	}
+	n, err := strconv.Atoi(value)
+	if err != nil || n < 0 {
+		log.Printf("[CONFIG] ⚠️ Invalid %s %q, using %d", key, value, def)
+		return def
+	}
+	return n
+}
+
+// sanitizeFileName keeps letters, digits, dashes and underscores so message
+// IDs can name files
+func sanitizeFileName(name string) string {
+	return strings.Map(func(r rune) rune {
+		if (r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z') || (r >= '0' && r <= '9') || r == '-' || r == '_' {
+			return r
+		}
+		return '_'
+	}, name)
+}
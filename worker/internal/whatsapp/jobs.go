@@ -0,0 +1,598 @@
+package whatsapp
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Job statuses
+const (
+	JobQueued  = "queued"
+	JobRunning = "running"
+	// JobDeferred is waiting for a policy, such as quiet hours, to allow it
+	JobDeferred  = "deferred"
+	JobSent      = "sent"
+	JobFailed    = "failed"
+	JobCancelled = "cancelled"
+	// JobInterrupted is a job that was running when the worker stopped. The
+	// message may or may not have left, so it is not retried.
+	JobInterrupted = "interrupted"
+)
+
+// DefaultJobTimeout bounds one job, anti-ban pauses included
+const DefaultJobTimeout = 30 * time.Minute
+
+// DefaultJobRetention is how long finished jobs stay queryable
+const DefaultJobRetention = 7 * 24 * time.Hour
+
+// jobSaveDelay batches the job changes of busy accounts into one write of
+// the jobs file
+const jobSaveDelay = 2 * time.Second
+
+// MaxJobReportAttempts bounds how many times an outcome is pushed to the
+// Master, once per finish and once per restart while unacknowledged
+const MaxJobReportAttempts = 5
+
+// ErrJobNotFound is returned for an unknown job ID
+var ErrJobNotFound = errors.New("job not found")
+
+// ErrJobFinished is returned when cancelling a job that already finished
+var ErrJobFinished = errors.New("job already finished")
+
+// JobRequest is a send to run in the background
+type JobRequest struct {
+	FromPhone      string `json:"from_phone"`
+	ToPhone        string `json:"to_phone"`
+	Message        string `json:"message"`
+	Name           string `json:"name,omitempty"`
+	ConsentScope   string `json:"consent_scope,omitempty"`
+	Campaign       string `json:"campaign,omitempty"`
+	IdempotencyKey string `json:"idempotency_key,omitempty"`
+}
+
+// Job is a queued send and its outcome
+type Job struct {
+	ID string `json:"id"`
+	JobRequest
+	Status    string `json:"status"`
+	MessageID string `json:"message_id,omitempty"`
+	Timestamp int64  `json:"timestamp,omitempty"`
+	Error     string `json:"error,omitempty"`
+	ErrorCode string `json:"error_code,omitempty"`
+	// NotBefore is when a deferred job is tried again
+	NotBefore *time.Time `json:"not_before,omitempty"`
+	// Attempted is true when the message was handed to WhatsApp
+	Attempted  bool       `json:"attempted"`
+	CreatedAt  time.Time  `json:"created_at"`
+	StartedAt  *time.Time `json:"started_at,omitempty"`
+	FinishedAt *time.Time `json:"finished_at,omitempty"`
+	// ReportedAt is set once the Master acknowledged the outcome
+	ReportedAt *time.Time `json:"reported_at,omitempty"`
+	// ReportAttempts counts failed pushes of the outcome to the Master
+	ReportAttempts int `json:"report_attempts,omitempty"`
+}
+
+// Finished reports whether the job reached a final status
+func (j *Job) Finished() bool {
+	return j.Status != JobQueued && j.Status != JobRunning && j.Status != JobDeferred
+}
+
+// JobQueue runs sends in the background, one at a time per account and in
+// submission order, and keeps them in a JSON file next to the sessions so
+// queued jobs survive a restart. The file is written at most every
+// jobSaveDelay, off the send path; Shutdown writes what is left.
+type JobQueue struct {
+	path      string
+	client    *ClientManager
+	timeout   time.Duration
+	retention time.Duration
+
+	jobs      map[string]*Job
+	keys      map[string]string             // idempotency key -> job ID
+	cancels   map[string]context.CancelFunc // running job ID -> cancel
+	workers   map[string]bool               // accounts with a running worker
+	wakeups   map[string]*time.Timer        // account -> restart for its next deferred job
+	saveTimer *time.Timer                   // pending write, nil when saved
+	mu        sync.Mutex
+	saveMu    sync.Mutex // serializes writes of the file
+
+	// Shutdown stops starting jobs and waits for the account workers
+	stopping bool
+	running  sync.WaitGroup
+}
+
+// NewJobQueue loads jobs from <sessions dir>/jobs.json. JOB_TIMEOUT and
+// JOB_RETENTION (Go durations) override the defaults.
+func NewJobQueue(client *ClientManager) *JobQueue {
+	q := &JobQueue{
+		path:      filepath.Join(getSessionsDir(), "jobs.json"),
+		client:    client,
+		timeout:   envDuration("JOB_TIMEOUT", DefaultJobTimeout),
+		retention: envDuration("JOB_RETENTION", DefaultJobRetention),
+		jobs:      make(map[string]*Job),
+		keys:      make(map[string]string),
+		cancels:   make(map[string]context.CancelFunc),
+		workers:   make(map[string]bool),
+		wakeups:   make(map[string]*time.Timer),
+	}
+
+	data, err := os.ReadFile(q.path)
+	if err != nil {
+		if !os.IsNotExist(err) {
+			log.Printf("[JOBS] ⚠️ Failed to read %s: %v", q.path, err)
+		}
+		return q
+	}
+
+	var jobs []*Job
+	if err := json.Unmarshal(data, &jobs); err != nil {
+		log.Printf("[JOBS] ⚠️ Failed to parse %s: %v", q.path, err)
+		return q
+	}
+	interrupted := 0
+	for _, j := range jobs {
+		if j.Status == JobRunning {
+			q.finishLocked(j, JobInterrupted, nil, fmt.Errorf("worker stopped while the job was running, delivery unknown"))
+			interrupted++
+		}
+		q.jobs[j.ID] = j
+		if j.IdempotencyKey != "" {
+			q.keys[j.IdempotencyKey] = j.ID
+		}
+	}
+
+	log.Printf("[JOBS] 📬 Loaded %d jobs, %d interrupted by the last shutdown", len(q.jobs), interrupted)
+	return q
+}
+
+// Start runs the queued jobs of every account and pushes outcomes the Master
+// has not acknowledged yet
+func (q *JobQueue) Start() {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	for _, j := range q.jobs {
+		if j.Status == JobQueued || j.Status == JobDeferred {
+			q.startWorkerLocked(j.FromPhone)
+		} else if j.ReportedAt == nil && j.ReportAttempts < MaxJobReportAttempts {
+			go q.report(j.ID)
+		}
+	}
+}
+
+// Submit queues a send. A key already used for the same sender and recipient
+// returns the existing job instead of queueing the message again.
+func (q *JobQueue) Submit(req JobRequest) (*Job, bool, error) {
+	if len(req.IdempotencyKey) > MaxIdempotencyKeyLength {
+		return nil, false, fmt.Errorf("idempotency key must be at most %d characters", MaxIdempotencyKeyLength)
+	}
+
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	if q.stopping {
+		return nil, false, ErrShuttingDown
+	}
+	if req.IdempotencyKey != "" {
+		if j, exists := q.jobs[q.keys[req.IdempotencyKey]]; exists {
+			if j.FromPhone != req.FromPhone || j.ToPhone != req.ToPhone {
+				return nil, false, ErrIdempotencyConflict
+			}
+			copied := *j
+			return &copied, true, nil
+		}
+	}
+
+	job := &Job{
+		ID:         uuid.NewString(),
+		JobRequest: req,
+		Status:     JobQueued,
+		CreatedAt:  time.Now().UTC(),
+	}
+	q.jobs[job.ID] = job
+	if job.IdempotencyKey != "" {
+		q.keys[job.IdempotencyKey] = job.ID
+	}
+	q.pruneLocked()
+	q.scheduleSaveLocked()
+	q.startWorkerLocked(job.FromPhone)
+
+	copied := *job
+	return &copied, false, nil
+}
+
+// Get returns a job by ID
+func (q *JobQueue) Get(id string) (*Job, error) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	j, exists := q.jobs[id]
+	if !exists {
+		return nil, ErrJobNotFound
+	}
+	copied := *j
+	return &copied, nil
+}
+
+// List returns jobs newest first, filtered by account and status when given
+func (q *JobQueue) List(fromPhone, status string) []Job {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	result := make([]Job, 0)
+	for _, j := range q.jobs {
+		if (fromPhone == "" || j.FromPhone == fromPhone) && (status == "" || j.Status == status) {
+			result = append(result, *j)
+		}
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].CreatedAt.After(result[j].CreatedAt) })
+	return result
+}
+
+// Cancel stops a job. A queued job is dropped; a running job is stopped if it
+// is still in its delays, otherwise it finishes with the send's outcome.
+func (q *JobQueue) Cancel(id string) (*Job, error) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	j, exists := q.jobs[id]
+	if !exists {
+		return nil, ErrJobNotFound
+	}
+
+	switch j.Status {
+	case JobQueued, JobDeferred:
+		q.finishLocked(j, JobCancelled, nil, nil)
+		q.scheduleSaveLocked()
+		go q.report(j.ID)
+	case JobRunning:
+		if cancel, ok := q.cancels[j.ID]; ok {
+			cancel()
+		}
+	default:
+		return nil, ErrJobFinished
+	}
+
+	copied := *j
+	return &copied, nil
+}
+
+// startWorkerLocked starts the worker of an account unless one is running.
+// Caller must hold the lock.
+func (q *JobQueue) startWorkerLocked(fromPhone string) {
+	if q.workers[fromPhone] || q.stopping {
+		return
+	}
+	q.workers[fromPhone] = true
+	q.running.Add(1)
+	go q.runAccount(fromPhone)
+}
+
+// StopAccepting refuses new jobs with ErrShuttingDown and stops starting
+// queued ones. Running jobs carry on.
+func (q *JobQueue) StopAccepting() {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	q.stopping = true
+	for phone, timer := range q.wakeups {
+		timer.Stop()
+		delete(q.wakeups, phone)
+	}
+}
+
+// Shutdown stops starting jobs and waits for the running ones until ctx
+// expires. Running jobs are then cancelled; those that had not reached
+// WhatsApp go back in the queue, which is written to disk for the next start.
+func (q *JobQueue) Shutdown(ctx context.Context) {
+	q.StopAccepting()
+	defer func() {
+		if err := q.Flush(); err != nil {
+			log.Printf("[JOBS] ⚠️ Failed to save jobs at shutdown: %v", err)
+		}
+	}()
+
+	done := make(chan struct{})
+	go func() {
+		q.running.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		log.Printf("[JOBS] All running jobs finished")
+		return
+	case <-ctx.Done():
+	}
+
+	q.mu.Lock()
+	for id, cancel := range q.cancels {
+		log.Printf("[JOBS] ⚠️ Cancelling job %s for shutdown", id)
+		cancel()
+	}
+	q.mu.Unlock()
+
+	// Give the cancelled jobs a moment to record their outcome
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		log.Printf("[JOBS] ⚠️ Jobs still running at shutdown will be marked interrupted on the next start")
+	}
+}
+
+// runAccount sends the queued jobs of one account in order, then exits. Jobs
+// a policy defers go back in the queue until their NotBefore time.
+func (q *JobQueue) runAccount(fromPhone string) {
+	defer q.running.Done()
+	for {
+		job, ctx, cancel := q.next(fromPhone)
+		if job == nil {
+			return
+		}
+
+		ctx = WithSendPolicy(ctx, SendPolicyOptions{ConsentScope: job.ConsentScope, Campaign: job.Campaign})
+		result, err := q.client.SendMessage(ctx, job.FromPhone, job.ToPhone, job.Message, job.Name)
+		cancelled := errors.Is(ctx.Err(), context.Canceled)
+		cancel()
+
+		var policyErr *PolicyError
+		q.mu.Lock()
+		delete(q.cancels, job.ID)
+		switch {
+		case errors.As(err, &policyErr) && policyErr.Deferrable() && !cancelled:
+			job.Status = JobDeferred
+			job.NotBefore = &policyErr.RetryAt
+			job.Error = policyErr.Error()
+			job.ErrorCode = policyErr.Code
+		case err == nil:
+			q.finishLocked(job, JobSent, result, nil)
+		case q.stopping && !SendAttempted(err) && (cancelled || errors.Is(err, ErrShuttingDown)):
+			// Cut short by shutdown before reaching WhatsApp, run it next start
+			job.Status = JobQueued
+			job.StartedAt = nil
+		case cancelled && !SendAttempted(err):
+			q.finishLocked(job, JobCancelled, nil, nil)
+		default:
+			q.finishLocked(job, JobFailed, nil, err)
+		}
+		q.scheduleSaveLocked()
+		status := job.Status
+		q.mu.Unlock()
+
+		if status == JobQueued {
+			log.Printf("[JOBS] ⏸️ %s → %s | job %s requeued for the next start", job.FromPhone, job.ToPhone, job.ID)
+			continue
+		}
+		if status == JobDeferred {
+			log.Printf("[JOBS] ⏸️ %s → %s | job %s deferred until %s: %v",
+				job.FromPhone, job.ToPhone, job.ID, policyErr.RetryAt.Format(time.RFC3339), err)
+			continue
+		}
+		log.Printf("[JOBS] %s → %s | job %s %s", job.FromPhone, job.ToPhone, job.ID, status)
+		// Reports retry for a while when the Master is down, the account's
+		// next job must not wait for them
+		go q.report(job.ID)
+	}
+}
+
+// next marks the oldest due job of an account as running and returns it with
+// its context. When none is due it stops the worker, scheduling a restart for
+// the earliest deferred job, and returns nil.
+func (q *JobQueue) next(fromPhone string) (*Job, context.Context, context.CancelFunc) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	if q.stopping {
+		delete(q.workers, fromPhone)
+		return nil, nil, nil
+	}
+
+	var job *Job
+	var wake *time.Time
+	for _, j := range q.jobs {
+		if j.FromPhone != fromPhone || (j.Status != JobQueued && j.Status != JobDeferred) {
+			continue
+		}
+		if j.Status == JobDeferred && j.NotBefore != nil && j.NotBefore.After(time.Now()) {
+			if wake == nil || j.NotBefore.Before(*wake) {
+				wake = j.NotBefore
+			}
+			continue
+		}
+		if job == nil || j.CreatedAt.Before(job.CreatedAt) {
+			job = j
+		}
+	}
+	if job == nil {
+		delete(q.workers, fromPhone)
+		if timer, ok := q.wakeups[fromPhone]; ok {
+			timer.Stop()
+			delete(q.wakeups, fromPhone)
+		}
+		if wake != nil {
+			q.wakeups[fromPhone] = time.AfterFunc(time.Until(*wake), func() {
+				q.mu.Lock()
+				defer q.mu.Unlock()
+				delete(q.wakeups, fromPhone)
+				q.startWorkerLocked(fromPhone)
+			})
+		}
+		return nil, nil, nil
+	}
+
+	now := time.Now().UTC()
+	job.Status = JobRunning
+	job.StartedAt = &now
+	job.NotBefore = nil
+	job.Error = ""
+	job.ErrorCode = ""
+	q.scheduleSaveLocked()
+
+	ctx, cancel := context.WithTimeout(context.Background(), q.timeout)
+	q.cancels[job.ID] = cancel
+	return job, ctx, cancel
+}
+
+// finishLocked records the final status of a job. Caller must hold the lock.
+func (q *JobQueue) finishLocked(j *Job, status string, result *SendResult, err error) {
+	now := time.Now().UTC()
+	j.Status = status
+	j.FinishedAt = &now
+	j.Attempted = status == JobSent || (status == JobFailed && SendAttempted(err))
+	if result != nil {
+		j.MessageID = result.MessageID
+		j.Timestamp = result.Timestamp
+	}
+	j.Error, j.ErrorCode = "", ""
+	if err != nil {
+		j.Error = err.Error()
+		_, j.ErrorCode = PolicyCode(err)
+		if errors.Is(err, context.DeadlineExceeded) {
+			j.ErrorCode = "timeout"
+		}
+	}
+}
+
+// report pushes the outcome of a finished job to the Master. Unacknowledged
+// outcomes are pushed again on the next start, up to MaxJobReportAttempts.
+func (q *JobQueue) report(id string) {
+	q.mu.Lock()
+	j, exists := q.jobs[id]
+	if !exists || !j.Finished() || j.ReportedAt != nil || j.ReportAttempts >= MaxJobReportAttempts {
+		q.mu.Unlock()
+		return
+	}
+	report := SendReport{FromPhone: j.FromPhone, ToPhone: j.ToPhone, JobID: j.ID, Status: j.Status}
+	if j.MessageID != "" {
+		report.Result = &SendResult{MessageID: j.MessageID, Timestamp: j.Timestamp, FromPhone: j.FromPhone, ToPhone: j.ToPhone}
+	}
+	if j.Error != "" {
+		report.Err = errors.New(j.Error)
+		if j.Attempted {
+			// Keep the attempted flag of sends WhatsApp refused
+			report.Err = fmt.Errorf("%w: %s", ErrSendFailed, j.Error)
+		}
+	} else if j.Status != JobSent {
+		report.Err = fmt.Errorf("job %s", j.Status)
+	}
+	q.mu.Unlock()
+
+	reportErr := q.client.ReportSendToMaster(report)
+
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if reportErr != nil {
+		j.ReportAttempts++
+		if j.ReportAttempts >= MaxJobReportAttempts {
+			log.Printf("[JOBS] ❌ Giving up reporting job %s to master after %d attempts: %v", id, j.ReportAttempts, reportErr)
+		} else {
+			log.Printf("[JOBS] ⚠️ Failed to report job %s to master, will retry on restart: %v", id, reportErr)
+		}
+	} else {
+		now := time.Now().UTC()
+		j.ReportedAt = &now
+	}
+	q.scheduleSaveLocked()
+}
+
+// pruneLocked drops finished jobs past the retention, reported or not; an
+// outcome the Master never took by then is not worth keeping. Caller must
+// hold the lock.
+func (q *JobQueue) pruneLocked() {
+	for id, j := range q.jobs {
+		if j.Finished() && j.FinishedAt != nil && time.Since(*j.FinishedAt) > q.retention {
+			delete(q.jobs, id)
+			if q.keys[j.IdempotencyKey] == id {
+				delete(q.keys, j.IdempotencyKey)
+			}
+		}
+	}
+}
+
+// scheduleSaveLocked writes the file after jobSaveDelay unless a write is
+// already pending. Caller must hold the lock.
+func (q *JobQueue) scheduleSaveLocked() {
+	if q.saveTimer != nil {
+		return
+	}
+	q.saveTimer = time.AfterFunc(jobSaveDelay, func() {
+		if err := q.Flush(); err != nil {
+			log.Printf("[JOBS] ⚠️ Failed to save jobs: %v", err)
+		}
+	})
+}
+
+// Flush writes pending changes now
+func (q *JobQueue) Flush() error {
+	q.saveMu.Lock()
+	defer q.saveMu.Unlock()
+
+	q.mu.Lock()
+	if q.saveTimer == nil {
+		q.mu.Unlock()
+		return nil
+	}
+	q.saveTimer.Stop()
+	q.saveTimer = nil
+	jobs := make([]Job, 0, len(q.jobs))
+	for _, j := range q.jobs {
+		jobs = append(jobs, *j)
+	}
+	q.mu.Unlock()
+
+	if err := q.persist(jobs); err != nil {
+		// Try again after the next delay
+		q.mu.Lock()
+		q.scheduleSaveLocked()
+		q.mu.Unlock()
+		return err
+	}
+	return nil
+}
+
+// persist writes a snapshot of the jobs to disk. Caller must hold saveMu.
+func (q *JobQueue) persist(jobs []Job) error {
+	sort.Slice(jobs, func(i, j int) bool { return jobs[i].CreatedAt.Before(jobs[j].CreatedAt) })
+
+	jsonData, err := json.Marshal(jobs)
+	if err != nil {
+		return fmt.Errorf("failed to marshal jobs: %w", err)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(q.path), 0755); err != nil {
+		return fmt.Errorf("failed to create jobs directory: %w", err)
+	}
+
+	tmpFile := q.path + ".tmp"
+	if err := os.WriteFile(tmpFile, jsonData, 0644); err != nil {
+		return fmt.Errorf("failed to write jobs file: %w", err)
+	}
+	if err := os.Rename(tmpFile, q.path); err != nil {
+		return fmt.Errorf("failed to replace jobs file: %w", err)
+	}
+
+	return nil
+}
+
+// envDuration reads a Go duration from the environment, falling back to def
+func envDuration(key string, def time.Duration) time.Duration {
+	value := os.Getenv(key)
+	if value == "" {
+		return def
+	}
+	d, err := time.ParseDuration(value)
+	if err != nil || d <= 0 {
+		log.Printf("[CONFIG] ⚠️ Invalid %s %q, using %s", key, value, def)
+		return def
+	}
+	return d
+}
@@ -2,9 +2,8 @@ package whatsapp
 
 import (
 	"context"
+	"fmt"
 	"log"
-	"os"
-	"path/filepath"
 	"sync"
 	"time"
 
@@ -172,11 +171,36 @@ func (m *ConnectionMonitor) checkAndReconnect() {
 		timeSinceDisconnect := time.Since(disconnectedTime)
 		if timeSinceDisconnect > RevivalPeriod {
 			expiredCount++
-			// === DELETE ACCOUNT AFTER 48 HOURS ===
-			log.Printf("[MONITOR] 💀 Account %s revival period expired (%.1f hours) - DELETING",
+			// === QUARANTINE ACCOUNT AFTER 48 HOURS ===
+			log.Printf("[MONITOR] 💀 Account %s revival period expired (%.1f hours) - QUARANTINING",
 				phone, timeSinceDisconnect.Hours())
 
-			go m.deleteExpiredAccount(phone, acc)
+			// Remove from monitor tracking
+			m.mu.Lock()
+			failures := m.reconnectFailures[phone]
+			delete(m.disconnectedSince, phone)
+			delete(m.reconnectFailures, phone)
+			delete(m.lastReconnectAttempt, phone)
+			m.mu.Unlock()
+
+			reason := fmt.Sprintf("not connected for %.1f hours, revival period of %s expired after %d failed reconnects",
+				timeSinceDisconnect.Hours(), RevivalPeriod, failures)
+			acc.mu.RLock()
+			if acc.LastError != "" {
+				reason += ", last error: " + acc.LastError
+			}
+			acc.mu.RUnlock()
+
+			go func() {
+				if err := m.manager.quarantineExpiredAccount(phone, acc, reason, disconnectedTime); err != nil {
+					log.Printf("[MONITOR] ❌ Failed to quarantine session of %s, retrying on the next check: %v", phone, err)
+					// Track it again so the next check retries instead of starting a new revival period
+					m.mu.Lock()
+					m.disconnectedSince[phone] = disconnectedTime
+					m.reconnectFailures[phone] = failures
+					m.mu.Unlock()
+				}
+			}()
 			continue
 		}
 
@@ -425,50 +449,33 @@ func (m *ConnectionMonitor) sendFirstAidMessage(phone string, acc *AccountClient
 	}
 }
 
-// deleteExpiredAccount removes an account after 48 hours of failed revival
-func (m *ConnectionMonitor) deleteExpiredAccount(phone string, acc *AccountClient) {
-	log.Printf("[MONITOR] 💀 Deleting expired account: %s", phone)
-
-	// Disconnect client if still exists
+// quarantineExpiredAccount moves an account's session into quarantine after
+// 48 hours of failed revival, where it can be restored, and then removes the
+// account. When the files can't be moved they are put back and the account
+// stays registered.
+func (m *ClientManager) quarantineExpiredAccount(phone string, acc *AccountClient, reason string, disconnectedSince time.Time) error {
+	// The database must be closed before its files move
 	if acc.Client != nil {
 		acc.Client.Disconnect()
 	}
+	if acc.Container != nil {
+		acc.Container.Close()
+	}
 
-	// Remove from manager
-	m.manager.mu.Lock()
-	delete(m.manager.accounts, phone)
-	m.manager.mu.Unlock()
-
-	// Remove from monitor tracking
-	m.mu.Lock()
-	delete(m.disconnectedSince, phone)
-	delete(m.reconnectFailures, phone)
-	delete(m.lastReconnectAttempt, phone)
-	m.mu.Unlock()
-
-	// Delete session files
-	go m.deleteSessionFiles(phone)
-
-	log.Printf("[MONITOR] 💀 Account %s deleted after 48h revival failure", phone)
-}
-
-// deleteSessionFiles removes session files for an account
-func (m *ConnectionMonitor) deleteSessionFiles(phone string) {
-	sessionsDir := "/data/sessions"
-
-	// Delete .db file
-	dbFile := filepath.Join(sessionsDir, phone+".db")
-	if err := os.Remove(dbFile); err != nil && !os.IsNotExist(err) {
-		log.Printf("[MONITOR] Failed to delete %s: %v", dbFile, err)
+	entry, err := m.quarantine.Quarantine(phone, reason, disconnectedSince)
+	if err != nil {
+		return err
 	}
 
-	// Delete .meta.json file
-	metaFile := filepath.Join(sessionsDir, phone+".meta.json")
-	if err := os.Remove(metaFile); err != nil && !os.IsNotExist(err) {
-		log.Printf("[MONITOR] Failed to delete %s: %v", metaFile, err)
+	m.mu.Lock()
+	// The account may have been paired anew in the meantime
+	if m.accounts[phone] == acc {
+		delete(m.accounts, phone)
 	}
+	m.mu.Unlock()
 
-	log.Printf("[MONITOR] Session files deleted for %s", phone)
+	log.Printf("[MONITOR] 💀 Account %s quarantined as %s: %s", phone, entry.ID, reason)
+	return nil
 }
 
 // GetRevivalAccounts returns list of accounts currently in revival period
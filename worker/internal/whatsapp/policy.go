@@ -0,0 +1,191 @@
+package whatsapp
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"time"
+)
+
+// ErrPolicyRejected is matched by errors.Is for every send refused by a
+// pre-send policy, whichever policy refused it
+var ErrPolicyRejected = errors.New("send rejected by policy")
+
+// PolicyError is returned by SendMessage when a pre-send policy refuses the
+// recipient. Code is machine readable, e.g. "consent_missing". RetryAt is set
+// when the send may be retried later instead of being dropped.
+type PolicyError struct {
+	Policy  string
+	Code    string
+	Phone   string
+	Reason  string
+	RetryAt time.Time
+}
+
+func (e *PolicyError) Error() string {
+	return fmt.Sprintf("%s policy rejected +%s: %s", e.Policy, e.Phone, e.Reason)
+}
+
+func (e *PolicyError) Is(target error) bool {
+	return target == ErrPolicyRejected
+}
+
+// Deferrable reports whether the send may be retried at RetryAt
+func (e *PolicyError) Deferrable() bool {
+	return !e.RetryAt.IsZero()
+}
+
+// SendPolicyOptions carries the per-send inputs of the pre-send policies
+type SendPolicyOptions struct {
+	// ConsentScope is the kind of contact the send needs consent for
+	ConsentScope string
+	// Campaign selects the campaign's quiet hours window, if configured
+	Campaign string
+}
+
+type sendPolicyKey struct{}
+
+// WithSendPolicy attaches policy inputs to the context given to SendMessage
+func WithSendPolicy(ctx context.Context, opts SendPolicyOptions) context.Context {
+	return context.WithValue(ctx, sendPolicyKey{}, opts)
+}
+
+func sendPolicyFromContext(ctx context.Context) SendPolicyOptions {
+	opts, _ := ctx.Value(sendPolicyKey{}).(SendPolicyOptions)
+	return opts
+}
+
+// CheckSendPolicy runs the pre-send policies for a recipient without sending,
+// so callers can screen a list of recipients up front
+func (m *ClientManager) CheckSendPolicy(ctx context.Context, toPhone string) error {
+	if err := m.suppressions.Check(toPhone); err != nil {
+		return err
+	}
+	opts := sendPolicyFromContext(ctx)
+	if err := m.consents.Check(toPhone, opts.ConsentScope); err != nil {
+		return err
+	}
+	if err := m.quietHours.Check(toPhone, opts.Campaign); err != nil {
+		return err
+	}
+	if err := m.frequency.Check(toPhone); err != nil {
+		return err
+	}
+	return nil
+}
+
+// PolicyCode returns the policy that refused a send and its machine readable
+// code, or empty strings when err is not a policy rejection
+func PolicyCode(err error) (string, string) {
+	var policyErr *PolicyError
+	switch {
+	case errors.As(err, &policyErr):
+		return policyErr.Policy, policyErr.Code
+	case errors.Is(err, ErrRecipientSuppressed):
+		return "suppression", "recipient_suppressed"
+	case errors.Is(err, ErrFrequencyCapped):
+		return "frequency_cap", FrequencyCapCode
+	case errors.Is(err, ErrPolicyRejected):
+		return "", "policy_rejected"
+	}
+	return "", ""
+}
+
+// reportPolicyRejectionToMaster tells the master a recipient was refused, so
+// campaigns can mark it instead of retrying
+func (m *ClientManager) reportPolicyRejectionToMaster(fromPhone, toPhone string, rejection error) {
+	masterURL := os.Getenv("MASTER_URL")
+	if masterURL == "" {
+		masterURL = "http://master:5000"
+	}
+
+	url := fmt.Sprintf("%s/api/accounts/%s/policy-rejections", masterURL, fromPhone)
+
+	payload := map[string]interface{}{
+		"worker_id": m.WorkerID,
+		"to_phone":  toPhone,
+		"reason":    rejection.Error(),
+	}
+	if policy, code := PolicyCode(rejection); code != "" {
+		payload["policy"] = policy
+		payload["code"] = code
+	}
+	var capErr *FrequencyCapError
+	if errors.As(rejection, &capErr) {
+		payload["sent"] = capErr.Sent
+		payload["max"] = capErr.Max
+		payload["next_allowed"] = capErr.NextAllowed
+	}
+
+	jsonData, err := json.Marshal(payload)
+	if err != nil {
+		return
+	}
+
+	req, err := http.NewRequest("POST", url, bytes.NewBuffer(jsonData))
+	if err != nil {
+		return
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+	setMasterAuth(req)
+
+	client := &http.Client{Timeout: 5 * time.Second}
+	resp, err := client.Do(req)
+	if err != nil {
+		log.Printf("[POLICY] ⚠️ Failed to report rejection of %s to master: %v", toPhone, err)
+		return
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		log.Printf("[POLICY] ⚠️ Master returned status %d for the rejection of %s", resp.StatusCode, toPhone)
+	}
+}
+
+// reportOptOutToMaster tells the master a recipient opted out, so messages
+// queued for it on any worker are dropped
+func (m *ClientManager) reportOptOutToMaster(account string, entry *SuppressionEntry) {
+	masterURL := os.Getenv("MASTER_URL")
+	if masterURL == "" {
+		masterURL = "http://master:5000"
+	}
+
+	url := fmt.Sprintf("%s/api/accounts/%s/opt-outs", masterURL, account)
+
+	payload := map[string]interface{}{
+		"worker_id":  m.WorkerID,
+		"to_phone":   entry.Phone,
+		"source":     entry.Source,
+		"keyword":    entry.Keyword,
+		"created_at": entry.CreatedAt,
+	}
+
+	jsonData, err := json.Marshal(payload)
+	if err != nil {
+		return
+	}
+
+	req, err := http.NewRequest("POST", url, bytes.NewBuffer(jsonData))
+	if err != nil {
+		return
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+	setMasterAuth(req)
+
+	client := &http.Client{Timeout: 5 * time.Second}
+	resp, err := client.Do(req)
+	if err != nil {
+		log.Printf("[POLICY] ⚠️ Failed to report opt-out of %s to master: %v", entry.Phone, err)
+		return
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		log.Printf("[POLICY] ⚠️ Master returned status %d for the opt-out of %s", resp.StatusCode, entry.Phone)
+	}
+}
@@ -0,0 +1,153 @@
+package whatsapp
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+)
+
+// quietHoursAround returns a window in UTC that contains now when open is
+// true and starts two hours from now otherwise
+func quietHoursAround(now time.Time, open bool) QuietHoursWindow {
+	if open {
+		return QuietHoursWindow{Start: now.Add(-time.Hour).Format("15:04"), End: now.Add(time.Hour).Format("15:04"), TimeZone: "UTC"}
+	}
+	return QuietHoursWindow{Start: now.Add(2 * time.Hour).Format("15:04"), End: now.Add(3 * time.Hour).Format("15:04"), TimeZone: "UTC"}
+}
+
+func TestCheckSendPolicyOrder(t *testing.T) {
+	const phone = "4915112345678"
+	now := time.Now().UTC()
+
+	tests := []struct {
+		name       string
+		suppressed bool
+		consent    bool
+		open       bool
+		capped     bool
+		wantPolicy string
+		wantCode   string
+	}{
+		{name: "suppression comes first", suppressed: true, wantPolicy: "suppression", wantCode: "recipient_suppressed"},
+		{name: "then consent", wantPolicy: "consent", wantCode: ConsentCodeMissing},
+		{name: "then quiet hours", consent: true, capped: true, wantPolicy: "quiet_hours", wantCode: QuietHoursCode},
+		{name: "then the frequency cap", consent: true, open: true, capped: true, wantPolicy: "frequency_cap", wantCode: FrequencyCapCode},
+		{name: "allowed when every policy passes", consent: true, open: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := &ClientManager{
+				suppressions: &SuppressionStore{entries: make(map[string]*SuppressionEntry)},
+				consents: &ConsentStore{
+					required:     true,
+					defaultScope: DefaultConsentScope,
+					records:      make(map[string]map[string]*ConsentRecord),
+				},
+				quietHours: &QuietHoursStore{config: QuietHoursConfig{
+					Enabled:   true,
+					Mode:      QuietHoursDefer,
+					Countries: map[string]QuietHoursWindow{"49": quietHoursAround(now, tt.open)},
+				}},
+				frequency: &FrequencyCapStore{max: 1, window: time.Hour, sends: make(map[string][]time.Time)},
+			}
+			if tt.suppressed {
+				m.suppressions.entries[phone] = &SuppressionEntry{Phone: phone, Source: "manual", CreatedAt: now}
+			}
+			if tt.consent {
+				m.consents.records[phone] = map[string]*ConsentRecord{
+					ConsentScopeAll: {Phone: phone, Scope: ConsentScopeAll, Source: "signup-form", GrantedAt: now},
+				}
+			}
+			if tt.capped {
+				m.frequency.sends[phone] = []time.Time{now.Add(-time.Minute)}
+			}
+
+			err := m.CheckSendPolicy(context.Background(), "+"+phone)
+			if tt.wantCode == "" {
+				if err != nil {
+					t.Fatalf("CheckSendPolicy() error = %v, want none", err)
+				}
+				return
+			}
+			if !errors.Is(err, ErrPolicyRejected) {
+				t.Fatalf("CheckSendPolicy() error = %v, want a policy rejection", err)
+			}
+			if policy, code := PolicyCode(err); policy != tt.wantPolicy || code != tt.wantCode {
+				t.Fatalf("PolicyCode() = %s/%s, want %s/%s", policy, code, tt.wantPolicy, tt.wantCode)
+			}
+		})
+	}
+}
+
+func TestPolicyErrors(t *testing.T) {
+	retryAt := time.Date(2026, 1, 15, 8, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name       string
+		err        error
+		rejected   bool
+		deferrable bool
+		wantPolicy string
+		wantCode   string
+	}{
+		{
+			name:       "deferred quiet hours",
+			err:        &PolicyError{Policy: "quiet_hours", Code: QuietHoursCode, RetryAt: retryAt},
+			rejected:   true,
+			deferrable: true,
+			wantPolicy: "quiet_hours",
+			wantCode:   QuietHoursCode,
+		},
+		{
+			name:       "rejected quiet hours",
+			err:        &PolicyError{Policy: "quiet_hours", Code: QuietHoursCode},
+			rejected:   true,
+			wantPolicy: "quiet_hours",
+			wantCode:   QuietHoursCode,
+		},
+		{
+			name:       "wrapped consent",
+			err:        fmt.Errorf("send failed: %w", &PolicyError{Policy: "consent", Code: ConsentCodeExpired}),
+			rejected:   true,
+			wantPolicy: "consent",
+			wantCode:   ConsentCodeExpired,
+		},
+		{
+			name:       "suppressed recipient",
+			err:        &SuppressedError{Phone: "4915112345678", Source: "keyword"},
+			rejected:   true,
+			wantPolicy: "suppression",
+			wantCode:   "recipient_suppressed",
+		},
+		{
+			name:       "frequency capped",
+			err:        &FrequencyCapError{Phone: "4915112345678", Sent: 3, Max: 3, NextAllowed: retryAt},
+			rejected:   true,
+			wantPolicy: "frequency_cap",
+			wantCode:   FrequencyCapCode,
+		},
+		{
+			name: "not a policy error",
+			err:  errors.New("not connected"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := errors.Is(tt.err, ErrPolicyRejected); got != tt.rejected {
+				t.Errorf("errors.Is(ErrPolicyRejected) = %v, want %v", got, tt.rejected)
+			}
+			var policyErr *PolicyError
+			deferrable := errors.As(tt.err, &policyErr) && policyErr.Deferrable()
+			if deferrable != tt.deferrable {
+				t.Errorf("deferrable = %v, want %v", deferrable, tt.deferrable)
+			}
+			if policy, code := PolicyCode(tt.err); policy != tt.wantPolicy || code != tt.wantCode {
+				t.Errorf("PolicyCode() = %q/%q, want %q/%q", policy, code, tt.wantPolicy, tt.wantCode)
+			}
+		})
+	}
+}
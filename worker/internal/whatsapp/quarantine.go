@@ -0,0 +1,375 @@
+package whatsapp
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// DefaultQuarantineRetention is how long quarantined sessions are kept
+const DefaultQuarantineRetention = 30 * 24 * time.Hour
+
+// ErrQuarantineNotFound is returned for an unknown quarantine ID
+var ErrQuarantineNotFound = errors.New("quarantined session not found")
+
+// ErrSessionExists is returned when restoring over a session already in use
+var ErrSessionExists = errors.New("account already has a session")
+
+// sessionFileSuffixes are the files that make up an account's session. The
+// sqlite side files only exist while the database is open or after a crash.
+var sessionFileSuffixes = []string{".db", ".db-wal", ".db-shm", ".db-journal", ".meta.json"}
+
+// sessionFileNames lists every file an account's sessions may have: session 1
+// is <phone>.db, the backup sessions 2-4 are <phone>-session-N.db
+func sessionFileNames(phone string) []string {
+	names := make([]string, 0, MaxSessionsPerPhone*len(sessionFileSuffixes))
+	for session := 1; session <= MaxSessionsPerPhone; session++ {
+		base := phone
+		if session > 1 {
+			base = fmt.Sprintf("%s-session-%d", phone, session)
+		}
+		for _, suffix := range sessionFileSuffixes {
+			names = append(names, base+suffix)
+		}
+	}
+	return names
+}
+
+// QuarantinedSession is an account's session moved out of the sessions
+// directory, and why
+type QuarantinedSession struct {
+	ID                string     `json:"id"`
+	Phone             string     `json:"phone"`
+	Reason            string     `json:"reason"`
+	QuarantinedAt     time.Time  `json:"quarantined_at"`
+	DisconnectedSince *time.Time `json:"disconnected_since,omitempty"`
+	Files             []string   `json:"files"`
+	PurgeAt           *time.Time `json:"purge_at,omitempty"`
+}
+
+// QuarantineStore keeps expired sessions under <sessions dir>/quarantine,
+// one directory per session holding its files and a quarantine.json that
+// records the reason. Sessions can be restored until the retention purges
+// them.
+type QuarantineStore struct {
+	dir       string
+	retention time.Duration
+	mu        sync.Mutex
+	stopChan  chan struct{}
+}
+
+// NewQuarantineStore uses <sessions dir>/quarantine. QUARANTINE_RETENTION
+// overrides how long sessions are kept; 0 keeps them until purged by hand.
+func NewQuarantineStore() *QuarantineStore {
+	return &QuarantineStore{
+		dir:       filepath.Join(getSessionsDir(), "quarantine"),
+		retention: envDuration("QUARANTINE_RETENTION", DefaultQuarantineRetention),
+	}
+}
+
+// Quarantine moves an account's session files out of the sessions directory.
+// The session's database must be closed first.
+func (q *QuarantineStore) Quarantine(phone, reason string, disconnectedSince time.Time) (*QuarantinedSession, error) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	now := time.Now().UTC()
+	entry := &QuarantinedSession{
+		ID:            fmt.Sprintf("%s-%d", sanitizeFileName(phone), now.Unix()),
+		Phone:         phone,
+		Reason:        reason,
+		QuarantinedAt: now,
+		Files:         make([]string, 0),
+	}
+	if !disconnectedSince.IsZero() {
+		since := disconnectedSince.UTC()
+		entry.DisconnectedSince = &since
+	}
+
+	entryDir := filepath.Join(q.dir, entry.ID)
+	if err := os.MkdirAll(entryDir, 0755); err != nil {
+		return nil, fmt.Errorf("failed to create quarantine directory: %w", err)
+	}
+	if err := writeQuarantineEntry(entryDir, entry); err != nil {
+		return nil, err
+	}
+
+	sessionsDir := getSessionsDir()
+	for _, name := range sessionFileNames(phone) {
+		err := os.Rename(filepath.Join(sessionsDir, name), filepath.Join(entryDir, name))
+		if os.IsNotExist(err) {
+			continue
+		}
+		if err != nil {
+			q.rollbackQuarantine(entryDir, entry.Files)
+			return nil, fmt.Errorf("failed to move %s: %w", name, err)
+		}
+		entry.Files = append(entry.Files, name)
+	}
+	if err := writeQuarantineEntry(entryDir, entry); err != nil {
+		q.rollbackQuarantine(entryDir, entry.Files)
+		return nil, err
+	}
+
+	q.setPurgeAt(entry)
+	return entry, nil
+}
+
+// rollbackQuarantine moves the files of a failed quarantine back into the
+// sessions directory so the account's session stays whole where it was.
+// Caller must hold the lock.
+func (q *QuarantineStore) rollbackQuarantine(entryDir string, moved []string) {
+	sessionsDir := getSessionsDir()
+	for _, name := range moved {
+		if err := os.Rename(filepath.Join(entryDir, name), filepath.Join(sessionsDir, name)); err != nil {
+			// Keep the entry so what is left can still be restored
+			log.Printf("[QUARANTINE] ⚠️ Failed to move %s back into the sessions directory: %v", name, err)
+			return
+		}
+	}
+	if err := os.RemoveAll(entryDir); err != nil {
+		log.Printf("[QUARANTINE] ⚠️ Failed to remove %s: %v", entryDir, err)
+	}
+}
+
+// List returns quarantined sessions newest first, for one phone when given
+func (q *QuarantineStore) List(phone string) ([]QuarantinedSession, error) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return q.listLocked(phone)
+}
+
+// Get returns a quarantined session
+func (q *QuarantineStore) Get(id string) (*QuarantinedSession, error) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return q.getLocked(id)
+}
+
+// Restore moves a quarantined session's files back into the sessions
+// directory. It refuses when the account has a session again, for example
+// because it was paired anew.
+func (q *QuarantineStore) Restore(id string) (*QuarantinedSession, error) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	entry, err := q.getLocked(id)
+	if err != nil {
+		return nil, err
+	}
+
+	sessionsDir := getSessionsDir()
+	if _, err := os.Stat(filepath.Join(sessionsDir, entry.Phone+".db")); err == nil {
+		return nil, ErrSessionExists
+	}
+	for _, name := range entry.Files {
+		if _, err := os.Stat(filepath.Join(sessionsDir, name)); err == nil {
+			return nil, ErrSessionExists
+		}
+	}
+
+	entryDir := filepath.Join(q.dir, entry.ID)
+	for i, name := range entry.Files {
+		if err := os.Rename(filepath.Join(entryDir, name), filepath.Join(sessionsDir, name)); err != nil {
+			// Put back what already moved so the quarantined session stays whole
+			for _, moved := range entry.Files[:i] {
+				if err := os.Rename(filepath.Join(sessionsDir, moved), filepath.Join(entryDir, moved)); err != nil {
+					log.Printf("[QUARANTINE] ⚠️ Failed to move %s back into quarantine: %v", moved, err)
+				}
+			}
+			return nil, fmt.Errorf("failed to restore %s: %w", name, err)
+		}
+	}
+	if err := os.RemoveAll(entryDir); err != nil {
+		log.Printf("[QUARANTINE] ⚠️ Failed to remove %s: %v", entryDir, err)
+	}
+	return entry, nil
+}
+
+// Purge deletes a quarantined session for good
+func (q *QuarantineStore) Purge(id string) (*QuarantinedSession, error) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	entry, err := q.getLocked(id)
+	if err != nil {
+		return nil, err
+	}
+	if err := os.RemoveAll(filepath.Join(q.dir, entry.ID)); err != nil {
+		return nil, fmt.Errorf("failed to purge quarantined session: %w", err)
+	}
+	return entry, nil
+}
+
+// StartPurger purges sessions past the retention every hour
+func (q *QuarantineStore) StartPurger() {
+	if q.retention <= 0 {
+		log.Printf("[QUARANTINE] Retention disabled, quarantined sessions are kept until purged")
+		return
+	}
+	q.stopChan = make(chan struct{})
+
+	go func() {
+		ticker := time.NewTicker(time.Hour)
+		defer ticker.Stop()
+
+		q.purgeExpired()
+		for {
+			select {
+			case <-ticker.C:
+				q.purgeExpired()
+			case <-q.stopChan:
+				return
+			}
+		}
+	}()
+}
+
+// StopPurger stops the retention timer
+func (q *QuarantineStore) StopPurger() {
+	if q.stopChan != nil {
+		close(q.stopChan)
+		q.stopChan = nil
+	}
+}
+
+func (q *QuarantineStore) purgeExpired() {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	entries, err := q.listLocked("")
+	if err != nil {
+		log.Printf("[QUARANTINE] ⚠️ Failed to list quarantined sessions: %v", err)
+		return
+	}
+	for _, entry := range entries {
+		if entry.PurgeAt == nil || entry.PurgeAt.After(time.Now()) {
+			continue
+		}
+		if err := os.RemoveAll(filepath.Join(q.dir, entry.ID)); err != nil {
+			log.Printf("[QUARANTINE] ⚠️ Failed to purge %s: %v", entry.ID, err)
+			continue
+		}
+		log.Printf("[QUARANTINE] 🗑️ Purged session of %s quarantined at %s", entry.Phone, entry.QuarantinedAt.Format(time.RFC3339))
+	}
+}
+
+func (q *QuarantineStore) listLocked(phone string) ([]QuarantinedSession, error) {
+	result := make([]QuarantinedSession, 0)
+
+	dirs, err := os.ReadDir(q.dir)
+	if os.IsNotExist(err) {
+		return result, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read quarantine directory: %w", err)
+	}
+
+	for _, dir := range dirs {
+		if !dir.IsDir() {
+			continue
+		}
+		entry, err := readQuarantineEntry(filepath.Join(q.dir, dir.Name()))
+		if err != nil {
+			log.Printf("[QUARANTINE] ⚠️ Skipping %s: %v", dir.Name(), err)
+			continue
+		}
+		if phone != "" && entry.Phone != phone {
+			continue
+		}
+		q.setPurgeAt(entry)
+		result = append(result, *entry)
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].QuarantinedAt.After(result[j].QuarantinedAt) })
+	return result, nil
+}
+
+func (q *QuarantineStore) getLocked(id string) (*QuarantinedSession, error) {
+	// IDs are directory names, never paths
+	if id == "" || id != filepath.Base(id) || strings.HasPrefix(id, ".") {
+		return nil, ErrQuarantineNotFound
+	}
+	entry, err := readQuarantineEntry(filepath.Join(q.dir, id))
+	if os.IsNotExist(err) {
+		return nil, ErrQuarantineNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	q.setPurgeAt(entry)
+	return entry, nil
+}
+
+// setPurgeAt fills in when the retention purges a session
+func (q *QuarantineStore) setPurgeAt(entry *QuarantinedSession) {
+	if q.retention <= 0 {
+		return
+	}
+	purgeAt := entry.QuarantinedAt.Add(q.retention)
+	entry.PurgeAt = &purgeAt
+}
+
+func readQuarantineEntry(entryDir string) (*QuarantinedSession, error) {
+	data, err := os.ReadFile(filepath.Join(entryDir, "quarantine.json"))
+	if err != nil {
+		return nil, err
+	}
+	var entry QuarantinedSession
+	if err := json.Unmarshal(data, &entry); err != nil {
+		return nil, fmt.Errorf("failed to parse quarantine.json: %w", err)
+	}
+	return &entry, nil
+}
+
+func writeQuarantineEntry(entryDir string, entry *QuarantinedSession) error {
+	jsonData, err := json.MarshalIndent(entry, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal quarantine entry: %w", err)
+	}
+
+	path := filepath.Join(entryDir, "quarantine.json")
+	tmpFile := path + ".tmp"
+	if err := os.WriteFile(tmpFile, jsonData, 0644); err != nil {
+		return fmt.Errorf("failed to write quarantine entry: %w", err)
+	}
+	if err := os.Rename(tmpFile, path); err != nil {
+		return fmt.Errorf("failed to replace quarantine entry: %w", err)
+	}
+	return nil
+}
+
+// RestoreQuarantinedSession moves a quarantined session back and loads it
+// like a session found at startup
+func (m *ClientManager) RestoreQuarantinedSession(ctx context.Context, id string) (*QuarantinedSession, bool, error) {
+	entry, err := m.quarantine.Get(id)
+	if err != nil {
+		return nil, false, err
+	}
+
+	m.mu.RLock()
+	_, active := m.accounts[entry.Phone]
+	m.mu.RUnlock()
+	if active {
+		return nil, false, ErrSessionExists
+	}
+
+	entry, err = m.quarantine.Restore(id)
+	if err != nil {
+		return nil, false, err
+	}
+	log.Printf("[QUARANTINE] ♻️ Restored session of %s", entry.Phone)
+
+	loaded, err := m.loadAndValidateSession(ctx, entry.Phone)
+	if err != nil {
+		return entry, false, fmt.Errorf("session restored but failed to load: %w", err)
+	}
+	return entry, loaded, nil
+}
@@ -0,0 +1,121 @@
+package whatsapp
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+const testQuarantinePhone = "4915112345678"
+
+// newTestQuarantine creates a quarantine store in a temporary sessions
+// directory holding the given session files
+func newTestQuarantine(t *testing.T, files ...string) *QuarantineStore {
+	t.Helper()
+	t.Chdir(t.TempDir())
+
+	sessionsDir := getSessionsDir()
+	if err := os.MkdirAll(sessionsDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	for _, name := range files {
+		if err := os.WriteFile(filepath.Join(sessionsDir, name), []byte(name), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return NewQuarantineStore()
+}
+
+func assertSessionFiles(t *testing.T, want ...string) {
+	t.Helper()
+	for _, name := range want {
+		data, err := os.ReadFile(filepath.Join(getSessionsDir(), name))
+		if err != nil {
+			t.Fatalf("session file %s: %v", name, err)
+		}
+		if string(data) != name {
+			t.Fatalf("session file %s has content %q", name, data)
+		}
+	}
+}
+
+func TestQuarantineAndRestore(t *testing.T) {
+	files := []string{testQuarantinePhone + ".db", testQuarantinePhone + ".meta.json", testQuarantinePhone + "-session-2.db"}
+	q := newTestQuarantine(t, files...)
+
+	entry, err := q.Quarantine(testQuarantinePhone, "revival period expired", time.Now().Add(-49*time.Hour))
+	if err != nil {
+		t.Fatalf("Quarantine() error = %v", err)
+	}
+	if len(entry.Files) != len(files) {
+		t.Fatalf("quarantined files = %v, want %v", entry.Files, files)
+	}
+	if _, err := os.Stat(filepath.Join(getSessionsDir(), testQuarantinePhone+".db")); !os.IsNotExist(err) {
+		t.Fatalf("session database still in the sessions directory: %v", err)
+	}
+
+	if _, err := q.Restore(entry.ID); err != nil {
+		t.Fatalf("Restore() error = %v", err)
+	}
+	assertSessionFiles(t, files...)
+	if _, err := q.Get(entry.ID); err != ErrQuarantineNotFound {
+		t.Fatalf("Get() after restore error = %v, want ErrQuarantineNotFound", err)
+	}
+}
+
+func TestQuarantineRollsBackFailedMove(t *testing.T) {
+	files := []string{testQuarantinePhone + ".db", testQuarantinePhone + "-session-2.db"}
+	q := newTestQuarantine(t, files...)
+
+	// A non-empty directory where the second file should go makes its move fail.
+	// Cover the next seconds too since the entry ID carries the time.
+	now := time.Now().Unix()
+	for i := int64(0); i < 3; i++ {
+		blocker := filepath.Join(q.dir, fmt.Sprintf("%s-%d", testQuarantinePhone, now+i), files[1], "blocker")
+		if err := os.MkdirAll(blocker, 0755); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if _, err := q.Quarantine(testQuarantinePhone, "revival period expired", time.Time{}); err == nil {
+		t.Fatal("Quarantine() succeeded, want a move error")
+	}
+	assertSessionFiles(t, files...)
+
+	entries, err := q.List(testQuarantinePhone)
+	if err != nil {
+		t.Fatalf("List() error = %v", err)
+	}
+	if len(entries) != 0 {
+		t.Fatalf("List() = %v after a failed quarantine, want none", entries)
+	}
+}
+
+func TestRestoreRollsBackFailedMove(t *testing.T) {
+	files := []string{testQuarantinePhone + ".db", testQuarantinePhone + "-session-2.db"}
+	q := newTestQuarantine(t, files...)
+
+	entry, err := q.Quarantine(testQuarantinePhone, "revival period expired", time.Time{})
+	if err != nil {
+		t.Fatalf("Quarantine() error = %v", err)
+	}
+	// The second file can't be moved back once it is gone
+	if err := os.Remove(filepath.Join(q.dir, entry.ID, files[1])); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := q.Restore(entry.ID); err == nil {
+		t.Fatal("Restore() succeeded, want a move error")
+	}
+	if _, err := os.Stat(filepath.Join(getSessionsDir(), files[0])); !os.IsNotExist(err) {
+		t.Fatalf("%s left in the sessions directory after a failed restore: %v", files[0], err)
+	}
+	if _, err := os.Stat(filepath.Join(q.dir, entry.ID, files[0])); err != nil {
+		t.Fatalf("%s not back in quarantine: %v", files[0], err)
+	}
+	if _, err := q.Get(entry.ID); err != nil {
+		t.Fatalf("Get() after a failed restore error = %v", err)
+	}
+}
@@ -0,0 +1,580 @@
+package whatsapp
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Quiet hours policy codes and modes
+const (
+	QuietHoursCode = "quiet_hours"
+
+	// QuietHoursDefer keeps jobs queued until the window opens
+	QuietHoursDefer = "defer"
+	// QuietHoursReject fails sends outside the window
+	QuietHoursReject = "reject"
+)
+
+// DefaultQuietHoursWindow is the local time sends are allowed in when nothing
+// more specific is configured
+const DefaultQuietHoursWindow = "09:00-21:00"
+
+// countryTimeZones maps calling codes to the time zone used for recipients of
+// that country. Countries spanning a few zones are in countryTimeZoneSpans
+// instead; Russia spans more zones than any window, so it uses Moscow. Override
+// them per country in the quiet hours config.
+var countryTimeZones = map[string]string{
+	"7":   "Europe/Moscow",
+	"20":  "Africa/Cairo",
+	"27":  "Africa/Johannesburg",
+	"30":  "Europe/Athens",
+	"31":  "Europe/Amsterdam",
+	"32":  "Europe/Brussels",
+	"33":  "Europe/Paris",
+	"34":  "Europe/Madrid",
+	"36":  "Europe/Budapest",
+	"39":  "Europe/Rome",
+	"40":  "Europe/Bucharest",
+	"41":  "Europe/Zurich",
+	"43":  "Europe/Vienna",
+	"44":  "Europe/London",
+	"45":  "Europe/Copenhagen",
+	"46":  "Europe/Stockholm",
+	"47":  "Europe/Oslo",
+	"48":  "Europe/Warsaw",
+	"49":  "Europe/Berlin",
+	"51":  "America/Lima",
+	"53":  "America/Havana",
+	"54":  "America/Argentina/Buenos_Aires",
+	"56":  "America/Santiago",
+	"57":  "America/Bogota",
+	"58":  "America/Caracas",
+	"60":  "Asia/Kuala_Lumpur",
+	"63":  "Asia/Manila",
+	"64":  "Pacific/Auckland",
+	"65":  "Asia/Singapore",
+	"66":  "Asia/Bangkok",
+	"81":  "Asia/Tokyo",
+	"82":  "Asia/Seoul",
+	"84":  "Asia/Ho_Chi_Minh",
+	"86":  "Asia/Shanghai",
+	"90":  "Europe/Istanbul",
+	"91":  "Asia/Kolkata",
+	"92":  "Asia/Karachi",
+	"93":  "Asia/Kabul",
+	"94":  "Asia/Colombo",
+	"95":  "Asia/Yangon",
+	"98":  "Asia/Tehran",
+	"211": "Africa/Juba",
+	"212": "Africa/Casablanca",
+	"213": "Africa/Algiers",
+	"216": "Africa/Tunis",
+	"218": "Africa/Tripoli",
+	"220": "Africa/Banjul",
+	"221": "Africa/Dakar",
+	"225": "Africa/Abidjan",
+	"233": "Africa/Accra",
+	"234": "Africa/Lagos",
+	"237": "Africa/Douala",
+	"244": "Africa/Luanda",
+	"249": "Africa/Khartoum",
+	"251": "Africa/Addis_Ababa",
+	"254": "Africa/Nairobi",
+	"255": "Africa/Dar_es_Salaam",
+	"256": "Africa/Kampala",
+	"260": "Africa/Lusaka",
+	"263": "Africa/Harare",
+	"351": "Europe/Lisbon",
+	"352": "Europe/Luxembourg",
+	"353": "Europe/Dublin",
+	"354": "Atlantic/Reykjavik",
+	"355": "Europe/Tirane",
+	"357": "Asia/Nicosia",
+	"358": "Europe/Helsinki",
+	"359": "Europe/Sofia",
+	"370": "Europe/Vilnius",
+	"371": "Europe/Riga",
+	"372": "Europe/Tallinn",
+	"373": "Europe/Chisinau",
+	"374": "Asia/Yerevan",
+	"375": "Europe/Minsk",
+	"380": "Europe/Kyiv",
+	"381": "Europe/Belgrade",
+	"385": "Europe/Zagreb",
+	"386": "Europe/Ljubljana",
+	"420": "Europe/Prague",
+	"421": "Europe/Bratislava",
+	"502": "America/Guatemala",
+	"503": "America/El_Salvador",
+	"504": "America/Tegucigalpa",
+	"505": "America/Managua",
+	"506": "America/Costa_Rica",
+	"507": "America/Panama",
+	"591": "America/La_Paz",
+	"593": "America/Guayaquil",
+	"595": "America/Asuncion",
+	"598": "America/Montevideo",
+	"852": "Asia/Hong_Kong",
+	"855": "Asia/Phnom_Penh",
+	"880": "Asia/Dhaka",
+	"886": "Asia/Taipei",
+	"960": "Indian/Maldives",
+	"961": "Asia/Beirut",
+	"962": "Asia/Amman",
+	"963": "Asia/Damascus",
+	"964": "Asia/Baghdad",
+	"965": "Asia/Kuwait",
+	"966": "Asia/Riyadh",
+	"967": "Asia/Aden",
+	"968": "Asia/Muscat",
+	"970": "Asia/Gaza",
+	"971": "Asia/Dubai",
+	"972": "Asia/Jerusalem",
+	"973": "Asia/Bahrain",
+	"974": "Asia/Qatar",
+	"976": "Asia/Ulaanbaatar",
+	"977": "Asia/Kathmandu",
+	"992": "Asia/Dushanbe",
+	"993": "Asia/Ashgabat",
+	"994": "Asia/Baku",
+	"995": "Asia/Tbilisi",
+	"996": "Asia/Bishkek",
+	"998": "Asia/Tashkent",
+}
+
+// prefixTimeZones maps number prefixes longer than the calling code to their
+// own zone: NANP area codes outside the contiguous US and Canada, and
+// Kazakhstan, which shares code 7 with Russia
+var prefixTimeZones = map[string]string{
+	"1242": "America/Nassau",
+	"1246": "America/Barbados",
+	"1441": "Atlantic/Bermuda",
+	"1506": "America/Moncton",
+	"1709": "America/St_Johns",
+	"1782": "America/Halifax",
+	"1787": "America/Puerto_Rico",
+	"1808": "Pacific/Honolulu",
+	"1809": "America/Santo_Domingo",
+	"1829": "America/Santo_Domingo",
+	"1849": "America/Santo_Domingo",
+	"1868": "America/Port_of_Spain",
+	"1876": "America/Jamaica",
+	"1902": "America/Halifax",
+	"1907": "America/Anchorage",
+	"1939": "America/Puerto_Rico",
+	"76":   "Asia/Almaty",
+	"77":   "Asia/Almaty",
+}
+
+// timeZoneSpan is the westernmost and easternmost zone of a country. The
+// window opens when it opened in the west and closes when it closed in the
+// east, so no recipient is messaged outside it wherever they live.
+type timeZoneSpan struct {
+	West string
+	East string
+}
+
+// countryTimeZoneSpans maps calling codes of countries spanning several zones
+// to their span
+var countryTimeZoneSpans = map[string]timeZoneSpan{
+	"1":  {West: "America/Los_Angeles", East: "America/New_York"},
+	"52": {West: "America/Tijuana", East: "America/Cancun"},
+	"55": {West: "America/Rio_Branco", East: "America/Sao_Paulo"},
+	"61": {West: "Australia/Perth", East: "Australia/Sydney"},
+	"62": {West: "Asia/Jakarta", East: "Asia/Jayapura"},
+}
+
+// QuietHoursWindow is the local time of day sends are allowed in, "HH:MM" to
+// "HH:MM". A window whose end is before its start spans midnight.
+type QuietHoursWindow struct {
+	Start string `json:"start"`
+	End   string `json:"end"`
+	// TimeZone overrides the zone derived from the country code. On the
+	// default window it is only used for unknown country codes.
+	TimeZone string `json:"timezone,omitempty"`
+}
+
+// QuietHoursCampaign replaces the window and mode for the sends of one campaign
+type QuietHoursCampaign struct {
+	QuietHoursWindow
+	Mode string `json:"mode,omitempty"` // defer or reject, the global mode when empty
+}
+
+// QuietHoursConfig is the whole quiet hours policy
+type QuietHoursConfig struct {
+	Enabled   bool                          `json:"enabled"`
+	Mode      string                        `json:"mode"`
+	Default   QuietHoursWindow              `json:"default"`
+	Countries map[string]QuietHoursWindow   `json:"countries,omitempty"` // calling code -> window
+	Campaigns map[string]QuietHoursCampaign `json:"campaigns,omitempty"` // campaign -> window
+}
+
+// QuietHoursDecision is the outcome of checking one recipient
+type QuietHoursDecision struct {
+	Allowed   bool       `json:"allowed"`
+	TimeZone  string     `json:"timezone"`
+	LocalTime string     `json:"local_time"`
+	Window    string     `json:"window"`
+	Mode      string     `json:"mode"`
+	NextOpen  *time.Time `json:"next_open,omitempty"`
+}
+
+// QuietHoursStore keeps the quiet hours policy in a JSON file next to the
+// sessions. Without a file it is seeded from QUIET_HOURS_ENABLED,
+// QUIET_HOURS_MODE and QUIET_HOURS_WINDOW.
+type QuietHoursStore struct {
+	path   string
+	config QuietHoursConfig
+	mu     sync.RWMutex
+}
+
+// NewQuietHoursStore loads the policy from <sessions dir>/quiet_hours.json
+func NewQuietHoursStore() *QuietHoursStore {
+	s := &QuietHoursStore{
+		path: filepath.Join(getSessionsDir(), "quiet_hours.json"),
+	}
+
+	config := QuietHoursConfig{
+		Enabled: os.Getenv("QUIET_HOURS_ENABLED") == "true",
+		Mode:    strings.ToLower(strings.TrimSpace(os.Getenv("QUIET_HOURS_MODE"))),
+	}
+	window := strings.TrimSpace(os.Getenv("QUIET_HOURS_WINDOW"))
+	if window == "" {
+		window = DefaultQuietHoursWindow
+	}
+	if start, end, found := strings.Cut(window, "-"); found {
+		config.Default = QuietHoursWindow{Start: strings.TrimSpace(start), End: strings.TrimSpace(end)}
+	}
+
+	if data, err := os.ReadFile(s.path); err == nil {
+		if err := json.Unmarshal(data, &config); err != nil {
+			log.Printf("[QUIET] ⚠️ Failed to parse %s: %v", s.path, err)
+		}
+	} else if !os.IsNotExist(err) {
+		log.Printf("[QUIET] ⚠️ Failed to read %s: %v", s.path, err)
+	}
+
+	if err := validateQuietHours(&config); err != nil {
+		log.Printf("[QUIET] ⚠️ Invalid quiet hours config, using %s: %v", DefaultQuietHoursWindow, err)
+		config = QuietHoursConfig{
+			Enabled: config.Enabled,
+			Mode:    QuietHoursDefer,
+			Default: QuietHoursWindow{Start: "09:00", End: "21:00"},
+		}
+	}
+	s.config = config
+
+	log.Printf("[QUIET] 🌙 Quiet hours enabled: %v, default window %s-%s, mode %s",
+		config.Enabled, config.Default.Start, config.Default.End, config.Mode)
+	return s
+}
+
+// Config returns the current policy
+func (s *QuietHoursStore) Config() QuietHoursConfig {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.config
+}
+
+// SetConfig validates and replaces the policy
+func (s *QuietHoursStore) SetConfig(config QuietHoursConfig) (QuietHoursConfig, error) {
+	if err := validateQuietHours(&config); err != nil {
+		return QuietHoursConfig{}, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.config = config
+	if err := s.persistLocked(); err != nil {
+		return QuietHoursConfig{}, err
+	}
+	return config, nil
+}
+
+// Decide works out whether a recipient may be messaged at the given time.
+// Recipients whose country code is unknown are allowed unless the default
+// window names a time zone.
+func (s *QuietHoursStore) Decide(phone, campaign string, at time.Time) QuietHoursDecision {
+	s.mu.RLock()
+	config := s.config
+	s.mu.RUnlock()
+
+	decision := QuietHoursDecision{Allowed: true, Mode: config.Mode}
+	if !config.Enabled {
+		return decision
+	}
+
+	window := config.Default
+	code, span := phoneTimeZone(sanitizePhone(phone), config.Countries)
+	if countryWindow, ok := config.Countries[code]; ok {
+		window = countryWindow
+		if countryWindow.TimeZone != "" {
+			span = timeZoneSpan{West: countryWindow.TimeZone, East: countryWindow.TimeZone}
+		}
+	} else if span.West == "" {
+		// Unknown country codes fall back to the default zone, if any
+		span = timeZoneSpan{West: config.Default.TimeZone, East: config.Default.TimeZone}
+	}
+	if campaignWindow, ok := config.Campaigns[campaign]; ok && campaign != "" {
+		if campaignWindow.Start != "" {
+			window.Start, window.End = campaignWindow.Start, campaignWindow.End
+		}
+		if campaignWindow.Mode != "" {
+			decision.Mode = campaignWindow.Mode
+		}
+	}
+	if span.West == "" {
+		return decision
+	}
+
+	west, err := time.LoadLocation(span.West)
+	if err != nil {
+		log.Printf("[QUIET] ⚠️ Unknown time zone %q for +%s, not applying quiet hours", span.West, phone)
+		return decision
+	}
+	east, err := time.LoadLocation(span.East)
+	if err != nil {
+		log.Printf("[QUIET] ⚠️ Unknown time zone %q for +%s, not applying quiet hours", span.East, phone)
+		return decision
+	}
+	start, _ := parseClock(window.Start)
+	end, _ := parseClock(window.End)
+	decision.Window = window.Start + "-" + window.End
+
+	zones := []*time.Location{west, east}
+	if span.West == span.East {
+		zones = zones[:1]
+	}
+	next, found := nextQuietHoursOpen(at, start, end, zones)
+	if !found {
+		// The window is shorter than the country is wide, so no time suits
+		// everyone. Go by the east, where the day ends first.
+		zones = []*time.Location{east}
+		next, _ = nextQuietHoursOpen(at, start, end, zones)
+	}
+
+	// Report the zone that keeps the window closed, or the westernmost one
+	local := at.In(zones[0])
+	decision.Allowed = true
+	for _, loc := range zones {
+		if zoneLocal := at.In(loc); !clockInWindow(zoneLocal.Hour()*60+zoneLocal.Minute(), start, end) {
+			local = zoneLocal
+			decision.Allowed = false
+			break
+		}
+	}
+	decision.TimeZone = local.Location().String()
+	decision.LocalTime = local.Format("15:04")
+	if !decision.Allowed {
+		next = next.UTC()
+		decision.NextOpen = &next
+	}
+	return decision
+}
+
+// nextQuietHoursOpen returns the first time after at the window is open in
+// every zone. The window opens as it starts in one of the zones, so only
+// those starts over the next days are tried. It reports false when the
+// window is never open in all zones at once.
+func nextQuietHoursOpen(at time.Time, start, end int, zones []*time.Location) (time.Time, bool) {
+	candidates := make([]time.Time, 0, 3*len(zones))
+	for _, loc := range zones {
+		local := at.In(loc)
+		for day := 0; day <= 2; day++ {
+			candidate := time.Date(local.Year(), local.Month(), local.Day()+day, start/60, start%60, 0, 0, loc)
+			if candidate.After(at) {
+				candidates = append(candidates, candidate)
+			}
+		}
+	}
+	sort.Slice(candidates, func(i, j int) bool { return candidates[i].Before(candidates[j]) })
+
+	for _, candidate := range candidates {
+		open := true
+		for _, loc := range zones {
+			local := candidate.In(loc)
+			if !clockInWindow(local.Hour()*60+local.Minute(), start, end) {
+				open = false
+				break
+			}
+		}
+		if open {
+			return candidate, true
+		}
+	}
+	return time.Time{}, false
+}
+
+// Check returns a *PolicyError when the recipient's local time is outside the
+// window. In defer mode the error carries the time the window opens.
+func (s *QuietHoursStore) Check(phone, campaign string) error {
+	decision := s.Decide(phone, campaign, time.Now())
+	if decision.Allowed {
+		return nil
+	}
+
+	policyErr := &PolicyError{
+		Policy: "quiet_hours",
+		Code:   QuietHoursCode,
+		Phone:  sanitizePhone(phone),
+		Reason: fmt.Sprintf("it is %s in %s, sends are allowed %s", decision.LocalTime, decision.TimeZone, decision.Window),
+	}
+	if decision.Mode == QuietHoursDefer {
+		policyErr.RetryAt = *decision.NextOpen
+	}
+	return policyErr
+}
+
+// persistLocked writes the policy to disk. Caller must hold the write lock.
+func (s *QuietHoursStore) persistLocked() error {
+	jsonData, err := json.MarshalIndent(s.config, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal quiet hours: %w", err)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(s.path), 0755); err != nil {
+		return fmt.Errorf("failed to create quiet hours directory: %w", err)
+	}
+
+	tmpFile := s.path + ".tmp"
+	if err := os.WriteFile(tmpFile, jsonData, 0644); err != nil {
+		return fmt.Errorf("failed to write quiet hours file: %w", err)
+	}
+	if err := os.Rename(tmpFile, s.path); err != nil {
+		return fmt.Errorf("failed to replace quiet hours file: %w", err)
+	}
+
+	return nil
+}
+
+// validateQuietHours normalizes a config and rejects windows and zones that
+// cannot be evaluated
+func validateQuietHours(config *QuietHoursConfig) error {
+	config.Mode = strings.ToLower(strings.TrimSpace(config.Mode))
+	if config.Mode == "" {
+		config.Mode = QuietHoursDefer
+	}
+	if config.Mode != QuietHoursDefer && config.Mode != QuietHoursReject {
+		return fmt.Errorf("mode must be %q or %q", QuietHoursDefer, QuietHoursReject)
+	}
+
+	if err := validateQuietHoursWindow(config.Default); err != nil {
+		return fmt.Errorf("default: %w", err)
+	}
+
+	countries := make(map[string]QuietHoursWindow, len(config.Countries))
+	for code, window := range config.Countries {
+		code = sanitizePhone(code)
+		if code == "" {
+			return fmt.Errorf("countries: calling codes must be digits")
+		}
+		if err := validateQuietHoursWindow(window); err != nil {
+			return fmt.Errorf("countries[%s]: %w", code, err)
+		}
+		countries[code] = window
+	}
+	config.Countries = countries
+
+	for name, campaign := range config.Campaigns {
+		campaign.Mode = strings.ToLower(strings.TrimSpace(campaign.Mode))
+		if campaign.Mode != "" && campaign.Mode != QuietHoursDefer && campaign.Mode != QuietHoursReject {
+			return fmt.Errorf("campaigns[%s]: mode must be %q or %q", name, QuietHoursDefer, QuietHoursReject)
+		}
+		if campaign.TimeZone != "" {
+			return fmt.Errorf("campaigns[%s]: the time zone comes from the recipient, not the campaign", name)
+		}
+		if campaign.Start != "" || campaign.End != "" {
+			if err := validateQuietHoursWindow(campaign.QuietHoursWindow); err != nil {
+				return fmt.Errorf("campaigns[%s]: %w", name, err)
+			}
+		}
+		config.Campaigns[name] = campaign
+	}
+
+	return nil
+}
+
+func validateQuietHoursWindow(window QuietHoursWindow) error {
+	if _, err := parseClock(window.Start); err != nil {
+		return fmt.Errorf("start: %w", err)
+	}
+	if _, err := parseClock(window.End); err != nil {
+		return fmt.Errorf("end: %w", err)
+	}
+	if window.TimeZone != "" {
+		if _, err := time.LoadLocation(window.TimeZone); err != nil {
+			return fmt.Errorf("unknown time zone %q", window.TimeZone)
+		}
+	}
+	return nil
+}
+
+// parseClock returns minutes since midnight for "HH:MM", allowing "24:00"
+func parseClock(value string) (int, error) {
+	if value == "24:00" {
+		return 24 * 60, nil
+	}
+	t, err := time.Parse("15:04", value)
+	if err != nil {
+		return 0, fmt.Errorf("%q is not an HH:MM time", value)
+	}
+	return t.Hour()*60 + t.Minute(), nil
+}
+
+// clockInWindow reports whether a minute of the day falls in [start, end).
+// Equal bounds allow the whole day.
+func clockInWindow(minute, start, end int) bool {
+	switch {
+	case start == end:
+		return true
+	case start < end:
+		return minute >= start && minute < end
+	default:
+		return minute >= start || minute < end
+	}
+}
+
+// phoneTimeZone returns the calling code of a phone number and the zones of
+// its recipients: a prefix with its own zone, or the zone or span of the
+// country. Configured countries count as known codes even when only their
+// window names a zone. Both are empty when the code is unknown.
+func phoneTimeZone(phone string, countries map[string]QuietHoursWindow) (string, timeZoneSpan) {
+	var prefixZone string
+	for length := 4; length >= 2; length-- {
+		if len(phone) > length {
+			if zone, ok := prefixTimeZones[phone[:length]]; ok {
+				prefixZone = zone
+				break
+			}
+		}
+	}
+
+	for length := 3; length >= 1; length-- {
+		if len(phone) <= length {
+			continue
+		}
+		code := phone[:length]
+		span, spans := countryTimeZoneSpans[code]
+		zone, known := countryTimeZones[code]
+		if _, configured := countries[code]; !known && !spans && !configured {
+			continue
+		}
+		switch {
+		case prefixZone != "":
+			return code, timeZoneSpan{West: prefixZone, East: prefixZone}
+		case spans:
+			return code, span
+		default:
+			return code, timeZoneSpan{West: zone, East: zone}
+		}
+	}
+	return "", timeZoneSpan{}
+}
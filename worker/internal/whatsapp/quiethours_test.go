@@ -0,0 +1,185 @@
+package whatsapp
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestQuietHoursDecide(t *testing.T) {
+	utc := func(value string) time.Time {
+		at, err := time.Parse(time.RFC3339, value)
+		if err != nil {
+			t.Fatalf("bad test time %q: %v", value, err)
+		}
+		return at
+	}
+
+	tests := []struct {
+		name     string
+		window   QuietHoursWindow
+		phone    string
+		at       string
+		allowed  bool
+		zone     string
+		nextOpen string // empty when allowed
+	}{
+		{
+			name:    "inside the window",
+			phone:   "4915112345678",
+			at:      "2026-01-15T12:00:00Z",
+			allowed: true,
+			zone:    "Europe/Berlin",
+		},
+		{
+			name:     "before the window opens",
+			phone:    "4915112345678",
+			at:       "2026-01-15T06:00:00Z",
+			zone:     "Europe/Berlin",
+			nextOpen: "2026-01-15T08:00:00Z",
+		},
+		{
+			name:     "after the window closed opens the next day",
+			phone:    "4915112345678",
+			at:       "2026-01-15T21:30:00Z",
+			zone:     "Europe/Berlin",
+			nextOpen: "2026-01-16T08:00:00Z",
+		},
+		{
+			name:    "window spanning midnight, after midnight",
+			window:  QuietHoursWindow{Start: "22:00", End: "06:00"},
+			phone:   "4915112345678",
+			at:      "2026-01-15T00:30:00Z",
+			allowed: true,
+			zone:    "Europe/Berlin",
+		},
+		{
+			name:     "window spanning midnight, during the day",
+			window:   QuietHoursWindow{Start: "22:00", End: "06:00"},
+			phone:    "4915112345678",
+			at:       "2026-01-15T12:00:00Z",
+			zone:     "Europe/Berlin",
+			nextOpen: "2026-01-15T21:00:00Z",
+		},
+		{
+			name:     "opens after the clocks went forward",
+			phone:    "4915112345678",
+			at:       "2026-03-28T21:00:00Z",
+			zone:     "Europe/Berlin",
+			nextOpen: "2026-03-29T07:00:00Z",
+		},
+		{
+			name:     "opens after the clocks went back",
+			phone:    "4915112345678",
+			at:       "2026-10-24T20:00:00Z",
+			zone:     "Europe/Berlin",
+			nextOpen: "2026-10-25T08:00:00Z",
+		},
+		{
+			name:     "span waits for the west to open",
+			phone:    "12125550123",
+			at:       "2026-01-15T14:00:00Z",
+			zone:     "America/Los_Angeles",
+			nextOpen: "2026-01-15T17:00:00Z",
+		},
+		{
+			name:    "span open in both zones",
+			phone:   "12125550123",
+			at:      "2026-01-15T18:00:00Z",
+			allowed: true,
+			zone:    "America/Los_Angeles",
+		},
+		{
+			name:     "span closes with the east",
+			phone:    "13105550123",
+			at:       "2026-01-16T02:30:00Z",
+			zone:     "America/New_York",
+			nextOpen: "2026-01-16T17:00:00Z",
+		},
+		{
+			name:     "window narrower than the span goes by the east",
+			window:   QuietHoursWindow{Start: "09:00", End: "10:00"},
+			phone:    "12125550123",
+			at:       "2026-01-15T13:30:00Z",
+			zone:     "America/New_York",
+			nextOpen: "2026-01-15T14:00:00Z",
+		},
+		{
+			name:     "NANP area code with its own zone",
+			phone:    "18085550123",
+			at:       "2026-01-15T18:00:00Z",
+			zone:     "Pacific/Honolulu",
+			nextOpen: "2026-01-15T19:00:00Z",
+		},
+		{
+			name:    "Kazakhstan is not Moscow",
+			phone:   "77011234567",
+			at:      "2026-01-15T08:00:00Z",
+			allowed: true,
+			zone:    "Asia/Almaty",
+		},
+		{
+			name:    "unknown calling code is allowed",
+			phone:   "999123456",
+			at:      "2026-01-15T03:00:00Z",
+			allowed: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			window := tt.window
+			if window.Start == "" {
+				window = QuietHoursWindow{Start: "09:00", End: "21:00"}
+			}
+			store := &QuietHoursStore{config: QuietHoursConfig{
+				Enabled: true,
+				Mode:    QuietHoursDefer,
+				Default: window,
+			}}
+
+			decision := store.Decide(tt.phone, "", utc(tt.at))
+			if decision.Allowed != tt.allowed {
+				t.Fatalf("allowed = %v, want %v (%+v)", decision.Allowed, tt.allowed, decision)
+			}
+			if decision.TimeZone != tt.zone {
+				t.Errorf("timezone = %q, want %q", decision.TimeZone, tt.zone)
+			}
+			if tt.nextOpen == "" {
+				if decision.NextOpen != nil {
+					t.Errorf("next open = %s, want none", decision.NextOpen)
+				}
+				return
+			}
+			if decision.NextOpen == nil {
+				t.Fatalf("next open missing, want %s", tt.nextOpen)
+			}
+			if want := utc(tt.nextOpen); !decision.NextOpen.Equal(want) {
+				t.Errorf("next open = %s, want %s", decision.NextOpen.Format(time.RFC3339), tt.nextOpen)
+			}
+		})
+	}
+}
+
+func TestQuietHoursCountryOverride(t *testing.T) {
+	store := &QuietHoursStore{config: QuietHoursConfig{
+		Enabled: true,
+		Mode:    QuietHoursReject,
+		Default: QuietHoursWindow{Start: "09:00", End: "21:00"},
+		Countries: map[string]QuietHoursWindow{
+			"1": {Start: "10:00", End: "18:00", TimeZone: "America/Chicago"},
+		},
+	}}
+
+	// A configured zone replaces the span
+	decision := store.Decide("12125550123", "", time.Date(2026, 1, 15, 15, 30, 0, 0, time.UTC))
+	if decision.Allowed || decision.TimeZone != "America/Chicago" || decision.Window != "10:00-18:00" {
+		t.Fatalf("unexpected decision %+v", decision)
+	}
+	if err := store.Check("12125550123", ""); err != nil {
+		var policyErr *PolicyError
+		if !errors.As(err, &policyErr) || !policyErr.RetryAt.IsZero() {
+			t.Fatalf("reject mode error = %v, want a policy error without retry time", err)
+		}
+	}
+}
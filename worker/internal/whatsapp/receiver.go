@@ -1,178 +1,319 @@
 package whatsapp
 
 import (
+	"context"
+	"encoding/hex"
+	"fmt"
 	"log"
-	"sync"
+	"mime"
+	"os"
+	"path/filepath"
 	"time"
 
+	"go.mau.fi/whatsmeow"
+	"go.mau.fi/whatsmeow/proto/waE2E"
+	"go.mau.fi/whatsmeow/types"
 	"go.mau.fi/whatsmeow/types/events"
+	"google.golang.org/protobuf/proto"
 )
 
-// ReceivedMessage represents an incoming message
-type ReceivedMessage struct {
-	ID        string    `json:"id"`
-	From      string    `json:"from"`
-	To        string    `json:"to"`
-	Message   string    `json:"message"`
-	Timestamp time.Time `json:"timestamp"`
-	IsGroup   bool      `json:"is_group"`
-	GroupName string    `json:"group_name,omitempty"`
+// mediaMessage is the part of the WhatsApp media messages the inbox needs
+type mediaMessage interface {
+	whatsmeow.DownloadableMessage
+	GetMimetype() string
+	GetFileLength() uint64
 }
 
-// MessageReceiver handles incoming messages
-type MessageReceiver struct {
-	messages     []ReceivedMessage
-	messagesByTo map[string][]ReceivedMessage // Messages indexed by recipient phone
-	mu           sync.RWMutex
-	maxMessages  int // Max messages to keep in memory
-}
+// handleIncomingMessage processes an incoming message. Every account gets it
+// through handleEvent.
+func (m *ClientManager) handleIncomingMessage(toPhone string, evt *events.Message) {
+	if evt == nil || evt.Message == nil || evt.Info.IsFromMe || evt.Info.Chat == types.StatusBroadcastJID {
+		return
+	}
 
-// Global message receiver
-var globalReceiver = &MessageReceiver{
-	messages:     make([]ReceivedMessage, 0),
-	messagesByTo: make(map[string][]ReceivedMessage),
-	maxMessages:  1000,
-}
+	msg, media := parseInboundMessage(evt)
+	if msg.Type == "" {
+		return // protocol messages, edits, polls and the like
+	}
+	msg.ID = evt.Info.ID
+	msg.To = toPhone
+	msg.FromName = evt.Info.PushName
+	msg.Timestamp = evt.Info.Timestamp
+	msg.IsGroup = evt.Info.IsGroup
+	if evt.Info.IsGroup {
+		msg.GroupID = evt.Info.Chat.User
+	}
+
+	// A direct reply made of an opt-out keyword suppresses the sender
+	if msg.Type == InboundTypeText && !evt.Info.IsGroup {
+		if keyword, ok := m.suppressions.MatchOptOut(msg.Message); ok {
+			go m.handleOptOut(toPhone, evt, keyword)
+		}
+	}
 
-// GetMessageReceiver returns the global message receiver
-func GetMessageReceiver() *MessageReceiver {
-	return globalReceiver
+	// Update account health - message received means connection is good
+	if health := m.GetAccountHealth(toPhone); health != nil {
+		health.LastMessageReceived = time.Now()
+		health.Status = StatusHealthy
+	}
+
+	// Lookups and downloads must not hold up the event loop
+	go m.storeInboundMessage(toPhone, evt, msg, media)
 }
 
-// AddMessage adds a received message
-func (r *MessageReceiver) AddMessage(msg ReceivedMessage) {
-	r.mu.Lock()
-	defer r.mu.Unlock()
+// storeInboundMessage resolves the sender and group, downloads media and
+// hands the message to the inbox for storage and forwarding
+func (m *ClientManager) storeInboundMessage(toPhone string, evt *events.Message, msg ReceivedMessage, media mediaMessage) {
+	m.mu.RLock()
+	acc, exists := m.accounts[toPhone]
+	m.mu.RUnlock()
+	if !exists || acc.Client == nil {
+		return
+	}
 
-	// Add to main list
-	r.messages = append([]ReceivedMessage{msg}, r.messages...)
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Minute)
+	defer cancel()
 
-	// Trim if too many
-	if len(r.messages) > r.maxMessages {
-		r.messages = r.messages[:r.maxMessages]
+	sender := evt.Info.Sender.ToNonAD()
+	if pn, ok := m.senderPhone(ctx, acc, evt); ok {
+		sender = pn
 	}
+	msg.From = "+" + sender.User
 
-	// Add to indexed map
-	if _, exists := r.messagesByTo[msg.To]; !exists {
-		r.messagesByTo[msg.To] = make([]ReceivedMessage, 0)
+	if evt.Info.IsGroup {
+		msg.GroupName = m.groupName(ctx, acc, evt.Info.Chat)
 	}
-	r.messagesByTo[msg.To] = append([]ReceivedMessage{msg}, r.messagesByTo[msg.To]...)
 
-	// Trim per-account list
-	if len(r.messagesByTo[msg.To]) > 100 {
-		r.messagesByTo[msg.To] = r.messagesByTo[msg.To][:100]
+	if media != nil {
+		msg.Media = m.downloadInboundMedia(ctx, acc, toPhone, evt.Info.ID, media, msg.Media)
+	}
+
+	if !m.inbox.Add(msg) {
+		return
 	}
+	log.Printf("[Receiver] 📥 %s received %s from %s: %s", toPhone, msg.Type, msg.From, truncateMessage(msg.Message, 50))
 }
 
-// GetRecentMessages returns recent messages
-func (r *MessageReceiver) GetRecentMessages(limit int) []ReceivedMessage {
-	r.mu.RLock()
-	defer r.mu.RUnlock()
+// parseInboundMessage extracts the content of a message, and the media to
+// download if it has any. Type stays empty for content the inbox ignores.
+func parseInboundMessage(evt *events.Message) (ReceivedMessage, mediaMessage) {
+	var msg ReceivedMessage
+	var media mediaMessage
+	var contextInfo *waE2E.ContextInfo
+
+	m := evt.Message
+	switch {
+	case m.Conversation != nil:
+		msg.Type = InboundTypeText
+		msg.Message = m.GetConversation()
+	case m.ExtendedTextMessage != nil:
+		msg.Type = InboundTypeText
+		msg.Message = m.ExtendedTextMessage.GetText()
+		contextInfo = m.ExtendedTextMessage.GetContextInfo()
+	case m.ImageMessage != nil:
+		msg.Type = InboundTypeImage
+		msg.Message = m.ImageMessage.GetCaption()
+		contextInfo = m.ImageMessage.GetContextInfo()
+		media = m.ImageMessage
+	case m.VideoMessage != nil:
+		msg.Type = InboundTypeVideo
+		msg.Message = m.VideoMessage.GetCaption()
+		contextInfo = m.VideoMessage.GetContextInfo()
+		media = m.VideoMessage
+	case m.AudioMessage != nil:
+		msg.Type = InboundTypeAudio
+		contextInfo = m.AudioMessage.GetContextInfo()
+		media = m.AudioMessage
+	case m.DocumentMessage != nil:
+		msg.Type = InboundTypeDocument
+		msg.Message = m.DocumentMessage.GetCaption()
+		contextInfo = m.DocumentMessage.GetContextInfo()
+		media = m.DocumentMessage
+	case m.StickerMessage != nil:
+		msg.Type = InboundTypeSticker
+		contextInfo = m.StickerMessage.GetContextInfo()
+		media = m.StickerMessage
+	case m.ReactionMessage != nil:
+		msg.Type = InboundTypeReaction
+		msg.Message = m.ReactionMessage.GetText()
+		msg.Reaction = &InboundReaction{
+			TargetID: m.ReactionMessage.GetKey().GetID(),
+			Emoji:    m.ReactionMessage.GetText(),
+		}
+	case m.LocationMessage != nil:
+		msg.Type = InboundTypeLocation
+		msg.Location = &InboundLocation{
+			Latitude:  m.LocationMessage.GetDegreesLatitude(),
+			Longitude: m.LocationMessage.GetDegreesLongitude(),
+			Name:      m.LocationMessage.GetName(),
+			Address:   m.LocationMessage.GetAddress(),
+		}
+		contextInfo = m.LocationMessage.GetContextInfo()
+	case m.ContactMessage != nil:
+		msg.Type = InboundTypeContact
+		msg.Message = m.ContactMessage.GetVcard()
+		contextInfo = m.ContactMessage.GetContextInfo()
+	}
+
+	if media != nil {
+		msg.Media = &InboundMedia{
+			MimeType: media.GetMimetype(),
+			Size:     media.GetFileLength(),
+			SHA256:   hex.EncodeToString(media.GetFileSHA256()),
+		}
+		if m.DocumentMessage != nil {
+			msg.Media.FileName = m.DocumentMessage.GetFileName()
+		}
+	}
 
-	if limit <= 0 || limit > len(r.messages) {
-		limit = len(r.messages)
+	if contextInfo.GetStanzaID() != "" {
+		msg.ReplyTo = &ReplyContext{
+			ID:          contextInfo.GetStanzaID(),
+			Participant: contextInfo.GetParticipant(),
+			Text:        quotedText(contextInfo.GetQuotedMessage()),
+		}
 	}
 
-	result := make([]ReceivedMessage, limit)
-	copy(result, r.messages[:limit])
-	return result
+	return msg, media
 }
 
-// GetMessagesForAccount returns messages received by a specific account
-func (r *MessageReceiver) GetMessagesForAccount(phone string) []ReceivedMessage {
-	r.mu.RLock()
-	defer r.mu.RUnlock()
-
-	if msgs, exists := r.messagesByTo[phone]; exists {
-		result := make([]ReceivedMessage, len(msgs))
-		copy(result, msgs)
-		return result
+// quotedText returns the text or caption of a quoted message
+func quotedText(quoted *waE2E.Message) string {
+	switch {
+	case quoted == nil:
+		return ""
+	case quoted.Conversation != nil:
+		return quoted.GetConversation()
+	case quoted.ExtendedTextMessage != nil:
+		return quoted.ExtendedTextMessage.GetText()
+	case quoted.ImageMessage != nil:
+		return quoted.ImageMessage.GetCaption()
+	case quoted.VideoMessage != nil:
+		return quoted.VideoMessage.GetCaption()
+	case quoted.DocumentMessage != nil:
+		return quoted.DocumentMessage.GetCaption()
 	}
-	return []ReceivedMessage{}
+	return ""
 }
 
-// GetMessageCount returns total message count
-func (r *MessageReceiver) GetMessageCount() int {
-	r.mu.RLock()
-	defer r.mu.RUnlock()
-	return len(r.messages)
+// downloadInboundMedia saves the media of a message under the inbox media
+// directory. Failures are recorded on the media rather than dropping the
+// message.
+func (m *ClientManager) downloadInboundMedia(ctx context.Context, acc *AccountClient, toPhone, id string, media mediaMessage, info *InboundMedia) *InboundMedia {
+	maxBytes := m.inbox.MediaMaxBytes()
+	if maxBytes == 0 {
+		return info
+	}
+	if info.Size > uint64(maxBytes) {
+		info.Error = fmt.Sprintf("media is %d bytes, larger than the %d byte limit", info.Size, maxBytes)
+		return info
+	}
+
+	data, err := acc.Client.Download(ctx, media)
+	if err != nil {
+		log.Printf("[Receiver] ⚠️ %s failed to download media of %s: %v", toPhone, id, err)
+		info.Error = err.Error()
+		return info
+	}
+
+	ext := ".bin"
+	if exts, err := mime.ExtensionsByType(info.MimeType); err == nil && len(exts) > 0 {
+		ext = exts[0]
+	}
+	dir := m.inbox.MediaDir(toPhone)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		info.Error = err.Error()
+		return info
+	}
+	name := sanitizeFileName(id) + ext
+	if err := os.WriteFile(filepath.Join(dir, name), data, 0644); err != nil {
+		log.Printf("[Receiver] ⚠️ %s failed to save media of %s: %v", toPhone, id, err)
+		info.Error = err.Error()
+		return info
+	}
+
+	info.File = filepath.Join(filepath.Base(dir), name)
+	info.Size = uint64(len(data))
+	return info
 }
 
-// GetLastMessageTime returns the time of the last received message
-func (r *MessageReceiver) GetLastMessageTime() *time.Time {
-	r.mu.RLock()
-	defer r.mu.RUnlock()
+// senderPhone returns the phone number JID of a message's sender. Senders may
+// be addressed by LID; those are resolved through the alternative address or
+// the LID store, and ok is false when neither knows the number.
+func (m *ClientManager) senderPhone(ctx context.Context, acc *AccountClient, evt *events.Message) (types.JID, bool) {
+	sender := evt.Info.Sender.ToNonAD()
+	if sender.Server != types.HiddenUserServer {
+		return sender, true
+	}
+	if evt.Info.SenderAlt.Server == types.DefaultUserServer {
+		return evt.Info.SenderAlt.ToNonAD(), true
+	}
+	if pn, err := acc.Client.Store.LIDs.GetPNForLID(ctx, sender); err == nil && !pn.IsEmpty() {
+		return pn, true
+	}
+	return sender, false
+}
 
-	if len(r.messages) > 0 {
-		return &r.messages[0].Timestamp
+// groupName returns the subject of a group, asking WhatsApp once per group
+func (m *ClientManager) groupName(ctx context.Context, acc *AccountClient, chat types.JID) string {
+	if name, ok := m.inbox.GroupName(chat.String()); ok {
+		return name
+	}
+	info, err := acc.Client.GetGroupInfo(ctx, chat)
+	if err != nil {
+		log.Printf("[Receiver] ⚠️ Failed to get info of group %s: %v", chat, err)
+		return ""
 	}
-	return nil
+	m.inbox.SetGroupName(chat.String(), info.Name)
+	return info.Name
 }
 
-// SetupMessageHandler sets up the message handler for an account
-func (m *ClientManager) SetupMessageHandler(phone string) {
+// handleOptOut adds the sender of an opt-out reply to the suppression list
+// and confirms it once, from the account that received it
+func (m *ClientManager) handleOptOut(toPhone string, evt *events.Message, keyword string) {
 	m.mu.RLock()
-	acc, exists := m.accounts[phone]
+	acc, exists := m.accounts[toPhone]
 	m.mu.RUnlock()
-
 	if !exists || acc.Client == nil {
 		return
 	}
 
-	// Add event handler for messages
-	acc.Client.AddEventHandler(func(evt interface{}) {
-		switch v := evt.(type) {
-		case *events.Message:
-			// Handle incoming message
-			m.handleIncomingMessage(phone, v)
-		}
-	})
-
-	log.Printf("[Receiver] 📥 Message handler setup for %s", phone)
-}
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
 
-// handleIncomingMessage processes an incoming message
-func (m *ClientManager) handleIncomingMessage(toPhone string, evt *events.Message) {
-	if evt == nil || evt.Message == nil {
+	// Senders may be addressed by LID, the list is keyed by phone number
+	sender, ok := m.senderPhone(ctx, acc, evt)
+	if !ok {
+		log.Printf("[Receiver] ⚠️ %s opt-out from %s ignored: no phone number for LID", toPhone, sender)
 		return
 	}
 
-	// Extract message text
-	var messageText string
-	if evt.Message.Conversation != nil {
-		messageText = *evt.Message.Conversation
-	} else if evt.Message.ExtendedTextMessage != nil && evt.Message.ExtendedTextMessage.Text != nil {
-		messageText = *evt.Message.ExtendedTextMessage.Text
-	}
-
-	if messageText == "" {
-		return // Skip non-text messages
+	entry, added, err := m.suppressions.Add(SuppressionEntry{
+		Phone:   sender.User,
+		Source:  SuppressionSourceKeyword,
+		Keyword: keyword,
+		Account: toPhone,
+	})
+	if err != nil {
+		log.Printf("[Receiver] ❌ %s failed to suppress %s: %v", toPhone, sender.User, err)
+		return
 	}
-
-	// Create received message
-	msg := ReceivedMessage{
-		ID:        evt.Info.ID,
-		From:      "+" + evt.Info.Sender.User,
-		To:        toPhone,
-		Message:   messageText,
-		Timestamp: evt.Info.Timestamp,
-		IsGroup:   evt.Info.IsGroup,
+	if !added {
+		return
 	}
+	log.Printf("[Receiver] 🚫 +%s opted out with %q, suppressed for all accounts", entry.Phone, keyword)
+	go m.reportOptOutToMaster(toPhone, entry)
 
-	if evt.Info.IsGroup {
-		msg.GroupName = evt.Info.Chat.User
+	confirmation := m.suppressions.Confirmation()
+	if confirmation == "" {
+		return
 	}
-
-	// Add to receiver
-	GetMessageReceiver().AddMessage(msg)
-
-	// Update account health - message received means connection is good
-	if health := m.GetAccountHealth(toPhone); health != nil {
-		health.LastMessageReceived = time.Now()
-		health.Status = StatusHealthy
+	// The confirmation goes out directly, SendMessage refuses suppressed recipients
+	if _, err := acc.Client.SendMessage(ctx, evt.Info.Chat, &waE2E.Message{Conversation: proto.String(confirmation)}); err != nil {
+		log.Printf("[Receiver] ⚠️ %s failed to confirm opt-out to +%s: %v", toPhone, entry.Phone, err)
+		return
 	}
-
-	log.Printf("[Receiver] 📥 %s received from %s: %s", toPhone, msg.From, truncateMessage(messageText, 50))
+	m.suppressions.MarkConfirmed(entry.Phone)
 }
 
 // truncateMessage truncates a message for logging
@@ -183,31 +324,12 @@ func truncateMessage(msg string, maxLen int) string {
 	return msg[:maxLen] + "..."
 }
 
-// SetupAllMessageHandlers sets up message handlers for all connected accounts
-func (m *ClientManager) SetupAllMessageHandlers() {
-	m.mu.RLock()
-	phones := make([]string, 0)
-	for phone, acc := range m.accounts {
-		if acc.Connected && acc.LoggedIn {
-			phones = append(phones, phone)
-		}
-	}
-	m.mu.RUnlock()
-
-	for _, phone := range phones {
-		m.SetupMessageHandler(phone)
-	}
-
-	log.Printf("[Receiver] 📥 Setup message handlers for %d accounts", len(phones))
-}
-
 // GetReceivedMessages returns recent received messages (for API)
 func (m *ClientManager) GetReceivedMessages(limit int) []ReceivedMessage {
-	return GetMessageReceiver().GetRecentMessages(limit)
+	return m.inbox.List("", time.Time{}, limit)
 }
 
 // GetReceivedMessagesForAccount returns messages for a specific account
 func (m *ClientManager) GetReceivedMessagesForAccount(phone string) []ReceivedMessage {
-	return GetMessageReceiver().GetMessagesForAccount(phone)
+	return m.inbox.List(phone, time.Time{}, 0)
 }
-
@@ -0,0 +1,88 @@
+package whatsapp
+
+import (
+	"context"
+	"errors"
+	"log"
+)
+
+// ErrShuttingDown is returned by SendMessage once the worker is stopping
+var ErrShuttingDown = errors.New("worker is shutting down")
+
+// beginSend registers an in-flight send, or refuses it once Shutdown started
+func (m *ClientManager) beginSend() error {
+	m.shutdownMu.Lock()
+	defer m.shutdownMu.Unlock()
+
+	if m.shuttingDown {
+		return ErrShuttingDown
+	}
+	m.inFlight.Add(1)
+	return nil
+}
+
+// StopAcceptingSends makes SendMessage refuse new sends with ErrShuttingDown.
+// Sends already in flight carry on.
+func (m *ClientManager) StopAcceptingSends() {
+	m.shutdownMu.Lock()
+	m.shuttingDown = true
+	m.shutdownMu.Unlock()
+}
+
+// Shutdown stops the worker's WhatsApp side in order: it refuses new sends,
+// waits for in-flight ones until ctx expires, stops the heartbeat, keepalive
+// and activity loops, then saves account meta and disconnects every client,
+// and finally writes the delivery, frequency cap and inbox changes not saved
+// yet.
+func (m *ClientManager) Shutdown(ctx context.Context) {
+	m.StopAcceptingSends()
+
+	drained := make(chan struct{})
+	go func() {
+		m.inFlight.Wait()
+		close(drained)
+	}()
+	select {
+	case <-drained:
+		log.Printf("[SHUTDOWN] All in-flight sends finished")
+	case <-ctx.Done():
+		log.Printf("[SHUTDOWN] ⚠️ Gave up waiting for in-flight sends: %v", ctx.Err())
+	}
+
+	m.StopHeartbeat()
+	m.StopKeepAlive()
+	m.StopAllActivitySimulators()
+
+	m.mu.Lock()
+	accounts := make(map[string]*AccountClient, len(m.accounts))
+	for phone, acc := range m.accounts {
+		accounts[phone] = acc
+	}
+	m.mu.Unlock()
+
+	for phone, acc := range accounts {
+		if !acc.CreatedAt.IsZero() {
+			if err := m.saveAccountMeta(phone, acc); err != nil {
+				log.Printf("[SHUTDOWN] ⚠️ Failed to save meta of %s: %v", phone, err)
+			}
+		}
+		if acc.Client != nil {
+			acc.Client.Disconnect()
+		}
+		if acc.Container != nil {
+			acc.Container.Close()
+		}
+	}
+	log.Printf("[SHUTDOWN] Disconnected %d accounts", len(accounts))
+
+	if err := m.delivery.Flush(); err != nil {
+		log.Printf("[SHUTDOWN] ⚠️ Failed to save tracked messages: %v", err)
+	}
+	if err := m.frequency.Flush(); err != nil {
+		log.Printf("[SHUTDOWN] ⚠️ Failed to save send times: %v", err)
+	}
+	// Disconnected clients receive nothing more, so this is the last change
+	if err := m.inbox.Flush(); err != nil {
+		log.Printf("[SHUTDOWN] ⚠️ Failed to save inbox: %v", err)
+	}
+}
@@ -0,0 +1,366 @@
+package whatsapp
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+	"unicode"
+)
+
+// Suppression sources
+const (
+	SuppressionSourceKeyword = "keyword" // the recipient replied with an opt-out keyword
+	SuppressionSourceAPI     = "api"     // added or removed through the REST API
+)
+
+// Suppression audit actions
+const (
+	SuppressionActionAdded   = "added"
+	SuppressionActionRemoved = "removed"
+)
+
+// MaxSuppressionAudit bounds the audit trail kept on disk
+const MaxSuppressionAudit = 10000
+
+// DefaultOptOutKeywords covers the languages of the supported proxy countries
+var DefaultOptOutKeywords = []string{
+	// English
+	"STOP", "STOPALL", "UNSUBSCRIBE", "CANCEL", "END", "QUIT", "OPTOUT", "OPT OUT", "REMOVE",
+	// Hebrew
+	"הסר", "הסרה", "עצור", "הפסק",
+	// German
+	"STOPP", "ABMELDEN", "ABBESTELLEN",
+	// French
+	"ARRET", "ARRÊT", "DESABONNER", "DÉSABONNER", "DESINSCRIRE", "DÉSINSCRIRE",
+	// Spanish
+	"ALTO", "BAJA", "PARAR",
+	// Arabic
+	"توقف", "إلغاء",
+}
+
+// DefaultOptOutConfirmation is sent once to a recipient that opted out
+const DefaultOptOutConfirmation = "You have been unsubscribed and will not receive further messages from us."
+
+// ErrRecipientSuppressed is matched by errors.Is for sends refused because
+// the recipient is on the suppression list
+var ErrRecipientSuppressed = errors.New("recipient is on the suppression list")
+
+// ErrSuppressionNotFound is returned when removing a phone that is not suppressed
+var ErrSuppressionNotFound = errors.New("phone is not on the suppression list")
+
+// SuppressedError is returned by SendMessage for a suppressed recipient
+type SuppressedError struct {
+	Phone  string
+	Source string
+	Since  time.Time
+}
+
+func (e *SuppressedError) Error() string {
+	return fmt.Sprintf("recipient +%s is on the suppression list since %s (%s)", e.Phone, e.Since.Format("2006-01-02"), e.Source)
+}
+
+func (e *SuppressedError) Is(target error) bool {
+	return target == ErrRecipientSuppressed || target == ErrPolicyRejected
+}
+
+// SuppressionEntry is a recipient that must not be messaged
+type SuppressionEntry struct {
+	Phone       string     `json:"phone"`
+	Source      string     `json:"source"`
+	Keyword     string     `json:"keyword,omitempty"`
+	Account     string     `json:"account,omitempty"` // account that received the opt-out
+	Reason      string     `json:"reason,omitempty"`
+	Actor       string     `json:"actor,omitempty"`
+	ConfirmedAt *time.Time `json:"confirmed_at,omitempty"`
+	CreatedAt   time.Time  `json:"created_at"`
+}
+
+// SuppressionAuditEntry records one change to the suppression list
+type SuppressionAuditEntry struct {
+	Action  string    `json:"action"`
+	Phone   string    `json:"phone"`
+	Source  string    `json:"source"`
+	Keyword string    `json:"keyword,omitempty"`
+	Account string    `json:"account,omitempty"`
+	Reason  string    `json:"reason,omitempty"`
+	Actor   string    `json:"actor,omitempty"`
+	At      time.Time `json:"at"`
+}
+
+// suppressionFile is the on-disk layout of the suppression list
+type suppressionFile struct {
+	Entries []*SuppressionEntry     `json:"entries"`
+	Audit   []SuppressionAuditEntry `json:"audit"`
+}
+
+// SuppressionStore keeps the recipients that opted out in a JSON file next to
+// the sessions. It is shared by every account of the worker.
+type SuppressionStore struct {
+	path         string
+	keywords     map[string]string // normalized keyword -> keyword as configured
+	confirmation string
+	entries      map[string]*SuppressionEntry
+	audit        []SuppressionAuditEntry
+	mu           sync.RWMutex
+}
+
+// NewSuppressionStore loads the list from <sessions dir>/suppression.json.
+// Keywords are read from OPT_OUT_KEYWORDS (comma separated) and the
+// confirmation from OPT_OUT_CONFIRMATION; an empty confirmation disables it.
+func NewSuppressionStore() *SuppressionStore {
+	s := &SuppressionStore{
+		path:         filepath.Join(getSessionsDir(), "suppression.json"),
+		keywords:     make(map[string]string),
+		confirmation: DefaultOptOutConfirmation,
+		entries:      make(map[string]*SuppressionEntry),
+	}
+
+	keywords := DefaultOptOutKeywords
+	if value := os.Getenv("OPT_OUT_KEYWORDS"); value != "" {
+		keywords = strings.Split(value, ",")
+	}
+	for _, keyword := range keywords {
+		if normalized := normalizeOptOutText(keyword); normalized != "" {
+			s.keywords[normalized] = strings.TrimSpace(keyword)
+		}
+	}
+	if value, ok := os.LookupEnv("OPT_OUT_CONFIRMATION"); ok {
+		s.confirmation = strings.TrimSpace(value)
+	}
+
+	data, err := os.ReadFile(s.path)
+	if err != nil {
+		if !os.IsNotExist(err) {
+			log.Printf("[SUPPRESSION] ⚠️ Failed to read %s: %v", s.path, err)
+		}
+		return s
+	}
+
+	var file suppressionFile
+	if err := json.Unmarshal(data, &file); err != nil {
+		log.Printf("[SUPPRESSION] ⚠️ Failed to parse %s: %v", s.path, err)
+		return s
+	}
+	for _, e := range file.Entries {
+		s.entries[e.Phone] = e
+	}
+	s.audit = file.Audit
+
+	log.Printf("[SUPPRESSION] 🚫 Loaded %d suppressed recipients, %d opt-out keywords", len(s.entries), len(s.keywords))
+	return s
+}
+
+// MatchOptOut returns the configured keyword when the whole message is an
+// opt-out, so "stop" matches but "don't stop" does not
+func (s *SuppressionStore) MatchOptOut(text string) (string, bool) {
+	keyword, ok := s.keywords[normalizeOptOutText(text)]
+	return keyword, ok
+}
+
+// Confirmation returns the message sent after an opt-out, empty when disabled
+func (s *SuppressionStore) Confirmation() string {
+	return s.confirmation
+}
+
+// Check returns a *SuppressedError when the phone is suppressed
+func (s *SuppressionStore) Check(phone string) error {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	entry, exists := s.entries[sanitizePhone(phone)]
+	if !exists {
+		return nil
+	}
+	return &SuppressedError{Phone: entry.Phone, Source: entry.Source, Since: entry.CreatedAt}
+}
+
+// Get returns the entry for a phone, nil when it is not suppressed
+func (s *SuppressionStore) Get(phone string) *SuppressionEntry {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	entry, exists := s.entries[sanitizePhone(phone)]
+	if !exists {
+		return nil
+	}
+	copied := *entry
+	return &copied
+}
+
+// List returns all suppressed recipients, newest first
+func (s *SuppressionStore) List() []SuppressionEntry {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	result := make([]SuppressionEntry, 0, len(s.entries))
+	for _, e := range s.entries {
+		result = append(result, *e)
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].CreatedAt.After(result[j].CreatedAt) })
+	return result
+}
+
+// Audit returns the trail newest first, for one phone when given
+func (s *SuppressionStore) Audit(phone string, limit int) []SuppressionAuditEntry {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	phone = sanitizePhone(phone)
+	result := make([]SuppressionAuditEntry, 0)
+	for i := len(s.audit) - 1; i >= 0; i-- {
+		if phone != "" && s.audit[i].Phone != phone {
+			continue
+		}
+		result = append(result, s.audit[i])
+		if limit > 0 && len(result) >= limit {
+			break
+		}
+	}
+	return result
+}
+
+// Add suppresses a recipient. Adding a phone already on the list keeps the
+// original entry and returns added false.
+func (s *SuppressionStore) Add(entry SuppressionEntry) (*SuppressionEntry, bool, error) {
+	entry.Phone = sanitizePhone(entry.Phone)
+	if entry.Phone == "" {
+		return nil, false, fmt.Errorf("phone required")
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if existing, exists := s.entries[entry.Phone]; exists {
+		copied := *existing
+		return &copied, false, nil
+	}
+
+	entry.CreatedAt = time.Now().UTC()
+	s.entries[entry.Phone] = &entry
+	s.appendAuditLocked(SuppressionAuditEntry{
+		Action:  SuppressionActionAdded,
+		Phone:   entry.Phone,
+		Source:  entry.Source,
+		Keyword: entry.Keyword,
+		Account: entry.Account,
+		Reason:  entry.Reason,
+		Actor:   entry.Actor,
+		At:      entry.CreatedAt,
+	})
+	if err := s.persistLocked(); err != nil {
+		// Keep refusing sends for this run even if the file could not be written
+		log.Printf("[SUPPRESSION] ⚠️ Failed to save +%s: %v", entry.Phone, err)
+	}
+
+	copied := entry
+	return &copied, true, nil
+}
+
+// Remove takes a recipient off the list so it can be messaged again
+func (s *SuppressionStore) Remove(phone, actor, reason string) error {
+	phone = sanitizePhone(phone)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, exists := s.entries[phone]; !exists {
+		return ErrSuppressionNotFound
+	}
+
+	delete(s.entries, phone)
+	s.appendAuditLocked(SuppressionAuditEntry{
+		Action: SuppressionActionRemoved,
+		Phone:  phone,
+		Source: SuppressionSourceAPI,
+		Reason: reason,
+		Actor:  actor,
+		At:     time.Now().UTC(),
+	})
+	return s.persistLocked()
+}
+
+// MarkConfirmed records that the opt-out confirmation was sent
+func (s *SuppressionStore) MarkConfirmed(phone string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	entry, exists := s.entries[sanitizePhone(phone)]
+	if !exists {
+		return
+	}
+	now := time.Now().UTC()
+	entry.ConfirmedAt = &now
+	if err := s.persistLocked(); err != nil {
+		log.Printf("[SUPPRESSION] ⚠️ Failed to save confirmation for +%s: %v", entry.Phone, err)
+	}
+}
+
+// appendAuditLocked adds to the trail, dropping the oldest records past the
+// limit. Caller must hold the write lock.
+func (s *SuppressionStore) appendAuditLocked(record SuppressionAuditEntry) {
+	s.audit = append(s.audit, record)
+	if len(s.audit) > MaxSuppressionAudit {
+		s.audit = s.audit[len(s.audit)-MaxSuppressionAudit:]
+	}
+}
+
+// persistLocked writes the list and the trail to disk. Caller must hold the write lock.
+func (s *SuppressionStore) persistLocked() error {
+	file := suppressionFile{
+		Entries: make([]*SuppressionEntry, 0, len(s.entries)),
+		Audit:   s.audit,
+	}
+	for _, e := range s.entries {
+		file.Entries = append(file.Entries, e)
+	}
+	sort.Slice(file.Entries, func(i, j int) bool { return file.Entries[i].CreatedAt.Before(file.Entries[j].CreatedAt) })
+
+	jsonData, err := json.MarshalIndent(file, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal suppression list: %w", err)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(s.path), 0755); err != nil {
+		return fmt.Errorf("failed to create suppression directory: %w", err)
+	}
+
+	tmpFile := s.path + ".tmp"
+	if err := os.WriteFile(tmpFile, jsonData, 0644); err != nil {
+		return fmt.Errorf("failed to write suppression file: %w", err)
+	}
+	if err := os.Rename(tmpFile, s.path); err != nil {
+		return fmt.Errorf("failed to replace suppression file: %w", err)
+	}
+
+	return nil
+}
+
+// normalizeOptOutText upper-cases a reply and drops punctuation, symbols
+// and extra spaces, so "Stop!" and " stop. " both match STOP. Combining
+// marks are dropped too, but precomposed letters such as Ê are kept as they
+// are, which is why keywords are listed with and without accents (ARRET and
+// ARRÊT).
+func normalizeOptOutText(text string) string {
+	var b strings.Builder
+	space := false
+	for _, r := range strings.TrimSpace(text) {
+		switch {
+		case unicode.IsLetter(r) || unicode.IsDigit(r):
+			if space && b.Len() > 0 {
+				b.WriteByte(' ')
+			}
+			space = false
+			b.WriteRune(unicode.ToUpper(r))
+		case unicode.IsSpace(r):
+			space = true
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,382 @@
+package whatsapp
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/url"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Template variable types
+const (
+	TemplateVarText   = "text"
+	TemplateVarNumber = "number"
+	TemplateVarDate   = "date"
+	TemplateVarURL    = "url"
+
+	// TemplateDateLayout is the format accepted for date variables
+	TemplateDateLayout = "2006-01-02"
+)
+
+// ErrTemplateNotFound is returned when a template ID is unknown
+var ErrTemplateNotFound = errors.New("template not found")
+
+var (
+	templatePlaceholderRegex = regexp.MustCompile(`\{\{\s*([A-Za-z_][A-Za-z0-9_]*)\s*\}\}`)
+	templateIDRegex          = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9_.-]{0,63}$`)
+	templateVariableRegex    = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+)
+
+// TemplateError describes an invalid template or invalid variables for a send.
+// It is a client error and should be reported as 400.
+type TemplateError struct {
+	Message string
+}
+
+func (e *TemplateError) Error() string {
+	return e.Message
+}
+
+// TemplateVariable describes a placeholder a template accepts
+type TemplateVariable struct {
+	Name     string `json:"name"`
+	Type     string `json:"type"`
+	Required bool   `json:"required"`
+	Default  string `json:"default,omitempty"`
+}
+
+// TemplateVariant is the template body for one language
+type TemplateVariant struct {
+	Language string `json:"language"`
+	Body     string `json:"body"`
+}
+
+// MessageTemplate is a named message template. Bodies reference variables as
+// {{name}} so they don't clash with spin tags like {a|b} or the {name} shortcut.
+type MessageTemplate struct {
+	ID              string             `json:"id"`
+	Description     string             `json:"description,omitempty"`
+	DefaultLanguage string             `json:"default_language"`
+	Variables       []TemplateVariable `json:"variables"`
+	Variants        []TemplateVariant  `json:"variants"`
+	CreatedAt       string             `json:"created_at"`
+	UpdatedAt       string             `json:"updated_at"`
+}
+
+// TemplateStore keeps message templates in a JSON file next to the sessions
+type TemplateStore struct {
+	path      string
+	templates map[string]*MessageTemplate
+	mu        sync.RWMutex
+}
+
+// NewTemplateStore loads templates from <sessions dir>/templates.json
+func NewTemplateStore() *TemplateStore {
+	s := &TemplateStore{
+		path:      filepath.Join(getSessionsDir(), "templates.json"),
+		templates: make(map[string]*MessageTemplate),
+	}
+
+	data, err := os.ReadFile(s.path)
+	if err != nil {
+		if !os.IsNotExist(err) {
+			log.Printf("[TEMPLATES] ⚠️ Failed to read %s: %v", s.path, err)
+		}
+		return s
+	}
+
+	var templates []*MessageTemplate
+	if err := json.Unmarshal(data, &templates); err != nil {
+		log.Printf("[TEMPLATES] ⚠️ Failed to parse %s: %v", s.path, err)
+		return s
+	}
+	for _, t := range templates {
+		s.templates[t.ID] = t
+	}
+
+	log.Printf("[TEMPLATES] 📝 Loaded %d templates", len(s.templates))
+	return s
+}
+
+// List returns all templates ordered by ID
+func (s *TemplateStore) List() []MessageTemplate {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	result := make([]MessageTemplate, 0, len(s.templates))
+	for _, t := range s.templates {
+		result = append(result, *t)
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].ID < result[j].ID })
+	return result
+}
+
+// Get returns a template by ID
+func (s *TemplateStore) Get(id string) (*MessageTemplate, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	t, exists := s.templates[id]
+	if !exists {
+		return nil, ErrTemplateNotFound
+	}
+	copied := *t
+	return &copied, nil
+}
+
+// Save validates and creates or replaces a template
+func (s *TemplateStore) Save(t MessageTemplate) (*MessageTemplate, error) {
+	if err := validateTemplate(&t); err != nil {
+		return nil, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now().Format(time.RFC3339)
+	t.CreatedAt = now
+	if existing, exists := s.templates[t.ID]; exists {
+		t.CreatedAt = existing.CreatedAt
+	}
+	t.UpdatedAt = now
+
+	previous := s.templates[t.ID]
+	s.templates[t.ID] = &t
+	if err := s.persistLocked(); err != nil {
+		if previous != nil {
+			s.templates[t.ID] = previous
+		} else {
+			delete(s.templates, t.ID)
+		}
+		return nil, err
+	}
+
+	log.Printf("[TEMPLATES] 📝 Saved template %s (%d variants)", t.ID, len(t.Variants))
+	return &t, nil
+}
+
+// Delete removes a template
+func (s *TemplateStore) Delete(id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	previous, exists := s.templates[id]
+	if !exists {
+		return ErrTemplateNotFound
+	}
+
+	delete(s.templates, id)
+	if err := s.persistLocked(); err != nil {
+		s.templates[id] = previous
+		return err
+	}
+
+	log.Printf("[TEMPLATES] 🗑️ Deleted template %s", id)
+	return nil
+}
+
+// Render validates the variables and renders the variant best matching the
+// language. It returns the rendered text and the language that was used.
+func (s *TemplateStore) Render(id, language string, values map[string]string) (string, string, error) {
+	t, err := s.Get(id)
+	if err != nil {
+		return "", "", err
+	}
+
+	resolved, err := resolveTemplateVariables(t, values)
+	if err != nil {
+		return "", "", err
+	}
+
+	variant := selectTemplateVariant(t, language)
+	text := templatePlaceholderRegex.ReplaceAllStringFunc(variant.Body, func(placeholder string) string {
+		return resolved[templatePlaceholderRegex.FindStringSubmatch(placeholder)[1]]
+	})
+	return text, variant.Language, nil
+}
+
+// persistLocked writes all templates to disk. Caller must hold the write lock.
+func (s *TemplateStore) persistLocked() error {
+	templates := make([]*MessageTemplate, 0, len(s.templates))
+	for _, t := range s.templates {
+		templates = append(templates, t)
+	}
+	sort.Slice(templates, func(i, j int) bool { return templates[i].ID < templates[j].ID })
+
+	jsonData, err := json.MarshalIndent(templates, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal templates: %w", err)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(s.path), 0755); err != nil {
+		return fmt.Errorf("failed to create templates directory: %w", err)
+	}
+
+	// Write to a temp file first so a crash never leaves a truncated store
+	tmpFile := s.path + ".tmp"
+	if err := os.WriteFile(tmpFile, jsonData, 0644); err != nil {
+		return fmt.Errorf("failed to write templates file: %w", err)
+	}
+	if err := os.Rename(tmpFile, s.path); err != nil {
+		return fmt.Errorf("failed to replace templates file: %w", err)
+	}
+
+	return nil
+}
+
+// validateTemplate checks a template before it is stored and fills in defaults
+func validateTemplate(t *MessageTemplate) error {
+	if !templateIDRegex.MatchString(t.ID) {
+		return &TemplateError{"id must be 1-64 letters, digits, '_', '.' or '-'"}
+	}
+	if len(t.Variants) == 0 {
+		return &TemplateError{"at least one variant is required"}
+	}
+
+	declared := make(map[string]bool, len(t.Variables))
+	for i := range t.Variables {
+		v := &t.Variables[i]
+		if v.Type == "" {
+			v.Type = TemplateVarText
+		}
+		if !templateVariableRegex.MatchString(v.Name) {
+			return &TemplateError{fmt.Sprintf("variables[%d]: invalid name %q", i, v.Name)}
+		}
+		if declared[v.Name] {
+			return &TemplateError{fmt.Sprintf("variables[%d]: duplicate variable %s", i, v.Name)}
+		}
+		switch v.Type {
+		case TemplateVarText, TemplateVarNumber, TemplateVarDate, TemplateVarURL:
+		default:
+			return &TemplateError{fmt.Sprintf("variables[%d]: type must be text, number, date or url", i)}
+		}
+		if v.Default != "" {
+			if err := checkTemplateValue(v.Type, v.Default); err != nil {
+				return &TemplateError{fmt.Sprintf("variables[%d]: default %v", i, err)}
+			}
+		}
+		declared[v.Name] = true
+	}
+
+	languages := make(map[string]bool, len(t.Variants))
+	for i, variant := range t.Variants {
+		if variant.Language == "" || variant.Body == "" {
+			return &TemplateError{fmt.Sprintf("variants[%d]: language and body required", i)}
+		}
+		lang := strings.ToLower(variant.Language)
+		if languages[lang] {
+			return &TemplateError{fmt.Sprintf("variants[%d]: duplicate language %s", i, variant.Language)}
+		}
+		languages[lang] = true
+
+		for _, match := range templatePlaceholderRegex.FindAllStringSubmatch(variant.Body, -1) {
+			if !declared[match[1]] {
+				return &TemplateError{fmt.Sprintf("variants[%d]: placeholder {{%s}} is not a declared variable", i, match[1])}
+			}
+		}
+	}
+
+	if t.DefaultLanguage == "" {
+		t.DefaultLanguage = t.Variants[0].Language
+	}
+	if !languages[strings.ToLower(t.DefaultLanguage)] {
+		return &TemplateError{"default_language must match one of the variant languages"}
+	}
+
+	return nil
+}
+
+// resolveTemplateVariables applies defaults and rejects missing or mistyped values
+func resolveTemplateVariables(t *MessageTemplate, values map[string]string) (map[string]string, error) {
+	resolved := make(map[string]string, len(t.Variables))
+	var missing, invalid []string
+
+	for _, v := range t.Variables {
+		value := values[v.Name]
+		if value == "" {
+			value = v.Default
+		}
+		if value == "" {
+			if v.Required {
+				missing = append(missing, v.Name)
+			}
+			continue
+		}
+		if err := checkTemplateValue(v.Type, value); err != nil {
+			invalid = append(invalid, fmt.Sprintf("%s: %v", v.Name, err))
+			continue
+		}
+		resolved[v.Name] = value
+	}
+
+	var problems []string
+	if len(missing) > 0 {
+		problems = append(problems, "missing required variables: "+strings.Join(missing, ", "))
+	}
+	problems = append(problems, invalid...)
+	if len(problems) > 0 {
+		return nil, &TemplateError{strings.Join(problems, "; ")}
+	}
+
+	return resolved, nil
+}
+
+// selectTemplateVariant tries an exact language match, then the base language
+// ("pt" for "pt-BR"), then the template default language
+func selectTemplateVariant(t *MessageTemplate, language string) TemplateVariant {
+	baseLanguage := func(lang string) string {
+		lang = strings.ToLower(lang)
+		if i := strings.IndexAny(lang, "-_"); i > 0 {
+			return lang[:i]
+		}
+		return lang
+	}
+
+	if language != "" {
+		for _, variant := range t.Variants {
+			if strings.EqualFold(variant.Language, language) {
+				return variant
+			}
+		}
+		for _, variant := range t.Variants {
+			if baseLanguage(variant.Language) == baseLanguage(language) {
+				return variant
+			}
+		}
+	}
+
+	for _, variant := range t.Variants {
+		if strings.EqualFold(variant.Language, t.DefaultLanguage) {
+			return variant
+		}
+	}
+	return t.Variants[0]
+}
+
+func checkTemplateValue(varType, value string) error {
+	switch varType {
+	case TemplateVarNumber:
+		if _, err := strconv.ParseFloat(value, 64); err != nil {
+			return errors.New("must be a number")
+		}
+	case TemplateVarDate:
+		if _, err := time.Parse(TemplateDateLayout, value); err != nil {
+			return errors.New("must be a date in YYYY-MM-DD format")
+		}
+	case TemplateVarURL:
+		u, err := url.ParseRequestURI(value)
+		if err != nil || u.Host == "" || (u.Scheme != "http" && u.Scheme != "https") {
+			return errors.New("must be an http(s) URL")
+		}
+	}
+	return nil
+}
@@ -0,0 +1,237 @@
+package whatsapp
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func newTestTemplate() MessageTemplate {
+	return MessageTemplate{
+		ID:              "order_shipped",
+		DefaultLanguage: "en",
+		Variables: []TemplateVariable{
+			{Name: "name", Type: TemplateVarText, Required: true},
+			{Name: "order_id", Type: TemplateVarNumber, Required: true},
+			{Name: "carrier", Type: TemplateVarText, Default: "our courier"},
+		},
+		Variants: []TemplateVariant{
+			{Language: "en", Body: "Hi {{name}}, order #{{order_id}} was handed to {{carrier}}."},
+			{Language: "pt-BR", Body: "Olá {{name}}, o pedido #{{order_id}} foi entregue a {{carrier}}."},
+		},
+	}
+}
+
+func TestValidateTemplate(t *testing.T) {
+	tests := []struct {
+		name        string
+		template    MessageTemplate
+		errContains string
+	}{
+		{
+			name: "typed variables and two languages",
+			template: MessageTemplate{
+				ID: "order_shipped",
+				Variables: []TemplateVariable{
+					{Name: "name", Required: true},
+					{Name: "order_id", Type: TemplateVarNumber, Required: true},
+					{Name: "eta", Type: TemplateVarDate, Default: "2025-01-31"},
+				},
+				Variants: []TemplateVariant{
+					{Language: "en", Body: "Hi {{name}}, order {{order_id}} arrives {{eta}}"},
+					{Language: "id", Body: "Halo {{name}}, pesanan {{order_id}} tiba {{eta}}"},
+				},
+			},
+		},
+		{
+			name:        "without variants",
+			template:    MessageTemplate{ID: "empty"},
+			errContains: "at least one variant is required",
+		},
+		{
+			name: "invalid template id",
+			template: MessageTemplate{
+				ID:       "has space",
+				Variants: []TemplateVariant{{Language: "en", Body: "hi"}},
+			},
+			errContains: "id must be",
+		},
+		{
+			name: "undeclared placeholder",
+			template: MessageTemplate{
+				ID:       "greeting",
+				Variants: []TemplateVariant{{Language: "en", Body: "Hi {{name}}"}},
+			},
+			errContains: "placeholder {{name}} is not a declared variable",
+		},
+		{
+			name: "unknown variable type",
+			template: MessageTemplate{
+				ID:        "greeting",
+				Variables: []TemplateVariable{{Name: "name", Type: "email"}},
+				Variants:  []TemplateVariant{{Language: "en", Body: "Hi {{name}}"}},
+			},
+			errContains: "variables[0]: type must be text, number, date or url",
+		},
+		{
+			name: "duplicate variable",
+			template: MessageTemplate{
+				ID:        "greeting",
+				Variables: []TemplateVariable{{Name: "name"}, {Name: "name"}},
+				Variants:  []TemplateVariant{{Language: "en", Body: "Hi {{name}}"}},
+			},
+			errContains: "variables[1]: duplicate variable name",
+		},
+		{
+			name: "default that does not match the type",
+			template: MessageTemplate{
+				ID:        "invoice",
+				Variables: []TemplateVariable{{Name: "amount", Type: TemplateVarNumber, Default: "ten"}},
+				Variants:  []TemplateVariant{{Language: "en", Body: "Total {{amount}}"}},
+			},
+			errContains: "variables[0]: default must be a number",
+		},
+		{
+			name: "duplicate language",
+			template: MessageTemplate{
+				ID: "greeting",
+				Variants: []TemplateVariant{
+					{Language: "en", Body: "Hi"},
+					{Language: "EN", Body: "Hello"},
+				},
+			},
+			errContains: "variants[1]: duplicate language EN",
+		},
+		{
+			name: "default language without a variant",
+			template: MessageTemplate{
+				ID:              "greeting",
+				DefaultLanguage: "fr",
+				Variants:        []TemplateVariant{{Language: "en", Body: "Hi"}},
+			},
+			errContains: "default_language must match one of the variant languages",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateTemplate(&tt.template)
+			if tt.errContains == "" {
+				if err != nil {
+					t.Fatalf("validateTemplate() error = %v", err)
+				}
+				return
+			}
+
+			var templateErr *TemplateError
+			if !errors.As(err, &templateErr) {
+				t.Fatalf("validateTemplate() error = %v, want a TemplateError", err)
+			}
+			if !strings.Contains(err.Error(), tt.errContains) {
+				t.Fatalf("validateTemplate() error = %q, want it to contain %q", err, tt.errContains)
+			}
+		})
+	}
+}
+
+func TestValidateTemplateDefaults(t *testing.T) {
+	template := MessageTemplate{
+		ID:        "greeting",
+		Variables: []TemplateVariable{{Name: "name"}},
+		Variants:  []TemplateVariant{{Language: "id", Body: "Halo {{name}}"}},
+	}
+
+	if err := validateTemplate(&template); err != nil {
+		t.Fatalf("validateTemplate() error = %v", err)
+	}
+	if template.Variables[0].Type != TemplateVarText {
+		t.Errorf("variable type = %q, want %q", template.Variables[0].Type, TemplateVarText)
+	}
+	if template.DefaultLanguage != "id" {
+		t.Errorf("default language = %q, want %q", template.DefaultLanguage, "id")
+	}
+}
+
+func TestSelectTemplateVariant(t *testing.T) {
+	tests := []struct {
+		name     string
+		language string
+		want     string
+	}{
+		{name: "exact match", language: "pt-BR", want: "pt-BR"},
+		{name: "case insensitive", language: "PT-br", want: "pt-BR"},
+		{name: "base language", language: "pt", want: "pt-BR"},
+		{name: "regional variant of the base language", language: "en-GB", want: "en"},
+		{name: "unknown falls back to the default", language: "de", want: "en"},
+		{name: "empty uses the default", language: "", want: "en"},
+	}
+
+	template := newTestTemplate()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := selectTemplateVariant(&template, tt.language); got.Language != tt.want {
+				t.Fatalf("selectTemplateVariant() = %q, want %q", got.Language, tt.want)
+			}
+		})
+	}
+}
+
+func TestTemplateStoreRender(t *testing.T) {
+	t.Chdir(t.TempDir())
+	store := NewTemplateStore()
+	if _, err := store.Save(newTestTemplate()); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+
+	tests := []struct {
+		name         string
+		language     string
+		values       map[string]string
+		wantText     string
+		wantLanguage string
+		errContains  string
+	}{
+		{
+			name:         "uses defaults for omitted variables",
+			language:     "pt",
+			values:       map[string]string{"name": "Ana", "order_id": "42"},
+			wantText:     "Olá Ana, o pedido #42 foi entregue a our courier.",
+			wantLanguage: "pt-BR",
+		},
+		{
+			name:        "rejects missing required variables",
+			language:    "en",
+			values:      map[string]string{"carrier": "DHL"},
+			errContains: "missing required variables: name, order_id",
+		},
+		{
+			name:        "rejects values of the wrong type",
+			language:    "en",
+			values:      map[string]string{"name": "Ana", "order_id": "abc"},
+			errContains: "order_id: must be a number",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			text, language, err := store.Render("order_shipped", tt.language, tt.values)
+			if tt.errContains != "" {
+				var templateErr *TemplateError
+				if !errors.As(err, &templateErr) || !strings.Contains(err.Error(), tt.errContains) {
+					t.Fatalf("Render() error = %v, want a TemplateError containing %q", err, tt.errContains)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Render() error = %v", err)
+			}
+			if text != tt.wantText || language != tt.wantLanguage {
+				t.Fatalf("Render() = %q (%s), want %q (%s)", text, language, tt.wantText, tt.wantLanguage)
+			}
+		})
+	}
+
+	if _, _, err := store.Render("unknown", "en", nil); !errors.Is(err, ErrTemplateNotFound) {
+		t.Fatalf("Render() of an unknown template error = %v, want ErrTemplateNotFound", err)
+	}
+}